@@ -27,6 +27,98 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Deliveries         []EscalationDelivery // External notification results, one per action
+
+	// Deduplication and incident grouping (see gt escalate)
+	Fingerprint string   // Hash of reason, related bead, and source rig
+	IncidentKey string   // Hash of reason and related bead, shared across rigs
+	Incident    string   // Incident bead this escalation rolled up into
+	Occurrences int      // Times this was escalated (or, for incidents, across members)
+	LastSeenAt  string   // When last escalated (ISO 8601)
+	Members     []string // Incident beads only: escalations in the incident
+	Rigs        []string // Incident beads only: rigs the members came from
+}
+
+// IncidentLabel marks escalation beads that group related escalations.
+const IncidentLabel = "gt:incident"
+
+// OccurrenceCount returns the number of times the escalation was raised,
+// counting the original for beads written before occurrences were tracked.
+func (f *EscalationFields) OccurrenceCount() int {
+	return max(f.Occurrences, 1)
+}
+
+// LastSeen returns when the escalation was last raised.
+func (f *EscalationFields) LastSeen() time.Time {
+	for _, ts := range []string{f.LastSeenAt, f.EscalatedAt} {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Escalation delivery statuses.
+const (
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+	DeliverySkipped = "skipped" // not configured; not retried
+)
+
+// EscalationDelivery records the outcome of one external notification
+// action (email:, sms:, slack, webhook:, log) for an escalation.
+// Stored as "delivery: <action> | <status> | <attempts> | <at> | <error>".
+type EscalationDelivery struct {
+	Action   string `json:"action"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	At       string `json:"at"`              // ISO 8601 time of the last attempt
+	Error    string `json:"error,omitempty"` // last error, for failed/skipped
+}
+
+// FailedDeliveries returns the deliveries that failed and should be retried.
+func (f *EscalationFields) FailedDeliveries() []EscalationDelivery {
+	var failed []EscalationDelivery
+	for _, d := range f.Deliveries {
+		if d.Status == DeliveryFailed {
+			failed = append(failed, d)
+		}
+	}
+	return failed
+}
+
+// SetDelivery records d, replacing any earlier record for the same action.
+func (f *EscalationFields) SetDelivery(d EscalationDelivery) {
+	for i := range f.Deliveries {
+		if f.Deliveries[i].Action == d.Action {
+			f.Deliveries[i] = d
+			return
+		}
+	}
+	f.Deliveries = append(f.Deliveries, d)
+}
+
+func formatDelivery(d EscalationDelivery) string {
+	// Errors can span lines (e.g., SMTP replies); keep the record on one.
+	errMsg := strings.Join(strings.Fields(d.Error), " ")
+	return fmt.Sprintf("delivery: %s | %s | %d | %s | %s", d.Action, d.Status, d.Attempts, d.At, errMsg)
+}
+
+func parseDelivery(value string) (EscalationDelivery, bool) {
+	parts := strings.SplitN(value, "|", 5)
+	if len(parts) < 4 {
+		return EscalationDelivery{}, false
+	}
+	d := EscalationDelivery{
+		Action: strings.TrimSpace(parts[0]),
+		Status: strings.TrimSpace(parts[1]),
+		At:     strings.TrimSpace(parts[3]),
+	}
+	d.Attempts, _ = strconv.Atoi(strings.TrimSpace(parts[2]))
+	if len(parts) == 5 {
+		d.Error = strings.TrimSpace(parts[4])
+	}
+	return d, d.Action != ""
 }
 
 
@@ -96,6 +188,32 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 	} else {
 		lines = append(lines, "last_reescalated_by: null")
 	}
+	for _, d := range fields.Deliveries {
+		lines = append(lines, formatDelivery(d))
+	}
+
+	// Dedup and incident fields, only once set
+	if fields.Fingerprint != "" {
+		lines = append(lines, fmt.Sprintf("fingerprint: %s", fields.Fingerprint))
+	}
+	if fields.IncidentKey != "" {
+		lines = append(lines, fmt.Sprintf("incident_key: %s", fields.IncidentKey))
+	}
+	if fields.Incident != "" {
+		lines = append(lines, fmt.Sprintf("incident: %s", fields.Incident))
+	}
+	if fields.Occurrences > 0 {
+		lines = append(lines, fmt.Sprintf("occurrences: %d", fields.Occurrences))
+	}
+	if fields.LastSeenAt != "" {
+		lines = append(lines, fmt.Sprintf("last_seen_at: %s", fields.LastSeenAt))
+	}
+	if len(fields.Members) > 0 {
+		lines = append(lines, fmt.Sprintf("members: %s", strings.Join(fields.Members, ",")))
+	}
+	if len(fields.Rigs) > 0 {
+		lines = append(lines, fmt.Sprintf("rigs: %s", strings.Join(fields.Rigs, ",")))
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -152,15 +270,56 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery":
+			if d, ok := parseDelivery(value); ok {
+				fields.SetDelivery(d)
+			}
+		case "fingerprint":
+			fields.Fingerprint = value
+		case "incident_key":
+			fields.IncidentKey = value
+		case "incident":
+			fields.Incident = value
+		case "occurrences":
+			if n, err := strconv.Atoi(value); err == nil {
+				fields.Occurrences = n
+			}
+		case "last_seen_at":
+			fields.LastSeenAt = value
+		case "members":
+			fields.Members = splitList(value)
+		case "rigs":
+			fields.Rigs = splitList(value)
 		}
 	}
 
 	return fields
 }
 
+// splitList splits a comma-separated field value.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // CreateEscalationBead creates an escalation bead for tracking escalations.
 // The created_by field is populated from BD_ACTOR env var for provenance tracking.
 func (b *Beads) CreateEscalationBead(title string, fields *EscalationFields) (*Issue, error) {
+	return b.createEscalationBead(title, fields)
+}
+
+// CreateIncidentBead creates an escalation bead that groups related
+// escalations. It is acked, closed, and re-escalated like any escalation.
+func (b *Beads) CreateIncidentBead(title string, fields *EscalationFields) (*Issue, error) {
+	return b.createEscalationBead(title, fields, "--labels="+IncidentLabel)
+}
+
+func (b *Beads) createEscalationBead(title string, fields *EscalationFields, extraArgs ...string) (*Issue, error) {
 	// Guard against flag-like titles (gt-e0kx5: --help garbage beads)
 	if IsFlagLikeTitle(title) {
 		return nil, fmt.Errorf("refusing to create escalation bead: %w (got %q)", ErrFlagTitle, title)
@@ -179,6 +338,7 @@ func (b *Beads) CreateEscalationBead(title string, fields *EscalationFields) (*I
 	if fields != nil && fields.Severity != "" {
 		args = append(args, fmt.Sprintf("--labels=severity:%s", fields.Severity))
 	}
+	args = append(args, extraArgs...)
 
 	// Default actor from BD_ACTOR env var for provenance tracking
 	// Uses getActor() to respect isolated mode (tests)
@@ -348,6 +508,51 @@ func (b *Beads) ListStaleEscalations(threshold time.Duration) ([]*Issue, error)
 	return stale, nil
 }
 
+// RecordEscalationDeliveries stores delivery results on an escalation bead,
+// replacing earlier results for the same actions.
+func (b *Beads) RecordEscalationDeliveries(id string, deliveries []EscalationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	issue, fields, err := b.GetEscalationBead(id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("escalation not found: %s", id)
+	}
+	for _, d := range deliveries {
+		fields.SetDelivery(d)
+	}
+	description := FormatEscalationDescription(issue.Title, fields)
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
+// UpdateEscalationFields applies update to an escalation bead's fields and
+// saves them, moving the severity label if update changed the severity.
+func (b *Beads) UpdateEscalationFields(id string, update func(*EscalationFields)) (*EscalationFields, error) {
+	issue, fields, err := b.GetEscalationBead(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("escalation not found: %s", id)
+	}
+	oldSeverity := fields.Severity
+	update(fields)
+
+	description := FormatEscalationDescription(issue.Title, fields)
+	opts := UpdateOptions{Description: &description}
+	if fields.Severity != oldSeverity {
+		opts.AddLabels = []string{"severity:" + fields.Severity}
+		opts.RemoveLabels = []string{"severity:" + oldSeverity}
+	}
+	if err := b.Update(id, opts); err != nil {
+		return nil, fmt.Errorf("updating escalation: %w", err)
+	}
+	return fields, nil
+}
+
 // ReescalationResult holds the result of a reescalation operation.
 type ReescalationResult struct {
 	ID              string
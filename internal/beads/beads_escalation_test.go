@@ -3,6 +3,7 @@ package beads
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatEscalationDescription(t *testing.T) {
@@ -300,6 +301,56 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestEscalationDeliveriesRoundTrip(t *testing.T) {
+	fields := &EscalationFields{Severity: "high"}
+	fields.SetDelivery(EscalationDelivery{Action: "email:human", Status: DeliveryFailed, Attempts: 1, At: "2024-06-15T12:00:00Z", Error: "dial tcp: connection refused\n421 try later"})
+	fields.SetDelivery(EscalationDelivery{Action: "webhook:pager", Status: DeliverySent, Attempts: 1, At: "2024-06-15T12:00:01Z"})
+	fields.SetDelivery(EscalationDelivery{Action: "email:human", Status: DeliveryFailed, Attempts: 2, At: "2024-06-15T13:00:00Z", Error: "535 auth | failed"})
+
+	parsed := ParseEscalationFields(FormatEscalationDescription("Escalation: Build failure", fields))
+	if len(parsed.Deliveries) != 2 {
+		t.Fatalf("Deliveries = %+v, want 2", parsed.Deliveries)
+	}
+	email := parsed.Deliveries[0]
+	if email.Action != "email:human" || email.Status != DeliveryFailed || email.Attempts != 2 || email.Error != "535 auth | failed" {
+		t.Errorf("email delivery = %+v", email)
+	}
+	if hook := parsed.Deliveries[1]; hook.Action != "webhook:pager" || hook.Status != DeliverySent || hook.Error != "" {
+		t.Errorf("webhook delivery = %+v", hook)
+	}
+	if failed := parsed.FailedDeliveries(); len(failed) != 1 || failed[0].Action != "email:human" {
+		t.Errorf("FailedDeliveries() = %+v", failed)
+	}
+}
+
+func TestEscalationIncidentFieldsRoundTrip(t *testing.T) {
+	original := &EscalationFields{
+		Severity:    "critical",
+		EscalatedAt: "2026-03-01T11:40:00Z",
+		IncidentKey: "a1b2c3d4e5f6",
+		Occurrences: 7,
+		LastSeenAt:  "2026-03-01T12:00:00Z",
+		Members:     []string{"hq-a", "hq-b"},
+		Rigs:        []string{"gastown", "beads"},
+	}
+	parsed := ParseEscalationFields(FormatEscalationDescription("Incident: Dolt down", original))
+	if parsed.IncidentKey != original.IncidentKey || parsed.Occurrences != 7 || parsed.LastSeenAt != original.LastSeenAt {
+		t.Errorf("parsed = %+v", parsed)
+	}
+	if strings.Join(parsed.Members, ",") != "hq-a,hq-b" || strings.Join(parsed.Rigs, ",") != "gastown,beads" {
+		t.Errorf("Members = %v, Rigs = %v", parsed.Members, parsed.Rigs)
+	}
+	if !parsed.LastSeen().Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastSeen() = %v", parsed.LastSeen())
+	}
+
+	// Beads from before occurrences were tracked count once, last seen when escalated
+	legacy := ParseEscalationFields("Dolt down\n\nseverity: high\nescalated_at: 2026-03-01T11:40:00Z")
+	if legacy.OccurrenceCount() != 1 || !legacy.LastSeen().Equal(time.Date(2026, 3, 1, 11, 40, 0, 0, time.UTC)) {
+		t.Errorf("legacy: count %d, last seen %v", legacy.OccurrenceCount(), legacy.LastSeen())
+	}
+}
+
 func TestBumpSeverity(t *testing.T) {
 	tests := []struct {
 		input string
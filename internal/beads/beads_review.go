@@ -0,0 +1,133 @@
+// Package beads provides review bead fields for the refinery's review gate.
+package beads
+
+import (
+	"strings"
+)
+
+// ReviewLabel marks review beads created by the refinery's review gate.
+const ReviewLabel = "gt:review"
+
+// Review outcomes, recorded in a review bead's outcome field (or given as
+// its close reason) by the reviewer.
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// ReviewFields holds structured fields for review beads.
+// These are stored as "key: value" lines in the description.
+type ReviewFields struct {
+	MR     string   // Merge request under review
+	Branch string   // Source branch
+	Target string   // Target branch
+	Head   string   // Branch commit that was reviewed; a new push needs a new review
+	Owners []string // Gas Town addresses asked to review
+	Paths  []string // Owned paths the MR touches
+
+	Outcome    string // approved or changes_requested (empty while pending)
+	ReviewedBy string // Who recorded the outcome
+	Comment    string // Reviewer's summary, sent back with changes_requested
+}
+
+// FormatReviewDescription creates a review bead description from a summary
+// paragraph and review fields. Only non-empty fields are included.
+func FormatReviewDescription(summary string, fields *ReviewFields) string {
+	var lines []string
+	if summary != "" {
+		lines = append(lines, summary, "")
+	}
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+	add("review_mr", fields.MR)
+	add("branch", fields.Branch)
+	add("target", fields.Target)
+	add("head", fields.Head)
+	add("owners", strings.Join(fields.Owners, ", "))
+	add("paths", strings.Join(fields.Paths, ", "))
+	add("outcome", fields.Outcome)
+	add("reviewed_by", fields.ReviewedBy)
+	add("comment", fields.Comment)
+	return strings.Join(lines, "\n")
+}
+
+// ParseReviewFields extracts review fields from a review bead's description.
+// Only the field block, from the review_mr line on, is read, so "key: value"
+// lines in the summary can't masquerade as fields. Returns nil if the
+// description has no review_mr field.
+func ParseReviewFields(description string) *ReviewFields {
+	_, block, ok := splitReviewDescription(description)
+	if !ok {
+		return nil
+	}
+	fields := &ReviewFields{}
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "review_mr":
+			fields.MR = value
+		case "branch":
+			fields.Branch = value
+		case "target":
+			fields.Target = value
+		case "head":
+			fields.Head = value
+		case "owners":
+			fields.Owners = splitReviewList(value)
+		case "paths":
+			fields.Paths = splitReviewList(value)
+		case "outcome":
+			fields.Outcome = value
+		case "reviewed_by":
+			fields.ReviewedBy = value
+		case "comment":
+			fields.Comment = value
+		}
+	}
+	if fields.MR == "" {
+		return nil
+	}
+	return fields
+}
+
+// SetReviewFields rewrites a review bead's description with fields, keeping
+// its summary (the text before the first field line).
+func SetReviewFields(issue *Issue, fields *ReviewFields) string {
+	summary, _, _ := splitReviewDescription(issue.Description)
+	return FormatReviewDescription(strings.TrimSpace(summary), fields)
+}
+
+// splitReviewDescription splits a review bead's description into its
+// summary and the field block starting at the review_mr line. ok is false,
+// and summary is the whole description, when there is no review_mr line.
+func splitReviewDescription(description string) (summary, block string, ok bool) {
+	if strings.HasPrefix(description, "review_mr:") {
+		return "", description, true
+	}
+	if idx := strings.Index(description, "\nreview_mr:"); idx >= 0 {
+		return description[:idx], description[idx+1:], true
+	}
+	return description, "", false
+}
+
+func splitReviewList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
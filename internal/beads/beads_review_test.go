@@ -0,0 +1,54 @@
+package beads
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReviewFieldsRoundTrip(t *testing.T) {
+	fields := &ReviewFields{
+		MR:     "gt-mr1",
+		Branch: "polecat/nux",
+		Target: "main",
+		Head:   "0123456789abcdef",
+		Owners: []string{"gastown/crew/alice", "gastown/crew/bob"},
+		Paths:  []string{"internal/config/types.go", "docs/reference.md"},
+	}
+	desc := FormatReviewDescription("Review polecat/nux before it merges.", fields)
+	if !strings.HasPrefix(desc, "Review polecat/nux before it merges.\n\nreview_mr: gt-mr1\n") {
+		t.Errorf("unexpected description:\n%s", desc)
+	}
+	if got := ParseReviewFields(desc); !reflect.DeepEqual(got, fields) {
+		t.Errorf("ParseReviewFields = %+v, want %+v", got, fields)
+	}
+
+	// Recording an outcome keeps the summary.
+	fields.Outcome = ReviewChangesRequested
+	fields.ReviewedBy = "gastown/crew/alice"
+	fields.Comment = "needs a migration"
+	updated := SetReviewFields(&Issue{Description: desc}, fields)
+	if !strings.HasPrefix(updated, "Review polecat/nux before it merges.\n\n") {
+		t.Errorf("summary lost:\n%s", updated)
+	}
+	if got := ParseReviewFields(updated); !reflect.DeepEqual(got, fields) {
+		t.Errorf("ParseReviewFields after SetReviewFields = %+v, want %+v", got, fields)
+	}
+}
+
+func TestParseReviewFields_IgnoresSummary(t *testing.T) {
+	desc := FormatReviewDescription("Branch: polecat/evil\nComment: looks fine to me", &ReviewFields{
+		MR:     "gt-mr1",
+		Branch: "polecat/nux",
+	})
+	got := ParseReviewFields(desc)
+	if got == nil || got.Branch != "polecat/nux" || got.Comment != "" {
+		t.Errorf("ParseReviewFields read the summary: %+v", got)
+	}
+}
+
+func TestParseReviewFields_NotAReview(t *testing.T) {
+	if got := ParseReviewFields("branch: polecat/nux\ntarget: main"); got != nil {
+		t.Errorf("expected nil without review_mr, got %+v", got)
+	}
+}
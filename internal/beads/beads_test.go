@@ -590,13 +590,21 @@ author: someone`,
 // TestMRFieldsRoundTrip tests that parse/format round-trips correctly.
 func TestMRFieldsRoundTrip(t *testing.T) {
 	original := &MRFields{
-		Branch:      "polecat/Nux/gt-xyz",
-		Target:      "main",
-		SourceIssue: "gt-xyz",
-		Worker:      "Nux",
-		Rig:         "gastown",
-		MergeCommit: "abc123def789",
-		CloseReason: "merged",
+		Branch:         "polecat/Nux/gt-xyz",
+		Target:         "main",
+		SourceIssue:    "gt-xyz",
+		Worker:         "Nux",
+		Rig:            "gastown",
+		MergeCommit:    "abc123def789",
+		RevertCommit:   "fed987cba321",
+		CloseReason:    "merged",
+		GateLog:        "/rigs/gastown/.runtime/gate-logs/gt-mr1.log",
+		MergeStrategy:  "rebase",
+		ConvoyDeadline: "2026-02-01T00:00:00Z",
+		DiffLines:      240,
+		ReviewBead:     "gt-rev1",
+		TraceID:        "tr-1a2b3c4d",
+		Traceparent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
 	}
 
 	// Format to string
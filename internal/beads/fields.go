@@ -27,6 +27,9 @@ type AttachmentFields struct {
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
+	MRStrategy       string // How the MR lands: "squash", "merge", "rebase", "ff", or "" (rig default)
+	TraceID          string // Correlation ID minted by gt sling, carried to the MR and protocol mail (see gt trace)
+	Traceparent      string // W3C traceparent of gt sling's span, parent of later spans for this work (see internal/tracing)
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -86,6 +89,15 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "convoy_owned", "convoy-owned", "convoyowned":
 			fields.ConvoyOwned = strings.ToLower(value) == "true"
 			hasFields = true
+		case "mr_strategy", "mr-strategy", "mrstrategy":
+			fields.MRStrategy = value
+			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
+		case "traceparent":
+			fields.Traceparent = value
+			hasFields = true
 		}
 	}
 
@@ -131,6 +143,15 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.ConvoyOwned {
 		lines = append(lines, "convoy_owned: true")
 	}
+	if fields.MRStrategy != "" {
+		lines = append(lines, "mr_strategy: "+fields.MRStrategy)
+	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
+	if fields.Traceparent != "" {
+		lines = append(lines, "traceparent: "+fields.Traceparent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -167,6 +188,13 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"convoy_owned":      true,
 		"convoy-owned":      true,
 		"convoyowned":       true,
+		"mr_strategy":       true,
+		"mr-strategy":       true,
+		"mrstrategy":        true,
+		"trace_id":          true,
+		"trace-id":          true,
+		"traceid":           true,
+		"traceparent":       true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -226,6 +254,7 @@ type MRFields struct {
 	Worker      string // Who did the work
 	Rig         string // Which rig
 	MergeCommit string // SHA of merge commit (set on close)
+	RevertCommit string // SHA of the revert pushed when post-merge gates failed
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
 
@@ -237,6 +266,29 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+	ConvoyDeadline  string // Convoy deadline (ISO 8601); MRs gain priority as it nears
+
+	// DiffLines is the number of lines the branch changes (added + deleted),
+	// recorded at submission for priority scoring. 0 means unknown.
+	DiffLines int
+
+	// Gate failure artifact
+	GateLog string // Path to the full output of the last failed gate run
+
+	// ReviewBead links the review requested by the refinery's review gate.
+	ReviewBead string
+
+	// MergeStrategy overrides the rig's merge strategy for this MR:
+	// "squash", "merge", "rebase", or "ff". Empty uses the rig default.
+	MergeStrategy string
+
+	// TraceID is the correlation ID of the work being merged, copied from
+	// the hook bead by gt done (see gt trace).
+	TraceID string
+
+	// Traceparent is the W3C traceparent of gt done's span, which the
+	// refinery's spans for this MR are children of (see internal/tracing).
+	Traceparent string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -288,6 +340,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "merge_commit", "merge-commit", "mergecommit":
 			fields.MergeCommit = value
 			hasFields = true
+		case "revert_commit", "revert-commit", "revertcommit":
+			fields.RevertCommit = value
+			hasFields = true
 		case "close_reason", "close-reason", "closereason":
 			fields.CloseReason = value
 			hasFields = true
@@ -311,6 +366,29 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "convoy_deadline", "convoy-deadline", "convoydeadline":
+			fields.ConvoyDeadline = value
+			hasFields = true
+		case "diff_lines", "diff-lines", "difflines":
+			if n, err := parseIntField(value); err == nil {
+				fields.DiffLines = n
+				hasFields = true
+			}
+		case "gate_log", "gate-log", "gatelog":
+			fields.GateLog = value
+			hasFields = true
+		case "review_bead", "review-bead", "reviewbead":
+			fields.ReviewBead = value
+			hasFields = true
+		case "merge_strategy", "merge-strategy", "mergestrategy":
+			fields.MergeStrategy = value
+			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
+		case "traceparent":
+			fields.Traceparent = value
+			hasFields = true
 		}
 	}
 
@@ -354,6 +432,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.MergeCommit != "" {
 		lines = append(lines, "merge_commit: "+fields.MergeCommit)
 	}
+	if fields.RevertCommit != "" {
+		lines = append(lines, "revert_commit: "+fields.RevertCommit)
+	}
 	if fields.CloseReason != "" {
 		lines = append(lines, "close_reason: "+fields.CloseReason)
 	}
@@ -375,6 +456,27 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.ConvoyDeadline != "" {
+		lines = append(lines, "convoy_deadline: "+fields.ConvoyDeadline)
+	}
+	if fields.DiffLines > 0 {
+		lines = append(lines, fmt.Sprintf("diff_lines: %d", fields.DiffLines))
+	}
+	if fields.GateLog != "" {
+		lines = append(lines, "gate_log: "+fields.GateLog)
+	}
+	if fields.ReviewBead != "" {
+		lines = append(lines, "review_bead: "+fields.ReviewBead)
+	}
+	if fields.MergeStrategy != "" {
+		lines = append(lines, "merge_strategy: "+fields.MergeStrategy)
+	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
+	if fields.Traceparent != "" {
+		lines = append(lines, "traceparent: "+fields.Traceparent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -399,6 +501,9 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"merge_commit":       true,
 		"merge-commit":       true,
 		"mergecommit":        true,
+		"revert_commit":      true,
+		"revert-commit":      true,
+		"revertcommit":       true,
 		"close_reason":       true,
 		"close-reason":       true,
 		"closereason":        true,
@@ -421,6 +526,25 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"convoy_deadline":    true,
+		"convoy-deadline":    true,
+		"convoydeadline":     true,
+		"diff_lines":         true,
+		"diff-lines":         true,
+		"difflines":          true,
+		"gate_log":           true,
+		"gate-log":           true,
+		"gatelog":            true,
+		"review_bead":        true,
+		"review-bead":        true,
+		"reviewbead":         true,
+		"merge_strategy":     true,
+		"merge-strategy":     true,
+		"mergestrategy":      true,
+		"trace_id":           true,
+		"trace-id":           true,
+		"traceid":            true,
+		"traceparent":        true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -207,6 +207,9 @@ func TestConvoyFieldsRoundTrip(t *testing.T) {
 		ConvoyID:         "hq-cv-xyz",
 		MergeStrategy:    "direct",
 		ConvoyOwned:      true,
+		MRStrategy:       "rebase",
+		TraceID:          "tr-1a2b3c4d",
+		Traceparent:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
 	}
 	formatted := FormatAttachmentFields(original)
 	parsed := ParseAttachmentFields(&Issue{Description: formatted})
@@ -225,6 +228,15 @@ func TestConvoyFieldsRoundTrip(t *testing.T) {
 	if parsed.ConvoyOwned != original.ConvoyOwned {
 		t.Errorf("ConvoyOwned: got %v, want %v", parsed.ConvoyOwned, original.ConvoyOwned)
 	}
+	if parsed.MRStrategy != original.MRStrategy {
+		t.Errorf("MRStrategy: got %q, want %q", parsed.MRStrategy, original.MRStrategy)
+	}
+	if parsed.TraceID != original.TraceID {
+		t.Errorf("TraceID: got %q, want %q", parsed.TraceID, original.TraceID)
+	}
+	if parsed.Traceparent != original.Traceparent {
+		t.Errorf("Traceparent: got %q, want %q", parsed.Traceparent, original.Traceparent)
+	}
 }
 
 func TestConvoyOwnedFalseNotFormatted(t *testing.T) {
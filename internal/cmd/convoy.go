@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -67,6 +68,7 @@ var (
 	convoyOwner        string
 	convoyOwned        bool
 	convoyMerge        string
+	convoyDeadline     string
 	convoyStatusJSON   bool
 	convoyListJSON     bool
 	convoyListStatus   string
@@ -187,6 +189,10 @@ The --merge flag sets the merge strategy for all work in the convoy:
   mr      Create merge-request bead, refinery processes (default)
   local   Keep on feature branch (for upstream PRs, human review)
 
+The --deadline flag records when the convoy is due, as a date, an RFC3339
+time, or a duration from now (e.g. 48h). As it nears, the refinery moves the
+convoy's MRs up the merge queue (merge_queue.scoring in rig settings).
+
 Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
@@ -194,7 +200,8 @@ Examples:
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
   gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
   gt convoy create --owned "Manual deploy" gt-abc           # caller-managed lifecycle
-  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery`,
+  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery
+  gt convoy create "Launch" gt-a gt-b --deadline 2026-03-01  # prioritize as due date nears`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -331,6 +338,7 @@ func init() {
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
 	convoyCreateCmd.Flags().BoolVar(&convoyOwned, "owned", false, "Mark convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	convoyCreateCmd.Flags().StringVar(&convoyDeadline, "deadline", "", "When the convoy is due: date, RFC3339 time, or duration from now (e.g. 48h)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -396,6 +404,16 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Validate --deadline flag if provided
+	var deadline time.Time
+	if convoyDeadline != "" {
+		var err error
+		deadline, err = parseTimeFlag("--deadline", convoyDeadline, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
 	if looksLikeIssueID(name) {
@@ -444,6 +462,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	if !deadline.IsZero() {
+		description += fmt.Sprintf("\nDeadline: %s", deadline.UTC().Format(time.RFC3339))
+	}
 
 	// Guard against flag-like convoy names (gt-e0kx5)
 	if beads.IsFlagLikeTitle(name) {
@@ -453,6 +474,11 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
 
+	// Root span of the convoy's trace: work slung from it is traced as its
+	// children. Only exported if the convoy is created.
+	convoySpan := tracing.New(filepath.Dir(townBeads)).StartConvoy(convoyID)
+	convoySpan.SetAttr("gt.convoy.name", name)
+
 	createArgs := []string{
 		"create",
 		"--type=convoy",
@@ -502,6 +528,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	convoySpan.SetAttr("gt.convoy.tracked", trackedCount)
+	convoySpan.End(nil)
+
 	// Output
 	fmt.Printf("%s Created convoy 🚚 %s\n\n", style.Bold.Render("✓"), convoyID)
 	fmt.Printf("  Name:     %s\n", name)
@@ -521,6 +550,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
+	if !deadline.IsZero() {
+		fmt.Printf("  Deadline: %s\n", deadline.Local().Format(time.RFC1123))
+	}
 	if convoyOwned {
 		fmt.Printf("  Owned:    %s\n", style.Warning.Render("caller-managed lifecycle"))
 	}
@@ -1579,6 +1611,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned         bool               `json:"owned"`
 			Lifecycle     string             `json:"lifecycle"`
 			MergeStrategy string             `json:"merge_strategy,omitempty"`
+			Deadline      string             `json:"deadline,omitempty"`
 			Tracked       []trackedIssueInfo `json:"tracked"`
 			Completed     int                `json:"completed"`
 			Total         int                `json:"total"`
@@ -1590,6 +1623,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned:         isOwned,
 			Lifecycle:     lifecycle,
 			MergeStrategy: parseConvoyMergeStrategy(convoy.Description),
+			Deadline:      parseConvoyDeadline(convoy.Description),
 			Tracked:       tracked,
 			Completed:     completed,
 			Total:         len(tracked),
@@ -1612,6 +1646,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	if merge != "" {
 		fmt.Printf("  Merge:     %s\n", merge)
 	}
+	if deadline := parseConvoyDeadline(convoy.Description); deadline != "" {
+		fmt.Printf("  Deadline:  %s\n", deadline)
+	}
 	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
@@ -1894,6 +1931,34 @@ func parseConvoyMergeStrategy(description string) string {
 	return ""
 }
 
+// parseConvoyDeadline extracts the deadline (RFC3339) from a convoy
+// description, or returns empty string if not set.
+func parseConvoyDeadline(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Deadline: ") {
+			return strings.TrimPrefix(line, "Deadline: ")
+		}
+	}
+	return ""
+}
+
+// parseTimeFlag parses the value of a time flag such as --deadline or
+// --until: an RFC3339 time, a date (YYYY-MM-DD, end of that day in local
+// time), or a duration from now.
+func parseTimeFlag(flag, value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q: want a date (2006-01-02), RFC3339 time, or duration (48h)", flag, value)
+}
+
 // formatYesNo returns "yes" or "no" for a boolean value.
 func formatYesNo(b bool) string {
 	if b {
@@ -17,6 +17,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -933,26 +934,6 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 	return nil
 }
 
-// CostLogEntry represents a single entry in the costs.jsonl log file.
-type CostLogEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
-}
-
-// getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
-func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
-	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
-}
-
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
 // This is called by the Claude Code Stop hook. It's designed to never fail due to
 // database availability - it's a simple file append operation.
@@ -1004,7 +985,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	role, rig, worker := parseSessionName(session)
 
 	// Build log entry
-	entry := CostLogEntry{
+	entry := costs.LogEntry{
 		SessionID: session,
 		Role:      role,
 		Rig:       rig,
@@ -1021,7 +1002,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	}
 
 	// Append to log file
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Ensure directory exists
 	logDir := filepath.Dir(logPath)
@@ -1232,7 +1213,7 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 
 // querySessionCostEntries reads session cost entries from the local log file for a target date.
 func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Read log file
 	data, err := os.ReadFile(logPath)
@@ -1246,7 +1227,7 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 	targetDay := targetDate.Format("2006-01-02")
 	var entries []CostEntry
 
-	// Parse each line as a CostLogEntry
+	// Parse each line as a costs.LogEntry
 	lines := strings.Split(string(data), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -1254,7 +1235,7 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 			continue
 		}
 
-		var logEntry CostLogEntry
+		var logEntry costs.LogEntry
 		if err := json.Unmarshal([]byte(line), &logEntry); err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] failed to parse log entry: %v\n", err)
@@ -1359,7 +1340,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 // deleteSessionCostEntries removes entries for a target date from the costs log file.
 // It rewrites the file without the entries for that date.
 func deleteSessionCostEntries(targetDate time.Time) (int, error) {
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Read log file
 	data, err := os.ReadFile(logPath)
@@ -1382,7 +1363,7 @@ func deleteSessionCostEntries(targetDate time.Time) (int, error) {
 			continue
 		}
 
-		var logEntry CostLogEntry
+		var logEntry costs.LogEntry
 		if err := json.Unmarshal([]byte(line), &logEntry); err != nil {
 			// Keep unparseable lines (shouldn't happen but be safe)
 			keepLines = append(keepLines, line)
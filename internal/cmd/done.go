@@ -24,6 +24,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -331,6 +332,25 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Carry the trace ID gt sling recorded on the issue into the MR bead,
+	// the witness notification, and the done event (see gt trace).
+	var traceID, slingTraceparent string
+	if issueID != "" {
+		traceID, slingTraceparent = slungTrace(beads.New(beads.ResolveBeadsDir(cwd)), issueID)
+	}
+
+	// Trace gt done as a child of the sling's span. The MR records this
+	// span as its traceparent, for the refinery's spans.
+	var doneSpan *tracing.Span
+	if parent, ok := tracing.ParseTraceparent(slingTraceparent); ok {
+		doneSpan = tracing.New(townRoot).Start("gt done", parent)
+		doneSpan.SetAttr("gt.bead", issueID)
+		doneSpan.SetAttr("gt.exit", exitType)
+		doneSpan.SetAttr("gt.branch", branch)
+		doneSpan.SetAttr("gt.trace_id", traceID)
+		defer func() { doneSpan.End(retErr) }()
+	}
+
 	// Write done-intent label EARLY, before push/MR operations.
 	// If gt done crashes after this point, the Witness can detect the intent
 	// and auto-nuke the zombie polecat.
@@ -399,6 +419,11 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				aheadCount = 1
 			}
 		}
+		if aheadCount > 0 && doneSpan != nil {
+			if first, err := g.FirstCommitTime(originDefault, "HEAD"); err == nil {
+				doneSpan.AddEvent("first commit", first)
+			}
+		}
 
 		// If no commits ahead, work was likely pushed directly to main (or already merged)
 		// For polecats, zero commits usually means the polecat hallucinated completion
@@ -714,18 +739,20 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 		}
 
-		// Get source issue for priority inheritance
+		// Get source issue for priority inheritance and scoring labels
 		var priority int
+		var sourceLabels []string
+		sourceIssue, sourceErr := bd.Show(issueID)
+		if sourceErr == nil {
+			sourceLabels = sourceIssue.Labels
+		}
 		if donePriority >= 0 {
 			priority = donePriority
+		} else if sourceErr != nil {
+			priority = 2 // Default
 		} else {
-			// Try to inherit from source issue
-			sourceIssue, err := bd.Show(issueID)
-			if err != nil {
-				priority = 2 // Default
-			} else {
-				priority = sourceIssue.Priority
-			}
+			// Inherit from source issue
+			priority = sourceIssue.Priority
 		}
 
 		// Check if MR bead already exists for this branch (idempotency)
@@ -751,6 +778,31 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			// Carry the merge strategy chosen at dispatch (gt sling --merge=rebase)
+			if strategy := slungMRStrategy(bd, issueID); strategy != "" {
+				description += fmt.Sprintf("\nmerge_strategy: %s", strategy)
+			}
+			if traceID != "" {
+				description += fmt.Sprintf("\ntrace_id: %s", traceID)
+			}
+			if tp := doneSpan.Traceparent(); tp != "" {
+				description += fmt.Sprintf("\ntraceparent: %s", tp)
+			}
+
+			// Convoy and diff size feed merge queue scoring
+			if convoyInfo != nil {
+				description += fmt.Sprintf("\nconvoy_id: %s", convoyInfo.ID)
+				createdAt, deadline := getConvoySchedule(townRoot, convoyInfo.ID)
+				if createdAt != "" {
+					description += fmt.Sprintf("\nconvoy_created_at: %s", createdAt)
+				}
+				if deadline != "" {
+					description += fmt.Sprintf("\nconvoy_deadline: %s", deadline)
+				}
+			}
+			if lines, err := g.DiffLines("origin/"+target, branch); err == nil && lines > 0 {
+				description += fmt.Sprintf("\ndiff_lines: %d", lines)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -775,6 +827,13 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 			mrID = mrIssue.ID
 
+			// Carry labels that weigh in merge queue scoring (hotfix, security)
+			if labels := scoringLabels(filepath.Join(townRoot, rigName), sourceLabels); len(labels) > 0 {
+				if err := bd.Update(mrID, beads.UpdateOptions{AddLabels: labels}); err != nil {
+					style.PrintWarning("could not label MR with %s: %v", strings.Join(labels, ", "), err)
+				}
+			}
+
 			// Update agent bead with active_mr reference (for traceability)
 			if agentBeadID != "" {
 				if err := bd.UpdateAgentActiveMR(agentBeadID, mrID); err != nil {
@@ -885,6 +944,9 @@ afterDoltMerge:
 	if len(doneErrors) > 0 {
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
+	if traceID != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("Trace: %s", traceID))
+	}
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
@@ -927,10 +989,15 @@ afterDoltMerge:
 	if err := LogDone(townRoot, sender, issueID); err != nil {
 		style.PrintWarning("could not log done event: %v", err)
 	}
-	if err := events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch)); err != nil {
+	if err := events.LogFeed(events.TypeDone, sender, events.WithTrace(events.DonePayload(issueID, branch), traceID)); err != nil {
 		style.PrintWarning("could not log feed event: %v", err)
 	}
 
+	// End the span before self-cleaning, which may kill this process
+	doneSpan.SetAttr("gt.mr", mrID)
+	doneSpan.SetAttr("gt.push_failed", pushFailed)
+	doneSpan.End(nil)
+
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
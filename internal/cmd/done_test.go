@@ -783,6 +783,38 @@ func TestParseConvoyMergeStrategy(t *testing.T) {
 	}
 }
 
+// TestParseDeadlineFlag verifies the --deadline formats accepted by gt convoy
+// create, and that the stored deadline round-trips through the description.
+func TestParseDeadlineFlag(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseTimeFlag("--deadline", "48h", now)
+	if err != nil || !got.Equal(now.Add(48*time.Hour)) {
+		t.Errorf("parseTimeFlag(48h) = %v, %v", got, err)
+	}
+	got, err = parseTimeFlag("--deadline", "2026-02-01T09:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseTimeFlag(RFC3339) = %v, %v", got, err)
+	}
+	got, err = parseTimeFlag("--deadline", "2026-02-01", now)
+	if err != nil || got.Day() != 1 || got.Hour() != 23 {
+		t.Errorf("parseTimeFlag(date) = %v, %v, want end of day", got, err)
+	}
+	for _, bad := range []string{"soon", "-2h", "2026-13-01"} {
+		if _, err := parseTimeFlag("--deadline", bad, now); err == nil {
+			t.Errorf("parseTimeFlag(%q) should fail", bad)
+		}
+	}
+
+	desc := "Convoy tracking 2 issues\nMerge: mr\nDeadline: 2026-02-01T09:00:00Z"
+	if got := parseConvoyDeadline(desc); got != "2026-02-01T09:00:00Z" {
+		t.Errorf("parseConvoyDeadline() = %q", got)
+	}
+	if got := parseConvoyDeadline("Merge: mr"); got != "" {
+		t.Errorf("parseConvoyDeadline() without deadline = %q, want empty", got)
+	}
+}
+
 // TestDoneCheckpointLabelFormat verifies the done-cp label format matches
 // the expected pattern: done-cp:<stage>:<value>:<unix-ts>
 func TestDoneCheckpointLabelFormat(t *testing.T) {
@@ -44,10 +44,23 @@ WORKFLOW:
 
 CONFIGURATION:
   Routing is configured in ~/gt/settings/escalation.json:
-  - routes: Map severity to action lists (bead, mail:mayor, email:human, sms:human)
-  - contacts: Human email/SMS for external notifications
+  - routes: Map severity to action lists (bead, mail:mayor, email:human,
+    sms:human, slack, webhook:<name>, log)
+  - contacts: Human email/SMS and Slack webhook for external notifications
+  - oncall: Rotations and overrides that email:human and sms:human page
+    (see gt escalate oncall)
+  - email, sms, webhooks: SMTP server, SMS provider, and named JSON webhooks
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
+  - dedup_window: How long repeats and related escalations are folded into
+    an open one (default: 1h)
+
+DEDUPLICATION:
+  Escalations are fingerprinted by reason, related bead, and source rig.
+  A repeat of an open escalation is counted as an occurrence on it instead
+  of creating a new bead. Related escalations from several rigs are rolled
+  up into an incident bead (label gt:incident), which is routed once and
+  acked, closed, and re-escalated as a unit.
 
 Examples:
   gt escalate "Build failing" --severity critical --reason "CI blocked"
@@ -56,7 +69,8 @@ Examples:
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
-  gt escalate stale                         # Re-escalate stale escalations`,
+  gt escalate stale                         # Re-escalate stale escalations
+  gt escalate oncall                        # Who is on call now and next`,
 }
 
 var escalateListCmd = &cobra.Command{
@@ -111,7 +125,13 @@ When run without --dry-run, this command:
 1. Finds escalations older than the stale threshold (default: 4h)
 2. Bumps their severity: low→medium→high→critical
 3. Re-routes them according to the new severity level
-4. Sends mail to the new routing targets
+4. Sends mail and external notifications to the new routing targets
+
+It also retries failed external deliveries (email, SMS, Slack, webhooks) on
+open, unacknowledged escalations, up to 5 attempts each.
+
+Escalations grouped into an incident are re-escalated once, through the
+incident, rather than one by one.
 
 Respects max_reescalations from config (default: 2) to prevent infinite escalation.
 
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// escalationGrouping is what gt escalate did with a new escalation after
+// checking it against the open ones.
+type escalationGrouping struct {
+	// ID is the new escalation, or the open one a repeat was folded into.
+	ID string
+
+	// Duplicate is set when the escalation was a repeat, recorded as an
+	// occurrence of ID instead of a new bead.
+	Duplicate   bool
+	Occurrences int
+
+	// Incident is the incident the escalation belongs to, if any.
+	Incident    *escalation.Open
+	NewIncident bool
+
+	// Route is the escalation or incident to mail and notify about, or
+	// nil when nothing needs new attention (a repeat or another member of
+	// an open incident, at no higher severity).
+	Route *escalation.Open
+}
+
+// recordEscalation records a new escalation titled title: as an occurrence
+// of an open duplicate, or as a new bead, grouped into an incident with
+// related ones. Matching against the open escalations and recording hold a
+// town-wide lock, so concurrent repeats fold into one escalation instead of
+// each finding none open and creating its own.
+func recordEscalation(townRoot string, bd *beads.Beads, cfg *config.EscalationConfig, title string, fields *beads.EscalationFields, now time.Time) (*escalationGrouping, error) {
+	lockDir := filepath.Join(townRoot, ".runtime", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(lockDir, "escalate.lock"))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring escalation lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	match := matchOpenEscalations(bd, cfg, fields.Fingerprint, fields.IncidentKey, now)
+	if match.Duplicate != nil {
+		return addOccurrence(bd, match.Duplicate, fields.Severity, now)
+	}
+
+	issue, err := bd.CreateEscalationBead(title, fields)
+	if err != nil {
+		return nil, fmt.Errorf("creating escalation bead: %w", err)
+	}
+	if issue.Title == "" {
+		issue.Title = title
+	}
+	return groupEscalation(bd, match, issue, fields, now)
+}
+
+// matchOpenEscalations returns how a new escalation relates to the open
+// ones, or an empty match if dedup is disabled or they can't be listed.
+func matchOpenEscalations(bd *beads.Beads, cfg *config.EscalationConfig, fingerprint, incidentKey string, now time.Time) *escalation.Match {
+	window := cfg.GetDedupWindow()
+	if window <= 0 {
+		return &escalation.Match{}
+	}
+	open, err := bd.ListEscalations()
+	if err != nil {
+		style.PrintWarning("could not check for duplicate escalations: %v", err)
+		return &escalation.Match{}
+	}
+	return escalation.MatchOpen(open, fingerprint, incidentKey, now.Add(-window))
+}
+
+// addOccurrence folds a repeat at severity into the open escalation dup,
+// and into its incident if it has one. The repeat is only routed if it
+// raised the severity.
+func addOccurrence(bd *beads.Beads, dup *escalation.Open, severity string, now time.Time) (*escalationGrouping, error) {
+	raised := false
+	fields, err := bd.UpdateEscalationFields(dup.Issue.ID, func(f *beads.EscalationFields) {
+		f.Occurrences = f.OccurrenceCount() + 1
+		f.LastSeenAt = now.Format(time.RFC3339)
+		if escalation.SeverityRank(severity) > escalation.SeverityRank(f.Severity) {
+			f.Severity = severity
+			raised = true
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recording occurrence on %s: %w", dup.Issue.ID, err)
+	}
+	g := &escalationGrouping{ID: dup.Issue.ID, Duplicate: true, Occurrences: fields.Occurrences}
+
+	if fields.Incident == "" {
+		if raised {
+			g.Route = &escalation.Open{Issue: dup.Issue, Fields: fields}
+		}
+		return g, nil
+	}
+
+	incident, _, err := bd.GetEscalationBead(fields.Incident)
+	if err != nil || incident == nil {
+		return g, nil // Incident gone; the occurrence is still recorded
+	}
+	incidentRaised := false
+	incidentFields, err := bd.UpdateEscalationFields(incident.ID, func(f *beads.EscalationFields) {
+		f.Occurrences = f.OccurrenceCount() + 1
+		f.LastSeenAt = now.Format(time.RFC3339)
+		if escalation.SeverityRank(severity) > escalation.SeverityRank(f.Severity) {
+			f.Severity = severity
+			incidentRaised = true
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating incident %s: %w", incident.ID, err)
+	}
+	g.Incident = &escalation.Open{Issue: incident, Fields: incidentFields}
+	if incidentRaised {
+		g.Route = g.Incident
+	}
+	return g, nil
+}
+
+// groupEscalation rolls a newly created escalation into an open incident
+// with the same incident key, or creates one if related escalations from
+// other rigs are open. Members of an incident aren't routed individually;
+// the incident is routed when it's created or its severity goes up.
+func groupEscalation(bd *beads.Beads, match *escalation.Match, issue *beads.Issue, fields *beads.EscalationFields, now time.Time) (*escalationGrouping, error) {
+	created := &escalation.Open{Issue: issue, Fields: fields}
+	g := &escalationGrouping{ID: issue.ID, Occurrences: 1, Route: created}
+
+	switch {
+	case match.Incident != nil:
+		raised := false
+		incidentFields, err := bd.UpdateEscalationFields(match.Incident.Issue.ID, func(f *beads.EscalationFields) {
+			for _, member := range append(slices.Clone(match.Related), created) {
+				addIncidentMember(f, member)
+			}
+			f.LastSeenAt = now.Format(time.RFC3339)
+			if escalation.SeverityRank(fields.Severity) > escalation.SeverityRank(f.Severity) {
+				f.Severity = fields.Severity
+				raised = true
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("adding %s to incident %s: %w", issue.ID, match.Incident.Issue.ID, err)
+		}
+		g.Incident = &escalation.Open{Issue: match.Incident.Issue, Fields: incidentFields}
+		g.Route = nil
+		if raised {
+			g.Route = g.Incident
+		}
+
+	case len(match.Related) > 0:
+		members := append(slices.Clone(match.Related), created)
+		incidentFields := newIncidentFields(members, now)
+		// Not "Incident: ...": the title is the first line of the
+		// description, and would parse as an incident field.
+		title := "[incident] " + issue.Title
+		incident, err := bd.CreateIncidentBead(title, incidentFields)
+		if err != nil {
+			return nil, fmt.Errorf("creating incident: %w", err)
+		}
+		if incident.Title == "" {
+			incident.Title = title
+		}
+		g.Incident = &escalation.Open{Issue: incident, Fields: incidentFields}
+		g.NewIncident = true
+		g.Route = g.Incident
+
+	default:
+		return g, nil
+	}
+
+	// Point the members at their incident
+	for _, member := range append(slices.Clone(match.Related), created) {
+		if member.Fields.Incident == g.Incident.Issue.ID {
+			continue
+		}
+		if _, err := bd.UpdateEscalationFields(member.Issue.ID, func(f *beads.EscalationFields) {
+			f.Incident = g.Incident.Issue.ID
+		}); err != nil {
+			style.PrintWarning("could not link %s to incident %s: %v", member.Issue.ID, g.Incident.Issue.ID, err)
+		}
+	}
+	return g, nil
+}
+
+// newIncidentFields returns the fields of an incident grouping members.
+func newIncidentFields(members []*escalation.Open, now time.Time) *beads.EscalationFields {
+	first := members[0].Fields
+	f := &beads.EscalationFields{
+		Severity:    first.Severity,
+		Reason:      first.Reason,
+		Source:      "incident",
+		EscalatedBy: "gt",
+		EscalatedAt: first.EscalatedAt,
+		RelatedBead: first.RelatedBead,
+		IncidentKey: first.IncidentKey,
+		LastSeenAt:  now.Format(time.RFC3339),
+	}
+	for _, m := range members {
+		addIncidentMember(f, m)
+		f.Severity = escalation.HigherSeverity(f.Severity, m.Fields.Severity)
+		if m.Fields.EscalatedAt < f.EscalatedAt {
+			f.EscalatedAt = m.Fields.EscalatedAt // first seen
+		}
+	}
+	return f
+}
+
+// addIncidentMember adds member to an incident's members, rigs, and
+// occurrence count.
+func addIncidentMember(f *beads.EscalationFields, member *escalation.Open) {
+	if slices.Contains(f.Members, member.Issue.ID) {
+		return
+	}
+	f.Members = append(f.Members, member.Issue.ID)
+	if rig := escalation.RigFromAddress(member.Fields.EscalatedBy); rig != "" && !slices.Contains(f.Rigs, rig) {
+		f.Rigs = append(f.Rigs, rig)
+	}
+	f.Occurrences += member.Fields.OccurrenceCount()
+}
+
+// formatIncidentMailBody is the mail body for an incident.
+func formatIncidentMailBody(incidentID string, fields *beads.EscalationFields) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Incident ID: %s", incidentID))
+	lines = append(lines, fmt.Sprintf("Severity: %s", fields.Severity))
+	rigs := "town"
+	if len(fields.Rigs) > 0 {
+		rigs = strings.Join(fields.Rigs, ", ")
+	}
+	lines = append(lines, fmt.Sprintf("Escalations: %d (%d occurrences) from %s", len(fields.Members), fields.OccurrenceCount(), rigs))
+	lines = append(lines, fmt.Sprintf("First seen: %s", fields.EscalatedAt))
+	lines = append(lines, fmt.Sprintf("Last seen: %s", fields.LastSeenAt))
+	if fields.RelatedBead != "" {
+		lines = append(lines, fmt.Sprintf("Related: %s", fields.RelatedBead))
+	}
+	if fields.Reason != "" {
+		lines = append(lines, "")
+		lines = append(lines, "Reason:")
+		lines = append(lines, fields.Reason)
+	}
+	lines = append(lines, "")
+	lines = append(lines, "Members: "+strings.Join(fields.Members, ", "))
+	lines = append(lines, "")
+	lines = append(lines, "Related escalations from several agents were grouped into this incident;")
+	lines = append(lines, "further repeats are counted here instead of mailed.")
+	lines = append(lines, "")
+	lines = append(lines, "---")
+	lines = append(lines, "To acknowledge all: gt escalate ack "+incidentID)
+	lines = append(lines, "To close all: gt escalate close "+incidentID+" --reason \"resolution\"")
+	return strings.Join(lines, "\n")
+}
+
+// incidentMembers returns the open members of an incident bead, for
+// propagating ack and close. Returns nil for ordinary escalations.
+func incidentMembers(bd *beads.Beads, id string) []string {
+	issue, fields, err := bd.GetEscalationBead(id)
+	if err != nil || issue == nil || !beads.HasLabel(issue, beads.IncidentLabel) {
+		return nil
+	}
+	var open []string
+	for _, member := range fields.Members {
+		if m, _, err := bd.GetEscalationBead(member); err == nil && m != nil && m.Status != "closed" {
+			open = append(open, member)
+		}
+	}
+	return open
+}
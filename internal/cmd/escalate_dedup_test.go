@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
+)
+
+func TestNewIncidentFields(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	members := []*escalation.Open{
+		{Issue: &beads.Issue{ID: "hq-a"}, Fields: &beads.EscalationFields{
+			Severity: "medium", EscalatedBy: "gastown/witness", EscalatedAt: "2026-03-01T11:50:00Z", Occurrences: 3, IncidentKey: "k1",
+		}},
+		{Issue: &beads.Issue{ID: "hq-b"}, Fields: &beads.EscalationFields{
+			Severity: "high", EscalatedBy: "beads/polecats/nux", EscalatedAt: "2026-03-01T11:40:00Z",
+		}},
+		{Issue: &beads.Issue{ID: "hq-c"}, Fields: &beads.EscalationFields{
+			Severity: "low", EscalatedBy: "gastown/refinery", EscalatedAt: "2026-03-01T11:55:00Z", Occurrences: 1,
+		}},
+	}
+
+	f := newIncidentFields(members, now)
+	if f.Severity != "high" {
+		t.Errorf("Severity = %s, want highest member severity", f.Severity)
+	}
+	if f.EscalatedAt != "2026-03-01T11:40:00Z" {
+		t.Errorf("first seen = %s, want earliest member", f.EscalatedAt)
+	}
+	if strings.Join(f.Members, ",") != "hq-a,hq-b,hq-c" || strings.Join(f.Rigs, ",") != "gastown,beads" {
+		t.Errorf("Members = %v, Rigs = %v", f.Members, f.Rigs)
+	}
+	if f.Occurrences != 5 {
+		t.Errorf("Occurrences = %d, want 5", f.Occurrences)
+	}
+	if f.IncidentKey != "k1" || f.LastSeenAt != "2026-03-01T12:00:00Z" {
+		t.Errorf("IncidentKey = %s, LastSeenAt = %s", f.IncidentKey, f.LastSeenAt)
+	}
+
+	// Adding a member twice doesn't double count
+	addIncidentMember(f, members[0])
+	if len(f.Members) != 3 || f.Occurrences != 5 {
+		t.Errorf("re-adding a member changed the incident: %v, %d", f.Members, f.Occurrences)
+	}
+
+	body := formatIncidentMailBody("hq-inc", f)
+	for _, want := range []string{"Incident ID: hq-inc", "Escalations: 3 (5 occurrences) from gastown, beads", "gt escalate ack hq-inc"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("mail body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestStaleByIncident(t *testing.T) {
+	stale := []*beads.Issue{
+		{ID: "hq-a", Description: "Dolt down\n\nseverity: high\nincident: hq-inc"},
+		{ID: "hq-b", Description: "Tests failing\n\nseverity: medium"},
+		{ID: "hq-inc", Description: "[incident] Dolt down\n\nseverity: high\nmembers: hq-a", Labels: []string{beads.IncidentLabel}},
+	}
+	var ids []string
+	for _, issue := range staleByIncident(stale) {
+		ids = append(ids, issue.ID)
+	}
+	if strings.Join(ids, ",") != "hq-b,hq-inc" {
+		t.Errorf("staleByIncident = %v, want [hq-b hq-inc]", ids)
+	}
+}
+
+func TestRecordEscalation_ConcurrentRepeatsCreateOne(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	newFields := func() *beads.EscalationFields {
+		return &beads.EscalationFields{
+			Severity:    "high",
+			EscalatedBy: "gastown/witness",
+			EscalatedAt: now.Format(time.RFC3339),
+			Fingerprint: escalation.Fingerprint("Dolt down", "", "gastown"),
+			IncidentKey: escalation.IncidentKey("Dolt down", ""),
+			Occurrences: 1,
+			LastSeenAt:  now.Format(time.RFC3339),
+		}
+	}
+
+	// Fake bd: nothing is open until a create (slow, to widen any race)
+	// opens the escalation, which show then returns for occurrences.
+	binDir := t.TempDir()
+	created, err := json.Marshal([]*beads.Issue{{
+		ID: "hq-esc", Title: "Dolt down", Status: "open", Labels: []string{"gt:escalation"},
+		Description: beads.FormatEscalationDescription("Dolt down", newFields()),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "open.json"), created, 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := `#!/bin/sh
+dir="` + binDir + `"
+cmd=""
+for arg in "$@"; do
+  case "$arg" in
+    --*) ;;
+    *) cmd="$arg"; break ;;
+  esac
+done
+case "$cmd" in
+  list) if [ -f "$dir/list.json" ]; then cat "$dir/list.json"; else echo '[]'; fi ;;
+  create) sleep 0.3; echo create >> "$dir/calls"; cp "$dir/open.json" "$dir/list.json"; echo '{"id":"hq-esc","title":"Dolt down"}' ;;
+  show) cat "$dir/open.json" ;;
+  update) echo update >> "$dir/calls" ;;
+  *) echo '[]' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	bd := beads.New(filepath.Join(townRoot, ".beads"))
+	cfg := config.NewEscalationConfig()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := recordEscalation(townRoot, bd, cfg, "Dolt down", newFields(), now); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	calls, _ := os.ReadFile(filepath.Join(binDir, "calls"))
+	if got := strings.Fields(string(calls)); strings.Count(string(calls), "create") != 1 || len(got) != 4 {
+		t.Errorf("bd calls = %v, want one create and three occurrence updates", got)
+	}
+}
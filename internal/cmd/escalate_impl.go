@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -61,8 +62,16 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		agentID = "unknown"
 	}
 
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	now := time.Now()
+
+	// Fingerprint the escalation to fold repeats and group related ones
+	fingerprint := escalation.Fingerprint(description, escalateRelatedBead, escalation.RigFromAddress(agentID))
+	incidentKey := escalation.IncidentKey(description, escalateRelatedBead)
+
 	// Dry run mode
 	if escalateDryRun {
+		match := matchOpenEscalations(bd, escalationConfig, fingerprint, incidentKey, now)
 		actions := escalationConfig.GetRouteForSeverity(severity)
 		targets := extractMailTargetsFromActions(actions)
 		fmt.Printf("Would create escalation:\n")
@@ -76,96 +85,157 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Actions: %s\n", strings.Join(actions, ", "))
 		fmt.Printf("  Mail targets: %s\n", strings.Join(targets, ", "))
+		switch {
+		case match.Duplicate != nil:
+			fmt.Printf("  Repeat of: %s (would record occurrence %d instead)\n",
+				match.Duplicate.Issue.ID, match.Duplicate.Fields.OccurrenceCount()+1)
+		case match.Incident != nil:
+			fmt.Printf("  Incident: would join %s\n", match.Incident.Issue.ID)
+		case len(match.Related) > 0:
+			fmt.Printf("  Incident: would group with %d related escalation(s)\n", len(match.Related))
+		}
 		return nil
 	}
 
-	// Create escalation bead
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	// Count a repeat of an open escalation there, or create the escalation
 	fields := &beads.EscalationFields{
 		Severity:    severity,
 		Reason:      escalateReason,
 		Source:      escalateSource,
 		EscalatedBy: agentID,
-		EscalatedAt: time.Now().Format(time.RFC3339),
+		EscalatedAt: now.Format(time.RFC3339),
 		RelatedBead: escalateRelatedBead,
+		Fingerprint: fingerprint,
+		IncidentKey: incidentKey,
+		Occurrences: 1,
+		LastSeenAt:  now.Format(time.RFC3339),
 	}
-
-	issue, err := bd.CreateEscalationBead(description, fields)
+	grouping, err := recordEscalation(townRoot, bd, escalationConfig, description, fields, now)
 	if err != nil {
-		return fmt.Errorf("creating escalation bead: %w", err)
+		return err
 	}
 
-	// Get routing actions for this severity
-	actions := escalationConfig.GetRouteForSeverity(severity)
-	targets := extractMailTargetsFromActions(actions)
-
-	// Send mail to each target (actions with "mail:" prefix)
-	router := mail.NewRouter(townRoot)
-	defer router.WaitPendingNotifications()
-	for _, target := range targets {
-		msg := &mail.Message{
-			From:    agentID,
-			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
-			Type:    mail.TypeTask,
-		}
-
-		// Set priority based on severity
-		switch severity {
-		case config.SeverityCritical:
-			msg.Priority = mail.PriorityUrgent
-		case config.SeverityHigh:
-			msg.Priority = mail.PriorityHigh
-		case config.SeverityMedium:
-			msg.Priority = mail.PriorityNormal
-		default:
-			msg.Priority = mail.PriorityLow
-		}
+	// Route the escalation, or the incident it rolled up into
+	var actions, targets []string
+	var deliveries []beads.EscalationDelivery
+	if route := grouping.Route; route != nil {
+		actions = escalationConfig.GetRouteForSeverity(route.Fields.Severity)
+		targets = extractMailTargetsFromActions(actions)
+		sendEscalationMail(townRoot, agentID, targets, route)
 
-		if err := router.Send(msg); err != nil {
-			style.PrintWarning("failed to send to %s: %v", target, err)
-		}
+		// Deliver external notification actions (email:, sms:, slack, webhook:, log)
+		deliveries = executeExternalActions(townRoot, actions, escalationConfig, escalation.FromBead(route.Issue, route.Fields), !escalateJSON)
+		recordDeliveries(bd, route.Issue.ID, deliveries)
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
-
 	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
+	payload := events.EscalationPayload(grouping.ID, agentID, strings.Join(targets, ","), description)
 	payload["severity"] = severity
 	payload["actions"] = strings.Join(actions, ",")
 	if escalateSource != "" {
 		payload["source"] = escalateSource
 	}
+	if grouping.Duplicate {
+		payload["occurrences"] = grouping.Occurrences
+	}
+	if grouping.Incident != nil {
+		payload["incident"] = grouping.Incident.Issue.ID
+	}
 	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
 
 	// Output
 	if escalateJSON {
 		result := map[string]interface{}{
-			"id":       issue.ID,
+			"id":       grouping.ID,
 			"severity": severity,
 			"actions":  actions,
 			"targets":  targets,
+			"routed":   grouping.Route != nil,
+		}
+		if len(deliveries) > 0 {
+			result["deliveries"] = deliveries
 		}
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if grouping.Duplicate {
+			result["duplicate"] = true
+			result["occurrences"] = grouping.Occurrences
+		}
+		if grouping.Incident != nil {
+			result["incident"] = grouping.Incident.Issue.ID
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
 		emoji := severityEmoji(severity)
-		fmt.Printf("%s Escalation created: %s\n", emoji, issue.ID)
+		if grouping.Duplicate {
+			fmt.Printf("%s Repeat of escalation %s (occurrence %d)\n", emoji, grouping.ID, grouping.Occurrences)
+		} else {
+			fmt.Printf("%s Escalation created: %s\n", emoji, grouping.ID)
+		}
 		fmt.Printf("  Severity: %s\n", severity)
 		if escalateSource != "" {
 			fmt.Printf("  Source: %s\n", escalateSource)
 		}
-		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+		if incident := grouping.Incident; incident != nil {
+			verb := "Part of"
+			if grouping.NewIncident {
+				verb = "Grouped into new"
+			}
+			fmt.Printf("  %s incident %s (%d escalations, %d occurrences)\n",
+				verb, incident.Issue.ID, len(incident.Fields.Members), incident.Fields.OccurrenceCount())
+		}
+		if grouping.Route != nil {
+			fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+		} else {
+			fmt.Printf("  Not routed: already open and not more severe\n")
+		}
 	}
 
 	return nil
 }
 
+// sendEscalationMail mails an escalation or incident to each target.
+func sendEscalationMail(townRoot, from string, targets []string, route *escalation.Open) {
+	fields := route.Fields
+	body := formatEscalationMailBody(route.Issue.ID, fields.Severity, fields.Reason, from, fields.RelatedBead)
+	if beads.HasLabel(route.Issue, beads.IncidentLabel) || len(fields.Members) > 0 {
+		body = formatIncidentMailBody(route.Issue.ID, fields)
+	} else if fields.OccurrenceCount() > 1 {
+		body += fmt.Sprintf("\n\nSeen %d times (last: %s)", fields.OccurrenceCount(), fields.LastSeenAt)
+	}
+
+	// Send mail to each target (actions with "mail:" prefix)
+	router := mail.NewRouter(townRoot)
+	defer router.WaitPendingNotifications()
+	for _, target := range targets {
+		msg := &mail.Message{
+			From:    from,
+			To:      target,
+			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(fields.Severity), route.Issue.Title),
+			Body:    body,
+			Type:    mail.TypeTask,
+		}
+
+		// Set priority based on severity
+		switch fields.Severity {
+		case config.SeverityCritical:
+			msg.Priority = mail.PriorityUrgent
+		case config.SeverityHigh:
+			msg.Priority = mail.PriorityHigh
+		case config.SeverityMedium:
+			msg.Priority = mail.PriorityNormal
+		default:
+			msg.Priority = mail.PriorityLow
+		}
+
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("failed to send to %s: %v", target, err)
+		}
+	}
+}
+
 func runEscalateList(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -218,6 +288,15 @@ func runEscalateList(cmd *cobra.Command, args []string) error {
 		if fields.AckedBy != "" {
 			fmt.Printf("     Acked by: %s\n", fields.AckedBy)
 		}
+		if beads.HasLabel(issue, beads.IncidentLabel) {
+			fmt.Printf("     Incident: %d escalations, %d occurrences from %s | Last seen: %s\n",
+				len(fields.Members), fields.OccurrenceCount(), strings.Join(fields.Rigs, ", "), formatRelativeTime(fields.LastSeenAt))
+		} else if fields.Incident != "" {
+			fmt.Printf("     Part of incident %s\n", fields.Incident)
+		}
+		if fields.OccurrenceCount() > 1 && !beads.HasLabel(issue, beads.IncidentLabel) {
+			fmt.Printf("     Seen %d times | Last seen: %s\n", fields.OccurrenceCount(), formatRelativeTime(fields.LastSeenAt))
+		}
 		fmt.Println()
 	}
 
@@ -243,6 +322,14 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("acknowledging escalation: %w", err)
 	}
 
+	// Acknowledging an incident acknowledges its members
+	members := incidentMembers(bd, escalationID)
+	for _, member := range members {
+		if err := bd.AckEscalation(member, ackedBy); err != nil {
+			style.PrintWarning("could not acknowledge incident member %s: %v", member, err)
+		}
+	}
+
 	// Log to activity feed
 	_ = events.LogFeed(events.TypeEscalationAcked, ackedBy, map[string]interface{}{
 		"escalation_id": escalationID,
@@ -250,6 +337,9 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 	})
 
 	fmt.Printf("%s Escalation acknowledged: %s\n", style.Bold.Render("✓"), escalationID)
+	if len(members) > 0 {
+		fmt.Printf("  Also acknowledged %d incident member(s)\n", len(members))
+	}
 	return nil
 }
 
@@ -268,10 +358,19 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 	}
 
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	members := incidentMembers(bd, escalationID)
 	if err := bd.CloseEscalation(escalationID, closedBy, escalateCloseReason); err != nil {
 		return fmt.Errorf("closing escalation: %w", err)
 	}
 
+	// Closing an incident closes its members
+	for _, member := range members {
+		reason := fmt.Sprintf("Closed with incident %s: %s", escalationID, escalateCloseReason)
+		if err := bd.CloseEscalation(member, closedBy, reason); err != nil {
+			style.PrintWarning("could not close incident member %s: %v", member, err)
+		}
+	}
+
 	// Log to activity feed
 	_ = events.LogFeed(events.TypeEscalationClosed, closedBy, map[string]interface{}{
 		"escalation_id": escalationID,
@@ -281,6 +380,9 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Escalation closed: %s\n", style.Bold.Render("✓"), escalationID)
 	fmt.Printf("  Reason: %s\n", escalateCloseReason)
+	if len(members) > 0 {
+		fmt.Printf("  Also closed %d incident member(s)\n", len(members))
+	}
 	return nil
 }
 
@@ -300,10 +402,15 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 	maxReescalations := escalationConfig.GetMaxReescalations()
 
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+
+	// Retry external deliveries that failed, whether or not the escalation is stale
+	retryFailedDeliveries(bd, townRoot, escalationConfig, escalateDryRun, !escalateStaleJSON)
+
 	stale, err := bd.ListStaleEscalations(threshold)
 	if err != nil {
 		return fmt.Errorf("listing stale escalations: %w", err)
 	}
+	stale = staleByIncident(stale)
 
 	if len(stale) == 0 {
 		if !escalateStaleJSON {
@@ -373,7 +480,7 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 					From:    reescalatedBy,
 					To:      target,
 					Subject: fmt.Sprintf("[%s→%s] Re-escalated: %s", strings.ToUpper(result.OldSeverity), strings.ToUpper(result.NewSeverity), result.Title),
-					Body:    formatReescalationMailBody(result, reescalatedBy),
+					Body:    formatReescalationMailBody(result, reescalatedBy, issue),
 					Type:    mail.TypeTask,
 				}
 
@@ -394,6 +501,12 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Deliver external notifications for the new severity
+			if issue, fields, err := bd.GetEscalationBead(result.ID); err == nil && issue != nil {
+				esc := escalation.FromBead(issue, fields)
+				recordDeliveries(bd, issue.ID, executeExternalActions(townRoot, actions, escalationConfig, esc, !escalateStaleJSON))
+			}
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -445,6 +558,19 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// staleByIncident drops stale escalations that belong to an incident, so
+// each incident is re-escalated once, as a unit, rather than per member.
+func staleByIncident(stale []*beads.Issue) []*beads.Issue {
+	var result []*beads.Issue
+	for _, issue := range stale {
+		if beads.ParseEscalationFields(issue.Description).Incident != "" {
+			continue
+		}
+		result = append(result, issue)
+	}
+	return result
+}
+
 func getNextSeverity(severity string) string {
 	switch severity {
 	case "low":
@@ -458,12 +584,21 @@ func getNextSeverity(severity string) string {
 	}
 }
 
-func formatReescalationMailBody(result *beads.ReescalationResult, reescalatedBy string) string {
+func formatReescalationMailBody(result *beads.ReescalationResult, reescalatedBy string, issue *beads.Issue) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Escalation ID: %s", result.ID))
 	lines = append(lines, fmt.Sprintf("Severity bumped: %s → %s", result.OldSeverity, result.NewSeverity))
 	lines = append(lines, fmt.Sprintf("Reescalation #%d", result.ReescalationNum))
 	lines = append(lines, fmt.Sprintf("Reescalated by: %s", reescalatedBy))
+	if issue != nil && beads.HasLabel(issue, beads.IncidentLabel) {
+		fields := beads.ParseEscalationFields(issue.Description)
+		lines = append(lines, fmt.Sprintf("Incident: %d escalations (%d occurrences), last seen %s",
+			len(fields.Members), fields.OccurrenceCount(), fields.LastSeenAt))
+	} else if issue != nil {
+		if fields := beads.ParseEscalationFields(issue.Description); fields.OccurrenceCount() > 1 {
+			lines = append(lines, fmt.Sprintf("Seen %d times (last: %s)", fields.OccurrenceCount(), fields.LastSeenAt))
+		}
+	}
 	lines = append(lines, "")
 	lines = append(lines, "This escalation was not acknowledged within the stale threshold and has been automatically re-escalated to a higher severity.")
 	lines = append(lines, "")
@@ -506,6 +641,20 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
 		}
+		if len(fields.Deliveries) > 0 {
+			data["deliveries"] = fields.Deliveries
+		}
+		if fields.Occurrences > 0 {
+			data["occurrences"] = fields.Occurrences
+			data["lastSeenAt"] = fields.LastSeenAt
+		}
+		if fields.Incident != "" {
+			data["incident"] = fields.Incident
+		}
+		if len(fields.Members) > 0 {
+			data["members"] = fields.Members
+			data["rigs"] = fields.Rigs
+		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
 		return nil
@@ -531,6 +680,26 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if fields.OccurrenceCount() > 1 {
+		fmt.Printf("  Occurrences: %d (first %s, last %s)\n", fields.OccurrenceCount(), fields.EscalatedAt, fields.LastSeenAt)
+	}
+	if fields.Incident != "" {
+		fmt.Printf("  Incident: %s\n", fields.Incident)
+	}
+	if len(fields.Members) > 0 {
+		fmt.Printf("  Members: %s\n", strings.Join(fields.Members, ", "))
+		fmt.Printf("  Rigs: %s\n", strings.Join(fields.Rigs, ", "))
+	}
+	if len(fields.Deliveries) > 0 {
+		fmt.Printf("  Deliveries:\n")
+		for _, d := range fields.Deliveries {
+			line := fmt.Sprintf("    %s %s: %s (attempt %d, %s)", deliveryEmoji(d.Action), d.Action, d.Status, d.Attempts, d.At)
+			if d.Error != "" {
+				line += " - " + d.Error
+			}
+			fmt.Println(line)
+		}
+	}
 
 	return nil
 }
@@ -553,43 +722,93 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
+// maxDeliveryAttempts caps how many times gt escalate stale retries a
+// failed external delivery.
+const maxDeliveryAttempts = 5
 
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
+// executeExternalActions delivers the external notification actions
+// (email:, sms:, slack, webhook:, log) and returns one result per action.
+func executeExternalActions(townRoot string, actions []string, cfg *config.EscalationConfig, esc *escalation.Escalation, verbose bool) []beads.EscalationDelivery {
+	deliveries := escalation.NewNotifier(townRoot, cfg).DeliverAll(actions, esc)
+	if verbose {
+		printDeliveries(deliveries)
+	}
+	return deliveries
+}
 
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
+// recordDeliveries stores delivery results on the escalation bead, where
+// gt escalate stale finds failures to retry.
+func recordDeliveries(bd *beads.Beads, id string, deliveries []beads.EscalationDelivery) {
+	if err := bd.RecordEscalationDeliveries(id, deliveries); err != nil {
+		style.PrintWarning("could not record deliveries on %s: %v", id, err)
+	}
+}
+
+// retryFailedDeliveries redelivers failed external notifications for open,
+// unacknowledged escalations, up to maxDeliveryAttempts each.
+func retryFailedDeliveries(bd *beads.Beads, townRoot string, cfg *config.EscalationConfig, dryRun, verbose bool) {
+	open, err := bd.ListEscalations()
+	if err != nil {
+		style.PrintWarning("listing escalations for delivery retry: %v", err)
+		return
+	}
+	notifier := escalation.NewNotifier(townRoot, cfg)
+	for _, issue := range open {
+		if beads.HasLabel(issue, "acked") {
+			continue
+		}
+		fields := beads.ParseEscalationFields(issue.Description)
+		var retried []beads.EscalationDelivery
+		for _, failed := range fields.FailedDeliveries() {
+			if failed.Attempts >= maxDeliveryAttempts {
+				continue
 			}
+			if dryRun {
+				fmt.Printf("Would retry %s for %s (attempt %d/%d)\n", failed.Action, issue.ID, failed.Attempts+1, maxDeliveryAttempts)
+				continue
+			}
+			retried = append(retried, notifier.Deliver(failed.Action, escalation.FromBead(issue, fields), failed.Attempts))
+		}
+		if len(retried) == 0 {
+			continue
+		}
+		recordDeliveries(bd, issue.ID, retried)
+		if verbose {
+			fmt.Printf("Retried deliveries for %s:\n", issue.ID)
+			printDeliveries(retried)
+		}
+	}
+}
 
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
+// printDeliveries prints one line per external delivery result.
+func printDeliveries(deliveries []beads.EscalationDelivery) {
+	for _, d := range deliveries {
+		switch d.Status {
+		case beads.DeliverySent:
+			fmt.Printf("  %s Delivered %s\n", deliveryEmoji(d.Action), d.Action)
+		case beads.DeliverySkipped:
+			style.PrintWarning("%s skipped: %s in settings/escalation.json", d.Action, d.Error)
+		default:
+			style.PrintWarning("%s failed (attempt %d/%d): %s", d.Action, d.Attempts, maxDeliveryAttempts, d.Error)
 		}
 	}
 }
 
+func deliveryEmoji(action string) string {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		return "📧"
+	case strings.HasPrefix(action, "sms:"):
+		return "📱"
+	case action == "slack":
+		return "💬"
+	case strings.HasPrefix(action, "webhook:"):
+		return "🔗"
+	default:
+		return "📝"
+	}
+}
+
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Escalation ID: %s", beadID))
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	escalateOnCallJSON     bool
+	escalateOnCallRig      string
+	escalateOnCallSeverity string
+)
+
+var escalateOnCallCmd = &cobra.Command{
+	Use:   "oncall",
+	Short: "Show who is on call now and next",
+	Long: `Show the current and next responder for each on-call rotation.
+
+The on-call schedule lives in the "oncall" section of settings/escalation.json:
+people with their email and SMS, weekly (or custom-length) rotations, and
+overrides for swaps and vacations. Each rotation can be limited to some rigs
+and severities; the first rotation that matches an escalation decides who
+email:human and sms:human reach. Without a matching rotation, they fall back
+to contacts.human_email and contacts.human_sms.
+
+With --rig and/or --severity, also shows who an escalation from that rig at
+that severity would page right now.
+
+Examples:
+  gt escalate oncall
+  gt escalate oncall --rig gastown --severity critical
+  gt escalate oncall --json`,
+	RunE: runEscalateOnCall,
+}
+
+func init() {
+	escalateOnCallCmd.Flags().BoolVar(&escalateOnCallJSON, "json", false, "Output as JSON")
+	escalateOnCallCmd.Flags().StringVar(&escalateOnCallRig, "rig", "", "Show who an escalation from this rig would page")
+	escalateOnCallCmd.Flags().StringVar(&escalateOnCallSeverity, "severity", "", "Show who an escalation at this severity would page")
+	escalateCmd.AddCommand(escalateOnCallCmd)
+}
+
+// OnCallRotationStatus is a rotation's current and next responder.
+type OnCallRotationStatus struct {
+	Name       string              `json:"name"`
+	Rigs       []string            `json:"rigs,omitempty"`
+	Severities []string            `json:"severities,omitempty"`
+	Current    *config.OnCallShift `json:"current,omitempty"`
+	Next       *config.OnCallShift `json:"next,omitempty"`
+}
+
+// OnCallStatus is the output of gt escalate oncall.
+type OnCallStatus struct {
+	At        time.Time               `json:"at"`
+	Rotations []*OnCallRotationStatus `json:"rotations"`
+
+	// Pages is who --rig/--severity would reach, when given.
+	Pages *OnCallPage `json:"pages,omitempty"`
+}
+
+// OnCallPage is who email:human and sms:human reach for an escalation.
+type OnCallPage struct {
+	Rig      string `json:"rig,omitempty"`
+	Severity string `json:"severity"`
+	Rotation string `json:"rotation,omitempty"` // empty when falling back to contacts
+	Person   string `json:"person,omitempty"`
+	Email    string `json:"email,omitempty"`
+	SMS      string `json:"sms,omitempty"`
+}
+
+func runEscalateOnCall(cmd *cobra.Command, args []string) error {
+	if escalateOnCallSeverity != "" && !config.IsValidSeverity(escalateOnCallSeverity) {
+		return fmt.Errorf("invalid severity '%s': must be critical, high, medium, or low", escalateOnCallSeverity)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	status := buildOnCallStatus(escalationConfig, time.Now())
+	if escalateOnCallJSON {
+		return outputJSON(status)
+	}
+	printOnCallStatus(status, escalationConfig.OnCall == nil)
+	return nil
+}
+
+// buildOnCallStatus returns each rotation's current and next responder at
+// t, and who --rig/--severity would page.
+func buildOnCallStatus(cfg *config.EscalationConfig, t time.Time) *OnCallStatus {
+	status := &OnCallStatus{At: t, Rotations: []*OnCallRotationStatus{}}
+	if schedule := cfg.OnCall; schedule != nil {
+		for _, r := range schedule.Rotations {
+			status.Rotations = append(status.Rotations, &OnCallRotationStatus{
+				Name:       r.Name,
+				Rigs:       r.Rigs,
+				Severities: r.Severities,
+				Current:    schedule.ShiftAt(r, t),
+				Next:       schedule.NextShift(r, t),
+			})
+		}
+	}
+
+	if escalateOnCallRig != "" || escalateOnCallSeverity != "" {
+		severity := escalateOnCallSeverity
+		if severity == "" {
+			severity = config.SeverityCritical
+		}
+		email, sms, shift := cfg.HumanContacts(t, escalateOnCallRig, severity)
+		page := &OnCallPage{Rig: escalateOnCallRig, Severity: severity, Email: email, SMS: sms}
+		if shift != nil {
+			page.Rotation = shift.Rotation
+			page.Person = shift.Person
+		}
+		status.Pages = page
+	}
+	return status
+}
+
+func printOnCallStatus(status *OnCallStatus, unconfigured bool) {
+	fmt.Printf("%s On call at %s\n\n", style.Bold.Render("📟"), status.At.Format("Mon Jan 2 15:04 MST"))
+	if unconfigured {
+		fmt.Printf("  %s\n", style.Dim.Render("No on-call schedule; email:human and sms:human use contacts in settings/escalation.json"))
+	}
+
+	for _, r := range status.Rotations {
+		scope := "all rigs"
+		if len(r.Rigs) > 0 {
+			scope = "rigs: " + strings.Join(r.Rigs, ", ")
+		}
+		if len(r.Severities) > 0 {
+			scope += " · severities: " + strings.Join(r.Severities, ", ")
+		}
+		fmt.Printf("  %s  %s\n", style.Bold.Render(r.Name), style.Dim.Render("["+scope+"]"))
+		if r.Current != nil {
+			fmt.Printf("    Now:  %s until %s\n", formatOnCallShift(r.Current), r.Current.End.Local().Format("Mon Jan 2 15:04"))
+		} else {
+			fmt.Printf("    Now:  %s\n", style.Dim.Render("(rotation not started)"))
+		}
+		if r.Next != nil {
+			fmt.Printf("    Next: %s from %s\n", formatOnCallShift(r.Next), r.Next.Start.Local().Format("Mon Jan 2 15:04"))
+		}
+		fmt.Println()
+	}
+
+	if page := status.Pages; page != nil {
+		who := "contacts (no matching rotation)"
+		if page.Person != "" {
+			who = fmt.Sprintf("%s via %s", page.Person, page.Rotation)
+		}
+		scope := page.Severity
+		if page.Rig != "" {
+			scope = page.Rig + " " + scope
+		}
+		fmt.Printf("  %s escalation pages %s\n", scope, style.Bold.Render(who))
+		fmt.Printf("    email: %s\n", valueOrNone(page.Email))
+		fmt.Printf("    sms:   %s\n", valueOrNone(page.SMS))
+	}
+}
+
+func formatOnCallShift(shift *config.OnCallShift) string {
+	s := style.Bold.Render(shift.Person)
+	var contacts []string
+	if shift.Email != "" {
+		contacts = append(contacts, shift.Email)
+	}
+	if shift.SMS != "" {
+		contacts = append(contacts, shift.SMS)
+	}
+	if len(contacts) > 0 {
+		s += style.Dim.Render(" (" + strings.Join(contacts, ", ") + ")")
+	}
+	if shift.Override {
+		s += " " + style.Warning.Render("[override]")
+	}
+	return s
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return style.Dim.Render("(none)")
+	}
+	return s
+}
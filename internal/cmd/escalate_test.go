@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -264,7 +267,7 @@ func TestFormatReescalationMailBody(t *testing.T) {
 		ReescalationNum: 2,
 	}
 
-	got := formatReescalationMailBody(result, "gastown/patrol")
+	got := formatReescalationMailBody(result, "gastown/patrol", nil)
 
 	wantIn := []string{
 		"Escalation ID: hq-esc123",
@@ -332,13 +335,16 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer slack.Close()
+
+	esc := &escalation.Escalation{ID: "hq-test", Title: "Test escalation", Severity: "high"}
 
 	tests := []struct {
 		name    string
 		actions []string
 		cfg     *config.EscalationConfig
+		want    []string // action=status per external action
 	}{
 		{
 			name:    "no external actions",
@@ -349,59 +355,43 @@ func TestExecuteExternalActions(t *testing.T) {
 			name:    "email action without contact",
 			actions: []string{"email:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    []string{"email:human=skipped"},
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp server",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail: "test@example.com",
 				},
 			},
+			want: []string{"email:human=skipped"},
 		},
 		{
-			name:    "sms action without contact",
-			actions: []string{"sms:human"},
-			cfg:     &config.EscalationConfig{},
-		},
-		{
-			name:    "sms action with contact",
+			name:    "sms action without provider",
 			actions: []string{"sms:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanSMS: "+15551234567",
 				},
 			},
+			want: []string{"sms:human=skipped"},
 		},
 		{
 			name:    "slack action without webhook",
 			actions: []string{"slack"},
 			cfg:     &config.EscalationConfig{},
-		},
-		{
-			name:    "slack action with webhook",
-			actions: []string{"slack"},
-			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
-				},
-			},
-		},
-		{
-			name:    "log action",
-			actions: []string{"log"},
-			cfg:     &config.EscalationConfig{},
+			want:    []string{"slack=skipped"},
 		},
 		{
 			name:    "all external actions combined",
-			actions: []string{"email:human", "sms:human", "slack", "log"},
+			actions: []string{"bead", "email:human", "slack", "log"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
-					HumanEmail:   "test@example.com",
-					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want: []string{"email:human=skipped", "slack=sent", "log=sent"},
 		},
 		{
 			name:    "empty actions",
@@ -412,8 +402,13 @@ func TestExecuteExternalActions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			var got []string
+			for _, d := range executeExternalActions(t.TempDir(), tt.actions, tt.cfg, esc, false) {
+				got = append(got, d.Action+"="+d.Status)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("deliveries = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -19,6 +19,11 @@ var (
 	mailNoNotify      bool // Suppress auto-nudge notification to recipient
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailSendAt        string   // Deliver at this time (scheduled mail)
+	mailSendAfter     string   // Deliver after this long (scheduled mail)
+	mailSendOnClose   string   // Deliver when this bead closes (scheduled mail)
+	mailExpectReply   string   // Expect a reply within this long
+	mailEscalateSev   string   // Severity to escalate an unanswered --expect-reply at
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -38,6 +43,8 @@ var (
 	mailSearchBody    bool
 	mailSearchArchive bool
 	mailSearchJSON    bool
+	mailSearchReindex bool
+	mailSearchLimit   int
 
 	// Announces flags
 	mailAnnouncesJSON bool
@@ -123,6 +130,26 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Scheduled delivery:
+  --at <time>        Deliver at a time: RFC3339, "2006-01-02 15:04", or "15:04"
+                     (the next occurrence)
+  --after <dur>      Deliver after a delay (90m, 4h, 2d)
+  --on-close <bead>  Deliver when a bead closes
+
+Scheduled mail is held until the daemon heartbeat releases it. Recipients are
+resolved and checked when you send. See and cancel pending deliveries with
+'gt mail scheduled'.
+
+Expecting a reply:
+  --expect-reply <dur>       Expect a reply in the thread within this long
+                             (90m, 4h, 2d); implies --type task
+  --escalate-severity <sev>  Severity to escalate at if no reply comes
+                             (default: reply_severity in the escalation config)
+
+If no reply arrives in time, the daemon re-sends the message as a reminder;
+if the reminder also goes unanswered within the window, it escalates with
+'gt escalate'. List outstanding questions with 'gt mail awaiting'.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -133,6 +160,10 @@ Examples:
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send --self -s "Re-check flaky test" -m "TestFoo again" --after 1d
+  gt mail send mayor/ -s "Deploy window" -m "Ship it" --at "2026-12-01 09:00"
+  gt mail send greenplace/Toast -s "Unblocked" -m "gt-abc landed" --on-close gt-abc
+  gt mail send gastown/refinery -s "Safe to merge?" -m "gt-abc touches the CI config" --expect-reply 2h
 
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
@@ -392,28 +423,51 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search your mail through the town's mail search index.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+Words and "quoted phrases" match the subject or body, case-insensitively;
+a word ending in * matches as a prefix (merg*). Every term must match.
+Narrow the search with field:value terms:
+
+  from:<text>        Sender contains text (from:witness)
+  to:<text>          Recipient or CC contains text
+  subject:<text>     Subject contains the word or "phrase"
+  body:<text>        Body contains the word or "phrase"
+  thread:<id>        In this thread
+  label:<label>      Has this label
+  type:<type>        task, scavenge, notification, reply
+  priority:<level>   low, normal, high, urgent
+  after:<when>       Newer than a date (2006-01-02) or duration ago (4h, 2d, 1w)
+  before:<when>      Older than a date or duration ago
+  is:<state>         unread, read, inbox, archived
+
+Results are ranked by relevance (subject matches count most), newest first
+among equals, with a highlighted snippet of the match.
+
+The index is kept up to date as mail is sent and archived; inbox mail it
+hasn't seen is indexed when you search. --reindex rebuilds your entries
+from your inbox and archive.
 
 FLAGS:
-  --from <sender>   Filter by sender address (substring match)
-  --subject         Only search subject lines
-  --body            Only search message body
-  --archive         Include archived (closed) messages
+  --from <sender>   Filter by sender address (same as from:)
+  --subject         Only match words in subject lines
+  --body            Only match words in message bodies
+  --archive         Include archived messages (same as is:archived, but
+                    alongside the inbox)
+  --limit <n>       Show at most n results (0 for all)
+  --reindex         Rebuild your index entries before searching
   --json            Output as JSON
 
-By default, searches both subject and body text.
-
 Examples:
-  gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
-  gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
+  gt mail search urgent                              # Find messages with "urgent"
+  gt mail search 'from:witness subject:"merge failed" after:2d'
+  gt mail search 'thread:thread-abc123 is:unread'
+  gt mail search "error" --from witness              # From witness, containing "error"
+  gt mail search "handoff" --archive                 # Include archived messages
+  gt mail search "" --from mayor/                    # All messages from mayor`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailSearch,
 }
@@ -468,6 +522,12 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailSendAt, "at", "", "Deliver at this time instead of now (RFC3339, \"2006-01-02 15:04\", or \"15:04\")")
+	mailSendCmd.Flags().StringVar(&mailSendAfter, "after", "", "Deliver after this delay instead of now (e.g. 4h, 2d)")
+	mailSendCmd.Flags().StringVar(&mailSendOnClose, "on-close", "", "Deliver when this bead closes")
+	mailSendCmd.MarkFlagsMutuallyExclusive("at", "after", "on-close")
+	mailSendCmd.Flags().StringVar(&mailExpectReply, "expect-reply", "", "Expect a reply within this long; remind, then escalate if none (e.g. 4h, 2d)")
+	mailSendCmd.Flags().StringVar(&mailEscalateSev, "escalate-severity", "", "Severity to escalate an unanswered --expect-reply at (low, medium, high, critical)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -500,6 +560,8 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
+	mailSearchCmd.Flags().BoolVar(&mailSearchReindex, "reindex", false, "Rebuild your search index entries before searching")
+	mailSearchCmd.Flags().IntVarP(&mailSearchLimit, "limit", "n", 50, "Maximum results to show (0 for all)")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
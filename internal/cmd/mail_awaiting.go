@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Awaiting command flags
+var (
+	mailAwaitingJSON bool
+	mailAwaitingAll  bool
+)
+
+var mailAwaitingCmd = &cobra.Command{
+	Use:   "awaiting",
+	Short: "List questions awaiting replies",
+	Long: `List mail sent with --expect-reply that hasn't been answered yet.
+
+A reply in the message's thread from anyone but the sender answers it. If
+no reply arrives within the window, the daemon heartbeat re-sends the
+message as a reminder and starts the window again; if that also goes
+unanswered, it escalates with 'gt escalate'. Escalated questions stay
+listed until they are answered or cancelled.
+
+By default only your own questions are listed; --all lists the whole town's.
+
+Examples:
+  gt mail awaiting
+  gt mail awaiting --all --json
+  gt mail awaiting cancel await-1a2b3c4d`,
+	Args: cobra.NoArgs,
+	RunE: runMailAwaiting,
+}
+
+var mailAwaitingCancelCmd = &cobra.Command{
+	Use:   "cancel <id>...",
+	Short: "Stop waiting for a reply",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMailAwaitingCancel,
+}
+
+func init() {
+	mailAwaitingCmd.Flags().BoolVar(&mailAwaitingJSON, "json", false, "Output as JSON")
+	mailAwaitingCmd.Flags().BoolVarP(&mailAwaitingAll, "all", "a", false, "List everyone's questions")
+
+	mailAwaitingCmd.AddCommand(mailAwaitingCancelCmd)
+	mailCmd.AddCommand(mailAwaitingCmd)
+}
+
+// awaitReplies records that msg's sender expects a reply from each of
+// recipients within window. Best-effort: the message is already sent.
+func awaitReplies(townRoot string, msg *mail.Message, recipients []string, window time.Duration) {
+	awaiting := mail.NewAwaiting(townRoot)
+	var ids []string
+	for _, to := range recipients {
+		question := *msg
+		question.To = to
+		a, err := awaiting.Add(&question, window, mailEscalateSev)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Could not track reply from %s: %v\n", to, err)
+			continue
+		}
+		ids = append(ids, a.ID)
+	}
+	if len(ids) > 0 {
+		fmt.Printf("  Expecting reply within %s: %s\n", window, strings.Join(ids, ", "))
+	}
+}
+
+func runMailAwaiting(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	awaiting, err := mail.NewAwaiting(townRoot).List()
+	if err != nil {
+		return err
+	}
+
+	if !mailAwaitingAll {
+		me := mail.AddressToIdentity(detectSender())
+		mine := awaiting[:0]
+		for _, a := range awaiting {
+			if mail.AddressToIdentity(a.From) == me {
+				mine = append(mine, a)
+			}
+		}
+		awaiting = mine
+	}
+
+	if mailAwaitingJSON {
+		if awaiting == nil {
+			awaiting = []*mail.AwaitedReply{}
+		}
+		return outputJSON(awaiting)
+	}
+
+	fmt.Printf("%s Awaiting replies:\n\n", style.Bold.Render("⏳"))
+	if len(awaiting) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return nil
+	}
+
+	now := time.Now()
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 15},
+		style.Column{Name: "STATUS", Width: 24},
+		style.Column{Name: "FROM", Width: 18},
+		style.Column{Name: "TO", Width: 18},
+		style.Column{Name: "SUBJECT", Width: 36},
+	)
+	for _, a := range awaiting {
+		status := a.Stage()
+		switch {
+		case a.Escalation != "":
+			status = style.Warning.Render("escalated " + a.Escalation)
+		case now.Before(a.DueAt):
+			status += ", due in " + a.DueAt.Sub(now).Round(time.Minute).String()
+		default:
+			status = style.Warning.Render(status + ", overdue")
+		}
+		table.AddRow(a.ID, status, a.From, a.To, a.Subject)
+	}
+	fmt.Print(table.Render())
+	for _, a := range awaiting {
+		if a.LastError != "" {
+			fmt.Printf("  %s %s: %s\n", style.Warning.Render("⚠"), a.ID, a.LastError)
+		}
+	}
+	return nil
+}
+
+func runMailAwaitingCancel(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	awaiting := mail.NewAwaiting(townRoot)
+	for _, id := range args {
+		a, err := awaiting.Cancel(id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Stopped waiting on %s to %s: %s\n", style.Bold.Render("✓"), a.ID, a.To, a.Subject)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Dead-letter command flags
+var (
+	mailDLQJSON bool
+	mailDLQRig  string
+	mailDLQAll  bool
+)
+
+var mailDLQCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Manage dead-lettered protocol mail",
+	Long: `Manage each rig's dead-letter queue of protocol mail.
+
+When a protocol message (MERGED, MERGE_FAILED, MERGE_READY, ...) has no
+handler, or its handler fails, it is kept in the rig's dead-letter queue
+with the error, attempt count, and timestamps. The daemon heartbeat retries
+it with backoff: after 1m, then doubling up to 1h. After 10 attempts it is
+only retried by hand. Mail rejected for a missing or wrong signature is
+never queued.
+
+Examples:
+  gt mail dlq list
+  gt mail dlq list --rig gastown --json
+  gt mail dlq retry dlq-1a2b3c4d
+  gt mail dlq retry --all --rig gastown
+  gt mail dlq drop dlq-1a2b3c4d`,
+	RunE: requireSubcommand,
+}
+
+var mailDLQListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dead-lettered messages",
+	Args:  cobra.NoArgs,
+	RunE:  runMailDLQList,
+}
+
+var mailDLQRetryCmd = &cobra.Command{
+	Use:   "retry [id]...",
+	Short: "Retry dead-lettered messages now",
+	Long: `Retry dead-lettered messages now, regardless of backoff.
+
+Give message IDs, or --all to retry every message (in --rig, if given).
+Messages handled this time leave the queue; the rest record the new error.`,
+	RunE: runMailDLQRetry,
+}
+
+var mailDLQDropCmd = &cobra.Command{
+	Use:   "drop <id>...",
+	Short: "Drop dead-lettered messages without retrying",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMailDLQDrop,
+}
+
+func init() {
+	mailDLQCmd.PersistentFlags().StringVar(&mailDLQRig, "rig", "", "Only this rig's queue")
+	mailDLQListCmd.Flags().BoolVar(&mailDLQJSON, "json", false, "Output as JSON")
+	mailDLQRetryCmd.Flags().BoolVar(&mailDLQAll, "all", false, "Retry every queued message")
+
+	mailDLQCmd.AddCommand(mailDLQListCmd)
+	mailDLQCmd.AddCommand(mailDLQRetryCmd)
+	mailDLQCmd.AddCommand(mailDLQDropCmd)
+	mailCmd.AddCommand(mailDLQCmd)
+}
+
+// dlqRigs returns the rigs whose dead-letter queues the command covers.
+func dlqRigs() ([]*rig.Rig, string, error) {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return nil, "", err
+	}
+	if mailDLQRig == "" {
+		return rigs, townRoot, nil
+	}
+	for _, r := range rigs {
+		if r.Name == mailDLQRig {
+			return []*rig.Rig{r}, townRoot, nil
+		}
+	}
+	return nil, "", fmt.Errorf("rig '%s' not found", mailDLQRig)
+}
+
+// rigDeadLetter is a dead letter and the rig whose queue holds it.
+type rigDeadLetter struct {
+	Rig string `json:"rig"`
+	*mail.DeadLetter
+}
+
+func runMailDLQList(cmd *cobra.Command, args []string) error {
+	rigs, _, err := dlqRigs()
+	if err != nil {
+		return err
+	}
+	var letters []rigDeadLetter
+	for _, r := range rigs {
+		queued, err := mail.OpenDeadLetters(r.Path).List()
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		for _, d := range queued {
+			letters = append(letters, rigDeadLetter{Rig: r.Name, DeadLetter: d})
+		}
+	}
+
+	if mailDLQJSON {
+		if letters == nil {
+			letters = []rigDeadLetter{}
+		}
+		return outputJSON(letters)
+	}
+
+	fmt.Printf("%s Dead-lettered mail:\n\n", style.Bold.Render("☠"))
+	if len(letters) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return nil
+	}
+
+	now := time.Now()
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 13},
+		style.Column{Name: "RIG", Width: 12},
+		style.Column{Name: "SUBJECT", Width: 28},
+		style.Column{Name: "TO", Width: 18},
+		style.Column{Name: "ATTEMPTS", Width: 8},
+		style.Column{Name: "NEXT RETRY", Width: 16},
+	)
+	for _, d := range letters {
+		next := "in " + d.NextRetryAt.Sub(now).Round(time.Second).String()
+		switch {
+		case d.Exhausted():
+			next = style.Warning.Render("manual only")
+		case !now.Before(d.NextRetryAt):
+			next = "due"
+		}
+		table.AddRow(d.ID, d.Rig, d.Message.Subject, d.Message.To, fmt.Sprintf("%d", d.Attempts), next)
+	}
+	fmt.Print(table.Render())
+	for _, d := range letters {
+		fmt.Printf("  %s %s: %s\n", style.Warning.Render("⚠"), d.ID, d.Error)
+	}
+	return nil
+}
+
+func runMailDLQRetry(cmd *cobra.Command, args []string) error {
+	if mailDLQAll == (len(args) > 0) {
+		return fmt.Errorf("give dead letter IDs or --all")
+	}
+	rigs, townRoot, err := dlqRigs()
+	if err != nil {
+		return err
+	}
+
+	remaining := make(map[string]bool, len(args))
+	for _, id := range args {
+		remaining[id] = true
+	}
+	var failures int
+	for _, r := range rigs {
+		queued, err := mail.OpenDeadLetters(r.Path).List()
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		var ids []string
+		for _, d := range queued {
+			if mailDLQAll || remaining[d.ID] {
+				ids = append(ids, d.ID)
+				delete(remaining, d.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		registry, err := protocol.NewRigRegistry(townRoot, r.Name, r.Path, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		resolved, failed, err := registry.RetryDeadLetters(time.Now(), ids...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		for _, d := range resolved {
+			fmt.Printf("%s Handled %s: %s\n", style.Bold.Render("✓"), d.ID, d.Message.Subject)
+		}
+		for _, d := range failed {
+			fmt.Printf("%s %s failed again (attempt %d): %s\n", style.Warning.Render("⚠"), d.ID, d.Attempts, d.Error)
+		}
+		failures += len(failed)
+	}
+
+	for _, id := range args {
+		if remaining[id] {
+			return fmt.Errorf("%w: dead letter %s", mail.ErrMessageNotFound, id)
+		}
+	}
+	if failures > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runMailDLQDrop(cmd *cobra.Command, args []string) error {
+	rigs, _, err := dlqRigs()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		var dropped *mail.DeadLetter
+		for _, r := range rigs {
+			d, err := mail.OpenDeadLetters(r.Path).Drop(id)
+			if errors.Is(err, mail.ErrMessageNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", r.Name, err)
+			}
+			dropped = d
+			break
+		}
+		if dropped == nil {
+			return fmt.Errorf("%w: dead letter %s", mail.ErrMessageNotFound, id)
+		}
+		fmt.Printf("%s Dropped %s: %s\n", style.Bold.Render("✓"), dropped.ID, dropped.Message.Subject)
+	}
+	return nil
+}
@@ -214,6 +214,9 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if msg.ReplyTo != "" {
 		fmt.Printf("Reply-To: %s\n", style.Dim.Render(msg.ReplyTo))
 	}
+	if len(msg.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", style.Dim.Render(strings.Join(msg.Labels, ", ")))
+	}
 
 	if msg.Body != "" {
 		fmt.Printf("\n%s\n", msg.Body)
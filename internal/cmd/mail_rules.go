@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Mail rules command flags
+var (
+	mailRulesFile     string
+	mailRulesIdentity string
+	mailRulesAll      bool
+	mailRulesJSON     bool
+)
+
+var mailRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Per-identity mail filter rules",
+	Long: `Mail filter rules sort an identity's incoming mail as it is sent.
+
+Rules live in ~/gt/config/mail-rules/<identity>.json, e.g. mayor.json or
+gastown/witness.json. The router checks them in order when a message is sent
+to that identity; every matching rule's actions apply, until a rule with
+"stop". A rules file that fails to load is reported and mail is delivered
+unfiltered.
+
+Match fields (all that are set must match):
+  from       Sender address; * matches one path segment (gastown/polecats/*)
+  subject    Regular expression on the subject
+  type       task, scavenge, notification, reply
+  priority   low, normal, high, urgent
+  thread     Thread ID
+
+Actions:
+  archive    File straight into the archive, without notifying
+  forward    Also send a copy to these addresses
+  labels     Add labels to the message
+  priority   Raise the priority to at least this
+  delivery   "interrupt" nudges the session immediately, even when busy
+  stop       Don't evaluate later rules
+
+Example (config/mail-rules/mayor.json):
+  {
+    "type": "mail-rules",
+    "version": 1,
+    "rules": [
+      {"name": "polecat-done", "match": {"from": "*/polecats/*", "subject": "^POLECAT_DONE"},
+       "archive": true, "stop": true},
+      {"name": "escalations", "match": {"subject": "(?i)escalat"},
+       "priority": "urgent", "delivery": "interrupt"}
+    ]
+  }`,
+	RunE: requireSubcommand,
+}
+
+var mailRulesTestCmd = &cobra.Command{
+	Use:   "test [address]",
+	Short: "Dry-run mail rules against an inbox",
+	Long: `Show what an identity's mail rules would do to the messages in its inbox.
+
+Nothing is changed. Use --file to try a draft rules file before installing it.
+Without an address, tests your own inbox.
+
+Examples:
+  gt mail rules test mayor/
+  gt mail rules test gastown/witness --file /tmp/witness-rules.json
+  gt mail rules test --all --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailRulesTest,
+}
+
+func init() {
+	mailRulesTestCmd.Flags().StringVar(&mailRulesFile, "file", "", "Rules file to test instead of the identity's installed rules")
+	mailRulesTestCmd.Flags().StringVar(&mailRulesIdentity, "identity", "", "Explicit identity whose inbox to test (e.g., greenplace/Toast)")
+	mailRulesTestCmd.Flags().BoolVarP(&mailRulesAll, "all", "a", false, "Show messages no rule matches")
+	mailRulesTestCmd.Flags().BoolVar(&mailRulesJSON, "json", false, "Output as JSON")
+
+	mailRulesCmd.AddCommand(mailRulesTestCmd)
+	mailCmd.AddCommand(mailRulesCmd)
+}
+
+func runMailRulesTest(cmd *cobra.Command, args []string) error {
+	address := mailRulesIdentity
+	if address == "" && len(args) > 0 {
+		address = args[0]
+	}
+	if address == "" {
+		address = detectSender()
+	}
+
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	path := mailRulesFile
+	if path == "" {
+		path = config.MailRulesPath(townRoot, mail.AddressToIdentity(address))
+	}
+	cfg, err := config.LoadMailRules(path)
+	if err != nil {
+		return err
+	}
+	rules, err := mail.CompileRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	mailbox, err := getMailbox(address)
+	if err != nil {
+		return err
+	}
+	messages, err := mailbox.List()
+	if err != nil {
+		return fmt.Errorf("listing inbox: %w", err)
+	}
+
+	type resultJSON struct {
+		ID      string            `json:"id"`
+		From    string            `json:"from"`
+		Subject string            `json:"subject"`
+		Outcome *mail.RuleOutcome `json:"outcome,omitempty"`
+	}
+	results := make([]resultJSON, 0, len(messages))
+	matched := 0
+	for _, msg := range messages {
+		outcome := rules.Evaluate(msg)
+		if outcome != nil {
+			matched++
+		} else if !mailRulesAll {
+			continue
+		}
+		results = append(results, resultJSON{ID: msg.ID, From: msg.From, Subject: msg.Subject, Outcome: outcome})
+	}
+
+	if mailRulesJSON {
+		return outputJSON(results)
+	}
+
+	fmt.Printf("%s %d rule(s) from %s against %s's inbox: %d of %d message(s) match\n\n",
+		style.Bold.Render("🧪"), rules.Len(), path, address, matched, len(messages))
+	if len(results) == 0 {
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 12},
+		style.Column{Name: "FROM", Width: 20},
+		style.Column{Name: "SUBJECT", Width: 36},
+		style.Column{Name: "RULES", Width: 20},
+		style.Column{Name: "ACTIONS", Width: 36},
+	)
+	for _, r := range results {
+		if r.Outcome == nil {
+			table.AddRow(r.ID, r.From, r.Subject, style.Dim.Render("-"), style.Dim.Render("deliver"))
+			continue
+		}
+		table.AddRow(r.ID, r.From, r.Subject, strings.Join(r.Outcome.Matched, ","), describeRuleOutcome(r.Outcome))
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+// describeRuleOutcome summarizes the actions a rule outcome takes.
+func describeRuleOutcome(o *mail.RuleOutcome) string {
+	var actions []string
+	if o.Archive {
+		actions = append(actions, "archive")
+	}
+	if len(o.Forward) > 0 {
+		actions = append(actions, "forward "+strings.Join(o.Forward, ","))
+	}
+	if len(o.Labels) > 0 {
+		actions = append(actions, "label "+strings.Join(o.Labels, ","))
+	}
+	if o.Priority != "" {
+		actions = append(actions, "priority≥"+string(o.Priority))
+	}
+	if o.Delivery != "" {
+		actions = append(actions, string(o.Delivery))
+	}
+	if len(actions) == 0 {
+		return "deliver"
+	}
+	return strings.Join(actions, "; ")
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Scheduled mail command flags
+var (
+	mailScheduledJSON bool
+	mailScheduledAll  bool
+)
+
+var mailScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List scheduled mail",
+	Long: `List mail waiting for scheduled delivery.
+
+Mail sent with --at, --after, or --on-close is held until the daemon
+heartbeat releases it: once its time has come, or once its bead closes.
+Each recipient is delivered separately. A delivery that fails is retried on
+the next heartbeat, up to 10 times, and then marked failed; the errors are
+shown here. Failed mail stays listed until cancelled.
+
+By default only mail you scheduled is listed; --all lists the whole town's.
+
+Examples:
+  gt mail scheduled
+  gt mail scheduled --all --json
+  gt mail scheduled cancel sched-1a2b3c4d`,
+	Args: cobra.NoArgs,
+	RunE: runMailScheduled,
+}
+
+var mailScheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <id>...",
+	Short: "Cancel scheduled mail",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMailScheduledCancel,
+}
+
+func init() {
+	mailScheduledCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+	mailScheduledCmd.Flags().BoolVarP(&mailScheduledAll, "all", "a", false, "List everyone's scheduled mail")
+
+	mailScheduledCmd.AddCommand(mailScheduledCancelCmd)
+	mailCmd.AddCommand(mailScheduledCmd)
+}
+
+// scheduleMail holds msg for later delivery according to the --at, --after
+// and --on-close flags. Recipients are resolved and checked now, so a bad
+// address fails the send rather than the release.
+func scheduleMail(townRoot string, msg *mail.Message) error {
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	now := time.Now()
+	sm := &mail.ScheduledMessage{
+		Message:        msg,
+		SuppressNotify: msg.SuppressNotify,
+		OnClose:        mailSendOnClose,
+		ScheduledAt:    now,
+	}
+	var err error
+	switch {
+	case mailSendAt != "":
+		if sm.DeliverAt, err = parseMailAt(mailSendAt, now); err != nil {
+			return err
+		}
+		if !sm.DeliverAt.After(now) {
+			return fmt.Errorf("--at %s is in the past", mailSendAt)
+		}
+	case mailSendAfter != "":
+		d, err := parseMailDuration("after", mailSendAfter)
+		if err != nil {
+			return err
+		}
+		sm.DeliverAt = now.Add(d)
+	case mailSendOnClose != "":
+		if _, err := beads.New(townRoot).Show(mailSendOnClose); err != nil {
+			return fmt.Errorf("--on-close %s: %w", mailSendOnClose, err)
+		}
+	}
+
+	recipients, err := mail.NewRouter(townRoot).ExpandRecipients(msg.To)
+	if err != nil {
+		return fmt.Errorf("scheduling message: %w", err)
+	}
+	for _, addr := range recipients {
+		sm.Recipients = append(sm.Recipients, &mail.ScheduledDelivery{Address: addr})
+	}
+
+	if err := mail.NewSchedule(townRoot).Add(sm); err != nil {
+		return fmt.Errorf("scheduling message: %w", err)
+	}
+
+	fmt.Printf("%s Message to %s scheduled %s\n", style.Bold.Render("⏰"), msg.To, sm.Trigger())
+	fmt.Printf("  Subject: %s\n", msg.Subject)
+	if len(recipients) > 1 || recipients[0] != msg.To {
+		fmt.Printf("  Recipients: %s\n", strings.Join(recipients, ", "))
+	}
+	fmt.Printf("  ID: %s %s\n", sm.ID, style.Dim.Render("(cancel with: gt mail scheduled cancel "+sm.ID+")"))
+	return nil
+}
+
+// parseMailAt parses --at: an RFC3339 time, a local "2006-01-02 15:04"
+// (or with a T), a date (midnight local), or a clock time "15:04" meaning
+// its next occurrence.
+func parseMailAt(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		local := now.Local()
+		at := time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: want RFC3339, \"2006-01-02 15:04\", a date, or \"15:04\"", value)
+}
+
+// parseMailDuration parses a duration flag such as --after: a Go duration,
+// or whole days ("2d").
+func parseMailDuration(flag, value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid --%s %q: want a duration such as 90m, 4h, or 2d", flag, value)
+}
+
+func runMailScheduled(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scheduled, err := mail.NewSchedule(townRoot).List()
+	if err != nil {
+		return err
+	}
+
+	if !mailScheduledAll {
+		me := mail.AddressToIdentity(detectSender())
+		mine := scheduled[:0]
+		for _, sm := range scheduled {
+			if mail.AddressToIdentity(sm.Message.From) == me {
+				mine = append(mine, sm)
+			}
+		}
+		scheduled = mine
+	}
+
+	if mailScheduledJSON {
+		if scheduled == nil {
+			scheduled = []*mail.ScheduledMessage{}
+		}
+		return outputJSON(scheduled)
+	}
+
+	fmt.Printf("%s Scheduled mail:\n\n", style.Bold.Render("⏰"))
+	if len(scheduled) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 15},
+		style.Column{Name: "WHEN", Width: 24},
+		style.Column{Name: "FROM", Width: 18},
+		style.Column{Name: "TO", Width: 18},
+		style.Column{Name: "SUBJECT", Width: 36},
+	)
+	for _, sm := range scheduled {
+		when := sm.Trigger()
+		if sm.Failed() {
+			when = style.Error.Render(when + " (failed)")
+		} else if len(sm.Errors()) > 0 {
+			when = style.Warning.Render(when + " (retrying)")
+		}
+		table.AddRow(sm.ID, when, sm.Message.From, sm.Message.To, sm.Message.Subject)
+	}
+	fmt.Print(table.Render())
+	for _, sm := range scheduled {
+		for _, e := range sm.Errors() {
+			fmt.Printf("  %s %s: %s\n", style.Warning.Render("⚠"), sm.ID, e)
+		}
+	}
+	return nil
+}
+
+func runMailScheduledCancel(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	schedule := mail.NewSchedule(townRoot)
+	for _, id := range args {
+		sm, err := schedule.Cancel(id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Cancelled %s to %s: %s\n", style.Bold.Render("✓"), sm.ID, sm.Message.To, sm.Message.Subject)
+	}
+	return nil
+}
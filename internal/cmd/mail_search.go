@@ -1,18 +1,21 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-// runMailSearch searches for messages matching a pattern.
+// runMailSearch searches for messages matching a query.
 func runMailSearch(cmd *cobra.Command, args []string) error {
-	query := args[0]
+	query, err := buildMailSearchQuery(args[0], time.Now())
+	if err != nil {
+		return err
+	}
 
 	// Determine which inbox to search
 	address := detectSender()
@@ -30,37 +33,41 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting mailbox: %w", err)
 	}
 
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
-		SubjectOnly: mailSearchSubject,
-		BodyOnly:    mailSearchBody,
+	if mailSearchReindex {
+		if err := mailbox.Reindex(true); err != nil {
+			return fmt.Errorf("reindexing mailbox: %w", err)
+		}
 	}
 
 	// Execute search
-	messages, err := mailbox.Search(opts)
+	hits, err := mailbox.SearchQuery(query)
 	if err != nil {
 		return fmt.Errorf("searching messages: %w", err)
 	}
+	total := len(hits)
+	if mailSearchLimit > 0 && len(hits) > mailSearchLimit {
+		hits = hits[:mailSearchLimit]
+	}
 
 	// JSON output
 	if mailSearchJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(messages)
+		if hits == nil {
+			hits = []*mail.SearchHit{}
+		}
+		return outputJSON(hits)
 	}
 
 	// Human-readable output
 	fmt.Printf("%s Search results for %s: %d message(s)\n\n",
-		style.Bold.Render("🔍"), address, len(messages))
+		style.Bold.Render("🔍"), address, total)
 
-	if len(messages) == 0 {
+	if len(hits) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
 		return nil
 	}
 
-	for _, msg := range messages {
+	for _, hit := range hits {
+		msg := hit.Message
 		readMarker := "●"
 		if msg.Read {
 			readMarker = "○"
@@ -77,14 +84,51 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		if msg.Wisp {
 			wispMarker = " " + style.Dim.Render("(wisp)")
 		}
+		archivedMarker := ""
+		if hit.Archived {
+			archivedMarker = " " + style.Dim.Render("(archived)")
+		}
 
-		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
+		fmt.Printf("  %s %s%s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker, archivedMarker)
 		fmt.Printf("    %s from %s\n",
 			style.Dim.Render(msg.ID),
 			msg.From)
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
+		if hit.Snippet != "" {
+			fmt.Printf("    %s\n", hit.Highlight(func(s string) string { return style.Bold.Render(s) }))
+		}
+	}
+	if len(hits) < total {
+		fmt.Printf("\n  %s\n", style.Dim.Render(fmt.Sprintf("(%d more; use --limit 0 to show all)", total-len(hits))))
 	}
 
 	return nil
 }
+
+// buildMailSearchQuery parses the query and folds in the --from, --subject,
+// --body and --archive flags.
+func buildMailSearchQuery(s string, now time.Time) (*mail.Query, error) {
+	query, err := mail.ParseQuery(s, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search query: %w", err)
+	}
+	if mailSearchFrom != "" {
+		query.From = append(query.From, strings.ToLower(mailSearchFrom))
+	}
+	switch {
+	case mailSearchSubject:
+		query.SubjectTerms = append(query.SubjectTerms, query.Terms...)
+		query.SubjectPhrases = append(query.SubjectPhrases, query.Phrases...)
+		query.Terms, query.Phrases = nil, nil
+	case mailSearchBody:
+		query.BodyTerms = append(query.BodyTerms, query.Terms...)
+		query.BodyPhrases = append(query.BodyPhrases, query.Phrases...)
+		query.Terms, query.Phrases = nil, nil
+	}
+	// Archived mail is searched only on request.
+	if !mailSearchArchive && !query.Archived {
+		query.Inbox = true
+	}
+	return query, nil
+}
@@ -7,8 +7,10 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -86,6 +88,25 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set message type
 	msg.Type = mail.ParseMessageType(mailType)
 
+	// A question expecting a reply is a task unless --type says otherwise.
+	var replyWindow time.Duration
+	if mailExpectReply != "" {
+		if mailSendAt != "" || mailSendAfter != "" || mailSendOnClose != "" {
+			return fmt.Errorf("--expect-reply cannot be combined with --at, --after, or --on-close")
+		}
+		if replyWindow, err = parseMailDuration("expect-reply", mailExpectReply); err != nil {
+			return err
+		}
+		if mailEscalateSev != "" && !config.IsValidSeverity(mailEscalateSev) {
+			return fmt.Errorf("invalid --escalate-severity %q: want low, medium, high, or critical", mailEscalateSev)
+		}
+		if !cmd.Flags().Changed("type") {
+			msg.Type = mail.TypeTask
+		}
+	} else if mailEscalateSev != "" {
+		return fmt.Errorf("--escalate-severity requires --expect-reply")
+	}
+
 	// Set pinned flag
 	msg.Pinned = mailPinned
 
@@ -131,6 +152,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		msg.ThreadID = generateThreadID()
 	}
 
+	// Scheduled mail is held for the daemon to release later, once its
+	// recipients are resolved and checked.
+	if mailSendAt != "" || mailSendAfter != "" || mailSendOnClose != "" {
+		return scheduleMail(workDir, msg)
+	}
+
 	// Use address resolver for new address types
 	townRoot, _ := workspace.FindFromCwd()
 	b := beads.New(townRoot)
@@ -147,6 +174,9 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
 		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 		fmt.Printf("  Subject: %s\n", mailSubject)
+		if replyWindow > 0 {
+			awaitReplies(workDir, msg, []string{to}, replyWindow)
+		}
 		return nil
 	}
 
@@ -213,6 +243,9 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if replyWindow > 0 {
+		awaitReplies(workDir, msg, recipientAddrs, replyWindow)
+	}
 
 	return nil
 }
@@ -23,6 +23,7 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitStrategy  string
 
 	// Retry flags
 	mqRetryNow bool
@@ -39,6 +40,8 @@ var (
 	mqListEpic    string
 	mqListJSON    bool
 	mqListVerify  bool
+	mqListHistory bool
+	mqListExplain bool
 
 	// Status command flags
 	mqStatusJSON bool
@@ -93,6 +96,13 @@ Target branch auto-detection:
 
 This ensures batch work on epics automatically flows to integration branches.
 
+Merge strategy:
+  --strategy sets how this MR lands, overriding the rig's
+  merge_queue.merge_strategy: squash (one commit), merge (merge commit),
+  rebase (replay commits onto the target and fast-forward), or ff
+  (fast-forward only; sent back for rebase if the target has moved).
+  Re-submitting an existing MR with --strategy updates it.
+
 Polecat auto-cleanup:
   When run from a polecat work branch (polecat/<worker>/<issue>), this command
   automatically triggers polecat shutdown after submitting the MR. The polecat
@@ -106,6 +116,7 @@ Examples:
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
+  gt mq submit --strategy rebase         # Land with linear history
   gt mq submit --no-cleanup              # Submit without auto-cleanup`,
 	RunE: runMqSubmit,
 }
@@ -143,7 +154,17 @@ Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
   gt mq list greenplace --status=open
-  gt mq list greenplace --worker=Nux`,
+  gt mq list greenplace --worker=Nux
+  gt mq list greenplace --history
+  gt mq list greenplace --explain
+
+--history adds the rig's post-merge incidents: MRs that landed but failed
+merge_queue.post_merge_gates and were reverted by the refinery. Reverted MRs
+show as "reverted" with --status=closed.
+
+--explain itemizes each MR's score: base, priority, queue age, convoy age
+and deadline, retry penalty, labels (hotfix, security), and diff size. The
+weights come from merge_queue.scoring in the rig's settings/config.json.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMQList,
 }
@@ -285,6 +306,7 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().StringVar(&mqSubmitStrategy, "strategy", "", "Merge strategy: squash, merge, rebase, or ff (default: rig setting)")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -296,6 +318,8 @@ func init() {
 	mqListCmd.Flags().StringVar(&mqListEpic, "epic", "", "Show MRs targeting integration/<epic>")
 	mqListCmd.Flags().BoolVar(&mqListJSON, "json", false, "Output as JSON")
 	mqListCmd.Flags().BoolVar(&mqListVerify, "verify", false, "Verify branches exist in git (shows MISSING for deleted branches)")
+	mqListCmd.Flags().BoolVar(&mqListHistory, "history", false, "Also show post-merge incidents (reverted merges)")
+	mqListCmd.Flags().BoolVar(&mqListExplain, "explain", false, "Show how each MR's priority score breaks down")
 
 	// Reject flags
 	mqRejectCmd.Flags().StringVarP(&mqRejectReason, "reason", "r", "", "Reason for rejection (required unless --stdin)")
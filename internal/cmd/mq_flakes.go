@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ flakes command flags
+var (
+	mqFlakesJSON       bool
+	mqFlakesQuarantine string
+	mqFlakesRelease    string
+)
+
+var mqFlakesCmd = &cobra.Command{
+	Use:   "flakes <rig>",
+	Short: "Show flaky tests recorded by the refinery",
+	Long: `Show the rig's flaky test ledger.
+
+When a quality gate fails and then passes on retry (merge_queue.retry_flaky_tests
+> 1), the refinery records the tests that failed on the earlier attempt. This
+command lists them, worst offenders first, so beads can be filed against them.
+
+A test is quarantined automatically once it has flaked
+merge_queue.flake_quarantine_after times (default 3), or by hand with
+--quarantine. Gates with "quarantine": true ignore failures of quarantined
+tests; other gates are unaffected.
+
+Test names match the Failing-Test fields of MERGE_FAILED messages, e.g.
+"TestFoo (example.com/pkg)". A name like "[gate lint]" means the gate flaked
+but its output named no tests.
+
+Examples:
+  gt mq flakes greenplace
+  gt mq flakes greenplace --json
+  gt mq flakes greenplace --quarantine "TestFoo (example.com/pkg)"
+  gt mq flakes greenplace --release "TestFoo (example.com/pkg)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFlakes,
+}
+
+func init() {
+	mqFlakesCmd.Flags().BoolVar(&mqFlakesJSON, "json", false, "Output as JSON")
+	mqFlakesCmd.Flags().StringVar(&mqFlakesQuarantine, "quarantine", "", "Quarantine a test")
+	mqFlakesCmd.Flags().StringVar(&mqFlakesRelease, "release", "", "Release a test from quarantine")
+	mqFlakesCmd.MarkFlagsMutuallyExclusive("quarantine", "release")
+
+	mqCmd.AddCommand(mqFlakesCmd)
+}
+
+func runMQFlakes(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	// The threshold only matters when recording flakes, which the CLI never does.
+	ledger := refinery.NewFlakeLedger(r.Path, 0)
+
+	if mqFlakesQuarantine != "" {
+		if err := ledger.SetQuarantined(mqFlakesQuarantine, true, time.Now()); err != nil {
+			return err
+		}
+		fmt.Printf("%s Quarantined %s\n", style.Bold.Render("✓"), mqFlakesQuarantine)
+		return nil
+	}
+	if mqFlakesRelease != "" {
+		if err := ledger.SetQuarantined(mqFlakesRelease, false, time.Now()); err != nil {
+			return err
+		}
+		fmt.Printf("%s Released %s from quarantine\n", style.Bold.Render("✓"), mqFlakesRelease)
+		return nil
+	}
+
+	records, err := ledger.List()
+	if err != nil {
+		return err
+	}
+
+	if mqFlakesJSON {
+		return outputJSON(records)
+	}
+
+	fmt.Printf("%s Flaky tests for '%s':\n\n", style.Bold.Render("🎲"), rigName)
+	if len(records) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none recorded)"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "TEST", Width: 48},
+		style.Column{Name: "GATE", Width: 12},
+		style.Column{Name: "FLAKES", Width: 6, Align: style.AlignRight},
+		style.Column{Name: "LAST SEEN", Width: 16},
+		style.Column{Name: "STATUS", Width: 11},
+	)
+	for _, rec := range records {
+		status := ""
+		if rec.Quarantined {
+			status = style.Warning.Render("quarantined")
+		}
+		lastSeen := style.Dim.Render("never")
+		if rec.Count > 0 {
+			lastSeen = formatAge(rec.LastSeen)
+		}
+		table.AddRow(rec.Test, rec.Gate, fmt.Sprintf("%d", rec.Count), lastSeen, status)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ freeze command flags
+var (
+	mqFreezeBranch   string
+	mqFreezeUntil    string
+	mqFreezeReason   string
+	mqFreezeList     bool
+	mqFreezeJSON     bool
+	mqUnfreezeBranch string
+	mqUnfreezeAll    bool
+)
+
+var mqFreezeCmd = &cobra.Command{
+	Use:   "freeze <rig>",
+	Short: "Freeze merges to a target branch",
+	Long: `Hold merges to a target branch until a time or until lifted.
+
+While a target is frozen the refinery keeps claiming and gating its MRs, but
+holds the push. Held MRs stay in the queue and show as "ready, frozen" in
+gt mq list; their gate results are cached, so they land on the first pass
+after the freeze lifts.
+
+--until takes a date (2006-01-02, end of that day), an RFC3339 time, or a
+duration from now (4h). Without --until the freeze holds until gt mq
+unfreeze. --branch defaults to the rig's default branch and may be a glob
+(release/*).
+
+Recurring and scheduled freezes belong in the rig settings, under
+merge_queue.freeze_windows; --list shows them alongside manual freezes.
+
+Examples:
+  gt mq freeze greenplace --until 2h --reason "release cut"
+  gt mq freeze greenplace --branch 'release/*' --until 2026-12-24
+  gt mq freeze greenplace --list`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFreeze,
+}
+
+var mqUnfreezeCmd = &cobra.Command{
+	Use:   "unfreeze <rig>",
+	Short: "Lift a manual merge freeze",
+	Long: `Lift a freeze set with gt mq freeze.
+
+Held MRs land on the refinery's next pass. Freeze windows from the rig settings
+can't be lifted here; edit merge_queue.freeze_windows instead.
+
+Examples:
+  gt mq unfreeze greenplace
+  gt mq unfreeze greenplace --branch 'release/*'
+  gt mq unfreeze greenplace --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQUnfreeze,
+}
+
+func init() {
+	mqFreezeCmd.Flags().StringVar(&mqFreezeBranch, "branch", "", "Target branch or glob to freeze (default: rig default branch)")
+	mqFreezeCmd.Flags().StringVar(&mqFreezeUntil, "until", "", "When the freeze lifts: date, RFC3339 time, or duration")
+	mqFreezeCmd.Flags().StringVar(&mqFreezeReason, "reason", "", "Why merges are frozen")
+	mqFreezeCmd.Flags().BoolVar(&mqFreezeList, "list", false, "Show freeze windows and manual freezes")
+	mqFreezeCmd.Flags().BoolVar(&mqFreezeJSON, "json", false, "Output as JSON (with --list)")
+
+	mqUnfreezeCmd.Flags().StringVar(&mqUnfreezeBranch, "branch", "", "Branch or glob to unfreeze, as given to freeze (default: rig default branch)")
+	mqUnfreezeCmd.Flags().BoolVar(&mqUnfreezeAll, "all", false, "Lift every manual freeze")
+	mqUnfreezeCmd.MarkFlagsMutuallyExclusive("branch", "all")
+
+	mqCmd.AddCommand(mqFreezeCmd)
+	mqCmd.AddCommand(mqUnfreezeCmd)
+}
+
+func runMQFreeze(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	if mqFreezeList {
+		return listMQFreezes(rigName, r.Path)
+	}
+
+	now := time.Now()
+	freeze := refinery.Freeze{
+		Branch: mqFreezeBranch,
+		Reason: mqFreezeReason,
+		By:     os.Getenv("BD_ACTOR"),
+		At:     now,
+	}
+	if freeze.Branch == "" {
+		freeze.Branch = r.DefaultBranch()
+	}
+	if mqFreezeUntil != "" {
+		if freeze.Until, err = parseTimeFlag("--until", mqFreezeUntil, now); err != nil {
+			return err
+		}
+		if !freeze.Until.After(now) {
+			return fmt.Errorf("--until %s is in the past", mqFreezeUntil)
+		}
+	}
+
+	if err := refinery.NewFreezeStore(r.Path).Add(freeze); err != nil {
+		return err
+	}
+
+	until := "until lifted (gt mq unfreeze)"
+	if !freeze.Until.IsZero() {
+		until = "until " + freeze.Until.Local().Format("2006-01-02 15:04 MST")
+	}
+	fmt.Printf("%s Froze merges to %s on '%s' %s\n", style.Bold.Render("❄"), freeze.Branch, rigName, until)
+	return nil
+}
+
+func runMQUnfreeze(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	branch := mqUnfreezeBranch
+	if branch == "" && !mqUnfreezeAll {
+		branch = r.DefaultBranch()
+	}
+	lifted, err := refinery.NewFreezeStore(r.Path).Lift(branch)
+	if err != nil {
+		return err
+	}
+	if len(lifted) == 0 {
+		if mqUnfreezeAll {
+			fmt.Printf("%s No manual freezes on '%s'\n", style.Dim.Render("○"), rigName)
+		} else {
+			fmt.Printf("%s No manual freeze on %s in '%s'\n", style.Dim.Render("○"), branch, rigName)
+		}
+		return nil
+	}
+	for _, f := range lifted {
+		fmt.Printf("%s Lifted freeze on %s\n", style.Bold.Render("✓"), f.Branch)
+	}
+	return nil
+}
+
+// listMQFreezes prints the rig's freeze windows and manual freezes.
+func listMQFreezes(rigName, rigPath string) error {
+	var windows []config.FreezeWindow
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings != nil && settings.MergeQueue != nil {
+		windows = settings.MergeQueue.FreezeWindows
+	}
+
+	now := time.Now()
+	freezes, err := refinery.NewFreezeStore(rigPath).List(now)
+	if err != nil {
+		return err
+	}
+
+	type windowJSON struct {
+		config.FreezeWindow
+		Active bool      `json:"active"`
+		Until  time.Time `json:"until,omitempty"`
+	}
+	rows := make([]windowJSON, 0, len(windows))
+	for i, w := range windows {
+		compiled, err := refinery.CompileFreezeWindow(w)
+		if err != nil {
+			return fmt.Errorf("freeze_windows[%d]: %w", i, err)
+		}
+		row := windowJSON{FreezeWindow: w}
+		row.Active, row.Until = compiled.Active(now)
+		rows = append(rows, row)
+	}
+
+	if mqFreezeJSON {
+		return outputJSON(struct {
+			Windows []windowJSON      `json:"windows"`
+			Freezes []refinery.Freeze `json:"freezes"`
+		}{rows, freezes})
+	}
+
+	fmt.Printf("%s Merge freezes for '%s':\n\n", style.Bold.Render("❄"), rigName)
+	if len(rows) == 0 && len(freezes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "SOURCE", Width: 7},
+		style.Column{Name: "BRANCHES", Width: 16},
+		style.Column{Name: "WHEN", Width: 36},
+		style.Column{Name: "STATUS", Width: 18},
+		style.Column{Name: "REASON", Width: 24},
+	)
+	for _, row := range rows {
+		status := style.Dim.Render("inactive")
+		if row.Active {
+			status = style.Warning.Render("frozen " + formatFreezeUntil(row.Until))
+		}
+		table.AddRow(refinery.FreezeSourceWindow, formatFreezeBranches(row.Branches),
+			formatFreezeWindow(row.FreezeWindow), status, row.Reason)
+	}
+	for _, f := range freezes {
+		when := "since " + f.At.Local().Format("2006-01-02 15:04")
+		if f.By != "" {
+			when += " by " + f.By
+		}
+		table.AddRow(refinery.FreezeSourceManual, f.Branch, when,
+			style.Warning.Render("frozen "+formatFreezeUntil(f.Until)), f.Reason)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+// formatFreezeWindow describes when a freeze window applies.
+func formatFreezeWindow(w config.FreezeWindow) string {
+	if !w.Recurring() {
+		if w.Start == "" {
+			return "until " + w.End
+		}
+		return w.Start + " → " + w.End
+	}
+	days := "daily"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	hours := "all day"
+	if w.From != "" {
+		hours = w.From + "-" + w.To
+	}
+	when := days + " " + hours
+	if w.Timezone != "" {
+		when += " " + w.Timezone
+	}
+	return when
+}
+
+// formatFreezeBranches renders a window's branch globs; none means all.
+func formatFreezeBranches(branches []string) string {
+	if len(branches) == 0 {
+		return "(all)"
+	}
+	return strings.Join(branches, ",")
+}
+
+// formatFreezeUntil renders when a freeze lifts.
+func formatFreezeUntil(until time.Time) string {
+	if until.IsZero() {
+		return "until lifted"
+	}
+	return "until " + until.Local().Format("01-02 15:04")
+}
@@ -75,11 +75,15 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Apply additional filters and calculate scores
+	scoring, err := refinery.LoadScoreConfig(r.Path)
+	if err != nil {
+		return err
+	}
 	now := time.Now()
 	type scoredIssue struct {
 		issue          *beads.Issue
 		fields         *beads.MRFields
-		score          float64
+		score          refinery.ScoreBreakdown
 		branchMissing  bool // true if branch doesn't exist in git (when --verify is set)
 		branchVerifyErr bool // true if git check errored (corrupt repo, permission, etc.)
 	}
@@ -132,13 +136,13 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		branchMissing, branchVerifyErr := verifyBranch(mqListVerify, gitClient, fields)
 
 		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
+		score := calculateMRScore(issue, fields, scoring, now)
 		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
 	}
 
 	// Sort by score descending (highest priority first)
 	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].score > scored[j].score
+		return scored[i].score.Total > scored[j].score.Total
 	})
 
 	// Extract filtered issues for JSON output compatibility
@@ -147,19 +151,38 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		filtered = append(filtered, s.issue)
 	}
 
+	// Post-merge incident history, newest first
+	var incidents []*refinery.IncidentRecord
+	if mqListHistory {
+		incidents, err = refinery.NewIncidentLog(r.Path).List()
+		if err != nil {
+			return err
+		}
+	}
+
 	// JSON output
 	if mqListJSON {
-		if mqListVerify {
-			// Extend JSON with verification results
+		if mqListHistory {
+			return outputJSON(struct {
+				MergeRequests []*beads.Issue             `json:"merge_requests"`
+				Incidents     []*refinery.IncidentRecord `json:"incidents"`
+			}{filtered, incidents})
+		}
+		if mqListVerify || mqListExplain {
+			// Extend JSON with verification results and score breakdowns
 			type verifiedIssue struct {
 				*beads.Issue
-				BranchExists *bool  `json:"branch_exists,omitempty"`
-				VerifyError  bool   `json:"verify_error,omitempty"`
+				BranchExists *bool                    `json:"branch_exists,omitempty"`
+				VerifyError  bool                     `json:"verify_error,omitempty"`
+				Score        *refinery.ScoreBreakdown `json:"score,omitempty"`
 			}
 			var verified []verifiedIssue
 			for _, s := range scored {
 				vi := verifiedIssue{Issue: s.issue}
-				if s.fields != nil && s.fields.Branch != "" {
+				if mqListExplain {
+					vi.Score = &s.score
+				}
+				if mqListVerify && s.fields != nil && s.fields.Branch != "" {
 					if s.branchVerifyErr {
 						vi.VerifyError = true
 					} else {
@@ -179,6 +202,9 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	if len(filtered) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(empty)"))
+		if mqListHistory {
+			printMQIncidents(incidents)
+		}
 		return nil
 	}
 
@@ -189,7 +215,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		{Name: "PRI", Width: 4},
 		{Name: "CONVOY", Width: 12},
 		{Name: "BRANCH", Width: 24},
-		{Name: "STATUS", Width: 10},
+		{Name: "STATUS", Width: 14},
 	}
 	if mqListVerify {
 		columns = append(columns, style.Column{Name: "GIT", Width: 8})
@@ -198,6 +224,16 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	table := style.NewTable(columns...)
 
+	// Ready MRs on a frozen target are gated but held at push.
+	var targets []string
+	for _, item := range scored {
+		targets = append(targets, mrTarget(item.fields, r.DefaultBranch()))
+	}
+	freezes, err := mqListFreezes(r.Path, targets, now)
+	if err != nil {
+		return err
+	}
+
 	// Add rows using scored items (already sorted by score)
 	for _, item := range scored {
 		issue := item.issue
@@ -210,7 +246,12 @@ func runMQList(cmd *cobra.Command, args []string) error {
 				displayStatus = "blocked"
 			} else {
 				displayStatus = "ready"
+				if freezes[mrTarget(fields, r.DefaultBranch())] != nil {
+					displayStatus = "ready, frozen"
+				}
 			}
+		} else if issue.Status == "closed" && fields != nil && fields.CloseReason == refinery.CloseReasonReverted {
+			displayStatus = "reverted"
 		}
 
 		// Format status with styling
@@ -218,12 +259,16 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		switch displayStatus {
 		case "ready":
 			styledStatus = style.Success.Render("ready")
+		case "ready, frozen":
+			styledStatus = style.Warning.Render("ready, frozen")
 		case "in_progress":
 			styledStatus = style.Warning.Render("active")
 		case "blocked":
 			styledStatus = style.Dim.Render("blocked")
 		case "closed":
 			styledStatus = style.Dim.Render("closed")
+		case "reverted":
+			styledStatus = style.Error.Render("reverted")
 		}
 
 		// Get MR fields
@@ -253,7 +298,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 
 		// Format score
-		scoreStr := fmt.Sprintf("%.1f", item.score)
+		scoreStr := fmt.Sprintf("%.1f", item.score.Total)
 
 		// Format branch status when --verify is set
 		gitStatus := ""
@@ -301,6 +346,11 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Show active freezes below table
+	for _, target := range sortedFreezeTargets(freezes) {
+		fmt.Printf("  %s %s\n", style.Warning.Render("❄"), style.Dim.Render(freezes[target].String()))
+	}
+
 	// Show blocking details below table
 	for _, item := range scored {
 		issue := item.issue
@@ -318,9 +368,69 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if mqListExplain {
+		fmt.Printf("\n%s Score breakdown:\n", style.Bold.Render("🧮"))
+		for _, item := range scored {
+			printMQScoreBreakdown(item.issue.ID, item.score)
+		}
+	}
+
+	if mqListHistory {
+		printMQIncidents(incidents)
+	}
+
 	return nil
 }
 
+// printMQScoreBreakdown prints the factors that make up an MR's score.
+func printMQScoreBreakdown(id string, b refinery.ScoreBreakdown) {
+	fmt.Printf("\n  %s  %.1f\n", style.Bold.Render(id), b.Total)
+	for _, f := range b.Factors {
+		points := fmt.Sprintf("%+9.1f", f.Points)
+		switch {
+		case f.Points < 0:
+			points = style.Error.Render(points)
+		case f.Points > 0 && f.Name != "base":
+			points = style.Success.Render(points)
+		}
+		fmt.Printf("    %-16s %s  %s\n", f.Name, points, style.Dim.Render(f.Detail))
+	}
+}
+
+// printMQIncidents prints the post-merge incident history below the queue.
+func printMQIncidents(incidents []*refinery.IncidentRecord) {
+	fmt.Printf("\n%s Post-merge incidents:\n\n", style.Bold.Render("⏪"))
+	if len(incidents) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+		return
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "MR", Width: 12},
+		style.Column{Name: "ISSUE", Width: 12},
+		style.Column{Name: "MERGE", Width: 8},
+		style.Column{Name: "REVERT", Width: 8},
+		style.Column{Name: "WHEN", Width: 16},
+		style.Column{Name: "ERROR", Width: 40},
+	)
+	for _, inc := range incidents {
+		revert := style.Error.Render("FAILED")
+		if inc.RevertCommit != "" {
+			revert = shortSHA(inc.RevertCommit)
+		}
+		table.AddRow(inc.MR, inc.SourceIssue, shortSHA(inc.MergeCommit), revert, formatAge(inc.At), inc.Error)
+	}
+	fmt.Print(table.Render())
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
 // formatMRAge formats the age of an MR from its created_at timestamp.
 func formatMRAge(createdAt string) string {
 	t, err := time.Parse(time.RFC3339, createdAt)
@@ -355,36 +465,8 @@ func outputJSON(data interface{}) error {
 
 // calculateMRScore computes the priority score for an MR using the refinery scoring function.
 // Higher scores mean higher priority (process first).
-func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
-	// Parse MR creation time
-	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
-	if err != nil {
-		mrCreatedAt, err = time.Parse("2006-01-02T15:04:05Z", issue.CreatedAt)
-		if err != nil {
-			mrCreatedAt = now // Fallback to now if parsing fails
-		}
-	}
-
-	// Build score input
-	input := refinery.ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
-	}
-
-	return refinery.ScoreMRWithDefaults(input)
+func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, scoring refinery.ScoreConfig, now time.Time) refinery.ScoreBreakdown {
+	return refinery.ExplainScore(refinery.ScoreInputFromIssue(issue, fields, now), scoring)
 }
 
 // branchVerifier abstracts git branch existence checks for testability.
@@ -416,3 +498,45 @@ func verifyBranch(verify bool, client branchVerifier, fields *beads.MRFields) (b
 	}
 	return false, false
 }
+
+// mrTarget returns the MR's target branch, defaulting to the rig's default
+// branch when the MR doesn't name one.
+func mrTarget(fields *beads.MRFields, defaultBranch string) string {
+	if fields != nil && fields.Target != "" {
+		return fields.Target
+	}
+	return defaultBranch
+}
+
+// mqListFreezes returns the active freeze of each of targets that is frozen
+// at now.
+func mqListFreezes(rigPath string, targets []string, now time.Time) (map[string]*refinery.FreezeStatus, error) {
+	windows, err := refinery.LoadFreezeWindows(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	freezes := make(map[string]*refinery.FreezeStatus)
+	for _, target := range targets {
+		if _, seen := freezes[target]; seen {
+			continue
+		}
+		status, err := refinery.ActiveFreeze(rigPath, windows, target, now)
+		if err != nil {
+			return nil, err
+		}
+		freezes[target] = status
+	}
+	return freezes, nil
+}
+
+// sortedFreezeTargets returns the frozen targets in freezes, sorted.
+func sortedFreezeTargets(freezes map[string]*refinery.FreezeStatus) []string {
+	var targets []string
+	for target, status := range freezes {
+		if status != nil {
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -27,6 +28,12 @@ The priority scoring function considers:
   - Issue priority: P0 > P1 > P2 > P3 > P4
   - Retry count: MRs that fail repeatedly get deprioritized
   - MR age: FIFO tiebreaker for same priority/convoy
+  - Labels: hotfix and security MRs jump the queue
+  - Diff size: smaller changes land first
+  - Convoy deadline: MRs gain priority as their convoy's deadline nears
+
+Weights are set per rig under merge_queue.scoring in settings/config.json;
+see gt mq list --explain for each MR's breakdown.
 
 Use --strategy=fifo for first-in-first-out ordering instead.
 
@@ -90,6 +97,10 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	scoring, err := refinery.LoadScoreConfig(r.Path)
+	if err != nil {
+		return err
+	}
 	now := time.Now()
 
 	// Sort based on strategy
@@ -109,7 +120,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		scored := make([]scoredIssue, len(ready))
 		for i, issue := range ready {
 			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
+			score := calculateMRScore(issue, fields, scoring, now).Total
 			scored[i] = scoredIssue{issue: issue, score: score}
 		}
 
@@ -140,7 +151,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
+	score := calculateMRScore(next, fields, scoring, now).Total
 
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
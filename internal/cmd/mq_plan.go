@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ plan command flags
+var (
+	mqPlanJSON          bool
+	mqPlanSkipConflicts bool
+)
+
+var mqPlanCmd = &cobra.Command{
+	Use:   "plan <rig>",
+	Short: "Predict queue order, conflicts, and landing times",
+	Long: `Show how the refinery is expected to work through the ready queue.
+
+Every ready MR is ranked by its current priority score (the order the refinery
+will take them in). Each MR's branch is then test-merged against its target
+and against every MR ahead of it with the same target, so MRs that will fail
+on a conflict are flagged before the refinery spends gate time on them.
+
+ETAs assume MRs are processed one at a time in plan order and come from the
+rig's gate duration history: the average of each gate's recent runs, for the
+gates whose path filters match the MR. An MR expected to conflict gets no ETA,
+and "?" means a gate has no history yet.
+
+Conflict checks run in a temporary worktree; use --skip-conflicts on large
+queues to just see order and ETAs.
+
+Examples:
+  gt mq plan greenplace
+  gt mq plan greenplace --skip-conflicts
+  gt mq plan greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQPlan,
+}
+
+func init() {
+	mqPlanCmd.Flags().BoolVar(&mqPlanJSON, "json", false, "Output as JSON")
+	mqPlanCmd.Flags().BoolVar(&mqPlanSkipConflicts, "skip-conflicts", false, "Skip pairwise conflict checks")
+
+	mqCmd.AddCommand(mqPlanCmd)
+}
+
+func runMQPlan(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	eng.SetOutput(io.Discard)
+
+	now := time.Now()
+	plan, err := eng.Plan(now, refinery.PlanOptions{SkipConflicts: mqPlanSkipConflicts})
+	if err != nil {
+		return err
+	}
+
+	if mqPlanJSON {
+		return outputJSON(plan)
+	}
+
+	fmt.Printf("%s Merge plan for '%s':\n\n", style.Bold.Render("🗺"), rigName)
+	if len(plan) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no ready MRs)"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "#", Width: 3, Align: style.AlignRight},
+		style.Column{Name: "ID", Width: 12},
+		style.Column{Name: "SCORE", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "BRANCH", Width: 28},
+		style.Column{Name: "GATES", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "ETA", Width: 8, Align: style.AlignRight},
+		style.Column{Name: "CONFLICTS", Width: 24},
+	)
+	for _, entry := range plan {
+		gates := "?"
+		if entry.EstimateKnown {
+			gates = formatPlanDuration(entry.GateEstimate)
+		}
+		eta := style.Dim.Render("?")
+		if !entry.ETA.IsZero() {
+			eta = "+" + formatPlanDuration(entry.ETA.Sub(now))
+		} else if entry.Conflicted() {
+			eta = style.Dim.Render("-")
+		}
+		table.AddRow(
+			fmt.Sprintf("%d", entry.Position),
+			entry.MR.ID,
+			fmt.Sprintf("%.1f", entry.Score),
+			entry.MR.Branch,
+			gates,
+			eta,
+			formatPlanConflicts(entry),
+		)
+	}
+	fmt.Print(table.Render())
+
+	// Spell out conflicts below the table, where file lists fit.
+	for _, entry := range plan {
+		if len(entry.TargetConflicts) > 0 {
+			fmt.Printf("  %s conflicts with %s in: %s\n", style.Error.Render(entry.MR.ID),
+				entry.MR.Target, strings.Join(entry.TargetConflicts, ", "))
+		}
+		if len(entry.ConflictsWith) > 0 {
+			fmt.Printf("  %s conflicts with %s ahead of it in: %s\n", style.Warning.Render(entry.MR.ID),
+				strings.Join(entry.ConflictsWith, ", "), strings.Join(entry.ConflictFiles, ", "))
+		}
+		if entry.ConflictCheckError != "" {
+			fmt.Printf("  %s %s\n", style.Dim.Render(entry.MR.ID+":"),
+				style.Dim.Render("conflict check failed: "+entry.ConflictCheckError))
+		}
+	}
+	return nil
+}
+
+// formatPlanConflicts summarizes an entry's conflicts for the table.
+func formatPlanConflicts(entry *refinery.PlanEntry) string {
+	var parts []string
+	if len(entry.TargetConflicts) > 0 {
+		parts = append(parts, style.Error.Render(entry.MR.Target))
+	}
+	for _, id := range entry.ConflictsWith {
+		parts = append(parts, style.Warning.Render(id))
+	}
+	if len(parts) == 0 {
+		if mqPlanSkipConflicts {
+			return style.Dim.Render("(not checked)")
+		}
+		return style.Success.Render("none")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatPlanDuration renders a duration compactly: 45s, 12m, 2h5m.
+func formatPlanDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
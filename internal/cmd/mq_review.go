@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ review command flags
+var (
+	mqReviewApprove        bool
+	mqReviewRequestChanges bool
+	mqReviewComment        string
+)
+
+var mqReviewCmd = &cobra.Command{
+	Use:   "review <rig> <review-id>",
+	Short: "Approve or request changes on a review bead",
+	Long: `Record the outcome of a review requested by the refinery's review gate.
+
+When merge_queue.review is enabled in the rig settings, an MR that touches
+paths owned in the rig's settings/OWNERS file or the target branch's
+CODEOWNERS is blocked on a review bead, and the owners are mailed. Closing
+the review unblocks the MR:
+
+  --approve          the refinery merges the MR on its next pass
+  --request-changes  the refinery closes the MR and sends the worker
+                     MERGE_FAILED with the comment; gt done resubmits it
+
+A review covers the branch head it was requested for. If the branch moves,
+the refinery requests a new review.
+
+Examples:
+  gt mq review greenplace gt-rev12 --approve
+  gt mq review greenplace gt-rev12 --request-changes --comment "needs a migration"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQReview,
+}
+
+func init() {
+	mqReviewCmd.Flags().BoolVar(&mqReviewApprove, "approve", false, "Approve the change")
+	mqReviewCmd.Flags().BoolVar(&mqReviewRequestChanges, "request-changes", false, "Send the change back to its worker")
+	mqReviewCmd.Flags().StringVarP(&mqReviewComment, "comment", "m", "", "Review comment (sent to the worker with --request-changes)")
+	mqReviewCmd.MarkFlagsMutuallyExclusive("approve", "request-changes")
+	mqReviewCmd.MarkFlagsOneRequired("approve", "request-changes")
+
+	mqCmd.AddCommand(mqReviewCmd)
+}
+
+func runMQReview(cmd *cobra.Command, args []string) error {
+	rigName, reviewID := args[0], args[1]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	outcome := beads.ReviewApproved
+	if mqReviewRequestChanges {
+		outcome = beads.ReviewChangesRequested
+		if mqReviewComment == "" {
+			return fmt.Errorf("--request-changes needs a --comment saying what to change")
+		}
+	}
+
+	fields, err := refinery.RecordReviewOutcome(beads.New(r.Path), reviewID, outcome, os.Getenv("BD_ACTOR"), mqReviewComment)
+	if err != nil {
+		return err
+	}
+
+	if outcome == beads.ReviewApproved {
+		fmt.Printf("%s Approved %s; %s will merge on the refinery's next pass\n", style.Bold.Render("✓"), reviewID, fields.MR)
+	} else {
+		fmt.Printf("%s Requested changes on %s; %s goes back to its worker\n", style.Bold.Render("✗"), reviewID, fields.MR)
+	}
+	return nil
+}
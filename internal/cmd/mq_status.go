@@ -26,13 +26,14 @@ type MRStatusOutput struct {
 	ClosedAt  string `json:"closed_at,omitempty"`
 
 	// MR-specific fields
-	Branch      string `json:"branch,omitempty"`
-	Target      string `json:"target,omitempty"`
-	SourceIssue string `json:"source_issue,omitempty"`
-	Worker      string `json:"worker,omitempty"`
-	Rig         string `json:"rig,omitempty"`
-	MergeCommit string `json:"merge_commit,omitempty"`
-	CloseReason string `json:"close_reason,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+	Target        string `json:"target,omitempty"`
+	SourceIssue   string `json:"source_issue,omitempty"`
+	Worker        string `json:"worker,omitempty"`
+	Rig           string `json:"rig,omitempty"`
+	MergeCommit   string `json:"merge_commit,omitempty"`
+	CloseReason   string `json:"close_reason,omitempty"`
+	MergeStrategy string `json:"merge_strategy,omitempty"`
 
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
@@ -95,6 +96,7 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.MergeStrategy = mrFields.MergeStrategy
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -178,6 +180,9 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.Rig != "" {
 			fmt.Printf("   Rig:          %s\n", mrFields.Rig)
 		}
+		if mrFields.MergeStrategy != "" {
+			fmt.Printf("   Strategy:     %s\n", mrFields.MergeStrategy)
+		}
 		if mrFields.MergeCommit != "" {
 			fmt.Printf("   Merge Commit: %s\n", mrFields.MergeCommit)
 		}
@@ -76,6 +76,10 @@ func parseBranchName(branch string) branchInfo {
 }
 
 func runMqSubmit(cmd *cobra.Command, args []string) error {
+	if mqSubmitStrategy != "" && !config.IsValidMergeStrategy(mqSubmitStrategy) {
+		return fmt.Errorf("invalid --strategy %q: must be one of %s", mqSubmitStrategy, strings.Join(config.MergeStrategies, ", "))
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -210,6 +214,23 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
 
+	// Merge strategy: explicit flag, else whatever gt sling --merge recorded
+	// on the source issue. Empty leaves it to the rig's merge_queue setting.
+	strategy := mqSubmitStrategy
+	if strategy == "" {
+		strategy = slungMRStrategy(bd, issueID)
+	}
+	if strategy != "" {
+		description += fmt.Sprintf("\nmerge_strategy: %s", strategy)
+	}
+	traceID, traceparent := slungTrace(bd, issueID)
+	if traceID != "" {
+		description += fmt.Sprintf("\ntrace_id: %s", traceID)
+	}
+	if traceparent != "" {
+		description += fmt.Sprintf("\ntraceparent: %s", traceparent)
+	}
+
 	// Check if MR bead already exists for this branch (idempotency)
 	var mrIssue *beads.Issue
 	existingMR, err := bd.FindMRForBranch(branch)
@@ -219,6 +240,11 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	} else if existingMR != nil {
 		mrIssue = existingMR
 		fmt.Printf("%s MR already exists (idempotent)\n", style.Bold.Render("✓"))
+		if mqSubmitStrategy != "" {
+			if err := setMRMergeStrategy(bd, existingMR, mqSubmitStrategy); err != nil {
+				return fmt.Errorf("updating merge strategy: %w", err)
+			}
+		}
 	} else {
 		// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 		mrIssue, err = bd.Create(beads.CreateOptions{
@@ -246,6 +272,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if strategy != "" {
+		fmt.Printf("  Strategy: %s\n", strategy)
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
@@ -322,3 +351,43 @@ Please verify state and execute lifecycle action.
 		}
 	}
 }
+
+// slungMRStrategy returns the merge strategy gt sling --merge recorded on
+// issueID, or "" if none was set or the issue can't be read.
+func slungMRStrategy(bd *beads.Beads, issueID string) string {
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return ""
+	}
+	if fields := beads.ParseAttachmentFields(issue); fields != nil {
+		return fields.MRStrategy
+	}
+	return ""
+}
+
+// slungTrace returns the trace ID and traceparent gt sling recorded on
+// issueID, or "" for those it has none of or if the issue can't be read.
+func slungTrace(bd *beads.Beads, issueID string) (traceID, traceparent string) {
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return "", ""
+	}
+	if fields := beads.ParseAttachmentFields(issue); fields != nil {
+		return fields.TraceID, fields.Traceparent
+	}
+	return "", ""
+}
+
+// setMRMergeStrategy sets the merge_strategy field on an existing MR bead.
+func setMRMergeStrategy(bd *beads.Beads, mr *beads.Issue, strategy string) error {
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	if fields.MergeStrategy == strategy {
+		return nil
+	}
+	fields.MergeStrategy = strategy
+	newDesc := beads.SetMRFields(mr, fields)
+	return bd.Update(mr.ID, beads.UpdateOptions{Description: &newDesc})
+}
@@ -30,6 +30,7 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 	DoltBranch  string // Dolt branch for write isolation (empty if not created)
 	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
+	Traceparent string // gt sling's span, parent of the session start span (see internal/tracing)
 
 	// Internal fields for deferred session start
 	account string
@@ -268,6 +269,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
 		DoltBranch:       s.DoltBranch,
+		Traceparent:      s.Traceparent,
 	}
 	if s.agent != "" {
 		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
@@ -214,8 +214,9 @@ var refineryBlockedCmd = &cobra.Command{
 	Short: "List MRs blocked by open tasks",
 	Long: `List merge requests blocked by open tasks.
 
-Shows MRs waiting for conflict resolution or other blocking tasks to complete.
-When the blocking task closes, the MR will appear in 'ready'.
+Shows MRs waiting for conflict resolution, review, or other blocking tasks
+to complete. When the blocking task closes, the MR will appear in 'ready'.
+Reviews are recorded with 'gt mq review'.
 
 Examples:
   gt refinery blocked
@@ -850,7 +851,9 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
 		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
-		if mr.BlockedBy != "" {
+		if mr.BlockedBy != "" && mr.BlockedBy == mr.ReviewBead {
+			fmt.Printf("     Awaiting review: %s\n", mr.BlockedBy)
+		} else if mr.BlockedBy != "" {
 			fmt.Printf("     Blocked by: %s\n", mr.BlockedBy)
 		}
 	}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Refinery process command flags
+var (
+	refineryProcessDryRun bool
+	refineryProcessJSON   bool
+)
+
+var refineryProcessCmd = &cobra.Command{
+	Use:   "process [rig]",
+	Short: "Process the next batch of ready MRs in parallel",
+	Long: `Merge up to merge_queue.max_concurrent ready MRs at once.
+
+The highest-scoring ready MRs that change disjoint sets of files (per target
+branch) are picked. Each is merged and gated in its own temporary worktree off
+the rig's shared .repo.git, so long gate runs overlap. Pushes still take turns:
+the refinery's push lock and the merge slot let only one MR land on a target
+at a time. An MR whose target moved while it was gating is re-merged onto the
+new head and re-gated there before it is pushed.
+
+With max_concurrent of 1 (the default), this processes the single best MR in
+the refinery worktree.
+
+Each decided MR is reported to the witness (MERGED or MERGE_FAILED).
+
+Examples:
+  gt refinery process
+  gt refinery process gastown --dry-run
+  gt refinery process gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryProcess,
+}
+
+func init() {
+	refineryProcessCmd.Flags().BoolVar(&refineryProcessDryRun, "dry-run", false, "Show the next batch without processing it")
+	refineryProcessCmd.Flags().BoolVar(&refineryProcessJSON, "json", false, "Output as JSON")
+
+	refineryCmd.AddCommand(refineryProcessCmd)
+}
+
+func runRefineryProcess(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	batch, err := eng.NextBatch(time.Now())
+	if err != nil {
+		return fmt.Errorf("selecting batch: %w", err)
+	}
+	if len(batch) == 0 {
+		if refineryProcessJSON {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Printf("%s No ready MRs for '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	if refineryProcessDryRun {
+		if refineryProcessJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(batch)
+		}
+		fmt.Printf("%s Next batch for '%s' (max_concurrent %d):\n\n", style.Bold.Render("⚙"), rigName, eng.Config().MaxConcurrent)
+		for i, mr := range batch {
+			fmt.Printf("  %d. [P%d] %s (%s → %s)  score %.1f\n", i+1, mr.Priority, mr.ID, mr.Branch, mr.Target, mr.ScoreWith(eng.Config().Scoring, time.Now()))
+		}
+		return nil
+	}
+
+	workerID := getWorkerID()
+	for _, mr := range batch {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			return fmt.Errorf("claiming %s: %w", mr.ID, err)
+		}
+	}
+
+	results := eng.ProcessBatch(context.Background(), batch)
+	return reportRefineryResults(eng, results, fmt.Sprintf("Batch results for '%s'", rigName), refineryProcessJSON)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Refinery train command flags
+var (
+	refineryTrainDryRun bool
+	refineryTrainJSON   bool
+)
+
+var refineryTrainCmd = &cobra.Command{
+	Use:   "train [rig]",
+	Short: "Process the next merge train",
+	Long: `Speculatively merge the top-N ready MRs and gate them together.
+
+Requires merge_queue.train_size > 1 in the rig's config.json. The highest-
+scoring ready MRs sharing a target are stacked on a temporary branch and the
+quality gates run once on the combination. If the gates pass, the whole train
+lands in a single push. If they fail, the train is bisected to find the MR
+that breaks the gates; the passing prefix lands, the culprit fails, and the
+rest stay in the queue for the next train.
+
+Each decided MR is reported to the witness (MERGED or MERGE_FAILED).
+
+Examples:
+  gt refinery train
+  gt refinery train gastown --dry-run
+  gt refinery train gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryTrain,
+}
+
+func init() {
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainDryRun, "dry-run", false, "Show the next train without processing it")
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainJSON, "json", false, "Output as JSON")
+
+	refineryCmd.AddCommand(refineryTrainCmd)
+}
+
+// trainResultJSON is the JSON representation of one MR's train outcome.
+type trainResultJSON struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	Outcome     string `json:"outcome"` // merged | failed | conflict | reverted | deferred
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func runRefineryTrain(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if eng.Config().TrainSize < 2 {
+		return fmt.Errorf("merge trains are disabled for %s (set merge_queue.train_size > 1 in config.json)", rigName)
+	}
+
+	train, err := eng.NextTrain(time.Now())
+	if err != nil {
+		return fmt.Errorf("selecting train: %w", err)
+	}
+	if len(train) == 0 {
+		if refineryTrainJSON {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Printf("%s No ready MRs for '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	if refineryTrainDryRun {
+		if refineryTrainJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(train)
+		}
+		fmt.Printf("%s Next train for '%s' → %s:\n\n", style.Bold.Render("🚆"), rigName, train[0].Target)
+		for i, mr := range train {
+			fmt.Printf("  %d. [P%d] %s (%s)  score %.1f\n", i+1, mr.Priority, mr.ID, mr.Branch, mr.ScoreWith(eng.Config().Scoring, time.Now()))
+		}
+		return nil
+	}
+
+	workerID := getWorkerID()
+	for _, mr := range train {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			return fmt.Errorf("claiming %s: %w", mr.ID, err)
+		}
+	}
+
+	results := eng.ProcessTrain(context.Background(), train)
+	return reportRefineryResults(eng, results, fmt.Sprintf("Train results for '%s'", rigName), refineryTrainJSON)
+}
+
+// reportRefineryResults applies train or batch results through
+// HandleTrainResults, returns undecided and failed MRs to the queue, and
+// prints the outcome of each MR.
+func reportRefineryResults(eng *refinery.Engineer, results []refinery.TrainResult, title string, asJSON bool) error {
+	eng.HandleTrainResults(results)
+
+	out := make([]trainResultJSON, 0, len(results))
+	for _, tr := range results {
+		row := trainResultJSON{ID: tr.MR.ID, Branch: tr.MR.Branch, MergeCommit: tr.Result.MergeCommit, Error: tr.Result.Error}
+		switch {
+		case tr.Deferred:
+			row.Outcome = "deferred"
+		case tr.Result.Success:
+			row.Outcome = "merged"
+		case tr.Result.PostMergeFailed:
+			row.Outcome = "reverted"
+		case tr.Result.Frozen:
+			row.Outcome = "frozen"
+		case tr.Result.ReviewPending:
+			row.Outcome = "review"
+		case tr.Result.ReviewRejected:
+			row.Outcome = "rejected"
+		case tr.Result.Conflict:
+			row.Outcome = "conflict"
+		default:
+			row.Outcome = "failed"
+		}
+		// Anything that didn't land goes back to the queue for the next pass.
+		// Reverted and rejected MRs were closed by HandleTrainResults.
+		if !tr.Result.Success && !tr.Result.PostMergeFailed && !tr.Result.ReviewRejected {
+			if err := eng.ReleaseMR(tr.MR.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: releasing %s: %v\n", tr.MR.ID, err)
+			}
+		}
+		out = append(out, row)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("\n%s %s:\n\n", style.Bold.Render("🚆"), title)
+	for _, row := range out {
+		line := fmt.Sprintf("  %-9s %s (%s)", row.Outcome, row.ID, row.Branch)
+		if row.Error != "" {
+			line += style.Dim.Render(" - " + row.Error)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
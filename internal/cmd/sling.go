@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -44,6 +45,13 @@ Merge Strategy (--merge):
   gt sling gt-abc gastown --merge=mr      # Merge queue (default)
   gt sling gt-abc gastown --merge=local   # Keep on feature branch
 
+  A merge queue strategy sets how the refinery lands the MR, overriding the
+  rig's merge_queue.merge_strategy. Stored on the issue and copied to the MR.
+  gt sling gt-abc gastown --merge=squash  # One commit (usual default)
+  gt sling gt-abc gastown --merge=merge   # Merge commit
+  gt sling gt-abc gastown --merge=rebase  # Rebase and fast-forward (linear)
+  gt sling gt-abc gastown --merge=ff      # Fast-forward only
+
 Target Resolution:
   gt sling gt-abc                       # Self (current agent)
   gt sling gt-abc crew                  # Crew worker in current rig
@@ -120,7 +128,7 @@ var (
 	slingNoConvoy      bool   // --no-convoy: skip auto-convoy creation
 	slingOwned         bool   // --owned: mark auto-convoy as caller-managed lifecycle
 	slingNoMerge       bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
-	slingMerge         string // --merge: merge strategy for convoy (direct/mr/local)
+	slingMerge         string // --merge: convoy merge route (direct/mr/local) or MR strategy (squash/merge/rebase/ff)
 	slingNoBoot        bool   // --no-boot: skip wakeRigAgents (avoid witness/refinery boot and lock contention)
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
@@ -144,7 +152,7 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingOwned, "owned", false, "Mark auto-convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
-	slingCmd.Flags().StringVar(&slingMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	slingCmd.Flags().StringVar(&slingMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch), or a merge queue strategy (squash, merge, rebase, ff)")
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip rig boot after polecat spawn (avoids witness/refinery lock contention)")
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
@@ -152,7 +160,7 @@ func init() {
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	// Polecats cannot sling - check early before writing anything.
 	// Check GT_ROLE first: coordinators (mayor, witness, etc.) may have a stale
 	// GT_POLECAT in their environment from spawning polecats. Only block if the
@@ -169,11 +177,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Validate --merge flag if provided
 	if slingMerge != "" {
-		switch slingMerge {
-		case "direct", "mr", "local":
-			// Valid
+		switch {
+		case slingMerge == "direct", slingMerge == "mr", slingMerge == "local":
+			// Valid convoy route
+		case config.IsValidMergeStrategy(slingMerge):
+			// Valid merge queue strategy (implies mr)
 		default:
-			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", slingMerge)
+			return fmt.Errorf("invalid --merge value %q: must be direct, mr, local, or one of %s",
+				slingMerge, strings.Join(config.MergeStrategies, ", "))
 		}
 	}
 
@@ -286,6 +297,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Trace the sling through polecat spawn, gt done, and merge
+	slingSpan := startSlingSpan(townRoot, beadID)
+	defer func() { slingSpan.End(retErr) }()
+
 	// Check if bead is already assigned (guard against accidental re-sling).
 	// This must happen before resolveTarget(), since rig targets can spawn/hook a new polecat as a side-effect.
 	info, err := getBeadInfo(beadID)
@@ -470,26 +485,28 @@ func runSling(cmd *cobra.Command, args []string) error {
 			if slingDryRun {
 				fmt.Printf("Would create convoy 'Work: %s'\n", info.Title)
 				fmt.Printf("Would add tracking relation to %s\n", beadID)
-				if slingMerge != "" {
-					fmt.Printf("Would set convoy merge strategy: %s\n", slingMerge)
+				if route := slingMergeRoute(); route != "" {
+					fmt.Printf("Would set convoy merge strategy: %s\n", route)
 				}
 			} else {
-				convoyID, err := createAutoConvoy(beadID, info.Title, slingOwned, slingMerge)
+				convoyID, err := createAutoConvoy(beadID, info.Title, slingOwned, slingMergeRoute())
 				if err != nil {
 					// Log warning but don't fail - convoy is optional
 					fmt.Printf("%s Could not create auto-convoy: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
+					traceConvoy(slingSpan, convoyID)
 					fmt.Printf("%s Created convoy 🚚 %s\n", style.Bold.Render("→"), convoyID)
 					fmt.Printf("  Tracking: %s\n", beadID)
 					if slingOwned {
 						fmt.Printf("  Lifecycle: caller-managed (owned)\n")
 					}
-					if slingMerge != "" {
-						fmt.Printf("  Merge:    %s\n", slingMerge)
+					if route := slingMergeRoute(); route != "" {
+						fmt.Printf("  Merge:    %s\n", route)
 					}
 				}
 			}
 		} else {
+			traceConvoy(slingSpan, existingConvoy)
 			fmt.Printf("%s Already tracked by convoy %s\n", style.Dim.Render("○"), existingConvoy)
 		}
 	}
@@ -606,9 +623,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Work attached to hook (status=hooked)\n", style.Bold.Render("✓"))
 
-	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -626,8 +641,12 @@ func runSling(cmd *cobra.Command, args []string) error {
 		Args:             slingArgs,
 		AttachedMolecule: attachedMoleculeID,
 		NoMerge:          slingNoMerge,
+		MRStrategy:       slingMRStrategy(),
+		Span:             slingSpan,
 	}
-	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
+	slingSpan.SetAttr("gt.target", targetAgent)
+	traceID, err := storeFieldsInBead(beadID, fieldUpdates)
+	if err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 	} else {
@@ -637,8 +656,15 @@ func runSling(cmd *cobra.Command, args []string) error {
 		if slingNoMerge {
 			fmt.Printf("%s No-merge mode enabled (work stays on feature branch)\n", style.Bold.Render("✓"))
 		}
+		if strategy := slingMRStrategy(); strategy != "" {
+			fmt.Printf("%s Merge strategy: %s\n", style.Bold.Render("✓"), strategy)
+		}
+		fmt.Printf("%s Trace: %s\n", style.Bold.Render("✓"), traceID)
 	}
 
+	// Log sling event to activity feed, with the trace ID gt trace follows
+	_ = events.LogFeed(events.TypeSling, actor, events.WithTrace(events.SlingPayload(beadID, targetAgent), traceID))
+
 	// Start delayed dog session now that hook is set
 	// This ensures dog sees the hook when gt prime runs on session start
 	if delayedDogInfo != nil {
@@ -663,6 +689,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// This ensures polecat sees the molecule when gt prime runs on session start.
 	freshlySpawned := newPolecatInfo != nil
 	if freshlySpawned {
+		newPolecatInfo.Traceparent = slingSpan.Traceparent()
 		pane, err := newPolecatInfo.StartSession()
 		if err != nil {
 			// Rollback: session failed, clean up zombie artifacts (worktree, hooked bead).
@@ -132,6 +132,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
+		// Trace this bead's sling through polecat spawn, gt done, and merge
+		slingSpan := startSlingSpan(townRoot, beadID)
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:      slingForce,
@@ -145,23 +148,27 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		if err != nil {
 			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
 			fmt.Printf("  %s Failed to spawn polecat: %v\n", style.Dim.Render("✗"), err)
+			slingSpan.End(err)
 			continue
 		}
 
 		targetAgent := spawnInfo.AgentID()
 		hookWorkDir := spawnInfo.ClonePath
+		slingSpan.SetAttr("gt.target", targetAgent)
 
 		// Auto-convoy: check if issue is already tracked
 		if !slingNoConvoy {
 			existingConvoy := isTrackedByConvoy(beadID)
 			if existingConvoy == "" {
-				convoyID, err := createAutoConvoy(beadID, info.Title, slingOwned, slingMerge)
+				convoyID, err := createAutoConvoy(beadID, info.Title, slingOwned, slingMergeRoute())
 				if err != nil {
 					fmt.Printf("  %s Could not create auto-convoy: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
+					traceConvoy(slingSpan, convoyID)
 					fmt.Printf("  %s Created convoy 🚚 %s\n", style.Bold.Render("→"), convoyID)
 				}
 			} else {
+				traceConvoy(slingSpan, existingConvoy)
 				fmt.Printf("  %s Already tracked by convoy %s\n", style.Dim.Render("○"), existingConvoy)
 			}
 		}
@@ -207,14 +214,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  %s Failed to hook bead: %v\n", style.Dim.Render("✗"), err)
 			// Clean up orphaned polecat to avoid leaving spawned-but-unhookable polecats
 			cleanupSpawnedPolecat(spawnInfo, rigName)
+			slingSpan.End(err)
 			continue
 		}
 
 		fmt.Printf("  %s Work attached to %s\n", style.Bold.Render("✓"), spawnInfo.PolecatName)
 
-		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -227,12 +233,18 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Args:             slingArgs,
 			AttachedMolecule: attachedMoleculeID,
 			NoMerge:          slingNoMerge,
+			MRStrategy:       slingMRStrategy(),
+			Span:             slingSpan,
 		}
 		// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
-		if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
+		traceID, err := storeFieldsInBead(beadToHook, fieldUpdates)
+		if err != nil {
 			fmt.Printf("  %s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 		}
 
+		// Log sling event
+		_ = events.LogFeed(events.TypeSling, actor, events.WithTrace(events.SlingPayload(beadToHook, targetAgent), traceID))
+
 		// Create Dolt branch AFTER all sling writes are complete.
 		// CommitWorkingSet flushes working set to HEAD, then CreatePolecatBranch
 		// forks from HEAD — ensuring the polecat's branch includes all writes.
@@ -241,17 +253,20 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 				fmt.Printf("  %s Could not create Dolt branch: %v, cleaning up...\n", style.Dim.Render("✗"), err)
 				rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
 				results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false})
+				slingSpan.End(err)
 				continue
 			}
 		}
 
 		// Start polecat session now that molecule/bead is attached.
 		// This ensures polecat sees its work when gt prime runs on session start.
+		spawnInfo.Traceparent = slingSpan.Traceparent()
 		pane, err := spawnInfo.StartSession()
 		if err != nil {
 			fmt.Printf("  %s Could not start session: %v, cleaning up partial state...\n", style.Dim.Render("✗"), err)
 			rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
 			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false})
+			slingSpan.End(err)
 			continue
 		} else {
 			fmt.Printf("  %s Session started for %s\n", style.Bold.Render("▶"), spawnInfo.PolecatName)
@@ -262,6 +277,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		activeCount++
 		results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: true})
+		slingSpan.End(nil)
 
 		// Delay between spawns to prevent Dolt lock contention — sequential
 		// spawns without delay cause database lock timeouts when multiple bd
@@ -12,6 +12,9 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -198,6 +201,44 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 	return info
 }
 
+// getConvoySchedule returns a convoy's creation time and deadline, for
+// merge queue scoring. Either is empty if unknown.
+func getConvoySchedule(townRoot, convoyID string) (createdAt, deadline string) {
+	showCmd := exec.Command("bd", "show", convoyID, "--json")
+	showCmd.Dir = filepath.Join(townRoot, ".beads")
+	var stdout bytes.Buffer
+	showCmd.Stdout = &stdout
+	if err := showCmd.Run(); err != nil {
+		return "", ""
+	}
+
+	var convoys []struct {
+		CreatedAt   string `json:"created_at"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+		return "", ""
+	}
+	return convoys[0].CreatedAt, parseConvoyDeadline(convoys[0].Description)
+}
+
+// scoringLabels returns the labels that carry a merge queue scoring weight
+// in the rig at rigPath, so gt done can copy them from the source issue to
+// its MR.
+func scoringLabels(rigPath string, labels []string) []string {
+	scoring, err := refinery.LoadScoreConfig(rigPath)
+	if err != nil {
+		return nil
+	}
+	var weighted []string
+	for _, label := range labels {
+		if scoring.LabelWeights[label] != 0 {
+			weighted = append(weighted, label)
+		}
+	}
+	return weighted
+}
+
 // getConvoyInfoFromIssue reads convoy info directly from the issue's attachment fields.
 // This is the primary lookup method (gt-7b6wf fix): gt sling stores convoy_id and
 // merge_strategy on the issue when dispatching, avoiding unreliable cross-rig dep
@@ -225,6 +266,26 @@ func getConvoyInfoFromIssue(issueID, cwd string) *ConvoyInfo {
 	}
 }
 
+// slingMergeRoute returns the convoy merge route selected by --merge:
+// "direct", "mr", "local", or "" (default). Merge queue strategies
+// (squash, merge, rebase, ff) only make sense through the queue, so they
+// leave the route at its mr default.
+func slingMergeRoute() string {
+	if config.IsValidMergeStrategy(slingMerge) {
+		return ""
+	}
+	return slingMerge
+}
+
+// slingMRStrategy returns the merge queue strategy selected by --merge, or
+// "" when --merge names a convoy route or is unset.
+func slingMRStrategy() string {
+	if config.IsValidMergeStrategy(slingMerge) {
+		return slingMerge
+	}
+	return ""
+}
+
 // createAutoConvoy creates an auto-convoy for a single issue and tracks it.
 // If owned is true, the convoy is marked with the gt:owned label for caller-managed lifecycle.
 // mergeStrategy is optional: "direct", "mr", or "local" (empty = default mr).
@@ -246,6 +307,11 @@ func createAutoConvoy(beadID, beadTitle string, owned bool, mergeStrategy string
 	// The hq-cv- prefix is registered in routes during gt install
 	convoyID := fmt.Sprintf("hq-cv-%s", slingGenerateShortID())
 
+	// Root span of the convoy's trace: the sling is traced as its child.
+	// Only exported if the convoy is created.
+	convoySpan := tracing.New(townRoot).StartConvoy(convoyID)
+	convoySpan.SetAttr("gt.bead", beadID)
+
 	// Create convoy with title "Work: <issue-title>"
 	convoyTitle := fmt.Sprintf("Work: %s", beadTitle)
 	description := fmt.Sprintf("Auto-created convoy tracking %s", beadID)
@@ -291,5 +357,6 @@ func createAutoConvoy(beadID, beadTitle string, owned bool, mergeStrategy string
 		return "", fmt.Errorf("adding tracking relation for %s: %w", beadID, err)
 	}
 
+	convoySpan.End(nil)
 	return convoyID, nil
 }
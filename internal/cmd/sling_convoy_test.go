@@ -141,3 +141,28 @@ echo '[{"id":"gt-other1"},{"id":"external:gt-abc:gt-abc123"},{"id":"gt-other2"}]
 		t.Error("convoyTracksBead should return true when bead found among multiple deps")
 	}
 }
+
+func TestSlingMergeSplitsRouteAndStrategy(t *testing.T) {
+	prev := slingMerge
+	t.Cleanup(func() { slingMerge = prev })
+
+	tests := []struct {
+		merge, route, strategy string
+	}{
+		{"", "", ""},
+		{"direct", "direct", ""},
+		{"local", "local", ""},
+		{"mr", "mr", ""},
+		{"rebase", "", "rebase"},
+		{"ff", "", "ff"},
+	}
+	for _, tt := range tests {
+		slingMerge = tt.merge
+		if got := slingMergeRoute(); got != tt.route {
+			t.Errorf("--merge=%q: route = %q, want %q", tt.merge, got, tt.route)
+		}
+		if got := slingMRStrategy(); got != tt.strategy {
+			t.Errorf("--merge=%q: strategy = %q, want %q", tt.merge, got, tt.strategy)
+		}
+	}
+}
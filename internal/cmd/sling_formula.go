@@ -72,7 +72,7 @@ func verifyFormulaExists(formulaName string) error {
 
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
-func runSlingFormula(args []string) error {
+func runSlingFormula(args []string) (retErr error) {
 	formulaName := args[0]
 
 	// Get town root early - needed for BEADS_DIR when running bd commands
@@ -126,6 +126,12 @@ func runSlingFormula(args []string) error {
 		return nil
 	}
 
+	// Trace the sling through polecat spawn, gt done, and merge
+	slingSpan := startSlingSpan(townRoot, "")
+	slingSpan.SetAttr("gt.formula", formulaName)
+	slingSpan.SetAttr("gt.target", targetAgent)
+	defer func() { slingSpan.End(retErr) }()
+
 	// Resolve working directory for bd commands (routes to correct rig beads)
 	// Fall back to townRoot (HQ beads) if no specific rig directory was determined
 	if formulaWorkDir == "" {
@@ -168,6 +174,7 @@ func runSlingFormula(args []string) error {
 	}
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
+	slingSpan.SetAttr("gt.bead", wispRootID)
 
 	// Step 3: Hook the wisp bead with retry and verification.
 	// See: https://github.com/steveyegge/gastown/issues/148
@@ -177,11 +184,7 @@ func runSlingFormula(args []string) error {
 	}
 	fmt.Printf("%s Attached to hook (status=hooked)\n", style.Bold.Render("✓"))
 
-	// Log sling event to activity feed (formula slinging)
 	actor := detectActor()
-	payload := events.SlingPayload(wispRootID, targetAgent)
-	payload["formula"] = formulaName
-	_ = events.LogFeed(events.TypeSling, actor, payload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
@@ -195,13 +198,20 @@ func runSlingFormula(args []string) error {
 	fieldUpdates := beadFieldUpdates{
 		Dispatcher: actor,
 		Args:       slingArgs,
+		Span:       slingSpan,
 	}
-	if err := storeFieldsInBead(wispRootID, fieldUpdates); err != nil {
+	traceID, err := storeFieldsInBead(wispRootID, fieldUpdates)
+	if err != nil {
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 	} else if slingArgs != "" {
 		fmt.Printf("%s Args stored in bead (durable)\n", style.Bold.Render("✓"))
 	}
 
+	// Log sling event to activity feed (formula slinging)
+	payload := events.WithTrace(events.SlingPayload(wispRootID, targetAgent), traceID)
+	payload["formula"] = formulaName
+	_ = events.LogFeed(events.TypeSling, actor, payload)
+
 	// Start delayed dog session now that hook is set
 	// This ensures dog sees the hook when gt prime runs on session start
 	if delayedDogInfo != nil {
@@ -226,6 +236,7 @@ func runSlingFormula(args []string) error {
 	// Start spawned polecat session now that hook is set.
 	// This ensures polecat sees the wisp when gt prime runs on session start.
 	if resolved.NewPolecatInfo != nil {
+		resolved.NewPolecatInfo.Traceparent = slingSpan.Traceparent()
 		pane, err := resolved.NewPolecatInfo.StartSession()
 		if err != nil {
 			// Rollback: unhook wisp, delete Dolt branch, clean up polecat worktree/agent bead
@@ -16,9 +16,11 @@ import (
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tracing"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -236,13 +238,21 @@ type beadFieldUpdates struct {
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
 	ConvoyOwned      bool   // Convoy has gt:owned label (caller-managed lifecycle)
+	MRStrategy       string // Merge queue strategy for the MR: "squash", "merge", "rebase", "ff"
+
+	// Span is gt sling's span, recorded as the bead's traceparent
+	Span *tracing.Span
 }
 
 // storeFieldsInBead performs a single read-modify-write to update all attachment fields
 // in a bead's description atomically. This replaces the sequential storeDispatcherInBead,
 // storeArgsInBead, storeAttachedMoleculeInBead, and storeNoMergeInBead calls that each
 // independently read-modify-write and could race under concurrent access.
-func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
+//
+// It also gives the bead a trace ID if it has none, keeping the existing one when
+// work is re-slung, and returns it (see gt trace). If updates.Span is set, it is
+// linked into the work's OpenTelemetry trace and recorded as the traceparent.
+func storeFieldsInBead(beadID string, updates beadFieldUpdates) (string, error) {
 	logPath := os.Getenv("GT_TEST_ATTACHED_MOLECULE_LOG")
 
 	issue := &beads.Issue{}
@@ -252,18 +262,18 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 		showCmd.Dir = resolveBeadDir(beadID)
 		out, err := showCmd.Output()
 		if err != nil {
-			return fmt.Errorf("fetching bead: %w", err)
+			return "", fmt.Errorf("fetching bead: %w", err)
 		}
 		if len(out) == 0 {
-			return fmt.Errorf("bead not found")
+			return "", fmt.Errorf("bead not found")
 		}
 
 		var issues []beads.Issue
 		if err := json.Unmarshal(out, &issues); err != nil {
-			return fmt.Errorf("parsing bead: %w", err)
+			return "", fmt.Errorf("parsing bead: %w", err)
 		}
 		if len(issues) == 0 {
-			return fmt.Errorf("bead not found")
+			return "", fmt.Errorf("bead not found")
 		}
 		issue = &issues[0]
 	}
@@ -302,22 +312,29 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.ConvoyOwned {
 		fields.ConvoyOwned = true
 	}
+	if updates.MRStrategy != "" {
+		fields.MRStrategy = updates.MRStrategy
+	}
+	if fields.TraceID == "" {
+		fields.TraceID = events.NewTraceID()
+	}
+	linkSlingSpan(updates.Span, fields)
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
 	if logPath != "" {
 		_ = os.WriteFile(logPath, []byte(newDesc), 0644)
-		return nil
+		return fields.TraceID, nil
 	}
 
 	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
 	updateCmd.Dir = resolveBeadDir(beadID)
 	updateCmd.Stderr = os.Stderr
 	if err := updateCmd.Run(); err != nil {
-		return fmt.Errorf("updating bead description: %w", err)
+		return "", fmt.Errorf("updating bead description: %w", err)
 	}
 
-	return nil
+	return fields.TraceID, nil
 }
 
 // injectStartPrompt sends a prompt to the target pane to start working.
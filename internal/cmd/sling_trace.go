@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/tracing"
+)
+
+// startSlingSpan starts the OpenTelemetry span for slinging beadID, or
+// returns nil for dry runs. storeFieldsInBead places it in the work's trace
+// and records it on the hook bead, where the polecat's session start, gt
+// done, and the refinery pick it up as their parent.
+func startSlingSpan(townRoot, beadID string) *tracing.Span {
+	if slingDryRun {
+		return nil
+	}
+	span := tracing.New(townRoot).Start("gt sling", tracing.SpanContext{})
+	span.SetAttr("gt.bead", beadID)
+	return span
+}
+
+// traceConvoy makes span a child of the root span of convoyID's trace, so
+// all the convoy's work renders as one trace tree.
+func traceConvoy(span *tracing.Span, convoyID string) {
+	if convoyID == "" {
+		return
+	}
+	span.SetParent(tracing.ConvoyContext(convoyID))
+	span.SetAttr("gt.convoy", convoyID)
+}
+
+// linkSlingSpan places gt sling's span in the trace of the work in fields
+// and records it as the bead's traceparent. Work outside a convoy is traced
+// under an ID derived from its gt trace ID, so a re-sling continues the
+// same trace.
+func linkSlingSpan(span *tracing.Span, fields *beads.AttachmentFields) {
+	if span == nil {
+		return
+	}
+	if fields.ConvoyID != "" {
+		traceConvoy(span, fields.ConvoyID)
+	} else if !span.HasParent() {
+		span.SetParent(tracing.SpanContext{TraceID: tracing.TraceIDFor(fields.TraceID)})
+	}
+	span.SetAttr("gt.trace_id", fields.TraceID)
+	fields.Traceparent = span.Traceparent()
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var traceJSON bool
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <bead-id>",
+	GroupID: GroupDiag,
+	Short:   "Show the timeline of one unit of work",
+	Long: `Show the full timeline of one unit of work, across every agent that touched it.
+
+gt sling gives each piece of work a trace ID (tr-xxxxxxxx), stored on the
+hook bead. gt done copies it to the MR bead and the POLECAT_DONE mail; the
+witness and refinery carry it in MERGE_READY, MERGED, and MERGE_FAILED, and
+sling, done, and merge events record it in .events.jsonl.
+
+gt trace takes the work bead, its MR bead, or the trace ID itself, and
+merges all of those into one ordered timeline with the time between hops:
+
+  sling → spawn → done → MERGE_READY → MERGED → convoy close
+
+Examples:
+  gt trace gt-abc12
+  gt trace gt-mr-xyz9
+  gt trace tr-1a2b3c4d --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(traceCmd)
+}
+
+// TraceEntry is one hop in a unit of work's timeline.
+type TraceEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"` // "events", "mail", "beads"
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor,omitempty"`
+	Summary   string    `json:"summary"`
+	ID        string    `json:"id,omitempty"` // bead or message ID
+
+	// SincePrevious is the time since the previous entry.
+	SincePrevious time.Duration `json:"since_previous"`
+}
+
+// Trace is a unit of work's timeline.
+type Trace struct {
+	TraceID string        `json:"trace_id"`
+	Bead    string        `json:"bead,omitempty"`
+	Entries []*TraceEntry `json:"entries"`
+
+	// Duration is the time from the first entry to the last.
+	Duration time.Duration `json:"duration"`
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	trace := &Trace{}
+	var work *beads.Issue
+	if strings.HasPrefix(args[0], "tr-") {
+		trace.TraceID = args[0]
+	} else {
+		bd := beads.New(resolveBeadDir(args[0]))
+		issue, err := bd.Show(args[0])
+		if err != nil {
+			return fmt.Errorf("showing %s: %w", args[0], err)
+		}
+		if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.TraceID != "" {
+			trace.TraceID = fields.TraceID
+			work = issue
+		} else if fields := beads.ParseMRFields(issue); fields != nil && fields.TraceID != "" {
+			trace.TraceID = fields.TraceID
+			if fields.SourceIssue != "" {
+				work, _ = bd.Show(fields.SourceIssue)
+			}
+		} else {
+			return fmt.Errorf("%s has no trace ID (only work slung with gt sling is traced)", args[0])
+		}
+	}
+
+	var entries []*TraceEntry
+	evs, err := traceEventEntries(filepath.Join(townRoot, events.EventsFile), trace.TraceID)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	entries = append(entries, evs...)
+
+	msgs, err := mail.OpenSearchIndex(townRoot).Messages(func(msg *mail.Message) bool {
+		return protocol.ParseTraceID(msg.Body) == trace.TraceID
+	})
+	if err != nil {
+		return fmt.Errorf("reading mail index: %w", err)
+	}
+	entries = append(entries, traceMailEntries(msgs)...)
+
+	if work != nil {
+		trace.Bead = work.ID
+		entries = append(entries, traceBeadEntries(work)...)
+	}
+
+	trace.Entries, trace.Duration = buildTraceTimeline(entries)
+
+	if traceJSON {
+		if trace.Entries == nil {
+			trace.Entries = []*TraceEntry{}
+		}
+		return outputJSON(trace)
+	}
+	printTrace(trace)
+	return nil
+}
+
+// traceEventEntries returns the events in the events log at path that
+// carry traceID, plus the spawn of each polecat the work was slung to.
+func traceEventEntries(path, traceID string) ([]*TraceEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // No events file yet
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	// Spawns are logged before gt sling has minted the trace ID, so they
+	// are matched to the sling that follows them by rig and polecat.
+	var entries []*TraceEntry
+	lastSpawn := make(map[string]*TraceEntry)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		entry := &TraceEntry{
+			Timestamp: ts,
+			Source:    "events",
+			Type:      e.Type,
+			Actor:     e.Actor,
+			Summary:   formatFeedSummary(e),
+		}
+
+		if e.Type == events.TypeSpawn {
+			rig, _ := e.Payload["rig"].(string)
+			polecat, _ := e.Payload["polecat"].(string)
+			entry.Summary = fmt.Sprintf("Spawned polecat %s", polecat)
+			lastSpawn[rig+"/polecats/"+polecat] = entry
+			continue
+		}
+		if e.TraceID() != traceID {
+			continue
+		}
+		if e.Type == events.TypeSling {
+			target, _ := e.Payload["target"].(string)
+			entry.Summary = fmt.Sprintf("%s → %s", entry.Summary, target)
+			if spawn := lastSpawn[target]; spawn != nil {
+				entries = append(entries, spawn)
+				delete(lastSpawn, target)
+			}
+		}
+		if id, ok := e.Payload["mr"].(string); ok {
+			entry.ID = id
+		} else if id, ok := e.Payload["bead"].(string); ok {
+			entry.ID = id
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// traceMailEntries returns an entry for each traced message.
+func traceMailEntries(msgs []*mail.Message) []*TraceEntry {
+	var entries []*TraceEntry
+	for _, msg := range msgs {
+		msgType := string(protocol.ParseMessageType(msg.Subject))
+		if msgType == "" {
+			msgType = "mail"
+		}
+		entries = append(entries, &TraceEntry{
+			Timestamp: msg.Timestamp,
+			Source:    "mail",
+			Type:      msgType,
+			Actor:     msg.From,
+			Summary:   fmt.Sprintf("%s → %s", msg.Subject, msg.To),
+			ID:        msg.ID,
+		})
+	}
+	return entries
+}
+
+// traceBeadEntries returns the work bead's closing and, if it belongs to a
+// convoy, the convoy's.
+func traceBeadEntries(work *beads.Issue) []*TraceEntry {
+	var entries []*TraceEntry
+	if entry := closedEntry(work, "Closed %s"); entry != nil {
+		entries = append(entries, entry)
+	}
+	fields := beads.ParseAttachmentFields(work)
+	if fields == nil || fields.ConvoyID == "" {
+		return entries
+	}
+	convoy, err := beads.New(resolveBeadDir(fields.ConvoyID)).Show(fields.ConvoyID)
+	if err != nil {
+		return entries
+	}
+	if entry := closedEntry(convoy, "Convoy %s closed"); entry != nil {
+		entry.Type = "convoy_closed"
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// closedEntry returns an entry for issue's closing, or nil if it's open.
+func closedEntry(issue *beads.Issue, format string) *TraceEntry {
+	if issue.Status != "closed" || issue.ClosedAt == "" {
+		return nil
+	}
+	ts, err := time.Parse(time.RFC3339, issue.ClosedAt)
+	if err != nil {
+		return nil
+	}
+	summary := fmt.Sprintf(format, issue.ID)
+	if issue.CloseReason != "" {
+		summary += ": " + issue.CloseReason
+	}
+	return &TraceEntry{
+		Timestamp: ts,
+		Source:    "beads",
+		Type:      "bead_closed",
+		Summary:   summary,
+		ID:        issue.ID,
+	}
+}
+
+// buildTraceTimeline orders entries by time, sets the time between hops,
+// and returns them with the total duration.
+func buildTraceTimeline(entries []*TraceEntry) ([]*TraceEntry, time.Duration) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	for i, e := range entries {
+		e.SincePrevious = 0
+		if i > 0 {
+			e.SincePrevious = e.Timestamp.Sub(entries[i-1].Timestamp)
+		}
+	}
+	if len(entries) < 2 {
+		return entries, 0
+	}
+	return entries, entries[len(entries)-1].Timestamp.Sub(entries[0].Timestamp)
+}
+
+func printTrace(trace *Trace) {
+	header := trace.TraceID
+	if trace.Bead != "" {
+		header = fmt.Sprintf("%s (%s)", trace.TraceID, trace.Bead)
+	}
+	fmt.Printf("%s Trace %s\n\n", style.Bold.Render("⛓"), header)
+	if len(trace.Entries) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no recorded hops)"))
+		return
+	}
+
+	for i, e := range trace.Entries {
+		delta := ""
+		if i > 0 {
+			delta = "+" + e.SincePrevious.Round(time.Second).String()
+		}
+		actor := ""
+		if e.Actor != "" {
+			actor = style.Dim.Render(" [" + e.Actor + "]")
+		}
+		fmt.Printf("  %s %s  %-14s %s%s\n",
+			style.Dim.Render(e.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			style.Dim.Render(fmt.Sprintf("%8s", delta)),
+			e.Type,
+			e.Summary,
+			actor,
+		)
+	}
+	fmt.Printf("\n  %d hops over %s\n", len(trace.Entries), trace.Duration.Round(time.Second))
+}
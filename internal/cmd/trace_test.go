@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestTraceEventEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".events.jsonl")
+	lines := []string{
+		`{"ts":"2026-03-01T12:00:00Z","type":"spawn","actor":"gt","payload":{"rig":"gastown","polecat":"nux"}}`,
+		`{"ts":"2026-03-01T12:00:05Z","type":"spawn","actor":"gt","payload":{"rig":"gastown","polecat":"ace"}}`,
+		`{"ts":"2026-03-01T12:00:10Z","type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/polecats/nux","trace_id":"tr-1"}}`,
+		`{"ts":"2026-03-01T12:00:12Z","type":"sling","actor":"mayor","payload":{"bead":"gt-def","target":"gastown/polecats/ace","trace_id":"tr-2"}}`,
+		`not json`,
+		`{"ts":"2026-03-01T12:20:00Z","type":"done","actor":"gastown/polecats/nux","payload":{"bead":"gt-abc","branch":"polecat/nux","trace_id":"tr-1"}}`,
+		`{"ts":"2026-03-01T12:30:00Z","type":"merged","actor":"gastown/refinery","payload":{"mr":"gt-mr1","worker":"nux","branch":"polecat/nux","trace_id":"tr-1"}}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := traceEventEntries(path, "tr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Type+":"+e.ID)
+	}
+	want := []string{"spawn:", "sling:gt-abc", "done:gt-abc", "merged:gt-mr1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+	if entries[0].Summary != "Spawned polecat nux" {
+		t.Errorf("spawn summary = %q", entries[0].Summary)
+	}
+
+	if entries, err := traceEventEntries(filepath.Join(t.TempDir(), "missing"), "tr-1"); err != nil || entries != nil {
+		t.Errorf("missing events file: %v, %v", entries, err)
+	}
+}
+
+func TestBuildTraceTimeline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	msgs := []*mail.Message{
+		{ID: "msg-1", From: "gastown/polecats/nux", To: "gastown/witness", Subject: "POLECAT_DONE nux", Timestamp: start.Add(20 * time.Minute)},
+		{ID: "msg-2", From: "gastown/witness", To: "gastown/refinery", Subject: "MERGE_READY nux", Timestamp: start.Add(21 * time.Minute)},
+	}
+	entries := append(traceMailEntries(msgs),
+		&TraceEntry{Timestamp: start.Add(30 * time.Minute), Type: "merged"},
+		&TraceEntry{Timestamp: start, Type: "sling"},
+	)
+
+	timeline, total := buildTraceTimeline(entries)
+	var types []string
+	for _, e := range timeline {
+		types = append(types, e.Type)
+	}
+	if strings.Join(types, " ") != "sling POLECAT_DONE MERGE_READY merged" {
+		t.Errorf("order = %v", types)
+	}
+	if timeline[0].SincePrevious != 0 || timeline[2].SincePrevious != time.Minute || timeline[3].SincePrevious != 9*time.Minute {
+		t.Errorf("hop durations = %v, %v, %v", timeline[0].SincePrevious, timeline[2].SincePrevious, timeline[3].SincePrevious)
+	}
+	if total != 30*time.Minute {
+		t.Errorf("total = %v, want 30m", total)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -225,6 +228,9 @@ func validateRigSettings(c *RigSettings) error {
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeStrategy indicates an invalid merge_strategy.
+var ErrInvalidMergeStrategy = errors.New("invalid merge_strategy")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -233,6 +239,12 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	// Validate merge_strategy
+	if c.MergeStrategy != "" && !IsValidMergeStrategy(c.MergeStrategy) {
+		return fmt.Errorf("%w: got '%s', want one of %s",
+			ErrInvalidMergeStrategy, c.MergeStrategy, strings.Join(MergeStrategies, ", "))
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
@@ -259,6 +271,121 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	if c.Scoring != nil {
+		if err := validateScoringConfig(c.Scoring); err != nil {
+			return fmt.Errorf("scoring: %w", err)
+		}
+	}
+
+	if c.Review != nil {
+		for owner, addr := range c.Review.OwnerMap {
+			if owner == "" || addr == "" {
+				return fmt.Errorf("review: owner_map entries need an owner and an address, got %q: %q", owner, addr)
+			}
+		}
+	}
+
+	for i := range c.FreezeWindows {
+		if err := validateFreezeWindow(&c.FreezeWindows[i]); err != nil {
+			return fmt.Errorf("freeze_windows[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFreezeWindow validates a merge freeze window: one-off windows need
+// an end after their start, recurring ones valid days, times, and timezone.
+func validateFreezeWindow(w *FreezeWindow) error {
+	for _, b := range w.Branches {
+		if _, err := path.Match(b, ""); b == "" || err != nil {
+			return fmt.Errorf("invalid branch pattern %q", b)
+		}
+	}
+
+	if !w.Recurring() {
+		if w.End == "" {
+			return fmt.Errorf("%w: end (or days/from/to for a recurring window)", ErrMissingField)
+		}
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return fmt.Errorf("invalid end: %w", err)
+		}
+		if w.Start != "" {
+			start, err := time.Parse(time.RFC3339, w.Start)
+			if err != nil {
+				return fmt.Errorf("invalid start: %w", err)
+			}
+			if !end.After(start) {
+				return fmt.Errorf("end must be after start")
+			}
+		}
+		return nil
+	}
+
+	if w.Start != "" || w.End != "" {
+		return fmt.Errorf("start/end can't be combined with days/from/to")
+	}
+	for _, d := range w.Days {
+		if _, ok := FreezeWeekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("invalid day %q, want mon..sun", d)
+		}
+	}
+	if (w.From == "") != (w.To == "") {
+		return fmt.Errorf("from and to must be set together")
+	}
+	if w.From != "" {
+		from, err := ParseFreezeClock(w.From)
+		if err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		to, err := ParseFreezeClock(w.To)
+		if err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		if from == to {
+			return fmt.Errorf("from and to must differ")
+		}
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateScoringConfig validates merge queue scoring overrides. Weights and
+// penalties must be non-negative (a penalty is already subtracted); base_score
+// and label weights may be anything.
+func validateScoringConfig(c *ScoringConfig) error {
+	weights := []struct {
+		name  string
+		value *float64
+	}{
+		{"convoy_age_weight", c.ConvoyAgeWeight},
+		{"priority_weight", c.PriorityWeight},
+		{"retry_penalty", c.RetryPenalty},
+		{"max_retry_penalty", c.MaxRetryPenalty},
+		{"mr_age_weight", c.MRAgeWeight},
+		{"diff_size_weight", c.DiffSizeWeight},
+		{"max_diff_size_penalty", c.MaxDiffSizePenalty},
+		{"convoy_deadline_weight", c.ConvoyDeadlineWeight},
+	}
+	for _, w := range weights {
+		if w.value != nil && *w.value < 0 {
+			return fmt.Errorf("%s must be non-negative, got %v", w.name, *w.value)
+		}
+	}
+	if c.ConvoyDeadlineHorizon != "" {
+		dur, err := time.ParseDuration(c.ConvoyDeadlineHorizon)
+		if err != nil {
+			return fmt.Errorf("invalid convoy_deadline_horizon: %w", err)
+		}
+		if dur <= 0 {
+			return fmt.Errorf("convoy_deadline_horizon must be positive, got %v", dur)
+		}
+	}
 	return nil
 }
 
@@ -842,6 +969,85 @@ func MessagingConfigPath(townRoot string) string {
 	return filepath.Join(townRoot, "config", "messaging.json")
 }
 
+// MailRulesPath returns the path of the mail rules file for a mail identity
+// ("mayor/", "gastown/witness", "gastown/max") in a town.
+func MailRulesPath(townRoot, identity string) string {
+	return filepath.Join(townRoot, "config", "mail-rules", strings.Trim(identity, "/")+".json")
+}
+
+// LoadMailRules loads and validates a mail rules file.
+func LoadMailRules(path string) (*MailRules, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading mail rules: %w", err)
+	}
+
+	var rules MailRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing mail rules: %w", err)
+	}
+
+	if err := validateMailRules(&rules); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// mailPriorities and mailTypes mirror the mail package's Priority and
+// MessageType values, which config can't import.
+var (
+	mailPriorities = map[string]bool{"low": true, "normal": true, "high": true, "urgent": true}
+	mailTypes      = map[string]bool{"task": true, "scavenge": true, "notification": true, "reply": true}
+)
+
+// validateMailRules validates mail filter rules.
+func validateMailRules(c *MailRules) error {
+	if c.Type != "mail-rules" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'mail-rules', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentMailRulesVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentMailRulesVersion)
+	}
+
+	for i, rule := range c.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rules[%d]", i)
+		}
+		if rule.Match.Subject != "" {
+			if _, err := regexp.Compile(rule.Match.Subject); err != nil {
+				return fmt.Errorf("mail rule %s: invalid subject pattern: %w", name, err)
+			}
+		}
+		if rule.Match.Type != "" && !mailTypes[rule.Match.Type] {
+			return fmt.Errorf("mail rule %s: unknown message type %q", name, rule.Match.Type)
+		}
+		if rule.Match.Priority != "" && !mailPriorities[rule.Match.Priority] {
+			return fmt.Errorf("mail rule %s: unknown priority %q", name, rule.Match.Priority)
+		}
+		if rule.Priority != "" && !mailPriorities[rule.Priority] {
+			return fmt.Errorf("mail rule %s: unknown priority %q", name, rule.Priority)
+		}
+		if rule.Delivery != "" && rule.Delivery != "interrupt" && rule.Delivery != "queue" {
+			return fmt.Errorf("mail rule %s: delivery must be 'interrupt' or 'queue', got %q", name, rule.Delivery)
+		}
+		for _, addr := range rule.Forward {
+			if strings.TrimSpace(addr) == "" {
+				return fmt.Errorf("%w: mail rule %s: empty forward address", ErrMissingField, name)
+			}
+		}
+		if !rule.Archive && len(rule.Forward) == 0 && len(rule.Labels) == 0 && rule.Priority == "" && rule.Delivery == "" && !rule.Stop {
+			return fmt.Errorf("%w: mail rule %s has no actions", ErrMissingField, name)
+		}
+	}
+
+	return nil
+}
+
 // LoadOrCreateMessagingConfig loads the messaging config, creating a default if not found.
 func LoadOrCreateMessagingConfig(path string) (*MessagingConfig, error) {
 	config, err := LoadMessagingConfig(path)
@@ -2217,6 +2423,12 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		}
 	}
 
+	if c.DedupWindow != "" {
+		if d, err := time.ParseDuration(c.DedupWindow); err != nil || d < 0 {
+			return fmt.Errorf("invalid dedup_window: must be a non-negative duration")
+		}
+	}
+
 	// Initialize nil maps
 	if c.Routes == nil {
 		c.Routes = make(map[string][]string)
@@ -2234,9 +2446,76 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if c.ReplySeverity != "" && !IsValidSeverity(c.ReplySeverity) {
+		return fmt.Errorf("%w: unknown reply_severity '%s' (valid: low, medium, high, critical)", ErrMissingField, c.ReplySeverity)
+	}
+
+	if c.Email != nil {
+		if c.Email.Host == "" || c.Email.From == "" {
+			return fmt.Errorf("%w: email.host and email.from are required", ErrMissingField)
+		}
+		switch c.Email.TLS {
+		case "", "starttls", "tls", "none":
+		default:
+			return fmt.Errorf("%w: unknown email.tls '%s' (valid: starttls, tls, none)", ErrMissingField, c.Email.TLS)
+		}
+	}
+	if c.SMS != nil {
+		if err := validateWebhookURL("sms.url", c.SMS.URL); err != nil {
+			return err
+		}
+	}
+	for name, hook := range c.Webhooks {
+		if hook == nil {
+			return fmt.Errorf("%w: webhooks.%s is empty", ErrMissingField, name)
+		}
+		if err := validateWebhookURL("webhooks."+name+".url", hook.URL); err != nil {
+			return err
+		}
+	}
+
+	if c.OnCall != nil {
+		if err := c.OnCall.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Every webhook:<name> action must name a configured webhook
+	for severity, actions := range c.Routes {
+		for _, action := range actions {
+			if name, ok := strings.CutPrefix(action, "webhook:"); ok && c.Webhooks[name] == nil {
+				return fmt.Errorf("%w: routes.%s uses %s but webhooks.%s is not configured", ErrMissingField, severity, action, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateWebhookURL checks that an escalation endpoint is an http(s) URL.
+// $VARS are allowed, since they're expanded from the environment at send time.
+func validateWebhookURL(field, raw string) error {
+	if raw == "" {
+		return fmt.Errorf("%w: %s is required", ErrMissingField, field)
+	}
+	u, err := url.Parse(os.ExpandEnv(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("%w: %s must be an http(s) URL", ErrMissingField, field)
+	}
 	return nil
 }
 
+// GetLogFile returns the path of the escalation log under townRoot.
+func (c *EscalationConfig) GetLogFile(townRoot string) string {
+	if c.LogFile == "" {
+		return filepath.Join(townRoot, "logs", "escalations.log")
+	}
+	if filepath.IsAbs(c.LogFile) {
+		return c.LogFile
+	}
+	return filepath.Join(townRoot, c.LogFile)
+}
+
 // GetStaleThreshold returns the stale threshold as a time.Duration.
 // Returns 4 hours if not configured or invalid.
 func (c *EscalationConfig) GetStaleThreshold() time.Duration {
@@ -2250,6 +2529,19 @@ func (c *EscalationConfig) GetStaleThreshold() time.Duration {
 	return d
 }
 
+// GetDedupWindow returns the escalation dedup window as a time.Duration.
+// Returns 1 hour if not configured or invalid; 0 means dedup is disabled.
+func (c *EscalationConfig) GetDedupWindow() time.Duration {
+	if c.DedupWindow == "" {
+		return time.Hour
+	}
+	d, err := time.ParseDuration(c.DedupWindow)
+	if err != nil || d < 0 {
+		return time.Hour
+	}
+	return d
+}
+
 // GetRouteForSeverity returns the escalation route actions for a given severity.
 // Falls back to ["bead", "mail:mayor"] if no specific route is configured.
 func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
@@ -2268,3 +2560,12 @@ func (c *EscalationConfig) GetMaxReescalations() int {
 	}
 	return *c.MaxReescalations
 }
+
+// GetReplySeverity returns the severity for escalating unanswered
+// --expect-reply mail. Returns "medium" if not configured.
+func (c *EscalationConfig) GetReplySeverity() string {
+	if c.ReplySeverity == "" {
+		return SeverityMedium
+	}
+	return c.ReplySeverity
+}
@@ -328,6 +328,28 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: MergeStrategyRebase,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: "octopus",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -372,6 +394,113 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid scoring",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Scoring: &ScoringConfig{
+						PriorityWeight:        float64Ptr(150),
+						LabelWeights:          map[string]float64{"hotfix": 500, "wip": -200},
+						ConvoyDeadlineHorizon: "48h",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative scoring weight",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Scoring: &ScoringConfig{RetryPenalty: float64Ptr(-50)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid convoy_deadline_horizon",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Scoring: &ScoringConfig{ConvoyDeadlineHorizon: "soon"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid freeze windows",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					FreezeWindows: []FreezeWindow{
+						{Branches: []string{"main"}, Start: "2026-12-20T00:00:00Z", End: "2027-01-04T00:00:00Z", Reason: "holidays"},
+						{Days: []string{"fri"}, From: "16:00", To: "09:00", Timezone: "America/New_York"},
+						{Branches: []string{"release/*"}, Days: []string{"sat", "sun"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "freeze window without end",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					FreezeWindows: []FreezeWindow{{Start: "2026-12-20T00:00:00Z"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "freeze window mixing one-off and recurring",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					FreezeWindows: []FreezeWindow{{End: "2026-12-20T00:00:00Z", Days: []string{"mon"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "freeze window invalid day",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					FreezeWindows: []FreezeWindow{{Days: []string{"friday"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "review owner_map without address",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					Review: &ReviewConfig{Enabled: true, OwnerMap: map[string]string{"@alice": ""}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "freeze window invalid time",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					FreezeWindows: []FreezeWindow{{From: "25:00", To: "09:00"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -855,6 +984,63 @@ func TestMessagingConfigPath(t *testing.T) {
 	}
 }
 
+func TestMailRulesValidation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		rules   []MailRule
+		wantErr bool
+	}{
+		{
+			name:  "valid rule",
+			rules: []MailRule{{Match: MailRuleMatch{From: "*/witness", Subject: "^MERGED", Type: "notification"}, Archive: true}},
+		},
+		{
+			name:  "stop only",
+			rules: []MailRule{{Match: MailRuleMatch{Priority: "low"}, Stop: true}},
+		},
+		{
+			name:    "bad subject regex",
+			rules:   []MailRule{{Match: MailRuleMatch{Subject: "(unclosed"}, Archive: true}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown match type",
+			rules:   []MailRule{{Match: MailRuleMatch{Type: "memo"}, Archive: true}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown priority action",
+			rules:   []MailRule{{Priority: "p0"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown delivery",
+			rules:   []MailRule{{Delivery: "carrier-pigeon"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty forward address",
+			rules:   []MailRule{{Forward: []string{""}}},
+			wantErr: true,
+		},
+		{
+			name:    "no actions",
+			rules:   []MailRule{{Name: "noop", Match: MailRuleMatch{From: "mayor/"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMailRules(&MailRules{Type: "mail-rules", Version: 1, Rules: tt.rules})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMailRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestRuntimeConfigDefaults(t *testing.T) {
 	t.Parallel()
 	rc := DefaultRuntimeConfig()
@@ -3514,6 +3700,82 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "invalid reply severity",
+			config: &EscalationConfig{
+				Type:          "escalation",
+				Version:       1,
+				ReplySeverity: "urgent",
+			},
+			wantErr: true,
+			errMsg:  "unknown reply_severity",
+		},
+		{
+			name: "valid external channels",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Routes: map[string][]string{
+					SeverityHigh: {"bead", "email:human", "webhook:pager"},
+				},
+				Email:    &EscalationEmail{Host: "smtp.example.com", From: "gt@example.com", TLS: "starttls"},
+				SMS:      &EscalationSMS{URL: "https://sms.example.com/$SMS_ACCOUNT/send"},
+				Webhooks: map[string]*EscalationWebhook{"pager": {URL: "https://pager.example.com/hook"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "email without host",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Email:   &EscalationEmail{From: "gt@example.com"},
+			},
+			wantErr: true,
+			errMsg:  "email.host and email.from are required",
+		},
+		{
+			name: "invalid email tls",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Email:   &EscalationEmail{Host: "smtp.example.com", From: "gt@example.com", TLS: "ssl"},
+			},
+			wantErr: true,
+			errMsg:  "unknown email.tls",
+		},
+		{
+			name: "webhook without http url",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Webhooks: map[string]*EscalationWebhook{"pager": {URL: "ftp://example.com"}},
+			},
+			wantErr: true,
+			errMsg:  "webhooks.pager.url must be an http(s) URL",
+		},
+		{
+			name: "route uses unknown webhook",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Routes: map[string][]string{
+					SeverityCritical: {"bead", "webhook:pager"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "webhooks.pager is not configured",
+		},
+		{
+			name: "invalid dedup window",
+			config: &EscalationConfig{
+				Type:        "escalation",
+				Version:     1,
+				DedupWindow: "-5m",
+			},
+			wantErr: true,
+			errMsg:  "invalid dedup_window",
+		},
 	}
 
 	for _, tt := range tests {
@@ -4545,3 +4807,7 @@ func TestResolveRoleAgentConfig_EphemeralDefaultPreservesNonClaudeOverride(t *te
 		t.Errorf("expected gemini for polecat (non-Claude rig override with tier default), got Command=%q", rc.Command)
 	}
 }
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultOnCallShift is the shift length of a rotation without one: weekly.
+const DefaultOnCallShift = 7 * 24 * time.Hour
+
+// ErrInvalidOnCall indicates an on-call schedule value that is set but
+// invalid, such as an unknown person or a malformed time.
+var ErrInvalidOnCall = errors.New("invalid oncall schedule")
+
+// OnCallSchedule is the on-call schedule for escalations (the "oncall"
+// section of settings/escalation.json). email:human and sms:human resolve
+// to whoever is on call when the escalation is delivered, falling back to
+// contacts when no rotation matches.
+type OnCallSchedule struct {
+	// Timezone is the IANA zone for times without a UTC offset.
+	// Default: UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// People maps each responder's name to their contact details.
+	People map[string]*OnCallPerson `json:"people"`
+
+	// Rotations are checked in order; the first one matching the
+	// escalation's rig and severity pages its current responder.
+	Rotations []*OnCallRotation `json:"rotations"`
+
+	// Overrides put someone on call in place of a rotation's responder
+	// for a window (swaps, vacations).
+	Overrides []*OnCallOverride `json:"overrides,omitempty"`
+}
+
+// OnCallPerson is a responder's contact details.
+type OnCallPerson struct {
+	Email string `json:"email,omitempty"`
+	SMS   string `json:"sms,omitempty"`
+}
+
+// OnCallRotation hands on-call duty between People in order, one shift each.
+type OnCallRotation struct {
+	Name   string   `json:"name"`
+	People []string `json:"people"`
+
+	// Start is when People[0]'s first shift begins, as RFC 3339 or
+	// "2006-01-02T15:04" in the schedule's timezone.
+	Start string `json:"start"`
+
+	// Shift is the length of each shift as a Go duration. Default: "168h".
+	Shift string `json:"shift,omitempty"`
+
+	// Rigs and Severities limit which escalations the rotation pages.
+	// Empty matches all.
+	Rigs       []string `json:"rigs,omitempty"`
+	Severities []string `json:"severities,omitempty"`
+}
+
+// OnCallOverride puts Person on call from Start until End.
+type OnCallOverride struct {
+	Person string `json:"person"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+
+	// Rotation limits the override to one rotation. Default: all.
+	Rotation string `json:"rotation,omitempty"`
+}
+
+// OnCallShift is one responder's stretch of on-call duty in a rotation.
+type OnCallShift struct {
+	Rotation string    `json:"rotation"`
+	Person   string    `json:"person"`
+	Email    string    `json:"email,omitempty"`
+	SMS      string    `json:"sms,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Override bool      `json:"override,omitempty"`
+}
+
+// Matches reports whether the rotation pages for escalations from rig at
+// severity. An empty rig (town-level escalations) only matches rotations
+// without a rig filter.
+func (r *OnCallRotation) Matches(rig, severity string) bool {
+	if len(r.Rigs) > 0 && !slices.Contains(r.Rigs, rig) {
+		return false
+	}
+	return len(r.Severities) == 0 || slices.Contains(r.Severities, severity)
+}
+
+// Responder returns the shift on call at t for escalations from rig at
+// severity, or nil if no rotation matches.
+func (s *OnCallSchedule) Responder(t time.Time, rig, severity string) *OnCallShift {
+	if s == nil {
+		return nil
+	}
+	for _, r := range s.Rotations {
+		if r.Matches(rig, severity) {
+			return s.ShiftAt(r, t)
+		}
+	}
+	return nil
+}
+
+// ShiftAt returns the rotation's shift covering t, taking overrides into
+// account. A shift ends early where an override begins. Returns nil if t is
+// before the rotation starts or the rotation is invalid.
+func (s *OnCallSchedule) ShiftAt(r *OnCallRotation, t time.Time) *OnCallShift {
+	loc := s.location()
+	for _, o := range s.Overrides {
+		if o.Rotation != "" && o.Rotation != r.Name {
+			continue
+		}
+		start, err1 := parseOnCallTime(o.Start, loc)
+		end, err2 := parseOnCallTime(o.End, loc)
+		if err1 == nil && err2 == nil && !t.Before(start) && t.Before(end) {
+			return s.shift(r, o.Person, start, end, true)
+		}
+	}
+
+	start, err := parseOnCallTime(r.Start, loc)
+	if err != nil || len(r.People) == 0 || t.Before(start) {
+		return nil
+	}
+	n, shiftStart, shiftEnd := r.shiftCovering(start.In(loc), t)
+
+	// Cut the shift short where an override takes over
+	for _, o := range s.Overrides {
+		if o.Rotation != "" && o.Rotation != r.Name {
+			continue
+		}
+		if oStart, err := parseOnCallTime(o.Start, loc); err == nil && oStart.After(t) && oStart.Before(shiftEnd) {
+			shiftEnd = oStart
+		}
+	}
+	return s.shift(r, r.People[n%int64(len(r.People))], shiftStart, shiftEnd, false)
+}
+
+// NextShift returns the rotation's shift after the one covering t.
+func (s *OnCallSchedule) NextShift(r *OnCallRotation, t time.Time) *OnCallShift {
+	current := s.ShiftAt(r, t)
+	if current == nil {
+		// Before the rotation starts, the next shift is its first
+		start, err := parseOnCallTime(r.Start, s.location())
+		if err != nil || !t.Before(start) {
+			return nil
+		}
+		return s.ShiftAt(r, start)
+	}
+	return s.ShiftAt(r, current.End)
+}
+
+func (s *OnCallSchedule) shift(r *OnCallRotation, person string, start, end time.Time, override bool) *OnCallShift {
+	shift := &OnCallShift{
+		Rotation: r.Name,
+		Person:   person,
+		Start:    start,
+		End:      end,
+		Override: override,
+	}
+	if p := s.People[person]; p != nil {
+		shift.Email = p.Email
+		shift.SMS = p.SMS
+	}
+	return shift
+}
+
+func (s *OnCallSchedule) location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (r *OnCallRotation) shiftLength() time.Duration {
+	if d, err := time.ParseDuration(r.Shift); err == nil && d > 0 {
+		return d
+	}
+	return DefaultOnCallShift
+}
+
+// shiftCovering returns the number of the shift covering t, counting from
+// 0 at start, and its bounds. Shifts of whole days are stepped on the
+// calendar in start's location, so handovers keep their wall-clock time
+// across daylight saving changes; other lengths are stepped as durations.
+func (r *OnCallRotation) shiftCovering(start, t time.Time) (n int64, shiftStart, shiftEnd time.Time) {
+	length := r.shiftLength()
+	n = int64(t.Sub(start) / length)
+	if length%(24*time.Hour) != 0 {
+		shiftStart = start.Add(time.Duration(n) * length)
+		return n, shiftStart, shiftStart.Add(length)
+	}
+
+	days := int(length / (24 * time.Hour))
+	at := func(n int64) time.Time { return start.AddDate(0, 0, int(n)*days) }
+	// The duration estimate is off by at most a DST shift, so at most one
+	// shift either way.
+	if at(n).After(t) {
+		n--
+	} else if !t.Before(at(n + 1)) {
+		n++
+	}
+	return n, at(n), at(n + 1)
+}
+
+// parseOnCallTime parses an RFC 3339 time, or one without a UTC offset in loc.
+func parseOnCallTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC 3339 or 2006-01-02T15:04)", s)
+}
+
+// validate checks the schedule's references, times, and durations.
+func (s *OnCallSchedule) validate() error {
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("%w: oncall.timezone: %v", ErrInvalidOnCall, err)
+		}
+	}
+	loc := s.location()
+
+	names := make(map[string]bool)
+	for i, r := range s.Rotations {
+		if r == nil || r.Name == "" {
+			return fmt.Errorf("%w: oncall.rotations[%d] needs a name", ErrMissingField, i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("%w: duplicate oncall rotation '%s'", ErrInvalidOnCall, r.Name)
+		}
+		names[r.Name] = true
+		if len(r.People) == 0 {
+			return fmt.Errorf("%w: oncall rotation '%s' has no people", ErrMissingField, r.Name)
+		}
+		for _, person := range r.People {
+			if s.People[person] == nil {
+				return fmt.Errorf("%w: oncall rotation '%s' names unknown person '%s'", ErrInvalidOnCall, r.Name, person)
+			}
+		}
+		if r.Start == "" {
+			return fmt.Errorf("%w: oncall rotation '%s' needs a start", ErrMissingField, r.Name)
+		}
+		if _, err := parseOnCallTime(r.Start, loc); err != nil {
+			return fmt.Errorf("%w: oncall rotation '%s' start: %v", ErrInvalidOnCall, r.Name, err)
+		}
+		if r.Shift != "" {
+			if d, err := time.ParseDuration(r.Shift); err != nil || d <= 0 {
+				return fmt.Errorf("%w: oncall rotation '%s' shift must be a positive duration", ErrInvalidOnCall, r.Name)
+			}
+		}
+		for _, severity := range r.Severities {
+			if !IsValidSeverity(severity) {
+				return fmt.Errorf("%w: oncall rotation '%s' has unknown severity '%s'", ErrInvalidOnCall, r.Name, severity)
+			}
+		}
+	}
+
+	for i, o := range s.Overrides {
+		if o == nil || o.Person == "" {
+			return fmt.Errorf("%w: oncall.overrides[%d] needs a person", ErrMissingField, i)
+		}
+		if s.People[o.Person] == nil {
+			return fmt.Errorf("%w: oncall.overrides[%d] names unknown person '%s'", ErrInvalidOnCall, i, o.Person)
+		}
+		if o.Rotation != "" && !names[o.Rotation] {
+			return fmt.Errorf("%w: oncall.overrides[%d] names unknown rotation '%s'", ErrInvalidOnCall, i, o.Rotation)
+		}
+		if o.Start == "" || o.End == "" {
+			return fmt.Errorf("%w: oncall.overrides[%d] needs a start and end", ErrMissingField, i)
+		}
+		start, err := parseOnCallTime(o.Start, loc)
+		if err != nil {
+			return fmt.Errorf("%w: oncall.overrides[%d] start: %v", ErrInvalidOnCall, i, err)
+		}
+		end, err := parseOnCallTime(o.End, loc)
+		if err != nil {
+			return fmt.Errorf("%w: oncall.overrides[%d] end: %v", ErrInvalidOnCall, i, err)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("%w: oncall.overrides[%d] ends before it starts", ErrInvalidOnCall, i)
+		}
+	}
+	return nil
+}
+
+// HumanContacts returns the email and SMS that email:human and sms:human
+// reach for an escalation from rig at severity at time t: the on-call
+// responder's where set, otherwise contacts. shift is nil when no rotation
+// matches.
+func (c *EscalationConfig) HumanContacts(t time.Time, rig, severity string) (email, sms string, shift *OnCallShift) {
+	email, sms = c.Contacts.HumanEmail, c.Contacts.HumanSMS
+	shift = c.OnCall.Responder(t, rig, severity)
+	if shift != nil {
+		if shift.Email != "" {
+			email = shift.Email
+		}
+		if shift.SMS != "" {
+			sms = shift.SMS
+		}
+	}
+	return email, sms, shift
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testOnCallSchedule() *OnCallSchedule {
+	return &OnCallSchedule{
+		Timezone: "America/Los_Angeles",
+		People: map[string]*OnCallPerson{
+			"alice": {Email: "alice@example.com", SMS: "+15550000001"},
+			"bob":   {Email: "bob@example.com"},
+			"carol": {Email: "carol@example.com", SMS: "+15550000003"},
+		},
+		Rotations: []*OnCallRotation{
+			{Name: "gastown", People: []string{"carol"}, Start: "2026-01-05T09:00", Rigs: []string{"gastown"}, Severities: []string{SeverityCritical}},
+			{Name: "primary", People: []string{"alice", "bob"}, Start: "2026-01-05T09:00"},
+		},
+		Overrides: []*OnCallOverride{
+			{Person: "carol", Rotation: "primary", Start: "2026-01-21T00:00", End: "2026-01-23T00:00"},
+		},
+	}
+}
+
+func TestOnCallSchedule_WeeklyRotation(t *testing.T) {
+	t.Parallel()
+	s := testOnCallSchedule()
+	primary := s.Rotations[1]
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2026, 1, 5, 9, 0, 0, 0, loc), "alice"},
+		{time.Date(2026, 1, 12, 8, 59, 0, 0, loc), "alice"},
+		{time.Date(2026, 1, 12, 9, 0, 0, 0, loc), "bob"},
+		{time.Date(2026, 1, 19, 10, 0, 0, 0, loc), "alice"},
+		{time.Date(2026, 1, 22, 10, 0, 0, 0, loc), "carol"}, // override
+		{time.Date(2026, 1, 23, 10, 0, 0, 0, loc), "alice"}, // back after override
+	}
+	for _, tt := range tests {
+		shift := s.ShiftAt(primary, tt.at)
+		if shift == nil || shift.Person != tt.want {
+			t.Errorf("ShiftAt(%s) = %+v, want %s", tt.at, shift, tt.want)
+		}
+	}
+
+	if shift := s.ShiftAt(primary, time.Date(2026, 1, 1, 0, 0, 0, 0, loc)); shift != nil {
+		t.Errorf("ShiftAt before start = %+v, want nil", shift)
+	}
+}
+
+func TestOnCallSchedule_ShiftsKeepWallClockAcrossDST(t *testing.T) {
+	t.Parallel()
+	s := testOnCallSchedule()
+	primary := s.Rotations[1]
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+
+	// DST starts 2026-03-08; the Mar 9 handover is still at 09:00 local.
+	if shift := s.ShiftAt(primary, time.Date(2026, 3, 9, 8, 59, 0, 0, loc)); shift == nil || shift.Person != "alice" {
+		t.Errorf("ShiftAt(Mar 9 08:59) = %+v, want alice", shift)
+	}
+	shift := s.ShiftAt(primary, time.Date(2026, 3, 9, 9, 0, 0, 0, loc))
+	if shift == nil || shift.Person != "bob" {
+		t.Fatalf("ShiftAt(Mar 9 09:00) = %+v, want bob", shift)
+	}
+	if want := time.Date(2026, 3, 9, 9, 0, 0, 0, loc); !shift.Start.Equal(want) {
+		t.Errorf("shift starts %s, want %s", shift.Start, want)
+	}
+	if want := time.Date(2026, 3, 16, 9, 0, 0, 0, loc); !shift.End.Equal(want) {
+		t.Errorf("shift ends %s, want %s", shift.End, want)
+	}
+
+	// Shorter shifts are plain durations, so they move an hour with DST.
+	primary.Shift = "12h"
+	if shift := s.ShiftAt(primary, time.Date(2026, 3, 9, 9, 30, 0, 0, loc)); shift == nil || !shift.End.Equal(time.Date(2026, 3, 9, 10, 0, 0, 0, loc)) {
+		t.Errorf("12h shift = %+v, want one ending 10:00 PDT", shift)
+	}
+}
+
+func TestOnCallSchedule_NextShift(t *testing.T) {
+	t.Parallel()
+	s := testOnCallSchedule()
+	primary := s.Rotations[1]
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+
+	// alice's shift from Jan 19 is cut short by carol's override on Jan 21
+	at := time.Date(2026, 1, 20, 12, 0, 0, 0, loc)
+	current := s.ShiftAt(primary, at)
+	if !current.End.Equal(time.Date(2026, 1, 21, 0, 0, 0, 0, loc)) {
+		t.Errorf("current ends %s, want override start", current.End)
+	}
+	next := s.NextShift(primary, at)
+	if next == nil || next.Person != "carol" || !next.Override || next.Email != "carol@example.com" {
+		t.Errorf("NextShift = %+v, want carol override", next)
+	}
+
+	// Before the rotation starts, next is the first shift
+	if next := s.NextShift(primary, time.Date(2026, 1, 1, 0, 0, 0, 0, loc)); next == nil || next.Person != "alice" {
+		t.Errorf("NextShift before start = %+v, want alice", next)
+	}
+}
+
+func TestEscalationConfig_HumanContacts(t *testing.T) {
+	t.Parallel()
+	cfg := NewEscalationConfig()
+	cfg.Contacts = EscalationContacts{HumanEmail: "ops@example.com", HumanSMS: "+15559999999"}
+	at := time.Date(2026, 1, 13, 12, 0, 0, 0, time.UTC) // bob's week
+
+	// No schedule: contacts
+	if email, sms, shift := cfg.HumanContacts(at, "gastown", SeverityCritical); email != "ops@example.com" || sms != "+15559999999" || shift != nil {
+		t.Errorf("no schedule: %s, %s, %+v", email, sms, shift)
+	}
+
+	cfg.OnCall = testOnCallSchedule()
+
+	// Per-rig, per-severity rotation wins for gastown critical
+	if email, _, shift := cfg.HumanContacts(at, "gastown", SeverityCritical); email != "carol@example.com" || shift.Rotation != "gastown" {
+		t.Errorf("gastown critical: %s, %+v", email, shift)
+	}
+
+	// Other rigs and severities fall through to primary; bob has no SMS
+	email, sms, shift := cfg.HumanContacts(at, "beads", SeverityHigh)
+	if email != "bob@example.com" || sms != "+15559999999" || shift.Person != "bob" {
+		t.Errorf("beads high: %s, %s, %+v", email, sms, shift)
+	}
+}
+
+func TestOnCallSchedule_Validate(t *testing.T) {
+	t.Parallel()
+	if err := testOnCallSchedule().validate(); err != nil {
+		t.Fatalf("valid schedule: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*OnCallSchedule)
+		errMsg  string
+		missing bool
+	}{
+		{"bad timezone", func(s *OnCallSchedule) { s.Timezone = "Mars/Olympus" }, "oncall.timezone", false},
+		{"unknown person", func(s *OnCallSchedule) { s.Rotations[1].People = []string{"dave"} }, "unknown person 'dave'", false},
+		{"bad start", func(s *OnCallSchedule) { s.Rotations[1].Start = "monday" }, "start", false},
+		{"no start", func(s *OnCallSchedule) { s.Rotations[1].Start = "" }, "needs a start", true},
+		{"bad shift", func(s *OnCallSchedule) { s.Rotations[1].Shift = "-1h" }, "positive duration", false},
+		{"bad severity", func(s *OnCallSchedule) { s.Rotations[0].Severities = []string{"urgent"} }, "unknown severity", false},
+		{"duplicate rotation", func(s *OnCallSchedule) { s.Rotations[0].Name = "primary" }, "duplicate", false},
+		{"no people", func(s *OnCallSchedule) { s.Rotations[1].People = nil }, "has no people", true},
+		{"override unknown rotation", func(s *OnCallSchedule) { s.Overrides[0].Rotation = "secondary" }, "unknown rotation", false},
+		{"override ends first", func(s *OnCallSchedule) { s.Overrides[0].End = "2026-01-20T00:00" }, "ends before it starts", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testOnCallSchedule()
+			tt.mutate(s)
+			err := s.validate()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("validate() = %v, want error containing %q", err, tt.errMsg)
+			}
+			if want := map[bool]error{true: ErrMissingField, false: ErrInvalidOnCall}[tt.missing]; !errors.Is(err, want) {
+				t.Errorf("validate() = %v, want it to wrap %v", err, want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -82,6 +83,9 @@ type TownSettings struct {
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
 
+	// Tracing configures export of sling-to-merge spans (see internal/tracing).
+	Tracing *TracingConfig `json:"tracing,omitempty"`
+
 	// CostTier tracks which cost tier preset was applied (informational).
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
@@ -182,6 +186,27 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// TracingConfig configures where gt exports OpenTelemetry spans for slung
+// work. Tracing is off unless Exporter is set.
+type TracingConfig struct {
+	// Exporter is "file" (OTLP/JSON lines appended to File) or "otlp"
+	// (OTLP/HTTP JSON posted to Endpoint). Empty disables tracing.
+	Exporter string `json:"exporter,omitempty"`
+
+	// File is the span file for the file exporter, relative to the town
+	// root unless absolute. Default: "logs/traces.jsonl".
+	File string `json:"file,omitempty"`
+
+	// Endpoint is the OTLP/HTTP collector base URL; spans are posted to
+	// <endpoint>/v1/traces. Default: $OTEL_EXPORTER_OTLP_ENDPOINT, then
+	// "http://localhost:4318".
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Headers are added to OTLP/HTTP requests (e.g., authentication).
+	// Values may reference environment variables as $VAR.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -849,6 +874,11 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is how MRs land on the target: "squash" (default),
+	// "merge" (merge commit), "rebase" (rebase and fast-forward), or "ff"
+	// (fast-forward only). Individual MRs can override it.
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -884,6 +914,118 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// Scoring overrides the weights the refinery uses to order the queue.
+	// Nil keeps the defaults.
+	Scoring *ScoringConfig `json:"scoring,omitempty"`
+
+	// FreezeWindows are periods when the refinery keeps gating MRs but holds
+	// their push. Held MRs land once the window closes.
+	FreezeWindows []FreezeWindow `json:"freeze_windows,omitempty"`
+
+	// Review requires an approved review before the refinery merges MRs
+	// that touch owned paths. Nil disables the review gate.
+	Review *ReviewConfig `json:"review,omitempty"`
+}
+
+// ReviewConfig configures the refinery's review gate. Path ownership comes
+// from the rig's settings/OWNERS file (owners are Gas Town addresses) and the
+// target branch's CODEOWNERS file (owners are mapped through OwnerMap).
+type ReviewConfig struct {
+	// Enabled turns the review gate on.
+	Enabled bool `json:"enabled"`
+
+	// OwnerMap maps CODEOWNERS owners ("@alice", "@org/team",
+	// "alice@example.com") to Gas Town addresses ("greenplace/crew/alice").
+	// A bare "@name" that matches a crew member of the rig needs no entry.
+	OwnerMap map[string]string `json:"owner_map,omitempty"`
+
+	// Reviewer is the address asked to review when an owned path's owners
+	// don't map to any Gas Town address. Empty leaves the review bead
+	// unassigned, for a reviewer polecat to be slung at it.
+	Reviewer string `json:"reviewer,omitempty"`
+}
+
+// FreezeWindow is a declarative merge freeze. It is either one-off (End,
+// optionally Start) or recurring (Days and/or From/To), not both.
+type FreezeWindow struct {
+	// Branches are the target branches frozen, as globs ("main",
+	// "release/*"). Empty freezes every target.
+	Branches []string `json:"branches,omitempty"`
+
+	// Reason is shown for held MRs (e.g. "release cut").
+	Reason string `json:"reason,omitempty"`
+
+	// Start and End bound a one-off freeze (RFC3339). A missing Start means
+	// the freeze is already in effect.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+
+	// Days are the weekdays a recurring freeze starts on ("mon".."sun").
+	// Empty means every day.
+	Days []string `json:"days,omitempty"`
+
+	// From and To are the daily "HH:MM" bounds of a recurring freeze. A To
+	// before From wraps past midnight; both empty freezes the whole day.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	// Timezone is the IANA zone From and To are in. Default: local time.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Recurring reports whether the window repeats rather than being one-off.
+func (w *FreezeWindow) Recurring() bool {
+	return len(w.Days) > 0 || w.From != "" || w.To != ""
+}
+
+// FreezeWeekdays maps freeze window day names to weekdays.
+var FreezeWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseFreezeClock parses an "HH:MM" time of day into minutes after midnight.
+// "24:00" is accepted as the end of the day.
+func ParseFreezeClock(s string) (int, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || n != 2 || len(s) != 5 {
+		return 0, fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// ScoringConfig overrides merge queue priority scoring weights for a rig.
+// Nil fields keep the refinery's defaults (see refinery.DefaultScoreConfig).
+type ScoringConfig struct {
+	BaseScore       *float64 `json:"base_score,omitempty"`
+	ConvoyAgeWeight *float64 `json:"convoy_age_weight,omitempty"`
+	PriorityWeight  *float64 `json:"priority_weight,omitempty"`
+	RetryPenalty    *float64 `json:"retry_penalty,omitempty"`
+	MaxRetryPenalty *float64 `json:"max_retry_penalty,omitempty"`
+	MRAgeWeight     *float64 `json:"mr_age_weight,omitempty"`
+
+	// LabelWeights adds points to MRs carrying a label (e.g. "hotfix": 300).
+	// Entries are merged over the defaults; set a label to 0 to disable it.
+	LabelWeights map[string]float64 `json:"label_weights,omitempty"`
+
+	// DiffSizeWeight is points subtracted per 100 changed lines, capped at
+	// MaxDiffSizePenalty.
+	DiffSizeWeight     *float64 `json:"diff_size_weight,omitempty"`
+	MaxDiffSizePenalty *float64 `json:"max_diff_size_penalty,omitempty"`
+
+	// ConvoyDeadlineWeight is points added per hour an MR's convoy is inside
+	// ConvoyDeadlineHorizon (e.g. "24h") of its deadline.
+	ConvoyDeadlineWeight  *float64 `json:"convoy_deadline_weight,omitempty"`
+	ConvoyDeadlineHorizon string   `json:"convoy_deadline_horizon,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -892,6 +1034,22 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge strategy constants.
+const (
+	MergeStrategySquash = "squash"
+	MergeStrategyMerge  = "merge"
+	MergeStrategyRebase = "rebase"
+	MergeStrategyFF     = "ff"
+)
+
+// MergeStrategies lists the valid merge strategies.
+var MergeStrategies = []string{MergeStrategySquash, MergeStrategyMerge, MergeStrategyRebase, MergeStrategyFF}
+
+// IsValidMergeStrategy reports whether s is a known merge strategy.
+func IsValidMergeStrategy(s string) bool {
+	return slices.Contains(MergeStrategies, s)
+}
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -1104,6 +1262,71 @@ func NewMessagingConfig() *MessagingConfig {
 	}
 }
 
+// MailRules is an identity's mail filter rules (config/mail-rules/<identity>.json).
+// The mail router evaluates them when a message is sent to that identity.
+// Rules are checked in order and every matching rule's actions apply, until
+// a matching rule with Stop set.
+type MailRules struct {
+	Type    string `json:"type"`    // "mail-rules"
+	Version int    `json:"version"` // schema version
+
+	Rules []MailRule `json:"rules"`
+}
+
+// MailRule is one filter rule: a match and the actions taken on a message
+// that matches it.
+type MailRule struct {
+	// Name identifies the rule in gt mail rules test output.
+	Name string `json:"name,omitempty"`
+
+	// Match selects the messages the rule applies to.
+	Match MailRuleMatch `json:"match"`
+
+	// Archive files the message straight into the archive instead of the
+	// inbox. The recipient isn't notified.
+	Archive bool `json:"archive,omitempty"`
+
+	// Forward sends a copy to each address. Copies are not filtered again.
+	Forward []string `json:"forward,omitempty"`
+
+	// Labels are added to the message (e.g. "noise", "ci").
+	Labels []string `json:"labels,omitempty"`
+
+	// Priority raises the message to at least this priority
+	// (low, normal, high, urgent). It never lowers it.
+	Priority string `json:"priority,omitempty"`
+
+	// Delivery overrides how the recipient is notified: "interrupt" nudges
+	// the session immediately, even when it's busy.
+	Delivery string `json:"delivery,omitempty"`
+
+	// Stop ends rule evaluation after this rule matches.
+	Stop bool `json:"stop,omitempty"`
+}
+
+// MailRuleMatch selects messages. Every field that is set must match; an
+// empty match matches every message.
+type MailRuleMatch struct {
+	// From is the sender address. '*' matches any single path segment
+	// ("gastown/polecats/*"). Addresses compare in canonical form.
+	From string `json:"from,omitempty"`
+
+	// Subject is a regular expression matched against the subject.
+	Subject string `json:"subject,omitempty"`
+
+	// Type is the message type (task, scavenge, notification, reply).
+	Type string `json:"type,omitempty"`
+
+	// Priority is the message priority (low, normal, high, urgent).
+	Priority string `json:"priority,omitempty"`
+
+	// Thread is the thread ID.
+	Thread string `json:"thread,omitempty"`
+}
+
+// CurrentMailRulesVersion is the current schema version for MailRules.
+const CurrentMailRulesVersion = 1
+
 // EscalationConfig represents escalation routing configuration (settings/escalation.json).
 // This defines severity-based routing for escalations to different channels.
 type EscalationConfig struct {
@@ -1118,12 +1341,33 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "webhook:<name>" → POST JSON to webhooks.<name>
 	//   - "log"         → Write to escalation log file
+	// email: and sms: take "human" for the configured contact, or an
+	// address/number directly (e.g., "email:oncall@example.com").
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// OnCall routes email:human and sms:human to whoever is on call.
+	// See OnCallSchedule.
+	OnCall *OnCallSchedule `json:"oncall,omitempty"`
+
+	// Email configures the SMTP server used by email: actions.
+	Email *EscalationEmail `json:"email,omitempty"`
+
+	// SMS configures the HTTP provider used by sms: actions.
+	SMS *EscalationSMS `json:"sms,omitempty"`
+
+	// Webhooks maps names to the generic JSON webhooks used by
+	// webhook:<name> actions.
+	Webhooks map[string]*EscalationWebhook `json:"webhooks,omitempty"`
+
+	// LogFile is where the log action appends, relative to the town root.
+	// Default: "logs/escalations.log"
+	LogFile string `json:"log_file,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1134,6 +1378,16 @@ type EscalationConfig struct {
 	// re-escalated. Default: 2 (low→medium→high, then stops)
 	// Pointer type to distinguish "not configured" (nil) from explicit 0.
 	MaxReescalations *int `json:"max_reescalations,omitempty"`
+
+	// DedupWindow is how long gt escalate folds repeats of an open
+	// escalation into it as occurrences, and groups related escalations
+	// from other rigs into an incident.
+	// Format: Go duration string; "0" disables. Default: "1h"
+	DedupWindow string `json:"dedup_window,omitempty"`
+
+	// ReplySeverity is the severity used when mail sent with --expect-reply
+	// goes unanswered after a reminder. Default: "medium"
+	ReplySeverity string `json:"reply_severity,omitempty"`
 }
 
 // EscalationContacts contains contact information for external notification channels.
@@ -1143,6 +1397,57 @@ type EscalationContacts struct {
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
 }
 
+// EscalationEmail configures SMTP delivery for email: actions.
+type EscalationEmail struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"` // default 587 (465 when tls is "tls")
+	Username string `json:"username,omitempty"`
+
+	// Password authenticates Username. PasswordEnv names an environment
+	// variable to read it from instead, to keep it out of the config file.
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+
+	From string `json:"from"`
+
+	// TLS is "starttls" (default, required before auth), "tls" for
+	// implicit TLS, or "none" for a plaintext relay.
+	TLS string `json:"tls,omitempty"`
+}
+
+// EscalationSMS configures the HTTP provider for sms: actions.
+// Header values and the URL have $VARS expanded from the environment, so
+// provider credentials can stay out of the config file.
+type EscalationSMS struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"` // default POST
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body is a text/template for the request body, executed with the
+	// escalation plus .To (the phone number) and .Message. Default is
+	// {"to": ..., "message": ...} as JSON.
+	Body string `json:"body,omitempty"`
+
+	// ContentType defaults to application/json.
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// EscalationWebhook configures a generic JSON webhook for webhook:<name> actions.
+type EscalationWebhook struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"` // $VARS expanded
+
+	// Template is a text/template for the JSON payload, executed with the
+	// escalation. Default is the escalation itself as JSON.
+	Template string `json:"template,omitempty"`
+
+	// Secret signs the payload with HMAC-SHA256, sent as
+	// X-Gastown-Signature: sha256=<hex>. SecretEnv names an environment
+	// variable to read it from instead.
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+}
+
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
 const CurrentEscalationVersion = 1
 
@@ -0,0 +1,29 @@
+// Package costs defines the session cost log that gt costs record appends
+// to. gt costs reads and digests it, and the daemon totals it for metrics.
+package costs
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogEntry represents a single entry in the costs.jsonl log file.
+type LogEntry struct {
+	SessionID string    `json:"session_id"`
+	Role      string    `json:"role"`
+	Rig       string    `json:"rig,omitempty"`
+	Worker    string    `json:"worker,omitempty"`
+	CostUSD   float64   `json:"cost_usd"`
+	EndedAt   time.Time `json:"ended_at"`
+	WorkItem  string    `json:"work_item,omitempty"`
+}
+
+// LogPath returns the path to the costs log file (~/.gt/costs.jsonl).
+func LogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt-costs.jsonl" // Fallback
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
@@ -26,8 +26,10 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -53,6 +55,7 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	metrics       *MetricsServer
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -265,6 +268,17 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start the Prometheus metrics endpoint if enabled in mayor/daemon.json.
+	if addr := metricsListenAddr(d.patrolConfig); addr != "" {
+		metrics := NewMetricsServer(d, addr)
+		if err := metrics.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start metrics server on %s: %v", addr, err)
+		} else {
+			d.metrics = metrics
+			d.logger.Printf("Metrics server listening on http://%s/metrics", addr)
+		}
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -445,6 +459,15 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 14. Release scheduled mail that is due (gt mail send --at/--after/--on-close).
+	d.releaseScheduledMail()
+
+	// 15. Follow up on unanswered mail sent with --expect-reply: remind, then escalate.
+	d.checkAwaitedReplies()
+
+	// 16. Retry dead-lettered protocol mail whose backoff has passed.
+	d.retryDeadLetters()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -455,6 +478,113 @@ func (d *Daemon) heartbeat(state *State) {
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// releaseScheduledMail delivers scheduled mail whose time has come or whose
+// bead has closed. Recipients that fail to receive a message stay scheduled
+// for the next heartbeat.
+func (d *Daemon) releaseScheduledMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	defer router.WaitPendingNotifications()
+
+	released, err := router.ReleaseScheduled(time.Now())
+	if err != nil {
+		d.logger.Printf("Warning: releasing scheduled mail: %v", err)
+	}
+	for _, sm := range released {
+		d.logger.Printf("Released scheduled mail %s to %s: %s", sm.ID, sm.Message.To, sm.Message.Subject)
+		_ = events.LogFeed(events.TypeMail, sm.Message.From, events.MailPayload(sm.Message.To, sm.Message.Subject))
+	}
+}
+
+// checkAwaitedReplies follows up on mail sent with --expect-reply whose
+// reply is overdue: the first time by re-sending it as a reminder, the
+// second by escalating with gt escalate.
+func (d *Daemon) checkAwaitedReplies() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	defer router.WaitPendingNotifications()
+
+	followed, err := router.CheckAwaiting(time.Now(), d.escalateUnanswered)
+	if err != nil {
+		d.logger.Printf("Warning: checking awaited replies: %v", err)
+	}
+	for _, a := range followed {
+		if a.Escalation != "" {
+			d.logger.Printf("Escalated unanswered mail %s to %s as %s: %s", a.ID, a.To, a.Escalation, a.Subject)
+			continue
+		}
+		d.logger.Printf("Reminded %s of unanswered mail %s: %s", a.To, a.ID, a.Subject)
+		_ = events.LogFeed(events.TypeMail, a.From, events.MailPayload(a.To, "Reminder: "+a.Subject))
+	}
+}
+
+// escalateUnanswered raises an escalation for a question that went
+// unanswered after a reminder and returns the escalation's ID.
+func (d *Daemon) escalateUnanswered(a *mail.AwaitedReply) (string, error) {
+	severity := a.Severity
+	if severity == "" {
+		cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(d.config.TownRoot))
+		if err != nil {
+			return "", fmt.Errorf("loading escalation config: %w", err)
+		}
+		severity = cfg.GetReplySeverity()
+	}
+	description := fmt.Sprintf("No reply from %s to %q", a.To, a.Subject)
+	reason := fmt.Sprintf("%s asked %s on %s and expected a reply within %s (thread %s). A reminder was sent and also went unanswered.",
+		a.From, a.To, a.SentAt.Local().Format("2006-01-02 15:04"), a.Window, a.ThreadID)
+
+	cmd := exec.Command(d.gtPath, "escalate", "-s", severity, "--source", "mail:expect-reply", //nolint:gosec // G204: args are constructed internally
+		"--reason", reason, "--json", "--", description)
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("gt escalate: %w", err)
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil || result.ID == "" {
+		return "", fmt.Errorf("gt escalate: unexpected output %q", strings.TrimSpace(string(out)))
+	}
+	return result.ID, nil
+}
+
+// retryDeadLetters retries each rig's dead-lettered protocol mail that is
+// due. Letters that fail again back off further; see gt mail dlq.
+func (d *Daemon) retryDeadLetters() {
+	now := time.Now()
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		letters, err := mail.OpenDeadLetters(rigPath).List()
+		if err != nil {
+			d.logger.Printf("Warning: %s: listing dead letters: %v", rigName, err)
+			continue
+		}
+		due := false
+		for _, dl := range letters {
+			due = due || (!dl.Exhausted() && !now.Before(dl.NextRetryAt))
+		}
+		if !due {
+			continue
+		}
+
+		registry, err := protocol.NewRigRegistry(d.config.TownRoot, rigName, rigPath, d.logger.Writer())
+		if err != nil {
+			d.logger.Printf("Warning: %s: retrying dead letters: %v", rigName, err)
+			continue
+		}
+		resolved, failed, err := registry.RetryDeadLetters(now)
+		if err != nil {
+			d.logger.Printf("Warning: %s: retrying dead letters: %v", rigName, err)
+		}
+		for _, dl := range resolved {
+			d.logger.Printf("Handled dead-lettered mail %s in %s: %s", dl.ID, rigName, dl.Message.Subject)
+		}
+		for _, dl := range failed {
+			d.logger.Printf("Dead-lettered mail %s in %s failed again (attempt %d): %s", dl.ID, rigName, dl.Attempts, dl.Error)
+		}
+	}
+}
+
 // ensureDoltServerRunning ensures the Dolt SQL server is running if configured.
 // This provides the backend for beads database access in server mode.
 func (d *Daemon) ensureDoltServerRunning() {
@@ -1119,6 +1249,12 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop metrics server
+	if d.metrics != nil {
+		d.metrics.Stop()
+		d.logger.Println("Metrics server stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
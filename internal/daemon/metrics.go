@@ -0,0 +1,565 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// DefaultMetricsListen is where the metrics endpoint listens when enabled
+// without an address. Loopback only: the metrics name agents and rigs.
+const DefaultMetricsListen = "127.0.0.1:9478"
+
+// metricsCacheTTL bounds how often a scrape recollects. Several collectors
+// shell out to bd and tmux, so back-to-back scrapes share one collection.
+const metricsCacheTTL = 15 * time.Second
+
+// metricsContentType is the Prometheus text exposition format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsServer serves the daemon's state on /metrics in Prometheus text
+// format. Collection happens on scrape, so nothing is computed unless
+// something is scraping.
+type MetricsServer struct {
+	daemon *Daemon
+	server *http.Server
+
+	mu          sync.Mutex
+	body        []byte
+	collectedAt time.Time
+}
+
+// metricsListenAddr returns the configured metrics address, or "" if the
+// endpoint is disabled.
+func metricsListenAddr(config *DaemonPatrolConfig) string {
+	if config == nil || config.Metrics == nil || !config.Metrics.Enabled {
+		return ""
+	}
+	if config.Metrics.Listen != "" {
+		return config.Metrics.Listen
+	}
+	return DefaultMetricsListen
+}
+
+// NewMetricsServer creates a metrics server for d listening on addr.
+func NewMetricsServer(d *Daemon, addr string) *MetricsServer {
+	m := &MetricsServer{daemon: d}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	m.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return m
+}
+
+// Start binds the listener and serves in the background.
+func (m *MetricsServer) Start() error {
+	ln, err := net.Listen("tcp", m.server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := m.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			m.daemon.logger.Printf("Metrics server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop shuts the listener down, waiting briefly for in-flight scrapes.
+func (m *MetricsServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = m.server.Shutdown(ctx)
+}
+
+func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", metricsContentType)
+	_, _ = w.Write(m.scrape(time.Now()))
+}
+
+// scrape returns the exposition, recollecting if the cached one is older
+// than metricsCacheTTL.
+func (m *MetricsServer) scrape(now time.Time) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.body == nil || now.Sub(m.collectedAt) >= metricsCacheTTL {
+		m.body = m.daemon.collectMetrics(now)
+		m.collectedAt = now
+	}
+	return m.body
+}
+
+// metricsCollector writes one group of metrics. A collector that fails
+// still keeps whatever it wrote; the failure shows up in
+// gastown_metrics_collector_success.
+type metricsCollector struct {
+	name    string
+	collect func(w *metricsWriter, now time.Time) error
+}
+
+// collectMetrics runs every collector and returns the exposition.
+func (d *Daemon) collectMetrics(now time.Time) []byte {
+	collectors := []metricsCollector{
+		{"heartbeats", d.collectHeartbeatMetrics},
+		{"sessions", d.collectSessionMetrics},
+		{"restarts", d.collectRestartMetrics},
+		{"merge_queue", d.collectMergeQueueMetrics},
+		{"nudge_queue", d.collectNudgeQueueMetrics},
+		{"mail", d.collectMailMetrics},
+		{"dolt", d.collectDoltMetrics},
+		{"quota", d.collectQuotaMetrics},
+		{"costs", d.collectCostMetrics},
+	}
+
+	w := &metricsWriter{}
+	success := make(map[string]bool, len(collectors))
+	durations := make(map[string]time.Duration, len(collectors))
+	for _, c := range collectors {
+		start := time.Now()
+		err := c.collect(w, now)
+		durations[c.name] = time.Since(start)
+		success[c.name] = err == nil
+		if err != nil {
+			d.logger.Printf("Metrics: %s collector: %v", c.name, err)
+		}
+	}
+
+	w.family("gastown_metrics_collector_success", "gauge", "Whether the last collection of each metrics group succeeded.")
+	for _, c := range collectors {
+		w.sample("gastown_metrics_collector_success", boolValue(success[c.name]), "collector", c.name)
+	}
+	w.family("gastown_metrics_collector_duration_seconds", "gauge", "How long the last collection of each metrics group took.")
+	for _, c := range collectors {
+		w.sample("gastown_metrics_collector_duration_seconds", durations[c.name].Seconds(), "collector", c.name)
+	}
+	return w.Bytes()
+}
+
+// collectHeartbeatMetrics reports the daemon's own heartbeat and the
+// Deacon's, as ages so alerts don't need to know the intervals.
+func (d *Daemon) collectHeartbeatMetrics(w *metricsWriter, now time.Time) error {
+	state, err := LoadState(d.config.TownRoot)
+	if err != nil {
+		return fmt.Errorf("loading daemon state: %w", err)
+	}
+
+	w.family("gastown_daemon_up", "gauge", "Always 1 while the daemon is serving metrics.")
+	w.sample("gastown_daemon_up", 1)
+	if !state.StartedAt.IsZero() {
+		w.family("gastown_daemon_start_time_seconds", "gauge", "When the daemon started, as a Unix timestamp.")
+		w.sample("gastown_daemon_start_time_seconds", unixSeconds(state.StartedAt))
+	}
+	w.family("gastown_daemon_heartbeats_total", "counter", "Recovery heartbeats completed since the daemon started.")
+	w.sample("gastown_daemon_heartbeats_total", float64(state.HeartbeatCount))
+
+	w.family("gastown_heartbeat_age_seconds", "gauge", "Seconds since the daemon's last heartbeat and the Deacon's last wake cycle.")
+	if !state.LastHeartbeat.IsZero() {
+		w.sample("gastown_heartbeat_age_seconds", now.Sub(state.LastHeartbeat).Seconds(), "agent", "daemon")
+	}
+	if hb := deacon.ReadHeartbeat(d.config.TownRoot); hb != nil {
+		w.sample("gastown_heartbeat_age_seconds", now.Sub(hb.Timestamp).Seconds(), "agent", "deacon")
+	}
+	return nil
+}
+
+// collectSessionMetrics counts live agent sessions by role and rig.
+// Sessions for rigs the town doesn't know about are not Gas Town agents
+// and are left out.
+func (d *Daemon) collectSessionMetrics(w *metricsWriter, now time.Time) error {
+	w.family("gastown_agent_sessions", "gauge", "Live agent tmux sessions by role and rig.")
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+
+	knownRigs := d.getKnownRigs()
+	type roleRig struct{ role, rig string }
+	counts := make(map[roleRig]int)
+	for _, name := range sessions {
+		identity, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		if identity.Rig != "" && !slices.Contains(knownRigs, identity.Rig) {
+			continue
+		}
+		counts[roleRig{string(identity.Role), identity.Rig}]++
+	}
+
+	keys := make([]roleRig, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rig != keys[j].rig {
+			return keys[i].rig < keys[j].rig
+		}
+		return keys[i].role < keys[j].role
+	})
+	for _, k := range keys {
+		w.sample("gastown_agent_sessions", float64(counts[k]), "role", k.role, "rig", k.rig)
+	}
+	return nil
+}
+
+// collectRestartMetrics reports the restart tracker's per-agent state.
+func (d *Daemon) collectRestartMetrics(w *metricsWriter, now time.Time) error {
+	agents := d.restartTracker.Snapshot()
+	ids := sortedKeys(agents)
+
+	w.family("gastown_agent_restarts", "gauge", "Restarts counted toward each agent's backoff; reset once the agent is stable.")
+	for _, id := range ids {
+		w.sample("gastown_agent_restarts", float64(agents[id].RestartCount), "agent", id)
+	}
+	w.family("gastown_agent_crash_loop", "gauge", "1 if the agent is crash-looping and will not be restarted automatically.")
+	for _, id := range ids {
+		w.sample("gastown_agent_crash_loop", boolValue(!agents[id].CrashLoopSince.IsZero()), "agent", id)
+	}
+	w.family("gastown_agent_restart_backoff_seconds", "gauge", "Seconds until the agent may be restarted again.")
+	for _, id := range ids {
+		w.sample("gastown_agent_restart_backoff_seconds", max(agents[id].BackoffUntil.Sub(now).Seconds(), 0), "agent", id)
+	}
+	return nil
+}
+
+// collectMergeQueueMetrics reports each rig's open merge requests and how
+// long they have been waiting.
+func (d *Daemon) collectMergeQueueMetrics(w *metricsWriter, now time.Time) error {
+	type queueStats struct {
+		depth         int
+		oldest, total time.Duration
+	}
+	rigs := d.getKnownRigs()
+	sort.Strings(rigs)
+
+	stats := make(map[string]*queueStats, len(rigs))
+	var errs []string
+	for _, rigName := range rigs {
+		issues, err := beads.New(filepath.Join(d.config.TownRoot, rigName)).List(beads.ListOptions{
+			Label:    "gt:merge-request",
+			Status:   "open",
+			Priority: -1,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		s := &queueStats{}
+		for _, issue := range issues {
+			if issue.Status != "open" {
+				continue
+			}
+			s.depth++
+			if created, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+				wait := now.Sub(created)
+				s.total += wait
+				s.oldest = max(s.oldest, wait)
+			}
+		}
+		stats[rigName] = s
+	}
+
+	w.family("gastown_merge_queue_depth", "gauge", "Open merge requests waiting in each rig's queue.")
+	for _, rigName := range rigs {
+		if s := stats[rigName]; s != nil {
+			w.sample("gastown_merge_queue_depth", float64(s.depth), "rig", rigName)
+		}
+	}
+	w.family("gastown_merge_queue_oldest_wait_seconds", "gauge", "How long the oldest open merge request has been queued.")
+	for _, rigName := range rigs {
+		if s := stats[rigName]; s != nil {
+			w.sample("gastown_merge_queue_oldest_wait_seconds", s.oldest.Seconds(), "rig", rigName)
+		}
+	}
+	w.family("gastown_merge_queue_wait_seconds_sum", "gauge", "Total time open merge requests have been queued; divide by depth for the mean.")
+	for _, rigName := range rigs {
+		if s := stats[rigName]; s != nil {
+			w.sample("gastown_merge_queue_wait_seconds_sum", s.total.Seconds(), "rig", rigName)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("listing merge requests: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// collectNudgeQueueMetrics reports queued nudges per session.
+func (d *Daemon) collectNudgeQueueMetrics(w *metricsWriter, now time.Time) error {
+	w.family("gastown_nudge_queue_depth", "gauge", "Nudges queued for each session, waiting for its next turn.")
+	entries, err := os.ReadDir(filepath.Join(d.config.TownRoot, constants.DirRuntime, "nudge_queue"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading nudge queues: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pending, err := nudge.Pending(d.config.TownRoot, entry.Name())
+		if err != nil {
+			return err
+		}
+		w.sample("gastown_nudge_queue_depth", float64(pending), "session", entry.Name())
+	}
+	return nil
+}
+
+// collectMailMetrics reports the mail backlog: open messages per mailbox,
+// dead-lettered protocol mail per rig, and scheduled mail.
+func (d *Daemon) collectMailMetrics(w *metricsWriter, now time.Time) error {
+	var errs []string
+
+	w.family("gastown_mail_backlog", "gauge", "Open (unarchived) messages in each mailbox.")
+	messages, err := beads.New(d.config.TownRoot).List(beads.ListOptions{
+		Label:    "gt:message",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("listing messages: %v", err))
+	} else {
+		backlog := make(map[string]int)
+		for _, msg := range messages {
+			if msg.Assignee != "" {
+				backlog[msg.Assignee]++
+			}
+		}
+		for _, mailbox := range sortedKeys(backlog) {
+			w.sample("gastown_mail_backlog", float64(backlog[mailbox]), "mailbox", mailbox)
+		}
+	}
+
+	w.family("gastown_mail_dead_letters", "gauge", "Protocol mail in each rig's dead-letter queue.")
+	rigs := d.getKnownRigs()
+	sort.Strings(rigs)
+	for _, rigName := range rigs {
+		letters, err := mail.OpenDeadLetters(filepath.Join(d.config.TownRoot, rigName)).List()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: listing dead letters: %v", rigName, err))
+			continue
+		}
+		w.sample("gastown_mail_dead_letters", float64(len(letters)), "rig", rigName)
+	}
+
+	scheduled, err := mail.NewSchedule(d.config.TownRoot).List()
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("listing scheduled mail: %v", err))
+	} else {
+		w.family("gastown_mail_scheduled", "gauge", "Messages scheduled for later delivery.")
+		w.sample("gastown_mail_scheduled", float64(len(scheduled)))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// collectDoltMetrics reports whether the Dolt server is up and, if so, its
+// query latency and connection count. It skips the read-only write probe
+// that GetHealthMetrics does; the health check ticker covers that.
+func (d *Daemon) collectDoltMetrics(w *metricsWriter, now time.Time) error {
+	running, _, err := doltserver.IsRunning(d.config.TownRoot)
+	w.family("gastown_dolt_up", "gauge", "1 if the Dolt SQL server is running.")
+	w.sample("gastown_dolt_up", boolValue(running))
+	if err != nil {
+		return fmt.Errorf("checking Dolt server: %w", err)
+	}
+	if !running {
+		return nil
+	}
+
+	latency, err := doltserver.MeasureQueryLatency(d.config.TownRoot)
+	if err != nil {
+		return err
+	}
+	w.family("gastown_dolt_query_latency_seconds", "gauge", "Round-trip time of a SELECT 1 against the Dolt server.")
+	w.sample("gastown_dolt_query_latency_seconds", latency.Seconds())
+
+	connections, err := doltserver.GetActiveConnectionCount(d.config.TownRoot)
+	if err != nil {
+		return err
+	}
+	w.family("gastown_dolt_connections", "gauge", "Active connections to the Dolt server.")
+	w.sample("gastown_dolt_connections", float64(connections))
+	maxConnections := doltserver.DefaultConfig(d.config.TownRoot).MaxConnections
+	if maxConnections <= 0 {
+		maxConnections = 1000 // Dolt default
+	}
+	w.family("gastown_dolt_max_connections", "gauge", "Configured maximum connections to the Dolt server.")
+	w.sample("gastown_dolt_max_connections", float64(maxConnections))
+	return nil
+}
+
+// collectQuotaMetrics counts accounts by quota status.
+func (d *Daemon) collectQuotaMetrics(w *metricsWriter, now time.Time) error {
+	state, err := quota.NewManager(d.config.TownRoot).Load()
+	if err != nil {
+		return fmt.Errorf("loading quota state: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, account := range state.Accounts {
+		counts[string(account.Status)]++
+	}
+	w.family("gastown_quota_accounts", "gauge", "Accounts by rate-limit status.")
+	for _, status := range sortedKeys(counts) {
+		w.sample("gastown_quota_accounts", float64(counts[status]), "status", status)
+	}
+	return nil
+}
+
+// collectCostMetrics totals recorded session costs by role and rig.
+// gt costs digest rolls the log up, so these can drop; Prometheus treats
+// that as a counter reset.
+func (d *Daemon) collectCostMetrics(w *metricsWriter, now time.Time) error {
+	w.family("gastown_session_cost_usd_total", "counter", "Recorded session cost in USD, from gt costs record.")
+	f, err := os.Open(costs.LogPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening costs log: %w", err)
+	}
+	defer f.Close()
+
+	type roleRig struct{ role, rig string }
+	totals := make(map[roleRig]float64)
+	sessions := make(map[roleRig]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry costs.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip malformed lines
+		}
+		k := roleRig{entry.Role, entry.Rig}
+		totals[k] += entry.CostUSD
+		sessions[k]++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading costs log: %w", err)
+	}
+
+	keys := make([]roleRig, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rig != keys[j].rig {
+			return keys[i].rig < keys[j].rig
+		}
+		return keys[i].role < keys[j].role
+	})
+	for _, k := range keys {
+		w.sample("gastown_session_cost_usd_total", totals[k], "role", k.role, "rig", k.rig)
+	}
+	w.family("gastown_sessions_recorded_total", "counter", "Sessions whose cost was recorded, from gt costs record.")
+	for _, k := range keys {
+		w.sample("gastown_sessions_recorded_total", float64(sessions[k]), "role", k.role, "rig", k.rig)
+	}
+	return nil
+}
+
+// metricsWriter writes the Prometheus text exposition format. Each family
+// must be followed directly by its samples.
+type metricsWriter struct {
+	buf bytes.Buffer
+}
+
+// family writes the HELP and TYPE lines for a metric.
+func (w *metricsWriter) family(name, typ, help string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one sample; labels are name/value pairs.
+func (w *metricsWriter) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, `%s="%s"`, labels[i], escapeLabelValue(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(formatMetricValue(value))
+	w.buf.WriteByte('\n')
+}
+
+// Bytes returns everything written so far.
+func (w *metricsWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestMetricsWriter(t *testing.T) {
+	w := &metricsWriter{}
+	w.family("gastown_test", "gauge", "A test\nmetric.")
+	w.sample("gastown_test", 1.5, "rig", "gastown", "note", `say "hi"\`)
+	w.sample("gastown_test", math.Inf(1))
+	w.sample("gastown_test", 3)
+
+	want := `# HELP gastown_test A test\nmetric.
+# TYPE gastown_test gauge
+gastown_test{rig="gastown",note="say \"hi\"\\"} 1.5
+gastown_test +Inf
+gastown_test 3
+`
+	if got := string(w.Bytes()); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMetricsListenAddr(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *DaemonPatrolConfig
+		want   string
+	}{
+		{"no config", nil, ""},
+		{"no metrics section", &DaemonPatrolConfig{}, ""},
+		{"disabled", &DaemonPatrolConfig{Metrics: &MetricsConfig{Listen: ":9000"}}, ""},
+		{"default address", &DaemonPatrolConfig{Metrics: &MetricsConfig{Enabled: true}}, DefaultMetricsListen},
+		{"custom address", &DaemonPatrolConfig{Metrics: &MetricsConfig{Enabled: true, Listen: ":9000"}}, ":9000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metricsListenAddr(tt.config); got != tt.want {
+				t.Errorf("metricsListenAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectMetrics(t *testing.T) {
+	townRoot := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	now := time.Now()
+
+	// Daemon state and a Deacon heartbeat two minutes old
+	if err := SaveState(townRoot, &State{Running: true, StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-time.Minute), HeartbeatCount: 7}); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(townRoot, "deacon", "heartbeat.json"),
+		`{"timestamp":"`+now.Add(-2*time.Minute).Format(time.RFC3339)+`","cycle":3}`)
+
+	// Two queued nudges for one session
+	queue := filepath.Join(townRoot, constants.DirRuntime, "nudge_queue", "gt-witness")
+	writeTestFile(t, filepath.Join(queue, "1.json"), "{}")
+	writeTestFile(t, filepath.Join(queue, "2.json"), "{}")
+
+	// Recorded session costs
+	writeTestFile(t, filepath.Join(home, ".gt", "costs.jsonl"),
+		`{"session_id":"gt-nux","role":"polecat","rig":"gastown","cost_usd":1.25}`+"\n"+
+			`{"session_id":"gt-max","role":"polecat","rig":"gastown","cost_usd":0.75}`+"\n"+
+			"not json\n"+
+			`{"session_id":"hq-mayor","role":"mayor","cost_usd":3}`+"\n")
+
+	tracker := NewRestartTracker(townRoot)
+	for i := 0; i < crashLoopCount; i++ {
+		tracker.RecordRestart("gastown/witness")
+	}
+	tracker.RecordRestart("hq-deacon")
+
+	d := &Daemon{
+		config:         &Config{TownRoot: townRoot},
+		tmux:           tmux.NewTmux(),
+		logger:         log.New(io.Discard, "", 0),
+		restartTracker: tracker,
+	}
+	server := NewMetricsServer(d, "127.0.0.1:0")
+	rec := httptest.NewRecorder()
+	server.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != metricsContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"gastown_daemon_up 1\n",
+		"gastown_daemon_heartbeats_total 7\n",
+		`gastown_heartbeat_age_seconds{agent="deacon"} 1`, // ~120s
+		`gastown_agent_restarts{agent="gastown/witness"} 5`,
+		`gastown_agent_crash_loop{agent="gastown/witness"} 1`,
+		`gastown_agent_crash_loop{agent="hq-deacon"} 0`,
+		`gastown_nudge_queue_depth{session="gt-witness"} 2`,
+		`gastown_session_cost_usd_total{role="polecat",rig="gastown"} 2`,
+		`gastown_session_cost_usd_total{role="mayor",rig=""} 3`,
+		`gastown_sessions_recorded_total{role="polecat",rig="gastown"} 2`,
+		`gastown_metrics_collector_success{collector="costs"} 1`,
+		"# TYPE gastown_merge_queue_depth gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	// A second scrape within the TTL is served from cache
+	collectedAt := server.collectedAt
+	server.scrape(collectedAt.Add(time.Second))
+	if !server.collectedAt.Equal(collectedAt) {
+		t.Error("scrape within TTL recollected")
+	}
+	server.scrape(collectedAt.Add(metricsCacheTTL))
+	if server.collectedAt.Equal(collectedAt) {
+		t.Error("scrape after TTL served stale metrics")
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
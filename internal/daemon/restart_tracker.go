@@ -189,6 +189,18 @@ func (rt *RestartTracker) GetBackoffRemaining(agentID string) time.Duration {
 	return remaining
 }
 
+// Snapshot returns a copy of the restart info for every tracked agent.
+func (rt *RestartTracker) Snapshot() map[string]AgentRestartInfo {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	agents := make(map[string]AgentRestartInfo, len(rt.state.Agents))
+	for id, info := range rt.state.Agents {
+		agents[id] = *info
+	}
+	return agents
+}
+
 // ClearCrashLoop manually clears the crash loop state for an agent.
 func (rt *RestartTracker) ClearCrashLoop(agentID string) {
 	rt.mu.Lock()
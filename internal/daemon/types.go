@@ -140,12 +140,23 @@ type DoltRemotesConfig struct {
 	Branch string `json:"branch,omitempty"`
 }
 
+// MetricsConfig holds configuration for the daemon's Prometheus endpoint.
+// The endpoint is opt-in: nothing listens unless Enabled is set.
+type MetricsConfig struct {
+	// Enabled starts an HTTP listener serving /metrics.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address to listen on (default 127.0.0.1:9478).
+	Listen string `json:"listen,omitempty"`
+}
+
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
 	Type      string         `json:"type"`
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+	Metrics   *MetricsConfig `json:"metrics,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
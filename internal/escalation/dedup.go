@@ -0,0 +1,110 @@
+package escalation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// digitsRe matches the parts of a reason that vary between repeats of the
+// same problem: ports, counts, durations, timestamps.
+var digitsRe = regexp.MustCompile(`[0-9]+`)
+
+// normalizeReason reduces a reason to the part that identifies the problem.
+func normalizeReason(reason string) string {
+	reason = digitsRe.ReplaceAllString(strings.ToLower(reason), "#")
+	return strings.Join(strings.Fields(reason), " ")
+}
+
+func hashKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Fingerprint identifies repeats of one escalation: the same reason (with
+// numbers ignored) about the same related bead from the same rig.
+func Fingerprint(reason, relatedBead, rig string) string {
+	return hashKey(normalizeReason(reason), relatedBead, rig)
+}
+
+// IncidentKey identifies related escalations across rigs: the same reason
+// about the same related bead, wherever it came from.
+func IncidentKey(reason, relatedBead string) string {
+	return hashKey(normalizeReason(reason), relatedBead)
+}
+
+// RigFromAddress returns the rig in an agent address ("gastown/witness"),
+// or "" for town-level agents (mayor, deacon, overseer).
+func RigFromAddress(address string) string {
+	rig, _, ok := strings.Cut(strings.TrimSuffix(address, "/"), "/")
+	if !ok {
+		return ""
+	}
+	return rig
+}
+
+// Open is an open escalation bead with its parsed fields.
+type Open struct {
+	Issue  *beads.Issue
+	Fields *beads.EscalationFields
+}
+
+// Match is how a new escalation relates to the open ones.
+type Match struct {
+	// Duplicate is an open escalation with the same fingerprint; the new
+	// one is a repeat of it.
+	Duplicate *Open
+
+	// Incident is an open incident with the same incident key.
+	Incident *Open
+
+	// Related are open escalations with the same incident key, from other
+	// rigs, not yet grouped into an incident.
+	Related []*Open
+}
+
+// MatchOpen finds the open escalations a new escalation with fingerprint
+// and incidentKey repeats or relates to, among those seen since since.
+func MatchOpen(open []*beads.Issue, fingerprint, incidentKey string, since time.Time) *Match {
+	match := &Match{}
+	for _, issue := range open {
+		fields := beads.ParseEscalationFields(issue.Description)
+		if fields.LastSeen().Before(since) {
+			continue
+		}
+		o := &Open{Issue: issue, Fields: fields}
+		switch {
+		case beads.HasLabel(issue, beads.IncidentLabel):
+			if fields.IncidentKey == incidentKey && match.Incident == nil {
+				match.Incident = o
+			}
+		case fields.Fingerprint == fingerprint:
+			if match.Duplicate == nil {
+				match.Duplicate = o
+			}
+		case fields.IncidentKey == incidentKey && fields.Incident == "":
+			match.Related = append(match.Related, o)
+		}
+	}
+	return match
+}
+
+// SeverityRank orders severities from low (0) to critical (3); unknown
+// severities rank lowest.
+func SeverityRank(severity string) int {
+	return max(slices.Index(config.ValidSeverities(), severity), 0)
+}
+
+// HigherSeverity returns the higher of two severities.
+func HigherSeverity(a, b string) string {
+	if SeverityRank(b) > SeverityRank(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,86 @@
+package escalation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("Dolt server unreachable: dial tcp 127.0.0.1:3307", "", "gastown")
+	if b := Fingerprint("dolt server  UNREACHABLE: dial tcp 127.0.0.1:3308", "", "gastown"); a != b {
+		t.Errorf("numbers, case, and spacing should not change the fingerprint: %s != %s", a, b)
+	}
+	if b := Fingerprint("Dolt server unreachable: dial tcp 127.0.0.1:3307", "", "beads"); a == b {
+		t.Error("different rigs should have different fingerprints")
+	}
+	if b := Fingerprint("Dolt server unreachable: dial tcp 127.0.0.1:3307", "gt-abc", "gastown"); a == b {
+		t.Error("different related beads should have different fingerprints")
+	}
+	if IncidentKey("Dolt down", "") != IncidentKey("dolt down", "") || IncidentKey("Dolt down", "") == IncidentKey("Tests failing", "") {
+		t.Error("incident keys should match on reason only")
+	}
+}
+
+func TestRigFromAddress(t *testing.T) {
+	for addr, want := range map[string]string{
+		"gastown/witness":      "gastown",
+		"gastown/polecats/nux": "gastown",
+		"mayor/":               "",
+		"deacon":               "",
+		"":                     "",
+	} {
+		if got := RigFromAddress(addr); got != want {
+			t.Errorf("RigFromAddress(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestMatchOpen(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fp := Fingerprint("Dolt down", "", "gastown")
+	key := IncidentKey("Dolt down", "")
+	escalationBead := func(id, fingerprint, incidentKey, incident string, lastSeen time.Time, labels ...string) *beads.Issue {
+		return &beads.Issue{
+			ID:     id,
+			Labels: append([]string{"gt:escalation"}, labels...),
+			Description: beads.FormatEscalationDescription("Dolt down", &beads.EscalationFields{
+				Severity:    "high",
+				EscalatedAt: lastSeen.Add(-time.Hour).Format(time.RFC3339),
+				LastSeenAt:  lastSeen.Format(time.RFC3339),
+				Fingerprint: fingerprint,
+				IncidentKey: incidentKey,
+				Incident:    incident,
+			}),
+		}
+	}
+	open := []*beads.Issue{
+		escalationBead("hq-old", fp, key, "", now.Add(-3*time.Hour)), // outside window
+		escalationBead("hq-dup", fp, key, "", now.Add(-10*time.Minute)),
+		escalationBead("hq-beads", Fingerprint("Dolt down", "", "beads"), key, "", now.Add(-5*time.Minute)),
+		escalationBead("hq-grouped", Fingerprint("Dolt down", "", "wyvern"), key, "hq-inc", now.Add(-5*time.Minute)),
+		escalationBead("hq-other", Fingerprint("Tests failing", "", "beads"), IncidentKey("Tests failing", ""), "", now),
+		escalationBead("hq-inc", "", key, "", now.Add(-5*time.Minute), beads.IncidentLabel),
+	}
+
+	match := MatchOpen(open, fp, key, now.Add(-time.Hour))
+	if match.Duplicate == nil || match.Duplicate.Issue.ID != "hq-dup" {
+		t.Errorf("Duplicate = %+v, want hq-dup", match.Duplicate)
+	}
+	if match.Incident == nil || match.Incident.Issue.ID != "hq-inc" {
+		t.Errorf("Incident = %+v, want hq-inc", match.Incident)
+	}
+	if len(match.Related) != 1 || match.Related[0].Issue.ID != "hq-beads" {
+		t.Errorf("Related = %+v, want [hq-beads]", match.Related)
+	}
+}
+
+func TestHigherSeverity(t *testing.T) {
+	if got := HigherSeverity("medium", "critical"); got != "critical" {
+		t.Errorf("HigherSeverity(medium, critical) = %s", got)
+	}
+	if got := HigherSeverity("high", "low"); got != "high" {
+		t.Errorf("HigherSeverity(high, low) = %s", got)
+	}
+}
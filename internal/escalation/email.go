@@ -0,0 +1,108 @@
+package escalation
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpTimeout bounds a whole SMTP session, from connecting to QUIT, so a
+// stalled server can't hang gt escalate (and the daemon waiting on it).
+const smtpTimeout = 30 * time.Second
+
+// sendEmail sends esc to the address to over the configured SMTP server.
+func (n *Notifier) sendEmail(to string, esc *Escalation) error {
+	cfg := n.cfg.Email
+	if cfg == nil {
+		return notConfigured("email (SMTP) not configured")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if cfg.TLS == "tls" {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12}
+
+	deadline := time.Now().Add(n.smtpTimeout)
+	var conn net.Conn
+	var err error
+	if cfg.TLS == "tls" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Deadline: deadline}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = (&net.Dialer{Deadline: deadline}).Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if cfg.TLS == "" || cfg.TLS == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS (set email.tls to \"none\" for a plaintext relay)", addr)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		auth := smtp.PlainAuth("", cfg.Username, secret(cfg.Password, cfg.PasswordEnv), cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp RCPT TO %s: %w", to, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(formatEmail(cfg.From, to, esc, n.now())); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	return c.Quit()
+}
+
+// formatEmail returns the RFC 5322 message for esc.
+func formatEmail(from, to string, esc *Escalation, now time.Time) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", from)
+	fmt.Fprintf(&sb, "To: %s\r\n", to)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", sanitizeHeader(esc.Subject()))
+	fmt.Fprintf(&sb, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&sb, "X-Gastown-Escalation: %s\r\n", esc.ID)
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(esc.Text(), "\n", "\r\n"))
+	return []byte(sb.String())
+}
+
+// sanitizeHeader keeps titles from injecting extra headers.
+func sanitizeHeader(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
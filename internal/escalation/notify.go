@@ -0,0 +1,259 @@
+// Package escalation delivers escalations to channels outside Gas Town:
+// email over SMTP, Slack incoming webhooks, SMS through an HTTP provider,
+// generic JSON webhooks, and the escalation log. It also fingerprints
+// escalations so gt escalate can fold repeats and group related ones into
+// incidents.
+package escalation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Escalation is what gets delivered. It is also the data passed to
+// webhook and SMS payload templates, so field names are part of the
+// config surface.
+type Escalation struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity"`
+	Reason      string `json:"reason,omitempty"`
+	Source      string `json:"source,omitempty"`
+	EscalatedBy string `json:"escalated_by"`
+	EscalatedAt string `json:"escalated_at"`
+	RelatedBead string `json:"related_bead,omitempty"`
+
+	// ReescalationCount is non-zero when gt escalate stale bumped it.
+	ReescalationCount int `json:"reescalation_count,omitempty"`
+}
+
+// FromBead builds the Escalation for an escalation bead.
+func FromBead(issue *beads.Issue, fields *beads.EscalationFields) *Escalation {
+	return &Escalation{
+		ID:                issue.ID,
+		Title:             issue.Title,
+		Severity:          fields.Severity,
+		Reason:            fields.Reason,
+		Source:            fields.Source,
+		EscalatedBy:       fields.EscalatedBy,
+		EscalatedAt:       fields.EscalatedAt,
+		RelatedBead:       fields.RelatedBead,
+		ReescalationCount: fields.ReescalationCount,
+	}
+}
+
+// Rig returns the rig the escalation came from, from the escalating
+// agent's address, or "" for town-level agents (mayor, deacon, overseer).
+func (e *Escalation) Rig() string {
+	return RigFromAddress(e.EscalatedBy)
+}
+
+// Subject returns a one-line summary, e.g. "[HIGH] Build failure".
+func (e *Escalation) Subject() string {
+	if e.ReescalationCount > 0 {
+		return fmt.Sprintf("[%s] Re-escalated: %s", strings.ToUpper(e.Severity), e.Title)
+	}
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(e.Severity), e.Title)
+}
+
+// Text returns the plain-text body used for email and Slack.
+func (e *Escalation) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Escalation ID: %s\n", e.ID)
+	fmt.Fprintf(&sb, "Severity: %s\n", e.Severity)
+	fmt.Fprintf(&sb, "From: %s\n", e.EscalatedBy)
+	if e.Source != "" {
+		fmt.Fprintf(&sb, "Source: %s\n", e.Source)
+	}
+	if e.RelatedBead != "" {
+		fmt.Fprintf(&sb, "Related: %s\n", e.RelatedBead)
+	}
+	if e.ReescalationCount > 0 {
+		fmt.Fprintf(&sb, "Reescalation #%d\n", e.ReescalationCount)
+	}
+	if e.Reason != "" {
+		fmt.Fprintf(&sb, "\nReason:\n%s\n", e.Reason)
+	}
+	fmt.Fprintf(&sb, "\n---\nTo acknowledge: gt escalate ack %s\n", e.ID)
+	return sb.String()
+}
+
+// IsExternal reports whether action is delivered by a Notifier, as
+// opposed to "bead" and "mail:" which gt escalate handles itself.
+func IsExternal(action string) bool {
+	switch {
+	case strings.HasPrefix(action, "email:"),
+		strings.HasPrefix(action, "sms:"),
+		strings.HasPrefix(action, "webhook:"),
+		action == "slack",
+		action == "log":
+		return true
+	}
+	return false
+}
+
+// errNotConfigured marks actions that can't be delivered until the config
+// changes; they are recorded as skipped rather than failed, so gt escalate
+// stale doesn't retry them.
+type errNotConfigured struct{ msg string }
+
+func (e *errNotConfigured) Error() string { return e.msg }
+
+func notConfigured(format string, args ...interface{}) error {
+	return &errNotConfigured{msg: fmt.Sprintf(format, args...)}
+}
+
+// Notifier delivers escalations according to an EscalationConfig.
+type Notifier struct {
+	cfg      *config.EscalationConfig
+	townRoot string
+	client   *http.Client
+	now      func() time.Time
+
+	// smtpTimeout bounds each email's SMTP session (see sendEmail).
+	smtpTimeout time.Duration
+}
+
+// NewNotifier returns a Notifier for the town at townRoot.
+func NewNotifier(townRoot string, cfg *config.EscalationConfig) *Notifier {
+	return &Notifier{
+		cfg:      cfg,
+		townRoot: townRoot,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+
+		smtpTimeout: smtpTimeout,
+	}
+}
+
+// DeliverAll delivers esc for each external action in actions and returns
+// one delivery record per action. Non-external actions are ignored.
+func (n *Notifier) DeliverAll(actions []string, esc *Escalation) []beads.EscalationDelivery {
+	var deliveries []beads.EscalationDelivery
+	for _, action := range actions {
+		if IsExternal(action) {
+			deliveries = append(deliveries, n.Deliver(action, esc, 0))
+		}
+	}
+	return deliveries
+}
+
+// Deliver delivers esc for a single action. prevAttempts is the number of
+// earlier attempts, for retries.
+func (n *Notifier) Deliver(action string, esc *Escalation, prevAttempts int) beads.EscalationDelivery {
+	d := beads.EscalationDelivery{
+		Action:   action,
+		Status:   beads.DeliverySent,
+		Attempts: prevAttempts + 1,
+		At:       n.now().Format(time.RFC3339),
+	}
+	if err := n.deliver(action, esc); err != nil {
+		d.Status = beads.DeliveryFailed
+		if _, ok := err.(*errNotConfigured); ok {
+			d.Status = beads.DeliverySkipped
+		}
+		d.Error = err.Error()
+	}
+	return d
+}
+
+func (n *Notifier) deliver(action string, esc *Escalation) error {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		to := strings.TrimPrefix(action, "email:")
+		if to == "human" {
+			to, _, _ = n.cfg.HumanContacts(n.now(), esc.Rig(), esc.Severity)
+			if to == "" {
+				return notConfigured("no on-call email and contacts.human_email not configured")
+			}
+		}
+		return n.sendEmail(to, esc)
+
+	case strings.HasPrefix(action, "sms:"):
+		to := strings.TrimPrefix(action, "sms:")
+		if to == "human" {
+			_, to, _ = n.cfg.HumanContacts(n.now(), esc.Rig(), esc.Severity)
+			if to == "" {
+				return notConfigured("no on-call number and contacts.human_sms not configured")
+			}
+		}
+		return n.sendSMS(to, esc)
+
+	case action == "slack":
+		return n.postSlack(esc)
+
+	case strings.HasPrefix(action, "webhook:"):
+		return n.postWebhook(strings.TrimPrefix(action, "webhook:"), esc)
+
+	case action == "log":
+		return n.writeLog(esc)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// logEntry is one line of the escalation log.
+type logEntry struct {
+	Timestamp string `json:"ts"`
+	*Escalation
+}
+
+// writeLog appends esc to the escalation log as a JSON line.
+func (n *Notifier) writeLog(esc *Escalation) error {
+	path := n.cfg.GetLogFile(n.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	data, err := json.Marshal(logEntry{Timestamp: n.now().Format(time.RFC3339), Escalation: esc})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is from town config
+	if err != nil {
+		return fmt.Errorf("opening escalation log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// templateFuncs are available to webhook and SMS payload templates.
+// json quotes a value for embedding in a JSON payload:
+//
+//	{"text": {{json .Title}}, "sev": {{json .Severity}}}
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+}
+
+// renderTemplate executes the text/template src with data.
+func renderTemplate(name, src string, data interface{}) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return nil, fmt.Errorf("executing %s template: %w", name, err)
+	}
+	return []byte(sb.String()), nil
+}
+
+// secret returns value, or the environment variable named by env if set.
+func secret(value, env string) string {
+	if env != "" {
+		return os.Getenv(env)
+	}
+	return value
+}
@@ -0,0 +1,331 @@
+package escalation
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var testEscalation = &Escalation{
+	ID:          "hq-esc1",
+	Title:       "Build failure",
+	Severity:    "high",
+	Reason:      "Tests failed 3 times",
+	EscalatedBy: "gastown/witness",
+	EscalatedAt: "2026-03-01T12:00:00Z",
+}
+
+func newTestNotifier(t *testing.T, cfg *config.EscalationConfig) *Notifier {
+	t.Helper()
+	n := NewNotifier(t.TempDir(), cfg)
+	n.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return n
+}
+
+// capture records the last request a test server received.
+type capture struct {
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, *capture) {
+	t.Helper()
+	got := &capture{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.header = r.Header.Clone()
+		got.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("nope"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	srv, got := newCaptureServer(t, http.StatusOK)
+	t.Setenv("GT_TEST_HOOK_SECRET", "s3cret")
+	t.Setenv("GT_TEST_HOOK_TOKEN", "tok")
+	cfg := config.NewEscalationConfig()
+	cfg.Webhooks = map[string]*config.EscalationWebhook{
+		"pager": {
+			URL:       srv.URL,
+			SecretEnv: "GT_TEST_HOOK_SECRET",
+			Template:  `{"summary": {{json .Title}}, "severity": {{json (upper .Severity)}}}`,
+			Headers:   map[string]string{"Authorization": "Bearer $GT_TEST_HOOK_TOKEN"},
+		},
+	}
+
+	d := newTestNotifier(t, cfg).Deliver("webhook:pager", testEscalation, 0)
+	if d.Status != beads.DeliverySent || d.Attempts != 1 || d.Error != "" {
+		t.Fatalf("delivery = %+v", d)
+	}
+	if string(got.body) != `{"summary": "Build failure", "severity": "HIGH"}` {
+		t.Errorf("body = %s", got.body)
+	}
+	if sig := got.header.Get(SignatureHeader); sig != Sign("s3cret", got.body) {
+		t.Errorf("signature = %q, want %q", sig, Sign("s3cret", got.body))
+	}
+	if auth := got.header.Get("Authorization"); auth != "Bearer tok" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestDeliverWebhook_DefaultPayloadUnsigned(t *testing.T) {
+	srv, got := newCaptureServer(t, http.StatusNoContent)
+	cfg := config.NewEscalationConfig()
+	cfg.Webhooks = map[string]*config.EscalationWebhook{"ops": {URL: srv.URL}}
+
+	if d := newTestNotifier(t, cfg).Deliver("webhook:ops", testEscalation, 0); d.Status != beads.DeliverySent {
+		t.Fatalf("delivery = %+v", d)
+	}
+	var payload Escalation
+	if err := json.Unmarshal(got.body, &payload); err != nil || payload != *testEscalation {
+		t.Errorf("payload = %s (%v)", got.body, err)
+	}
+	if sig := got.header.Get(SignatureHeader); sig != "" {
+		t.Errorf("unexpected signature %q", sig)
+	}
+}
+
+func TestDeliverSlack(t *testing.T) {
+	srv, got := newCaptureServer(t, http.StatusOK)
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+
+	if d := newTestNotifier(t, cfg).Deliver("slack", testEscalation, 0); d.Status != beads.DeliverySent {
+		t.Fatalf("delivery = %+v", d)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(payload["text"], "[HIGH] Build failure") || !strings.Contains(payload["text"], "gt escalate ack hq-esc1") {
+		t.Errorf("text = %q", payload["text"])
+	}
+}
+
+func TestDeliverSMS(t *testing.T) {
+	srv, got := newCaptureServer(t, http.StatusAccepted)
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanSMS = "+15551234567"
+	cfg.SMS = &config.EscalationSMS{
+		URL:         srv.URL,
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        `To={{.To}}&Body={{.Message}}`,
+	}
+
+	if d := newTestNotifier(t, cfg).Deliver("sms:human", testEscalation, 0); d.Status != beads.DeliverySent {
+		t.Fatalf("delivery = %+v", d)
+	}
+	if string(got.body) != "To=+15551234567&Body=[HIGH] Build failure (hq-esc1)" {
+		t.Errorf("body = %s", got.body)
+	}
+	if ct := got.header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestDeliver_FailedAndSkipped(t *testing.T) {
+	srv, _ := newCaptureServer(t, http.StatusInternalServerError)
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+	n := newTestNotifier(t, cfg)
+
+	d := n.Deliver("slack", testEscalation, 2)
+	if d.Status != beads.DeliveryFailed || d.Attempts != 3 || d.Error != "HTTP 500: nope" {
+		t.Errorf("failed delivery = %+v", d)
+	}
+
+	// Missing contacts and providers are skipped, not retried.
+	for _, action := range []string{"email:human", "sms:human", "webhook:missing"} {
+		if d := n.Deliver(action, testEscalation, 0); d.Status != beads.DeliverySkipped {
+			t.Errorf("%s: delivery = %+v, want skipped", action, d)
+		}
+	}
+}
+
+func TestDeliverSlack_ErrorHidesWebhookURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	webhook := srv.URL + "/services/T000/B000/s3cretToken"
+	srv.Close() // Connection refused
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = webhook
+	d := newTestNotifier(t, cfg).Deliver("slack", testEscalation, 0)
+	if d.Status != beads.DeliveryFailed || d.Error == "" {
+		t.Fatalf("delivery = %+v, want failed", d)
+	}
+	if strings.Contains(d.Error, "s3cretToken") || strings.Contains(d.Error, srv.URL) {
+		t.Errorf("delivery error leaks the webhook URL: %s", d.Error)
+	}
+
+	cfg.Contacts.SlackWebhook = "https://hooks.example.com/s3cretToken\x7f"
+	if d := newTestNotifier(t, cfg).Deliver("slack", testEscalation, 0); strings.Contains(d.Error, "s3cretToken") {
+		t.Errorf("request error leaks the webhook URL: %s", d.Error)
+	}
+}
+
+func TestDeliverLog(t *testing.T) {
+	cfg := config.NewEscalationConfig()
+	n := newTestNotifier(t, cfg)
+
+	deliveries := n.DeliverAll([]string{"bead", "mail:mayor", "log"}, testEscalation)
+	if len(deliveries) != 1 || deliveries[0].Action != "log" || deliveries[0].Status != beads.DeliverySent {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+	data, err := os.ReadFile(filepath.Join(n.townRoot, "logs", "escalations.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `{"ts":"2026-03-01T12:00:00Z","id":"hq-esc1"`) {
+		t.Errorf("log = %s", data)
+	}
+}
+
+// fakeSMTP accepts one plaintext SMTP session and returns the DATA it received.
+func fakeSMTP(t *testing.T) (host string, port int, data <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	out := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		reply("220 fake ESMTP")
+		var body strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					out <- body.String()
+					reply("250 queued")
+					continue
+				}
+				body.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 ok")
+			case "DATA":
+				inData = true
+				reply("354 go ahead")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, out
+}
+
+func TestDeliverEmail(t *testing.T) {
+	host, port, data := fakeSMTP(t)
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanEmail = "overseer@example.com"
+	cfg.Email = &config.EscalationEmail{Host: host, Port: port, From: "gastown@example.com", TLS: "none"}
+
+	if d := newTestNotifier(t, cfg).Deliver("email:human", testEscalation, 0); d.Status != beads.DeliverySent {
+		t.Fatalf("delivery = %+v", d)
+	}
+	msg := <-data
+	for _, want := range []string{"To: overseer@example.com\r\n", "Subject: [HIGH] Build failure\r\n", "X-Gastown-Escalation: hq-esc1\r\n", "Tests failed 3 times"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestDeliverEmail_RequiresStartTLS(t *testing.T) {
+	host, port, _ := fakeSMTP(t)
+	cfg := config.NewEscalationConfig()
+	cfg.Email = &config.EscalationEmail{Host: host, Port: port, From: "gastown@example.com"}
+
+	d := newTestNotifier(t, cfg).Deliver("email:oncall@example.com", testEscalation, 0)
+	if d.Status != beads.DeliveryFailed || !strings.Contains(d.Error, "STARTTLS") {
+		t.Errorf("delivery = %+v, want STARTTLS failure", d)
+	}
+}
+
+func TestDeliverEmail_SilentServerTimesOut(t *testing.T) {
+	// Accepts the connection but never sends its 220 greeting
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	cfg := config.NewEscalationConfig()
+	cfg.Email = &config.EscalationEmail{Host: addr.IP.String(), Port: addr.Port, From: "gastown@example.com", TLS: "none"}
+
+	n := newTestNotifier(t, cfg)
+	n.smtpTimeout = 200 * time.Millisecond
+	start := time.Now()
+	d := n.Deliver("email:oncall@example.com", testEscalation, 0)
+	if d.Status != beads.DeliveryFailed || !strings.Contains(d.Error, "timeout") {
+		t.Errorf("delivery = %+v, want a timeout failure", d)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("delivery took %v", elapsed)
+	}
+}
+
+func TestDeliverSMS_OnCall(t *testing.T) {
+	srv, got := newCaptureServer(t, http.StatusOK)
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanSMS = "+15559999999"
+	cfg.SMS = &config.EscalationSMS{URL: srv.URL, Body: `{{.To}}`}
+	cfg.OnCall = &config.OnCallSchedule{
+		People: map[string]*config.OnCallPerson{"alice": {SMS: "+15550000001"}},
+		Rotations: []*config.OnCallRotation{
+			{Name: "gastown", People: []string{"alice"}, Start: "2026-01-05T09:00", Rigs: []string{"gastown"}},
+		},
+	}
+	n := newTestNotifier(t, cfg)
+
+	// testEscalation comes from gastown/witness, so the gastown rotation pages
+	if d := n.Deliver("sms:human", testEscalation, 0); d.Status != beads.DeliverySent || string(got.body) != "+15550000001" {
+		t.Errorf("gastown escalation: %+v, sent to %s", d, got.body)
+	}
+
+	// Town-level escalations fall back to contacts
+	town := *testEscalation
+	town.EscalatedBy = "mayor/"
+	if d := n.Deliver("sms:human", &town, 0); d.Status != beads.DeliverySent || string(got.body) != "+15559999999" {
+		t.Errorf("mayor escalation: %+v, sent to %s", d, got.body)
+	}
+}
@@ -0,0 +1,148 @@
+package escalation
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook payload, as
+// "sha256=<hex>", when the webhook has a secret.
+const SignatureHeader = "X-Gastown-Signature"
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postSlack posts esc to the Slack-compatible incoming webhook in
+// contacts.slack_webhook.
+func (n *Notifier) postSlack(esc *Escalation) error {
+	if n.cfg.Contacts.SlackWebhook == "" {
+		return notConfigured("contacts.slack_webhook not configured")
+	}
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", esc.Subject(), esc.Text()),
+	})
+	if err != nil {
+		return err
+	}
+	return n.send(http.MethodPost, os.ExpandEnv(n.cfg.Contacts.SlackWebhook), "application/json", body, nil)
+}
+
+// postWebhook posts esc to the named generic webhook, signing the payload
+// if the webhook has a secret.
+func (n *Notifier) postWebhook(name string, esc *Escalation) error {
+	hook := n.cfg.Webhooks[name]
+	if hook == nil {
+		return notConfigured("webhooks.%s not configured", name)
+	}
+
+	var body []byte
+	var err error
+	if hook.Template != "" {
+		body, err = renderTemplate("webhook "+name, hook.Template, esc)
+	} else {
+		body, err = json.Marshal(esc)
+	}
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(hook.Headers)+2)
+	for k, v := range hook.Headers {
+		headers[k] = v
+	}
+	headers["X-Gastown-Event"] = "escalation"
+	if key := secret(hook.Secret, hook.SecretEnv); key != "" {
+		headers[SignatureHeader] = Sign(key, body)
+	}
+	return n.send(http.MethodPost, os.ExpandEnv(hook.URL), "application/json", body, headers)
+}
+
+// smsData is the data for SMS body templates.
+type smsData struct {
+	*Escalation
+	To      string
+	Message string
+}
+
+// sendSMS sends esc to the phone number to through the configured HTTP provider.
+func (n *Notifier) sendSMS(to string, esc *Escalation) error {
+	provider := n.cfg.SMS
+	if provider == nil {
+		return notConfigured("sms provider not configured")
+	}
+
+	data := smsData{Escalation: esc, To: to, Message: fmt.Sprintf("%s (%s)", esc.Subject(), esc.ID)}
+	var body []byte
+	var err error
+	if provider.Body != "" {
+		body, err = renderTemplate("sms", provider.Body, data)
+	} else {
+		body, err = json.Marshal(map[string]string{"to": data.To, "message": data.Message})
+	}
+	if err != nil {
+		return err
+	}
+
+	method := provider.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	contentType := provider.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return n.send(method, os.ExpandEnv(provider.URL), contentType, body, provider.Headers)
+}
+
+// send makes an HTTP request and treats any non-2xx response as a failure.
+// Header values have $VARS expanded from the environment. Errors never
+// include target, which may hold a secret (Slack webhook URLs do).
+func (n *Notifier) send(method, target, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", method, withoutURL(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if msg := strings.TrimSpace(string(detail)); msg != "" {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// withoutURL unwraps a *url.Error to the error it carries, dropping the URL
+// it names.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -5,6 +5,8 @@
 package events
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -68,6 +70,9 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Protocol mail rejected for a missing or wrong sender signature
+	TypeMailRejected = "mail_rejected"
 )
 
 // EventsFile is the name of the raw events log.
@@ -98,6 +103,37 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityAudit)
 }
 
+// TraceKey is the payload key holding an event's trace ID.
+const TraceKey = "trace_id"
+
+// NewTraceID returns a new trace ID. gt sling mints one for each unit of
+// work; it is carried on the hook bead, the MR bead, protocol mail, and
+// event payloads so gt trace can rebuild the work's timeline.
+func NewTraceID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("tr-%x", time.Now().UnixNano())
+	}
+	return "tr-" + hex.EncodeToString(b)
+}
+
+// WithTrace adds traceID to payload, if set, and returns payload.
+func WithTrace(payload map[string]interface{}, traceID string) map[string]interface{} {
+	if traceID != "" {
+		if payload == nil {
+			payload = map[string]interface{}{}
+		}
+		payload[TraceKey] = traceID
+	}
+	return payload
+}
+
+// TraceID returns the event's trace ID, or "" if it has none.
+func (e Event) TraceID() string {
+	id, _ := e.Payload[TraceKey].(string)
+	return id
+}
+
 // write appends an event to the events file.
 // Uses flock for cross-process synchronization — sync.Mutex only protects
 // intra-process goroutines, but multiple gt processes write concurrently.
@@ -182,6 +218,16 @@ func MailPayload(to, subject string) map[string]interface{} {
 	}
 }
 
+// MailRejectedPayload creates a payload for rejected protocol mail events.
+func MailRejectedPayload(messageID, to, subject, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"message_id": messageID,
+		"to":         to,
+		"subject":    subject,
+		"reason":     reason,
+	}
+}
+
 // SpawnPayload creates a payload for spawn events.
 func SpawnPayload(rig, polecat string) map[string]interface{} {
 	return map[string]interface{}{
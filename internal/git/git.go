@@ -9,7 +9,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -625,6 +627,28 @@ func (g *Git) MergeNoFF(branch, message string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to the given branch.
+// Fails if the current branch is not an ancestor of branch.
+func (g *Git) MergeFFOnly(branch string) error {
+	_, err := g.run("merge", "--ff-only", branch)
+	return err
+}
+
+// RevertTo adds a single commit on top of the current branch that restores
+// base's tree, undoing everything since base however it landed (one squash
+// commit, a merge commit, or several rebased commits). Returns the new
+// commit's SHA. The working tree must be clean.
+func (g *Git) RevertTo(base, message string) (string, error) {
+	sha, err := g.run("commit-tree", base+"^{tree}", "-p", "HEAD", "-m", message)
+	if err != nil {
+		return "", err
+	}
+	if _, err := g.run("merge", "--ff-only", sha); err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
 // MergeSquash performs a squash merge of the given branch and commits with the provided message.
 // This stages all changes from the branch without creating a merge commit, then commits them
 // as a single commit with the given message. This eliminates redundant merge commits while
@@ -771,6 +795,51 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// ChangedFiles returns the paths that differ between the trees of base and
+// head, relative to the repository root. Renames are reported by their new path.
+func (g *Git) ChangedFiles(base, head string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base, head)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+
+	var files []string
+	for _, f := range strings.Split(out, "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// DiffLines returns how many lines head changes since it forked from base
+// (lines added plus lines deleted, as in "git diff --numstat base...head").
+// Binary files count as zero lines.
+func (g *Git) DiffLines(base, head string) (int, error) {
+	out, err := g.run("diff", "--numstat", base+"..."+head)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		added, errA := strconv.Atoi(parts[0])
+		deleted, errD := strconv.Atoi(parts[1])
+		if errA != nil || errD != nil {
+			continue // binary file: "-\t-\tpath"
+		}
+		total += added + deleted
+	}
+	return total, nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -919,6 +988,16 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// MergeBase returns the best common ancestor of a and b.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.run("merge-base", a, b)
+}
+
+// ShowFile returns the contents of path as of ref (e.g. "origin/main").
+func (g *Git) ShowFile(ref, path string) (string, error) {
+	return g.run("show", ref+":"+path)
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -1112,6 +1191,24 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FirstCommitTime returns the commit time of the earliest commit on branch
+// that isn't on base, i.e. when work on the branch began.
+func (g *Git) FirstCommitTime(base, branch string) (time.Time, error) {
+	out, err := g.run("log", "--reverse", "--format=%ct", base+".."+branch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	first, _, _ := strings.Cut(out, "\n")
+	if first == "" {
+		return time.Time{}, fmt.Errorf("no commits on %s ahead of %s", branch, base)
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing commit time: %w", err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
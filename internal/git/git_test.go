@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -298,6 +299,113 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "guide.md"), []byte("guide\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("docs"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	files, err := g.ChangedFiles(base, "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(files) != 2 || files[0] != "README.md" || files[1] != "docs/guide.md" {
+		t.Errorf("ChangedFiles = %v, want [README.md docs/guide.md]", files)
+	}
+
+	files, err = g.ChangedFiles("HEAD", "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if files != nil {
+		t.Errorf("ChangedFiles(HEAD, HEAD) = %v, want nil", files)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+
+	// README.md: 1 line replaced (+1 -1); new.txt: 3 lines added
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "blob.bin"), []byte{0, 1, 2, 0}, 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("change"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	lines, err := g.DiffLines(base, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffLines: %v", err)
+	}
+	if lines != 5 {
+		t.Errorf("DiffLines = %d, want 5", lines)
+	}
+}
+
+func TestFirstCommitTime(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+	if _, err := g.FirstCommitTime(base, "HEAD"); err == nil {
+		t.Error("FirstCommitTime with no commits ahead succeeded")
+	}
+
+	for _, date := range []string{"2026-01-02T03:04:05Z", "2026-01-03T00:00:00Z"} {
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", "work")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date, "GIT_AUTHOR_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+
+	first, err := g.FirstCommitTime(base, "HEAD")
+	if err != nil {
+		t.Fatalf("FirstCommitTime: %v", err)
+	}
+	if want := "2026-01-02T03:04:05Z"; first.UTC().Format(time.RFC3339) != want {
+		t.Errorf("FirstCommitTime = %s, want %s", first.UTC().Format(time.RFC3339), want)
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -1554,3 +1662,42 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestRevertTo(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit("add " + name); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	merged, _ := g.Rev("HEAD")
+
+	sha, err := g.RevertTo(base, "Revert a and b")
+	if err != nil {
+		t.Fatalf("RevertTo: %v", err)
+	}
+	if head, _ := g.Rev("HEAD"); head != sha {
+		t.Errorf("HEAD = %s, want revert commit %s", head, sha)
+	}
+	if ok, _ := g.IsAncestor(merged, sha); !ok {
+		t.Error("expected revert commit to sit on top of the reverted commits")
+	}
+	if files, _ := g.ChangedFiles(base, "HEAD"); files != nil {
+		t.Errorf("expected tree to match base after revert, got changes %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !os.IsNotExist(err) {
+		t.Error("expected a.txt removed from the working tree")
+	}
+}
@@ -0,0 +1,304 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// awaitingFile is the awaited-reply list's file name under <town>/.runtime/.
+const awaitingFile = "mail-awaiting.json"
+
+// AwaitedReply is a sent message whose sender expects a reply in its thread
+// within Window. If none arrives by DueAt the recipient is reminded once,
+// and if the reminder also goes unanswered the question is escalated.
+type AwaitedReply struct {
+	ID       string `json:"id"`
+	ThreadID string `json:"thread_id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body,omitempty"`
+
+	Window time.Duration `json:"window"`
+
+	// Severity is the escalation severity. Empty uses the town's
+	// escalation config.
+	Severity string `json:"severity,omitempty"`
+
+	SentAt time.Time `json:"sent_at"`
+	DueAt  time.Time `json:"due_at"`
+
+	// RemindedAt and EscalatedAt record the follow-ups; Escalation is the
+	// escalation bead's ID.
+	RemindedAt  time.Time `json:"reminded_at,omitempty"`
+	EscalatedAt time.Time `json:"escalated_at,omitempty"`
+	Escalation  string    `json:"escalation,omitempty"`
+
+	// LastError records a failed reminder or escalation, retried on the
+	// next check.
+	LastError string `json:"last_error,omitempty"`
+
+	// ClaimedUntil is set while a check is following up on the entry, so a
+	// concurrent check doesn't remind or escalate twice. A claim that
+	// outlives a crashed check expires.
+	ClaimedUntil time.Time `json:"claimed_until,omitempty"`
+}
+
+// awaitClaimTTL bounds how long a check may hold an entry; escalating runs
+// gt escalate, which can take a while.
+const awaitClaimTTL = 10 * time.Minute
+
+// Stage describes how far the follow-ups have gone.
+func (a *AwaitedReply) Stage() string {
+	switch {
+	case !a.EscalatedAt.IsZero():
+		return "escalated"
+	case !a.RemindedAt.IsZero():
+		return "reminded"
+	default:
+		return "waiting"
+	}
+}
+
+// Reminder returns the nudge re-sent to the recipient when no reply has
+// arrived in time. It goes in the same thread so a reply to either counts.
+func (a *AwaitedReply) Reminder() *Message {
+	msg := NewMessage(a.From, a.To, "Reminder: "+a.Subject,
+		fmt.Sprintf("No reply yet to this message, sent %s. Please reply in this thread.\n\n%s",
+			a.SentAt.Local().Format("2006-01-02 15:04"), a.Body))
+	msg.ThreadID = a.ThreadID
+	msg.Type = TypeTask
+	msg.Priority = PriorityHigh
+	msg.Delivery = DeliveryInterrupt
+	return msg
+}
+
+// awaitingState is the on-disk awaited-reply list.
+type awaitingState struct {
+	Replies []*AwaitedReply `json:"replies"`
+}
+
+func newAwaitingState() *awaitingState {
+	return &awaitingState{}
+}
+
+// Awaiting is a town's messages awaiting replies, checked by the daemon
+// heartbeat.
+type Awaiting struct {
+	*runtimeStore[awaitingState]
+}
+
+// NewAwaiting returns the awaited-reply list for the town at townRoot.
+func NewAwaiting(townRoot string) *Awaiting {
+	return &Awaiting{newRuntimeStore(townRoot, awaitingFile, "awaited replies", newAwaitingState)}
+}
+
+// Add records that a reply to msg is expected within window, assigning
+// the entry's ID.
+func (w *Awaiting) Add(msg *Message, window time.Duration, severity string) (*AwaitedReply, error) {
+	if msg.ThreadID == "" {
+		return nil, fmt.Errorf("expecting a reply: message has no thread")
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("expecting a reply: window must be positive")
+	}
+	sentAt := msg.Timestamp
+	if sentAt.IsZero() {
+		sentAt = timeNow()
+	}
+	a := &AwaitedReply{
+		ID:       generateAwaitID(),
+		ThreadID: msg.ThreadID,
+		From:     msg.From,
+		To:       msg.To,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Window:   window,
+		Severity: severity,
+		SentAt:   sentAt,
+		DueAt:    sentAt.Add(window),
+	}
+	err := w.update(func(state *awaitingState) (bool, error) {
+		state.Replies = append(state.Replies, a)
+		return true, nil
+	})
+	return a, err
+}
+
+// List returns the messages awaiting replies, soonest due first.
+func (w *Awaiting) List() ([]*AwaitedReply, error) {
+	var out []*AwaitedReply
+	err := w.update(func(state *awaitingState) (bool, error) {
+		out = state.Replies
+		return false, nil
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].DueAt.Before(out[j].DueAt)
+	})
+	return out, err
+}
+
+// Cancel stops waiting for a reply and returns the entry.
+func (w *Awaiting) Cancel(id string) (*AwaitedReply, error) {
+	var cancelled *AwaitedReply
+	err := w.update(func(state *awaitingState) (bool, error) {
+		for i, a := range state.Replies {
+			if a.ID == id {
+				cancelled = a
+				state.Replies = append(state.Replies[:i], state.Replies[i+1:]...)
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%w: awaited reply %s", ErrMessageNotFound, id)
+	})
+	return cancelled, err
+}
+
+// Awaits reports whether any entry is waiting on thread. It reads without
+// the lock, as a cheap check before Resolve.
+func (w *Awaiting) Awaits(threadID string) bool {
+	state, err := w.peek()
+	if err != nil {
+		return true // Let Resolve take the lock and report it
+	}
+	for _, a := range state.Replies {
+		if a.ThreadID == threadID {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve removes the entries msg answers: those in its thread that it
+// wasn't sent by the waiting sender. Returns the entries resolved.
+func (w *Awaiting) Resolve(msg *Message) ([]*AwaitedReply, error) {
+	if msg.ThreadID == "" {
+		return nil, nil
+	}
+	from := AddressToIdentity(msg.From)
+	var resolved []*AwaitedReply
+	err := w.update(func(state *awaitingState) (bool, error) {
+		kept := state.Replies[:0]
+		for _, a := range state.Replies {
+			if a.ThreadID == msg.ThreadID && AddressToIdentity(a.From) != from {
+				resolved = append(resolved, a)
+				continue
+			}
+			kept = append(kept, a)
+		}
+		state.Replies = kept
+		return len(resolved) > 0, nil
+	})
+	return resolved, err
+}
+
+// Check follows up on every entry overdue at now. The first time an entry
+// is overdue, remind re-sends the question and the window starts again; the
+// second time, escalate raises it and returns the escalation's ID.
+// Escalated entries stay listed until a reply arrives or they are
+// cancelled. Returns the entries followed up on.
+//
+// remind and escalate run without the lock held: sending mail resolves
+// awaited replies, and gt escalate sends mail of its own. Due entries are
+// claimed first, then the outcomes are written back.
+func (w *Awaiting) Check(now time.Time, remind func(*AwaitedReply) error, escalate func(*AwaitedReply) (string, error)) ([]*AwaitedReply, error) {
+	var due []AwaitedReply
+	err := w.update(func(state *awaitingState) (bool, error) {
+		for _, a := range state.Replies {
+			if !a.EscalatedAt.IsZero() || now.Before(a.DueAt) || now.Before(a.ClaimedUntil) {
+				continue
+			}
+			a.ClaimedUntil = now.Add(awaitClaimTTL)
+			due = append(due, *a)
+		}
+		return len(due) > 0, nil
+	})
+	if err != nil || len(due) == 0 {
+		return nil, err
+	}
+
+	// Follow up outside the lock
+	for i := range due {
+		a := &due[i]
+		a.LastError = ""
+		if a.RemindedAt.IsZero() {
+			if err := remind(a); err != nil {
+				a.LastError = err.Error()
+				continue
+			}
+			a.RemindedAt = now
+			a.DueAt = now.Add(a.Window)
+		} else {
+			id, err := escalate(a)
+			if err != nil {
+				a.LastError = err.Error()
+				continue
+			}
+			a.EscalatedAt = now
+			a.Escalation = id
+		}
+	}
+
+	// Write back, skipping entries resolved or cancelled in the meantime
+	var followed []*AwaitedReply
+	err = w.update(func(state *awaitingState) (bool, error) {
+		for i := range due {
+			done := &due[i]
+			for _, a := range state.Replies {
+				if a.ID != done.ID {
+					continue
+				}
+				a.RemindedAt, a.DueAt = done.RemindedAt, done.DueAt
+				a.EscalatedAt, a.Escalation = done.EscalatedAt, done.Escalation
+				a.LastError = done.LastError
+				a.ClaimedUntil = time.Time{}
+				if a.LastError == "" {
+					followed = append(followed, a)
+				}
+				break
+			}
+		}
+		return true, nil
+	})
+	return followed, err
+}
+
+// CheckAwaiting follows up on the town's messages whose replies are overdue
+// at now, sending reminders through the router. See Awaiting.Check.
+func (r *Router) CheckAwaiting(now time.Time, escalate func(*AwaitedReply) (string, error)) ([]*AwaitedReply, error) {
+	if r.townRoot == "" {
+		return nil, fmt.Errorf("checking awaited replies: no town root")
+	}
+	remind := func(a *AwaitedReply) error {
+		return r.Send(a.Reminder())
+	}
+	return NewAwaiting(r.townRoot).Check(now, remind, escalate)
+}
+
+// resolveAwaited stops waiting on questions msg replies to. Best-effort:
+// the message is already delivered. Only replies are checked, and the lock
+// is only taken if something awaits the thread, since every send lands here.
+func (r *Router) resolveAwaited(msg *Message) {
+	if r.townRoot == "" || msg.ThreadID == "" || (msg.ReplyTo == "" && msg.Type != TypeReply) {
+		return
+	}
+	w := NewAwaiting(r.townRoot)
+	if !w.Awaits(msg.ThreadID) {
+		return
+	}
+	if _, err := w.Resolve(msg); err != nil {
+		fmt.Fprintf(os.Stderr, "mail awaiting: %v\n", err)
+	}
+}
+
+func generateAwaitID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("await-%x", time.Now().UnixNano())
+	}
+	return "await-" + hex.EncodeToString(b)
+}
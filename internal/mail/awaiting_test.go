@@ -0,0 +1,204 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestAwaiting_AddListCancelResolve(t *testing.T) {
+	w := NewAwaiting(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	slow := NewMessage("mayor/", "gastown/witness", "status?", "")
+	slow.Timestamp = now
+	fast := NewMessage("mayor/", "gastown/refinery", "merge ok?", "")
+	fast.Timestamp = now
+	a, err := w.Add(slow, 4*time.Hour, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add(fast, time.Hour, "high"); err != nil {
+		t.Fatal(err)
+	}
+	if !a.DueAt.Equal(now.Add(4 * time.Hour)) {
+		t.Errorf("DueAt = %v, want sent + window", a.DueAt)
+	}
+	if _, err := w.Add(NewMessage("a", "b", "c", ""), 0, ""); err == nil {
+		t.Error("expected Add with no window to fail")
+	}
+
+	list, err := w.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Subject != "merge ok?" {
+		t.Fatalf("List = %+v, want merge ok? first", list)
+	}
+
+	// The sender's own follow-up in the thread doesn't count as a reply.
+	own := NewMessage("mayor/", "gastown/witness", "Re: status?", "")
+	own.ThreadID = slow.ThreadID
+	if resolved, _ := w.Resolve(own); len(resolved) != 0 {
+		t.Errorf("sender's own message resolved %d entries", len(resolved))
+	}
+
+	reply := NewMessage("gastown/witness", "mayor/", "Re: status?", "all good")
+	reply.ThreadID = slow.ThreadID
+	resolved, err := w.Resolve(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 1 || resolved[0].ID != a.ID {
+		t.Errorf("Resolve = %+v, want %s", resolved, a.ID)
+	}
+
+	list, _ = w.List()
+	if _, err := w.Cancel(list[0].ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if _, err := w.Cancel(list[0].ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("second Cancel error = %v, want ErrMessageNotFound", err)
+	}
+}
+
+func TestAwaiting_Check(t *testing.T) {
+	w := NewAwaiting(t.TempDir())
+	sent := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	msg := NewMessage("mayor/", "gastown/witness", "status?", "")
+	msg.Timestamp = sent
+	if _, err := w.Add(msg, time.Hour, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var reminders, escalations int
+	remind := func(a *AwaitedReply) error {
+		reminders++
+		if r := a.Reminder(); r.ThreadID != msg.ThreadID || r.To != msg.To {
+			t.Errorf("reminder %+v not in the original thread to the recipient", r)
+		}
+		return nil
+	}
+	failEscalation := true
+	escalate := func(a *AwaitedReply) (string, error) {
+		if failEscalation {
+			return "", errors.New("bd unavailable")
+		}
+		escalations++
+		return "hq-esc1", nil
+	}
+	check := func(at time.Time) []*AwaitedReply {
+		t.Helper()
+		followed, err := w.Check(at, remind, escalate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return followed
+	}
+
+	if len(check(sent.Add(30*time.Minute))) != 0 || reminders != 0 {
+		t.Fatal("followed up before the window passed")
+	}
+
+	first := sent.Add(time.Hour)
+	if len(check(first)) != 1 || reminders != 1 {
+		t.Fatalf("expected a reminder once overdue, got %d", reminders)
+	}
+	list, _ := w.List()
+	if list[0].Stage() != "reminded" || !list[0].DueAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("after reminder: stage %s, due %v; want reminded, due an hour later", list[0].Stage(), list[0].DueAt)
+	}
+
+	// Escalation failures are recorded and retried.
+	second := first.Add(time.Hour)
+	if len(check(second)) != 0 {
+		t.Error("failed escalation reported as followed up")
+	}
+	if list, _ = w.List(); list[0].LastError == "" {
+		t.Error("failed escalation not recorded")
+	}
+	failEscalation = false
+	if len(check(second)) != 1 || escalations != 1 || reminders != 1 {
+		t.Fatalf("reminders %d, escalations %d; want 1 each", reminders, escalations)
+	}
+	list, _ = w.List()
+	if a := list[0]; a.Stage() != "escalated" || a.Escalation != "hq-esc1" || a.LastError != "" {
+		t.Errorf("after escalation: %+v", a)
+	}
+
+	// Escalated entries aren't followed up again.
+	if len(check(second.Add(24*time.Hour))) != 0 || escalations != 1 {
+		t.Error("escalated entry followed up again")
+	}
+}
+
+// TestAwaiting_CheckWithRouter follows up through a real Router, whose sends
+// resolve awaited replies; Check must not hold its lock while they do.
+func TestAwaiting_CheckWithRouter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  list) echo '[{"id":"hq-mayor","status":"open"}]' ;;
+  create) echo '{"id":"hq-msg"}' ;;
+  *) echo '{}' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	w := NewAwaiting(townRoot)
+	sent := time.Now().Add(-2 * time.Hour)
+	msg := NewMessage("overseer", "mayor/", "status?", "")
+	msg.Timestamp = sent
+	if _, err := w.Add(msg, time.Hour, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The mayor replies while the escalation is underway.
+	remind := func(a *AwaitedReply) error { return r.Send(a.Reminder()) }
+	escalate := func(a *AwaitedReply) (string, error) {
+		return "hq-esc1", r.Send(NewReplyMessage("mayor/", "overseer", "Re: status?", "all good", msg))
+	}
+
+	check := func(at time.Time) []*AwaitedReply {
+		t.Helper()
+		done := make(chan []*AwaitedReply, 1)
+		go func() {
+			followed, err := w.Check(at, remind, escalate)
+			if err != nil {
+				t.Error(err)
+			}
+			done <- followed
+		}()
+		select {
+		case followed := <-done:
+			return followed
+		case <-time.After(30 * time.Second):
+			t.Fatal("Check deadlocked")
+			return nil
+		}
+	}
+
+	now := time.Now()
+	if followed := check(now); len(followed) != 1 || followed[0].Stage() != "reminded" {
+		t.Fatalf("first check = %+v, want a reminder", followed)
+	}
+	check(now.Add(2 * time.Hour))
+	r.WaitPendingNotifications()
+	if list, _ := w.List(); len(list) != 0 {
+		t.Errorf("entry answered during the check was written back: %+v", list)
+	}
+}
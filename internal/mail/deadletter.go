@@ -0,0 +1,216 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// deadLetterFile is a rig's dead-letter queue file name under <rig>/.runtime/.
+const deadLetterFile = "mail-dlq.json"
+
+// Dead letters are retried after deadLetterBaseDelay, doubling with each
+// failed attempt up to deadLetterMaxDelay. After MaxDeadLetterAttempts
+// they are only retried by hand.
+const (
+	deadLetterBaseDelay   = time.Minute
+	deadLetterMaxDelay    = time.Hour
+	MaxDeadLetterAttempts = 10
+)
+
+// DeadLetter is a message its handler couldn't process, kept for retry.
+type DeadLetter struct {
+	ID      string   `json:"id"`
+	Message *Message `json:"message"`
+
+	// Error is the most recent failure.
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastFailedAt  time.Time `json:"last_failed_at"`
+
+	// NextRetryAt is when the letter is next retried automatically. Zero
+	// once it has exhausted its attempts.
+	NextRetryAt time.Time `json:"next_retry_at,omitempty"`
+
+	// ClaimedUntil is set while a retry is running the letter's handler, so
+	// a concurrent retry skips it. A claim that outlives a crashed retry
+	// expires.
+	ClaimedUntil time.Time `json:"claimed_until,omitempty"`
+}
+
+// deadLetterClaimTTL bounds how long a retry may hold a letter.
+const deadLetterClaimTTL = 10 * time.Minute
+
+// Exhausted reports whether the letter is no longer retried automatically.
+func (d *DeadLetter) Exhausted() bool {
+	return d.Attempts >= MaxDeadLetterAttempts
+}
+
+// fail records a failed attempt at now and schedules the next retry.
+func (d *DeadLetter) fail(cause error, now time.Time) {
+	d.Attempts++
+	d.Error = cause.Error()
+	d.LastFailedAt = now
+	d.NextRetryAt = time.Time{}
+	if !d.Exhausted() {
+		d.NextRetryAt = now.Add(deadLetterBackoff(d.Attempts))
+	}
+}
+
+// deadLetterBackoff returns the delay before retrying after attempts
+// failures.
+func deadLetterBackoff(attempts int) time.Duration {
+	delay := deadLetterBaseDelay
+	for i := 1; i < attempts && delay < deadLetterMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, deadLetterMaxDelay)
+}
+
+// deadLetterState is the on-disk dead-letter queue.
+type deadLetterState struct {
+	Letters []*DeadLetter `json:"letters"`
+}
+
+func newDeadLetterState() *deadLetterState {
+	return &deadLetterState{}
+}
+
+// DeadLetters is a rig's dead-letter queue: protocol mail whose handler
+// failed, or that no handler took, kept with its error until a retry
+// succeeds or it is dropped.
+type DeadLetters struct {
+	*runtimeStore[deadLetterState]
+}
+
+// OpenDeadLetters returns the dead-letter queue for the rig at rigPath.
+func OpenDeadLetters(rigPath string) *DeadLetters {
+	return &DeadLetters{newRuntimeStore(rigPath, deadLetterFile, "dead-letter queue", newDeadLetterState)}
+}
+
+// Add records that handling msg failed with cause at now. A message
+// already in the queue (by ID) has the failure added to its letter.
+func (q *DeadLetters) Add(msg *Message, cause error, now time.Time) (*DeadLetter, error) {
+	var letter *DeadLetter
+	err := q.update(func(state *deadLetterState) (bool, error) {
+		for _, d := range state.Letters {
+			if msg.ID != "" && d.Message.ID == msg.ID {
+				letter = d
+				break
+			}
+		}
+		if letter == nil {
+			letter = &DeadLetter{ID: generateDeadLetterID(), Message: msg, FirstFailedAt: now}
+			state.Letters = append(state.Letters, letter)
+		}
+		letter.fail(cause, now)
+		return true, nil
+	})
+	return letter, err
+}
+
+// List returns the queue, oldest first.
+func (q *DeadLetters) List() ([]*DeadLetter, error) {
+	var out []*DeadLetter
+	err := q.update(func(state *deadLetterState) (bool, error) {
+		out = state.Letters
+		return false, nil
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].FirstFailedAt.Before(out[j].FirstFailedAt)
+	})
+	return out, err
+}
+
+// Drop removes a letter without retrying it and returns it.
+func (q *DeadLetters) Drop(id string) (*DeadLetter, error) {
+	var dropped *DeadLetter
+	err := q.update(func(state *deadLetterState) (bool, error) {
+		for i, d := range state.Letters {
+			if d.ID == id {
+				dropped = d
+				state.Letters = slices.Delete(state.Letters, i, i+1)
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%w: dead letter %s", ErrMessageNotFound, id)
+	})
+	return dropped, err
+}
+
+// Retry passes letters back to process: those with the given IDs, or with
+// none, those due at now. Letters process accepts leave the queue; the
+// rest record the failure and back off. Returns the letters resolved and
+// those that failed again.
+//
+// process runs without the lock held, as handlers may take a while or
+// dead-letter other mail; letters are claimed first so a concurrent retry
+// skips them, and the outcomes are written back after.
+func (q *DeadLetters) Retry(now time.Time, ids []string, process func(*Message) error) (resolved, failed []*DeadLetter, err error) {
+	var due []DeadLetter
+	err = q.update(func(state *deadLetterState) (bool, error) {
+		for _, id := range ids {
+			if !slices.ContainsFunc(state.Letters, func(d *DeadLetter) bool { return d.ID == id }) {
+				return false, fmt.Errorf("%w: dead letter %s", ErrMessageNotFound, id)
+			}
+		}
+		for _, d := range state.Letters {
+			if now.Before(d.ClaimedUntil) {
+				continue
+			}
+			if len(ids) > 0 && !slices.Contains(ids, d.ID) {
+				continue
+			}
+			if len(ids) == 0 && (d.Exhausted() || now.Before(d.NextRetryAt)) {
+				continue
+			}
+			d.ClaimedUntil = now.Add(deadLetterClaimTTL)
+			due = append(due, *d)
+		}
+		return len(due) > 0, nil
+	})
+	if err != nil || len(due) == 0 {
+		return nil, nil, err
+	}
+
+	// Handle outside the lock
+	outcomes := make(map[string]error, len(due))
+	for i := range due {
+		outcomes[due[i].ID] = process(due[i].Message)
+	}
+
+	err = q.update(func(state *deadLetterState) (bool, error) {
+		kept := state.Letters[:0]
+		for _, d := range state.Letters {
+			perr, ok := outcomes[d.ID]
+			if !ok {
+				kept = append(kept, d)
+				continue
+			}
+			d.ClaimedUntil = time.Time{}
+			if perr != nil {
+				d.fail(perr, now)
+				failed = append(failed, d)
+				kept = append(kept, d)
+				continue
+			}
+			resolved = append(resolved, d)
+		}
+		state.Letters = kept
+		return true, nil
+	})
+	return resolved, failed, err
+}
+
+func generateDeadLetterID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("dlq-%x", time.Now().UnixNano())
+	}
+	return "dlq-" + hex.EncodeToString(b)
+}
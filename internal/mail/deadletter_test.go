@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadLetterBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := deadLetterBackoff(tt.attempts); got != tt.want {
+			t.Errorf("deadLetterBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeadLetters_AddListDrop(t *testing.T) {
+	q := OpenDeadLetters(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	msg := NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "")
+	first, err := q.Add(msg, errors.New("dolt: connection refused"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Attempts != 1 || !first.NextRetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("first failure: attempts %d, next retry %v", first.Attempts, first.NextRetryAt)
+	}
+
+	// The same message failing again updates its letter.
+	again, err := q.Add(msg, errors.New("dolt: timeout"), now.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.Attempts != 2 || again.Error != "dolt: timeout" {
+		t.Errorf("second failure = %+v, want same letter with 2 attempts", again)
+	}
+	if _, err := q.Add(NewMessage("gastown/witness", "gastown/refinery", "MERGE_READY nux", ""), ErrMessageNotFound, now); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := q.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != first.ID {
+		t.Fatalf("List = %+v, want 2 letters, oldest first", list)
+	}
+
+	if _, err := q.Drop(first.ID); err != nil {
+		t.Fatalf("Drop: %v", err)
+	}
+	if _, err := q.Drop(first.ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("second Drop error = %v, want ErrMessageNotFound", err)
+	}
+}
+
+func TestDeadLetters_Retry(t *testing.T) {
+	q := OpenDeadLetters(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	flaky, _ := q.Add(NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", ""), errors.New("dolt down"), now)
+	broken, _ := q.Add(NewMessage("gastown/refinery", "gastown/witness", "MERGED ace", ""), errors.New("bad payload"), now)
+
+	healthy := false
+	process := func(msg *Message) error {
+		if msg.Subject == "MERGED nux" && healthy {
+			return nil
+		}
+		return errors.New("still failing")
+	}
+
+	// Nothing is due before the backoff passes.
+	resolved, failed, err := q.Retry(now.Add(30*time.Second), nil, process)
+	if err != nil || len(resolved)+len(failed) != 0 {
+		t.Fatalf("early retry: resolved %d, failed %d, err %v", len(resolved), len(failed), err)
+	}
+
+	resolved, failed, err = q.Retry(now.Add(time.Minute), nil, process)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 0 || len(failed) != 2 {
+		t.Fatalf("resolved %d, failed %d; want 0 and 2", len(resolved), len(failed))
+	}
+	if list, _ := q.List(); list[0].Attempts != 2 || !list[0].NextRetryAt.Equal(now.Add(3*time.Minute)) {
+		t.Errorf("after second failure: %+v, want 2 attempts, retry 2m later", list[0])
+	}
+
+	// Retrying by ID ignores the backoff.
+	healthy = true
+	resolved, _, err = q.Retry(now.Add(time.Minute), []string{flaky.ID}, process)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 1 || resolved[0].ID != flaky.ID {
+		t.Errorf("resolved = %+v, want %s", resolved, flaky.ID)
+	}
+	if list, _ := q.List(); len(list) != 1 || list[0].ID != broken.ID {
+		t.Errorf("queue after retry = %+v, want only %s", list, broken.ID)
+	}
+	if _, _, err := q.Retry(now, []string{"dlq-missing"}, process); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("retry of unknown ID: err = %v, want ErrMessageNotFound", err)
+	}
+
+	// Exhausted letters are left for a human.
+	for i := 0; i < MaxDeadLetterAttempts; i++ {
+		_, _, _ = q.Retry(now.Add(24*time.Hour*time.Duration(i+1)), []string{broken.ID}, process)
+	}
+	list, _ := q.List()
+	if !list[0].Exhausted() || !list[0].NextRetryAt.IsZero() {
+		t.Errorf("letter not exhausted after %d attempts: %+v", list[0].Attempts, list[0])
+	}
+	if _, failed, _ := q.Retry(now.Add(365*24*time.Hour), nil, process); len(failed) != 0 {
+		t.Error("exhausted letter retried automatically")
+	}
+}
+
+func TestDeadLetters_RetryRunsHandlersOutsideLock(t *testing.T) {
+	q := OpenDeadLetters(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	letter, _ := q.Add(NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", ""), errors.New("dolt down"), now)
+
+	// A handler that dead-letters other mail needs the queue's lock.
+	other := NewMessage("gastown/refinery", "gastown/witness", "MERGED ace", "")
+	process := func(msg *Message) error {
+		_, err := q.Add(other, errors.New("dolt down"), now)
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := q.Retry(now, []string{letter.ID}, process)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("Retry deadlocked")
+	}
+
+	list, _ := q.List()
+	if len(list) != 1 || list[0].Message.Subject != "MERGED ace" || !list[0].ClaimedUntil.IsZero() {
+		t.Errorf("queue = %+v, want only the letter added during the retry", list)
+	}
+}
@@ -0,0 +1,596 @@
+package mail
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// indexDir holds the mail search index under <town>/.runtime/, one file
+// per mailbox, so a delivery rewrites only its recipients' files.
+const indexDir = "mail-index"
+
+// legacyIndexFile is the town-wide index file that per-mailbox files
+// replaced; Reindex removes it.
+const legacyIndexFile = "mail-index.json"
+
+// wispIndexRetention is how long wisps stay indexed after delivery. Wisps
+// still in the inbox are indexed again when their mailbox is searched.
+const wispIndexRetention = 7 * 24 * time.Hour
+
+// subjectWeight is how much more a term in the subject counts than one in
+// the body when ranking.
+const subjectWeight = 3
+
+// SearchIndex is a town's on-disk inverted index of direct mail, kept per
+// mailbox. The router adds messages as it delivers them; mailboxes mark
+// them archived, and drop them when they are closed, deleted, or purged
+// from the archive. Reindex rebuilds a mailbox's entries from its inbox
+// and archive.
+type SearchIndex struct {
+	townRoot string
+}
+
+// indexState is a mailbox's on-disk index: its indexed messages, and for
+// each word the messages whose subject or body contains it, with its count
+// there.
+type indexState struct {
+	Docs    map[string]*indexDoc      `json:"docs"`
+	Subject map[string]map[string]int `json:"subject"`
+	Body    map[string]map[string]int `json:"body"`
+}
+
+// indexDoc is an indexed message.
+type indexDoc struct {
+	Message  *Message `json:"message"`
+	Archived bool     `json:"archived,omitempty"`
+}
+
+func newIndexState() *indexState {
+	return &indexState{
+		Docs:    make(map[string]*indexDoc),
+		Subject: make(map[string]map[string]int),
+		Body:    make(map[string]map[string]int),
+	}
+}
+
+// OpenSearchIndex returns the mail search index for the town at townRoot.
+func OpenSearchIndex(townRoot string) *SearchIndex {
+	return &SearchIndex{townRoot: townRoot}
+}
+
+// mailbox returns the index file of identity's mailbox.
+func (x *SearchIndex) mailbox(identity string) *runtimeStore[indexState] {
+	file := filepath.Join(indexDir, url.PathEscape(AddressToIdentity(identity))+".json")
+	return newRuntimeStore(x.townRoot, file, "mail index", newIndexState)
+}
+
+// Add indexes messages in the mailboxes of their recipient and CCs.
+func (x *SearchIndex) Add(msgs ...*Message) error {
+	var owners []string
+	byOwner := make(map[string][]*Message)
+	for _, msg := range msgs {
+		for _, owner := range messageOwners(msg) {
+			if _, ok := byOwner[owner]; !ok {
+				owners = append(owners, owner)
+			}
+			byOwner[owner] = append(byOwner[owner], msg)
+		}
+	}
+	var errs []error
+	for _, owner := range owners {
+		errs = append(errs, x.mailbox(owner).update(func(state *indexState) (bool, error) {
+			for _, msg := range byOwner[owner] {
+				state.put(msg, false)
+			}
+			state.expireWisps(timeNow())
+			return true, nil
+		}))
+	}
+	return errors.Join(errs...)
+}
+
+// MarkArchived records that identity archived msg, indexing it if it
+// wasn't.
+func (x *SearchIndex) MarkArchived(identity string, msg *Message) error {
+	return x.mailbox(identity).update(func(state *indexState) (bool, error) {
+		if doc, ok := state.Docs[msg.ID]; ok {
+			doc.Archived = true
+			return true, nil
+		}
+		state.put(msg, true)
+		return true, nil
+	})
+}
+
+// Remove drops messages that left identity's mailbox without being
+// archived, or were purged from its archive.
+func (x *SearchIndex) Remove(identity string, ids ...string) error {
+	return x.mailbox(identity).update(func(state *indexState) (bool, error) {
+		changed := false
+		for _, id := range ids {
+			if _, ok := state.Docs[id]; ok {
+				state.drop(id)
+				changed = true
+			}
+		}
+		return changed, nil
+	})
+}
+
+// Reindex replaces identity's entries with its current inbox and archive.
+func (x *SearchIndex) Reindex(identity string, inbox, archived []*Message) error {
+	legacy := filepath.Join(x.townRoot, ".runtime", legacyIndexFile)
+	_ = os.Remove(legacy)
+	_ = os.Remove(legacy + ".lock")
+	return x.mailbox(identity).update(func(state *indexState) (bool, error) {
+		*state = *newIndexState()
+		for _, msg := range archived {
+			state.put(msg, true)
+		}
+		for _, msg := range inbox {
+			state.put(msg, false)
+		}
+		return true, nil
+	})
+}
+
+// Len returns how many messages the index holds for identity.
+func (x *SearchIndex) Len(identity string) (int, error) {
+	state, err := x.mailbox(identity).peek()
+	if err != nil {
+		return 0, err
+	}
+	return len(state.Docs), nil
+}
+
+// Messages returns every indexed message, in any mailbox, that match
+// accepts, oldest first. A message in several mailboxes is returned once.
+func (x *SearchIndex) Messages(match func(*Message) bool) ([]*Message, error) {
+	files, err := filepath.Glob(filepath.Join(x.townRoot, ".runtime", indexDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var out []*Message
+	for _, file := range files {
+		identity, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			continue
+		}
+		state, err := x.mailbox(identity).peek()
+		if err != nil {
+			return nil, err
+		}
+		for id, doc := range state.Docs {
+			if !seen[id] && match(doc.Message) {
+				seen[id] = true
+				out = append(out, doc.Message)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Timestamp.Equal(out[j].Timestamp) {
+			return out[i].Timestamp.Before(out[j].Timestamp)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// SearchHit is a message matching a query.
+type SearchHit struct {
+	Message  *Message `json:"message"`
+	Score    float64  `json:"score"`
+	Archived bool     `json:"archived"`
+
+	// Snippet is an excerpt around the first match; Highlights are the
+	// byte ranges of matched words within it.
+	Snippet    string `json:"snippet,omitempty"`
+	Highlights []Span `json:"highlights,omitempty"`
+}
+
+// Span is a byte range [Start, End) of a string.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Highlight returns the snippet with each highlighted range passed
+// through mark.
+func (h *SearchHit) Highlight(mark func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, s := range h.Highlights {
+		b.WriteString(h.Snippet[last:s.Start])
+		b.WriteString(mark(h.Snippet[s.Start:s.End]))
+		last = s.End
+	}
+	b.WriteString(h.Snippet[last:])
+	return b.String()
+}
+
+// AddMissing indexes those of identity's inbox msgs the index doesn't hold
+// yet, such as mail delivered before the index existed. Returns how many
+// were added.
+func (x *SearchIndex) AddMissing(identity string, msgs []*Message) (int, error) {
+	added := 0
+	err := x.mailbox(identity).update(func(state *indexState) (bool, error) {
+		for _, msg := range msgs {
+			if _, ok := state.Docs[msg.ID]; !ok && msg.ID != "" {
+				state.put(msg, false)
+				added++
+			}
+		}
+		return added > 0, nil
+	})
+	return added, err
+}
+
+// Search returns identity's messages matching q, best first. Messages with
+// equal scores (all of them, for a query without words) are newest first.
+//
+// live is the mailbox's current inbox, if known: it decides read state,
+// since messages can be read without the index hearing of it. Messages
+// neither in it nor archived were closed or deleted behind the index's
+// back, and are dropped. Without live the index's own record is used.
+func (x *SearchIndex) Search(identity string, q *Query, live []*Message) ([]*SearchHit, error) {
+	var hits []*SearchHit
+	err := x.mailbox(identity).update(func(state *indexState) (bool, error) {
+		var stale bool
+		hits, stale = state.search(q, live)
+		return stale, nil
+	})
+	return hits, err
+}
+
+// search returns the hits for q, and whether it dropped stale messages
+// (see Search).
+func (s *indexState) search(q *Query, live []*Message) ([]*SearchHit, bool) {
+	inbox := make(map[string]*Message, len(live))
+	for _, msg := range live {
+		inbox[msg.ID] = msg
+	}
+
+	var hits []*SearchHit
+	stale := false
+	score := s.scorer(q)
+	for _, id := range s.candidates(q) {
+		doc := s.Docs[id]
+		msg := *doc.Message
+		if live != nil {
+			current, ok := inbox[id]
+			if !ok && !doc.Archived {
+				s.drop(id)
+				stale = true
+				continue
+			}
+			msg.Read = !ok || current.Read
+		}
+		if !q.matches(&msg, doc.Archived) {
+			continue
+		}
+		hit := &SearchHit{Message: &msg, Archived: doc.Archived, Score: score(id)}
+		hit.Snippet, hit.Highlights = snippet(&msg, q)
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Message.Timestamp.After(hits[j].Message.Timestamp)
+	})
+	return hits, stale
+}
+
+// messageOwners returns the identities whose mailboxes hold msg.
+func messageOwners(msg *Message) []string {
+	owners := []string{AddressToIdentity(msg.To)}
+	for _, cc := range msg.CC {
+		if owner := AddressToIdentity(cc); !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// put indexes msg, replacing any earlier entry with its ID.
+func (s *indexState) put(msg *Message, archived bool) {
+	if msg.ID == "" {
+		return
+	}
+	s.drop(msg.ID)
+	stored := *msg
+	s.Docs[msg.ID] = &indexDoc{Message: &stored, Archived: archived}
+	addPostings(s.Subject, msg.ID, msg.Subject)
+	addPostings(s.Body, msg.ID, msg.Body)
+}
+
+// expireWisps drops wisps delivered more than wispIndexRetention before now.
+func (s *indexState) expireWisps(now time.Time) {
+	for id, doc := range s.Docs {
+		if doc.Message.Wisp && !doc.Archived && now.Sub(doc.Message.Timestamp) > wispIndexRetention {
+			s.drop(id)
+		}
+	}
+}
+
+// drop removes a message and its postings.
+func (s *indexState) drop(id string) {
+	doc, ok := s.Docs[id]
+	if !ok {
+		return
+	}
+	delete(s.Docs, id)
+	removePostings(s.Subject, id, doc.Message.Subject)
+	removePostings(s.Body, id, doc.Message.Body)
+}
+
+func addPostings(postings map[string]map[string]int, id, text string) {
+	for _, word := range tokenize(text) {
+		if postings[word] == nil {
+			postings[word] = make(map[string]int)
+		}
+		postings[word][id]++
+	}
+}
+
+func removePostings(postings map[string]map[string]int, id, text string) {
+	for _, word := range tokenize(text) {
+		delete(postings[word], id)
+		if len(postings[word]) == 0 {
+			delete(postings, word)
+		}
+	}
+}
+
+// lookup returns the postings for a term in one field, merging every word
+// a prefix term (ending in *) matches.
+func lookup(postings map[string]map[string]int, term string) map[string]int {
+	prefix, ok := strings.CutSuffix(term, "*")
+	if !ok {
+		return postings[term]
+	}
+	merged := make(map[string]int)
+	for word, docs := range postings {
+		if strings.HasPrefix(word, prefix) {
+			for id, n := range docs {
+				merged[id] += n
+			}
+		}
+	}
+	return merged
+}
+
+// candidates returns the IDs of messages containing every query word in
+// the right field. A query without words matches every message.
+func (s *indexState) candidates(q *Query) []string {
+	var sets []map[string]bool
+	collect := func(terms []string, fields ...map[string]map[string]int) {
+		for _, term := range terms {
+			set := make(map[string]bool)
+			for _, field := range fields {
+				for id := range lookup(field, term) {
+					set[id] = true
+				}
+			}
+			sets = append(sets, set)
+		}
+	}
+	collect(q.Terms, s.Subject, s.Body)
+	collect(q.SubjectTerms, s.Subject)
+	collect(q.BodyTerms, s.Body)
+
+	var ids []string
+	if len(sets) == 0 {
+		for id := range s.Docs {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	sort.Slice(sets, func(i, j int) bool { return len(sets[i]) < len(sets[j]) })
+	for id := range sets[0] {
+		inAll := true
+		for _, set := range sets[1:] {
+			if !set[id] {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// scorer returns a function ranking a message by tf-idf over the query
+// words, counting a subject match subjectWeight times a body match, plus a
+// bonus per phrase.
+func (s *indexState) scorer(q *Query) func(id string) float64 {
+	type termPostings struct {
+		subject, body map[string]int
+		idf           float64
+	}
+	n := float64(len(s.Docs))
+	var terms []termPostings
+	for _, term := range q.textTerms() {
+		tp := termPostings{subject: lookup(s.Subject, term), body: lookup(s.Body, term)}
+		if df := len(tp.subject) + len(tp.body); df > 0 {
+			tp.idf = math.Log(1 + n/float64(df))
+			terms = append(terms, tp)
+		}
+	}
+	phrases := float64(len(q.Phrases) + len(q.SubjectPhrases) + len(q.BodyPhrases))
+	return func(id string) float64 {
+		total := phrases
+		for _, tp := range terms {
+			total += tp.idf * float64(subjectWeight*tp.subject[id]+tp.body[id])
+		}
+		return math.Round(total*1000) / 1000
+	}
+}
+
+// matches reports whether msg satisfies q's filters and phrases. Words are
+// already matched through the postings.
+func (q *Query) matches(msg *Message, archived bool) bool {
+	subject, body := strings.ToLower(msg.Subject), strings.ToLower(msg.Body)
+	for _, p := range q.Phrases {
+		if !strings.Contains(subject, p) && !strings.Contains(body, p) {
+			return false
+		}
+	}
+	for _, p := range q.SubjectPhrases {
+		if !strings.Contains(subject, p) {
+			return false
+		}
+	}
+	for _, p := range q.BodyPhrases {
+		if !strings.Contains(body, p) {
+			return false
+		}
+	}
+	for _, from := range q.From {
+		if !strings.Contains(strings.ToLower(msg.From), from) {
+			return false
+		}
+	}
+	for _, to := range q.To {
+		found := strings.Contains(strings.ToLower(msg.To), to)
+		for _, cc := range msg.CC {
+			found = found || strings.Contains(strings.ToLower(cc), to)
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Thread != "" && msg.ThreadID != q.Thread {
+		return false
+	}
+	for _, label := range q.Labels {
+		if !slices.Contains(msg.Labels, label) {
+			return false
+		}
+	}
+	if q.Type != "" && msg.Type != q.Type {
+		return false
+	}
+	if q.Priority != "" && messagePriority(msg) != q.Priority {
+		return false
+	}
+	if !q.After.IsZero() && !msg.Timestamp.After(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && !msg.Timestamp.Before(q.Before) {
+		return false
+	}
+	if (q.Unread && msg.Read) || (q.Read && !msg.Read) {
+		return false
+	}
+	if (q.Inbox && archived) || (q.Archived && !archived) {
+		return false
+	}
+	return true
+}
+
+// messagePriority returns msg's priority, treating unset as normal.
+func messagePriority(msg *Message) Priority {
+	if msg.Priority == "" {
+		return PriorityNormal
+	}
+	return msg.Priority
+}
+
+// snippetContext is how many bytes of text a snippet shows before its
+// first match, and snippetLength its total length.
+const (
+	snippetContext = 40
+	snippetLength  = 160
+)
+
+// snippet excerpts the body around the first query word (the subject if
+// only the subject matches) and finds the words to highlight in it.
+func snippet(msg *Message, q *Query) (string, []Span) {
+	terms := q.textTerms()
+	text := msg.Body
+	first := firstMatch(text, terms)
+	if first < 0 && len(terms) > 0 {
+		if i := firstMatch(msg.Subject, terms); i >= 0 {
+			text, first = msg.Subject, i
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if first < 0 {
+		first = 0
+	} else {
+		first = firstMatch(text, terms)
+	}
+
+	start := max(first-snippetContext, 0)
+	for start > 0 && !unicode.IsSpace(rune(text[start-1])) {
+		start--
+	}
+	end := min(start+snippetLength, len(text))
+	for end < len(text) && !unicode.IsSpace(rune(text[end])) {
+		end++
+	}
+	excerpt := text[start:end]
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt, wordSpans(excerpt, terms)
+}
+
+// firstMatch returns the byte offset of the first word in text matching
+// one of terms, or -1.
+func firstMatch(text string, terms []string) int {
+	spans := wordSpans(text, terms)
+	if len(spans) == 0 {
+		return -1
+	}
+	return spans[0].Start
+}
+
+// wordSpans returns the byte ranges of words in text matching one of terms.
+func wordSpans(text string, terms []string) []Span {
+	if len(terms) == 0 {
+		return nil
+	}
+	var spans []Span
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := strings.ToLower(text[start:end])
+		for _, term := range terms {
+			prefix, isPrefix := strings.CutSuffix(term, "*")
+			if word == term || (isPrefix && strings.HasPrefix(word, prefix)) {
+				spans = append(spans, Span{Start: start, End: end})
+				break
+			}
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return spans
+}
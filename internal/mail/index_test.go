@@ -0,0 +1,281 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func testIndexMessages(now time.Time) []*Message {
+	return []*Message{
+		{ID: "hq-1", From: "gastown/witness", To: "mayor/", Subject: "Merge failed: nux",
+			Body: "The refinery could not merge nux because of a rebase conflict in go.mod.", Timestamp: now.Add(-time.Hour), ThreadID: "thread-a"},
+		{ID: "hq-2", From: "gastown/witness", To: "mayor/", Subject: "Status",
+			Body: "All quiet. Earlier the merge failed once, then passed.", Timestamp: now.Add(-30 * time.Minute)},
+		{ID: "hq-3", From: "deacon/", To: "mayor/", Subject: "Merge queue backlog",
+			Body: "Five MRs waiting.", Timestamp: now.Add(-72 * time.Hour), Priority: PriorityHigh, Labels: []string{"mq"}},
+		{ID: "hq-4", From: "gastown/witness", To: "gastown/refinery", CC: []string{"mayor/"}, Subject: "Merge failed: slit",
+			Body: "Tests failed on main.", Timestamp: now.Add(-10 * time.Minute)},
+		{ID: "hq-5", From: "mayor/", To: "deacon/", Subject: "Merge failed elsewhere",
+			Body: "Not in the mayor's mailbox.", Timestamp: now},
+	}
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	index := OpenSearchIndex(t.TempDir())
+	if err := index.Add(testIndexMessages(now)...); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(query string) []string {
+		t.Helper()
+		q, err := ParseQuery(query, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits, err := index.Search("mayor/", q, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, h := range hits {
+			out = append(out, h.Message.ID)
+		}
+		return out
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		// Subject matches outrank body matches; CC'd mail is included and
+		// other mailboxes' mail is not.
+		{`merge failed`, []string{"hq-4", "hq-1", "hq-2"}},
+		{`subject:"merge failed"`, []string{"hq-4", "hq-1"}},
+		{`body:"merge failed"`, []string{"hq-2"}},
+		{`from:deacon`, []string{"hq-3"}},
+		{`to:refinery`, []string{"hq-4"}},
+		{`merg* after:2d`, []string{"hq-1", "hq-4", "hq-2"}},
+		{`before:1d`, []string{"hq-3"}},
+		{`thread:thread-a`, []string{"hq-1"}},
+		{`label:mq priority:high`, []string{"hq-3"}},
+		{`priority:normal merge`, []string{"hq-1", "hq-4", "hq-2"}},
+		{`rebase conflict nonexistent`, nil},
+		// No words: newest first.
+		{``, []string{"hq-4", "hq-2", "hq-1", "hq-3"}},
+	} {
+		got := ids(tc.query)
+		if len(got) != len(tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.query, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%q: got %v, want %v", tc.query, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSearchIndex_LiveState(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	index := OpenSearchIndex(t.TempDir())
+	msgs := testIndexMessages(now)
+	if err := index.Add(msgs...); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.MarkArchived("mayor/", msgs[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string, live []*Message) map[string]bool {
+		t.Helper()
+		q, _ := ParseQuery(query, now)
+		hits, err := index.Search("mayor/", q, live)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make(map[string]bool)
+		for _, h := range hits {
+			out[h.Message.ID] = true
+		}
+		return out
+	}
+
+	if got := search("is:archived", nil); len(got) != 1 || !got["hq-3"] {
+		t.Errorf("is:archived without live inbox = %v, want hq-3", got)
+	}
+
+	// The live inbox overrides the index: hq-1 was read, and hq-4 was
+	// closed without being archived, so it is dropped.
+	read := *msgs[0]
+	read.Read = true
+	live := []*Message{&read, msgs[1]}
+	if got := search("is:unread", live); len(got) != 1 || !got["hq-2"] {
+		t.Errorf("is:unread = %v, want hq-2", got)
+	}
+	if got := search("is:archived", live); len(got) != 1 || !got["hq-3"] {
+		t.Errorf("is:archived = %v, want hq-3", got)
+	}
+	if n, _ := index.Len("mayor/"); n != 3 {
+		t.Errorf("mayor has %d indexed messages after search, want 3", n)
+	}
+	if n, _ := index.Len("gastown/refinery"); n != 1 {
+		t.Errorf("refinery lost its copy of hq-4: %d indexed messages", n)
+	}
+}
+
+func TestSearchIndex_ExpiresWisps(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now.Add(-8 * 24 * time.Hour) }
+	defer func() { timeNow = time.Now }()
+	index := OpenSearchIndex(t.TempDir())
+	old := &Message{ID: "hq-old", From: "deacon/", To: "mayor/", Subject: "nudge", Wisp: true, Timestamp: now.Add(-8 * 24 * time.Hour)}
+	kept := &Message{ID: "hq-kept", From: "deacon/", To: "mayor/", Subject: "report", Timestamp: now.Add(-8 * 24 * time.Hour)}
+	if err := index.Add(old, kept); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := index.Len("mayor/"); n != 2 {
+		t.Fatalf("indexed %d messages, want 2", n)
+	}
+
+	// A week later, the next delivery expires the old wisp.
+	timeNow = func() time.Time { return now }
+	recent := &Message{ID: "hq-new", From: "deacon/", To: "mayor/", Subject: "nudge", Wisp: true, Timestamp: now}
+	if err := index.Add(recent); err != nil {
+		t.Fatal(err)
+	}
+	msgs, _ := index.Messages(func(*Message) bool { return true })
+	if len(msgs) != 2 || msgs[0].ID != "hq-kept" || msgs[1].ID != "hq-new" {
+		t.Errorf("indexed %v, want the old wisp expired", messageIDs(msgs))
+	}
+}
+
+func TestSearchIndex_Reindex(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	index := OpenSearchIndex(t.TempDir())
+	msgs := testIndexMessages(now)
+	if err := index.Add(msgs...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuilding the mayor's entries drops mail no longer in the mailbox,
+	// but keeps the CC'd message for its other owner.
+	if err := index.Reindex("mayor/", []*Message{msgs[0]}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := index.Len("mayor/"); n != 1 {
+		t.Errorf("mayor has %d indexed messages, want 1", n)
+	}
+	if n, _ := index.Len("gastown/refinery"); n != 1 {
+		t.Errorf("refinery has %d indexed messages, want 1", n)
+	}
+
+	added, err := index.AddMissing("mayor/", msgs[:2])
+	if err != nil || added != 1 {
+		t.Errorf("AddMissing = %d, %v; want 1", added, err)
+	}
+
+	q, _ := ParseQuery("nux", now)
+	hits, _ := index.Search("mayor", q, nil)
+	if len(hits) != 1 {
+		t.Fatalf("expected the old postings for hq-1 to be replaced, got %d hits", len(hits))
+	}
+}
+
+func TestSearchHit_Snippet(t *testing.T) {
+	body := "Witness patrol report. " +
+		"Nothing much happened for a long while, polecats were busy and the queue was short. " +
+		"Then the merge failed for nux: the rebase hit a conflict in go.mod, which needs a human."
+	msg := &Message{Subject: "Report", Body: body}
+	q, _ := ParseQuery("conflict merg*", time.Now())
+
+	s, spans := snippet(msg, q)
+	if s[:len("…")] != "…" {
+		t.Errorf("snippet %q should start mid-body", s)
+	}
+	var marked []string
+	for _, sp := range spans {
+		marked = append(marked, s[sp.Start:sp.End])
+	}
+	if len(marked) != 2 || marked[0] != "merge" || marked[1] != "conflict" {
+		t.Errorf("highlights = %v, want [merge conflict]", marked)
+	}
+
+	hit := &SearchHit{Snippet: "a merge b", Highlights: []Span{{Start: 2, End: 7}}}
+	if got := hit.Highlight(func(s string) string { return "[" + s + "]" }); got != "a [merge] b" {
+		t.Errorf("Highlight = %q", got)
+	}
+
+	// Only the subject matches: the snippet comes from the subject.
+	msg = &Message{Subject: "Merge failed", Body: "see logs"}
+	q, _ = ParseQuery("merge", time.Now())
+	if s, spans := snippet(msg, q); s != "Merge failed" || len(spans) != 1 {
+		t.Errorf("subject snippet = %q %v", s, spans)
+	}
+}
+
+func TestMailbox_DeleteAndPurgeUnindex(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	m := NewMailbox(dir)
+	m.identity = "mayor/"
+	m.index = OpenSearchIndex(dir)
+	for _, msg := range testIndexMessages(now)[:3] {
+		if err := m.Append(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Reindex(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Delete("hq-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Archive("hq-3"); err != nil {
+		t.Fatal(err)
+	}
+	msgs, _ := m.index.Messages(func(*Message) bool { return true })
+	if len(msgs) != 2 {
+		t.Fatalf("indexed %v after delete, want hq-3 and hq-2", messageIDs(msgs))
+	}
+
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+	if n, err := m.PurgeArchive(1); err != nil || n != 1 {
+		t.Fatalf("PurgeArchive = %d, %v; want hq-3 purged", n, err)
+	}
+	if msgs, _ = m.index.Messages(func(*Message) bool { return true }); len(msgs) != 1 || msgs[0].ID != "hq-2" {
+		t.Errorf("indexed %v after purge, want hq-2", messageIDs(msgs))
+	}
+}
+
+func TestMailbox_SearchQueryLegacy(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	m := NewMailbox(t.TempDir())
+	msgs := testIndexMessages(now)
+	for _, msg := range msgs[:3] {
+		if err := m.Append(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Archive("hq-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	q, _ := ParseQuery("merge is:inbox", now)
+	hits, err := m.SearchQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 2 || hits[0].Message.ID != "hq-1" || hits[1].Message.ID != "hq-3" {
+		t.Errorf("inbox hits = %+v, want hq-1, hq-3", hits)
+	}
+
+	q, _ = ParseQuery("is:archived", now)
+	if hits, _ = m.SearchQuery(q); len(hits) != 1 || hits[0].Message.ID != "hq-2" || !hits[0].Archived {
+		t.Errorf("archived hits = %+v, want hq-2", hits)
+	}
+}
@@ -34,6 +34,8 @@ type Mailbox struct {
 	beadsDir string // explicit .beads directory path (set via BEADS_DIR)
 	path     string // for legacy JSONL mode (crew workers)
 	legacy   bool   // true = use JSONL files, false = use beads
+
+	index *SearchIndex // town search index to keep current (nil = none)
 }
 
 // NewMailbox creates a mailbox for the given JSONL path (legacy mode).
@@ -320,7 +322,11 @@ func (m *Mailbox) MarkRead(id string) error {
 	if m.legacy {
 		return m.markReadLegacy(id)
 	}
-	return m.markReadBeads(id)
+	if err := m.markReadBeads(id); err != nil {
+		return err
+	}
+	m.unindex(id) // Closed mail leaves the mailbox
+	return nil
 }
 
 func (m *Mailbox) markReadBeads(id string) error {
@@ -489,7 +495,11 @@ func (m *Mailbox) markUnreadLegacy(id string) error {
 // Delete removes a message.
 func (m *Mailbox) Delete(id string) error {
 	if m.legacy {
-		return m.deleteLegacy(id)
+		if err := m.deleteLegacy(id); err != nil {
+			return err
+		}
+		m.unindex(id)
+		return nil
 	}
 	return m.MarkRead(id) // beads: just acknowledge/close
 }
@@ -536,7 +546,11 @@ func (m *Mailbox) Archive(id string) error {
 	if err := m.appendToArchive(msg); err != nil {
 		return err
 	}
-	return m.Delete(id)
+	if err := m.Delete(id); err != nil {
+		return err
+	}
+	m.indexArchived(msg)
+	return nil
 }
 
 // archiveLegacy moves a message to the archive file atomically.
@@ -576,7 +590,33 @@ func (m *Mailbox) archiveLegacy(id string) error {
 	}
 
 	// Rewrite inbox without the target
-	return m.rewriteLegacy(remaining)
+	if err := m.rewriteLegacy(remaining); err != nil {
+		return err
+	}
+	m.indexArchived(target)
+	return nil
+}
+
+// indexArchived records an archived message in the town search index.
+// Best-effort: the archive itself has already succeeded.
+func (m *Mailbox) indexArchived(msg *Message) {
+	if m.index == nil {
+		return
+	}
+	if err := m.index.MarkArchived(m.identity, msg); err != nil {
+		fmt.Fprintf(os.Stderr, "mail index: %v\n", err)
+	}
+}
+
+// unindex drops messages that left the mailbox from the town search index.
+// Best-effort, like indexArchived: search drops them too once it notices.
+func (m *Mailbox) unindex(ids ...string) {
+	if m.index == nil || len(ids) == 0 {
+		return
+	}
+	if err := m.index.Remove(m.identity, ids...); err != nil {
+		fmt.Fprintf(os.Stderr, "mail index: %v\n", err)
+	}
 }
 
 // ArchivePath returns the path to the archive file.
@@ -675,17 +715,17 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		if err := os.Remove(m.ArchivePath()); err != nil && !os.IsNotExist(err) {
 			return 0, err
 		}
+		m.unindex(messageIDs(messages)...)
 		return len(messages), nil
 	}
 
 	// Filter by age
 	cutoff := timeNow().AddDate(0, 0, -olderThanDays)
-	var keep []*Message
-	purged := 0
+	var keep, purged []*Message
 
 	for _, msg := range messages {
 		if msg.Timestamp.Before(cutoff) {
-			purged++
+			purged = append(purged, msg)
 		} else {
 			keep = append(keep, msg)
 		}
@@ -702,7 +742,17 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		}
 	}
 
-	return purged, nil
+	m.unindex(messageIDs(purged)...)
+	return len(purged), nil
+}
+
+// messageIDs returns the IDs of msgs.
+func messageIDs(msgs []*Message) []string {
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+	return ids
 }
 
 func (m *Mailbox) rewriteArchive(messages []*Message) error {
@@ -744,7 +794,8 @@ type SearchOptions struct {
 	BodyOnly    bool   // Only search body
 }
 
-// Search finds messages matching the given criteria.
+// Search finds messages matching the given criteria by scanning the
+// mailbox; SearchQuery is the indexed search with query syntax.
 // Returns messages from both inbox and archive.
 // Query and FromFilter are treated as literal strings (not regex) to prevent ReDoS.
 func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
@@ -809,6 +860,68 @@ func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
 	return matches, nil
 }
 
+// SearchQuery finds the mailbox's messages, inbox and archive, matching q,
+// best first. It searches the town's index, first indexing any inbox mail
+// the index hasn't seen; an identity with nothing indexed yet is indexed in
+// full. Mailboxes without a town index (legacy JSONL) are searched in memory.
+func (m *Mailbox) SearchQuery(q *Query) ([]*SearchHit, error) {
+	live, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	if live == nil {
+		live = []*Message{} // empty inbox, not unknown
+	}
+
+	if m.index == nil {
+		archived, err := m.ListArchived()
+		if err != nil {
+			return nil, err
+		}
+		state := newIndexState()
+		for _, msg := range archived {
+			state.put(msg, true)
+		}
+		for _, msg := range live {
+			state.put(msg, false)
+		}
+		hits, _ := state.search(q, live)
+		return hits, nil
+	}
+
+	if err := m.Reindex(false); err != nil {
+		return nil, err
+	}
+	if _, err := m.index.AddMissing(m.identity, live); err != nil {
+		return nil, err
+	}
+	return m.index.Search(m.identity, q, live)
+}
+
+// Reindex rebuilds the mailbox's entries in the town search index from its
+// inbox and archive. Unless force is set, it only does so when the index
+// holds nothing for the mailbox.
+func (m *Mailbox) Reindex(force bool) error {
+	if m.index == nil {
+		return nil
+	}
+	if !force {
+		n, err := m.index.Len(m.identity)
+		if err != nil || n > 0 {
+			return err
+		}
+	}
+	live, err := m.List()
+	if err != nil {
+		return err
+	}
+	archived, err := m.ListArchived()
+	if err != nil {
+		return err
+	}
+	return m.index.Reindex(m.identity, live, archived)
+}
+
 // Count returns the total and unread message counts.
 func (m *Mailbox) Count() (total, unread int, err error) {
 	messages, err := m.List()
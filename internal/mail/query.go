@@ -0,0 +1,238 @@
+package mail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query is a parsed mail search query. All of its clauses must match.
+//
+// Syntax: free words and "quoted phrases" match the subject or body;
+// field:value (or field:"quoted value") narrows the search:
+//
+//	from:witness        sender contains "witness"
+//	to:mayor            recipient or CC contains "mayor"
+//	subject:"merge failed"
+//	body:conflict
+//	thread:thread-abc   exact thread ID
+//	label:ci            exact label
+//	type:task           task, scavenge, notification, reply
+//	priority:urgent     low, normal, high, urgent
+//	after:2d            newer than 2 days (or a date, 2006-01-02)
+//	before:2026-01-31   older than a date (or a duration ago)
+//	is:unread           unread, read, inbox, archived
+//
+// A word ending in * matches as a prefix (merg*).
+type Query struct {
+	// Terms and Phrases match the subject or body.
+	Terms   []string
+	Phrases []string
+
+	// SubjectTerms/Phrases and BodyTerms/Phrases match only that field.
+	SubjectTerms   []string
+	SubjectPhrases []string
+	BodyTerms      []string
+	BodyPhrases    []string
+
+	From     []string
+	To       []string
+	Thread   string
+	Labels   []string
+	Type     MessageType
+	Priority Priority
+	After    time.Time
+	Before   time.Time
+
+	// Unread and Read filter on read state; Inbox and Archived on whether
+	// the message is still in the inbox.
+	Unread   bool
+	Read     bool
+	Inbox    bool
+	Archived bool
+}
+
+// ParseQuery parses a search query. Relative after:/before: values are
+// measured back from now.
+func ParseQuery(s string, now time.Time) (*Query, error) {
+	q := &Query{}
+	tokens, err := splitQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		field, value, hasField := tok.field, tok.value, tok.field != ""
+		if !hasField {
+			q.addText(value, tok.quoted, &q.Terms, &q.Phrases)
+			continue
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s: needs a value", field)
+		}
+		switch field {
+		case "from":
+			q.From = append(q.From, strings.ToLower(value))
+		case "to":
+			q.To = append(q.To, strings.ToLower(value))
+		case "subject":
+			q.addText(value, tok.quoted, &q.SubjectTerms, &q.SubjectPhrases)
+		case "body":
+			q.addText(value, tok.quoted, &q.BodyTerms, &q.BodyPhrases)
+		case "thread":
+			q.Thread = value
+		case "label":
+			q.Labels = append(q.Labels, value)
+		case "type":
+			switch t := MessageType(strings.ToLower(value)); t {
+			case TypeTask, TypeScavenge, TypeNotification, TypeReply:
+				q.Type = t
+			default:
+				return nil, fmt.Errorf("type:%s: want task, scavenge, notification, or reply", value)
+			}
+		case "priority":
+			switch p := Priority(strings.ToLower(value)); p {
+			case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+				q.Priority = p
+			default:
+				return nil, fmt.Errorf("priority:%s: want low, normal, high, or urgent", value)
+			}
+		case "after", "before":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%s: %w", field, value, err)
+			}
+			if field == "after" {
+				q.After = t
+			} else {
+				q.Before = t
+			}
+		case "is":
+			switch strings.ToLower(value) {
+			case "unread":
+				q.Unread = true
+			case "read":
+				q.Read = true
+			case "inbox":
+				q.Inbox = true
+			case "archived":
+				q.Archived = true
+			default:
+				return nil, fmt.Errorf("is:%s: want unread, read, inbox, or archived", value)
+			}
+		default:
+			// Not a known field (e.g. a URL or "note:"): search it as text.
+			q.addText(field+":"+value, tok.quoted, &q.Terms, &q.Phrases)
+		}
+	}
+	return q, nil
+}
+
+// addText adds a word or phrase to the given term and phrase lists. A
+// phrase that tokenizes to a single word is just a term.
+func (q *Query) addText(value string, quoted bool, terms, phrases *[]string) {
+	prefix := !quoted && strings.HasSuffix(value, "*")
+	words := tokenize(value)
+	if len(words) == 0 {
+		return
+	}
+	if prefix {
+		words[len(words)-1] += "*"
+	}
+	if quoted && len(words) > 1 {
+		*phrases = append(*phrases, strings.ToLower(value))
+	}
+	*terms = append(*terms, words...)
+}
+
+// textTerms returns every term the query ranks on.
+func (q *Query) textTerms() []string {
+	var all []string
+	all = append(all, q.Terms...)
+	all = append(all, q.SubjectTerms...)
+	all = append(all, q.BodyTerms...)
+	return all
+}
+
+// queryToken is a query word, phrase, or field:value.
+type queryToken struct {
+	field  string
+	value  string
+	quoted bool
+}
+
+// splitQuery splits a query on whitespace, keeping "quoted phrases" (also
+// as field values) together.
+func splitQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+		var tok queryToken
+		start := i
+		for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != '"' {
+			if rs[i] == ':' && tok.field == "" && i > start {
+				tok.field = strings.ToLower(string(rs[start:i]))
+				start = i + 1
+			}
+			i++
+		}
+		if i < len(rs) && rs[i] == '"' {
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("unterminated quote in %q", s)
+			}
+			if i > start {
+				// A quote inside a word: treat the whole word as text.
+				tok = queryToken{value: string(rs[start:end])}
+			} else {
+				tok.value = string(rs[i+1 : end])
+				tok.quoted = true
+			}
+			i = end + 1
+		} else {
+			tok.value = string(rs[start:i])
+		}
+		if tok.field == "" && tok.value == "" {
+			continue
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// parseQueryTime parses an after:/before: value: a date, an RFC3339 time,
+// or a duration ago (90m, 4h, 2d, 1w).
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			if days, err := strconv.Atoi(n); err == nil && days >= 0 {
+				return now.Add(-time.Duration(days) * unit), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("want a date (2006-01-02) or a duration such as 4h, 2d, or 1w")
+}
+
+// tokenize splits text into lowercase words for indexing and matching.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	q, err := ParseQuery(`from:witness subject:"merge failed" after:2d thread:xyz is:unread rebase conflic* "on main" http://ci`, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Query{
+		Terms:          []string{"rebase", "conflic*", "on", "main", "http", "ci"},
+		Phrases:        []string{"on main"},
+		SubjectTerms:   []string{"merge", "failed"},
+		SubjectPhrases: []string{"merge failed"},
+		From:           []string{"witness"},
+		Thread:         "xyz",
+		After:          now.Add(-48 * time.Hour),
+		Unread:         true,
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("ParseQuery =\n  %+v\nwant\n  %+v", q, want)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, s := range []string{
+		`subject:"unterminated`,
+		`is:starred`,
+		`priority:critical`,
+		`type:memo`,
+		`after:yesterday`,
+		`from:`,
+	} {
+		if _, err := ParseQuery(s, time.Now()); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestParseQueryTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Time{
+		"4h":                   now.Add(-4 * time.Hour),
+		"1w":                   now.Add(-7 * 24 * time.Hour),
+		"2026-03-01T08:00:00Z": time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+	} {
+		got, err := parseQueryTime(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseQueryTime(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+}
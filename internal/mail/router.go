@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -897,10 +898,30 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
+	// Run the recipient's mail rules: they may relabel, raise priority, or
+	// change delivery, and decide whether the message is archived on
+	// arrival or forwarded.
+	// The changes apply to this delivery only, not the caller's message.
+	rules := r.evaluateRules(msg)
+	if rules != nil {
+		filtered := *msg
+		filtered.Labels = append([]string(nil), msg.Labels...)
+		rules.Apply(&filtered)
+		msg = &filtered
+		if rules.Archive {
+			if err := r.archiveOnDelivery(msg); err != nil {
+				return err
+			}
+			r.resolveAwaited(msg)
+			return r.forwardCopies(msg, rules.Forward)
+		}
+	}
+
 	// Build labels for type, from/thread/reply-to/cc
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
+	labels = append(labels, r.signatureLabels(msg)...)
 	labels = append(labels, DeliverySendLabels()...)
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
@@ -913,11 +934,14 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	for _, label := range msg.Labels {
+		labels = append(labels, "label:"+label)
+	}
 
 	// Build command: bd create --assignee=<recipient> -d <body> --labels=gt:message,... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags (see web/api.go).
-	args := []string{"create",
+	args := []string{"create", "--json",
 		"--assignee", toIdentity,
 		"-d", msg.Body,
 	}
@@ -949,10 +973,12 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
+	r.indexDelivered(msg, out)
+	r.resolveAwaited(msg)
 
 	// Notify recipient if they have an active session (best-effort notification).
 	// Skip when the caller explicitly suppressed notification (--no-notify)
@@ -969,6 +995,9 @@ func (r *Router) sendToSingle(msg *Message) error {
 		}()
 	}
 
+	if rules != nil {
+		return r.forwardCopies(msg, rules.Forward)
+	}
 	return nil
 }
 
@@ -1349,7 +1378,43 @@ func isSelfMail(from, to string) bool {
 func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	beadsDir := r.resolveBeadsDir()
 	workDir := filepath.Dir(beadsDir) // Parent of .beads
-	return NewMailboxFromAddress(address, workDir), nil
+	mailbox := NewMailboxFromAddress(address, workDir)
+	mailbox.index = r.searchIndex()
+	return mailbox, nil
+}
+
+// searchIndex returns the town's mail search index, or nil when the router
+// has no town root.
+func (r *Router) searchIndex() *SearchIndex {
+	if r.townRoot == "" {
+		return nil
+	}
+	return OpenSearchIndex(r.townRoot)
+}
+
+// indexDelivered adds a delivered message to its recipients' search index
+// under the ID bd gave its bead (from bd create --json output), marking
+// wisps so the index expires them. Best-effort: the message is already
+// delivered, and gt mail search indexes inbox mail it's missing.
+func (r *Router) indexDelivered(msg *Message, createOutput []byte) {
+	index := r.searchIndex()
+	if index == nil {
+		return
+	}
+	indexed := *msg
+	indexed.Wisp = r.shouldBeWisp(msg)
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(createOutput, &created); err == nil && created.ID != "" {
+		indexed.ID = created.ID
+	}
+	if indexed.Timestamp.IsZero() {
+		indexed.Timestamp = timeNow()
+	}
+	if err := index.Add(&indexed); err != nil {
+		fmt.Fprintf(os.Stderr, "mail index: %v\n", err)
+	}
 }
 
 // notifyRecipient sends a notification to a recipient's tmux session.
@@ -1360,6 +1425,8 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 //     the next turn boundary.
 //  3. For the overseer (human operator), always use a visible banner.
 //
+// Messages with interrupt delivery skip step 2 and nudge immediately.
+//
 // Supports mayor/, deacon/, rig/crew/name, rig/polecats/name, and rig/name addresses.
 // Respects agent DND/muted state - skips notification if recipient has DND enabled.
 func (r *Router) notifyRecipient(msg *Message) error {
@@ -1397,6 +1464,16 @@ func (r *Router) notifyRecipient(msg *Message) error {
 
 		notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
 
+		// Interrupt delivery (set by mail rules) doesn't wait for the
+		// session to go idle.
+		if msg.Delivery == DeliveryInterrupt {
+			if err := r.tmux.NudgeSession(sessionID, notification); errors.Is(err, tmux.ErrSessionNotFound) {
+				continue
+			} else if err == nil || errors.Is(err, tmux.ErrNoServer) {
+				return nil
+			}
+		}
+
 		// Idle-aware notification: try immediate nudge first, fall back to queue.
 		waitErr := r.tmux.WaitForIdle(sessionID, timeout)
 		if waitErr == nil {
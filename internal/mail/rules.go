@@ -0,0 +1,216 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RuleSet is an identity's compiled mail filter rules.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	config.MailRule
+	subject *regexp.Regexp
+}
+
+// RuleOutcome is what a RuleSet decided for a message.
+type RuleOutcome struct {
+	// Matched are the names of the rules that matched, in order.
+	Matched []string `json:"matched"`
+
+	Archive  bool     `json:"archive,omitempty"`
+	Forward  []string `json:"forward,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+	Delivery Delivery `json:"delivery,omitempty"`
+}
+
+// CompileRules compiles mail filter rules. A nil config yields an empty set.
+func CompileRules(cfg *config.MailRules) (*RuleSet, error) {
+	rs := &RuleSet{}
+	if cfg == nil {
+		return rs, nil
+	}
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		cr := compiledRule{MailRule: rule}
+		if rule.Match.Subject != "" {
+			re, err := regexp.Compile(rule.Match.Subject)
+			if err != nil {
+				return nil, fmt.Errorf("mail rule %s: invalid subject pattern: %w", rule.Name, err)
+			}
+			cr.subject = re
+		}
+		rs.rules = append(rs.rules, cr)
+	}
+	return rs, nil
+}
+
+// LoadRules loads the mail filter rules for the identity at address in the
+// town at townRoot. An identity without a rules file gets an empty set.
+func LoadRules(townRoot, address string) (*RuleSet, error) {
+	cfg, err := config.LoadMailRules(config.MailRulesPath(townRoot, AddressToIdentity(address)))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return &RuleSet{}, nil
+		}
+		return nil, err
+	}
+	return CompileRules(cfg)
+}
+
+// Len returns the number of rules in the set.
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// Evaluate runs the rules against msg without changing it. Returns nil if
+// no rule matches.
+func (rs *RuleSet) Evaluate(msg *Message) *RuleOutcome {
+	var out *RuleOutcome
+	for i := range rs.rules {
+		rule := &rs.rules[i]
+		if !rule.matches(msg) {
+			continue
+		}
+		if out == nil {
+			out = &RuleOutcome{}
+		}
+		out.Matched = append(out.Matched, rule.Name)
+		out.Archive = out.Archive || rule.Archive
+		out.Forward = appendUnique(out.Forward, rule.Forward...)
+		out.Labels = appendUnique(out.Labels, rule.Labels...)
+		if rule.Priority != "" && (out.Priority == "" || priorityRank(Priority(rule.Priority)) < priorityRank(out.Priority)) {
+			out.Priority = Priority(rule.Priority)
+		}
+		if rule.Delivery != "" {
+			out.Delivery = Delivery(rule.Delivery)
+		}
+		if rule.Stop {
+			break
+		}
+	}
+	return out
+}
+
+// matches reports whether every condition the rule sets holds for msg.
+func (r *compiledRule) matches(msg *Message) bool {
+	m := r.Match
+	if m.From != "" && !matchPattern(AddressToIdentity(m.From), AddressToIdentity(msg.From)) {
+		return false
+	}
+	if r.subject != nil && !r.subject.MatchString(msg.Subject) {
+		return false
+	}
+	if m.Type != "" {
+		msgType := msg.Type
+		if msgType == "" {
+			msgType = TypeNotification
+		}
+		if MessageType(m.Type) != msgType {
+			return false
+		}
+	}
+	if m.Priority != "" && priorityRank(Priority(m.Priority)) != priorityRank(msg.Priority) {
+		return false
+	}
+	if m.Thread != "" && m.Thread != msg.ThreadID {
+		return false
+	}
+	return true
+}
+
+// Apply makes the outcome's changes to msg: labels, a raised priority, and
+// the delivery mode. Archiving and forwarding are up to the router.
+func (o *RuleOutcome) Apply(msg *Message) {
+	msg.Labels = appendUnique(msg.Labels, o.Labels...)
+	if o.Priority != "" && priorityRank(o.Priority) < priorityRank(msg.Priority) {
+		msg.Priority = o.Priority
+	}
+	if o.Delivery != "" {
+		msg.Delivery = o.Delivery
+	}
+}
+
+// priorityRank orders priorities, most urgent first (the beads scale).
+func priorityRank(p Priority) int {
+	return PriorityToBeads(p)
+}
+
+// evaluateRules runs the recipient's mail rules on msg, which is about to be
+// delivered to a single recipient. Returns nil if no rule matched. A rules
+// file that can't be loaded is reported and ignored, so a typo can't stop
+// mail.
+func (r *Router) evaluateRules(msg *Message) *RuleOutcome {
+	if r.townRoot == "" || msg.rulesApplied {
+		return nil
+	}
+	rules, err := LoadRules(r.townRoot, msg.To)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mail rules for %s: %v (delivering unfiltered)\n", msg.To, err)
+		return nil
+	}
+	return rules.Evaluate(msg)
+}
+
+// archiveOnDelivery files msg straight into its recipient's archive.
+func (r *Router) archiveOnDelivery(msg *Message) error {
+	mailbox, err := r.GetMailbox(msg.To)
+	if err != nil {
+		return err
+	}
+	archived := *msg
+	if archived.Timestamp.IsZero() {
+		archived.Timestamp = timeNow()
+	}
+	archived.Read = true
+	if err := mailbox.appendToArchive(&archived); err != nil {
+		return fmt.Errorf("archiving message: %w", err)
+	}
+	mailbox.indexArchived(&archived)
+	return nil
+}
+
+// forwardCopies sends msg to each of the outcome's forward addresses.
+// Forwarded copies skip the new recipients' rules, so rules can't loop.
+func (r *Router) forwardCopies(msg *Message, forward []string) error {
+	var errs []error
+	for _, addr := range forward {
+		if AddressToIdentity(addr) == AddressToIdentity(msg.To) {
+			continue
+		}
+		fwd := *msg
+		fwd.To = addr
+		fwd.ID = ""
+		fwd.CC = nil
+		fwd.rulesApplied = true
+		if err := r.sendToSingle(&fwd); err != nil {
+			errs = append(errs, fmt.Errorf("forwarding to %s: %w", addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,146 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func mustCompileRules(t *testing.T, rules ...config.MailRule) *RuleSet {
+	t.Helper()
+	rs, err := CompileRules(&config.MailRules{Rules: rules})
+	if err != nil {
+		t.Fatalf("CompileRules: %v", err)
+	}
+	return rs
+}
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	rs := mustCompileRules(t,
+		config.MailRule{
+			Name:    "polecat-done",
+			Match:   config.MailRuleMatch{From: "gastown/polecats/*", Subject: "^POLECAT_DONE"},
+			Archive: true,
+			Stop:    true,
+		},
+		config.MailRule{
+			Name:     "escalations",
+			Match:    config.MailRuleMatch{Subject: "(?i)escalat"},
+			Labels:   []string{"escalation"},
+			Priority: "urgent",
+			Delivery: "interrupt",
+		},
+		config.MailRule{
+			Name:    "tasks",
+			Match:   config.MailRuleMatch{Type: "task"},
+			Labels:  []string{"todo"},
+			Forward: []string{"gastown/crew/max"},
+		},
+	)
+
+	for _, tc := range []struct {
+		name string
+		msg  *Message
+		want *RuleOutcome
+	}{
+		{
+			name: "no match",
+			msg:  &Message{From: "mayor/", Subject: "hello"},
+		},
+		{
+			// Crew and polecat addresses compare in canonical form.
+			name: "stop ends evaluation",
+			msg:  &Message{From: "gastown/Toast", Subject: "POLECAT_DONE escalate", Type: TypeTask},
+			want: &RuleOutcome{Matched: []string{"polecat-done"}, Archive: true},
+		},
+		{
+			name: "actions accumulate",
+			msg:  &Message{From: "deacon/", Subject: "Escalation: disk full", Type: TypeTask},
+			want: &RuleOutcome{
+				Matched:  []string{"escalations", "tasks"},
+				Forward:  []string{"gastown/crew/max"},
+				Labels:   []string{"escalation", "todo"},
+				Priority: PriorityUrgent,
+				Delivery: DeliveryInterrupt,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rs.Evaluate(tc.msg); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Evaluate = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRuleSet_MatchPriorityAndThread(t *testing.T) {
+	rs := mustCompileRules(t, config.MailRule{
+		Match:  config.MailRuleMatch{Priority: "normal", Thread: "thread-1"},
+		Labels: []string{"x"},
+	})
+	if rs.Evaluate(&Message{ThreadID: "thread-1"}) == nil {
+		t.Error("expected an unset priority to match normal")
+	}
+	if rs.Evaluate(&Message{ThreadID: "thread-1", Priority: PriorityHigh}) != nil {
+		t.Error("expected high priority not to match normal")
+	}
+	if rs.Evaluate(&Message{ThreadID: "thread-2"}) != nil {
+		t.Error("expected another thread not to match")
+	}
+}
+
+func TestRuleOutcome_ApplyOnlyRaisesPriority(t *testing.T) {
+	msg := &Message{Priority: PriorityUrgent, Labels: []string{"ci"}}
+	(&RuleOutcome{Priority: PriorityHigh, Labels: []string{"ci", "noise"}}).Apply(msg)
+	if msg.Priority != PriorityUrgent {
+		t.Errorf("priority lowered to %s", msg.Priority)
+	}
+	if !reflect.DeepEqual(msg.Labels, []string{"ci", "noise"}) {
+		t.Errorf("Labels = %v", msg.Labels)
+	}
+
+	msg = &Message{Priority: PriorityLow}
+	(&RuleOutcome{Priority: PriorityHigh, Delivery: DeliveryInterrupt}).Apply(msg)
+	if msg.Priority != PriorityHigh || msg.Delivery != DeliveryInterrupt {
+		t.Errorf("got priority %s, delivery %s", msg.Priority, msg.Delivery)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	townRoot := t.TempDir()
+
+	rs, err := LoadRules(townRoot, "gastown/witness")
+	if err != nil || rs.Len() != 0 {
+		t.Fatalf("missing rules file: got %d rules, %v; want none", rs.Len(), err)
+	}
+
+	path := config.MailRulesPath(townRoot, "gastown/witness")
+	if want := filepath.Join(townRoot, "config", "mail-rules", "gastown", "witness.json"); path != want {
+		t.Errorf("MailRulesPath = %s, want %s", path, want)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "mail-rules", "version": 1, "rules": [{"match": {"subject": "^MERGED"}, "archive": true}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err = LoadRules(townRoot, "gastown/witness/")
+	if err != nil || rs.Len() != 1 {
+		t.Fatalf("LoadRules = %d rules, %v; want 1", rs.Len(), err)
+	}
+	if out := rs.Evaluate(&Message{Subject: "MERGED nux"}); out == nil || !out.Archive || out.Matched[0] != "rule 1" {
+		t.Errorf("Evaluate = %+v, want archived by rule 1", out)
+	}
+}
+
+func TestBeadsMessage_Labels(t *testing.T) {
+	bm := &BeadsMessage{ID: "hq-1", Labels: []string{"from:mayor/", "label:noise", "label:ci"}}
+	if got := bm.ToMessage().Labels; !reflect.DeepEqual(got, []string{"noise", "ci"}) {
+		t.Errorf("Labels = %v, want [noise ci]", got)
+	}
+}
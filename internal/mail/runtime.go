@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/agent"
+)
+
+// runtimeStore is a town state file under <town>/.runtime/ that the CLI and
+// the daemon share, so every read and write holds a file lock.
+type runtimeStore[T any] struct {
+	store *agent.StateManager[T]
+	name  string // what the file holds, for errors ("scheduled mail")
+}
+
+func newRuntimeStore[T any](townRoot, file, name string, defaultFn func() *T) *runtimeStore[T] {
+	return &runtimeStore[T]{
+		store: agent.NewStateManager[T](townRoot, file, defaultFn),
+		name:  name,
+	}
+}
+
+func (s *runtimeStore[T]) lock() (*flock.Flock, error) {
+	if err := os.MkdirAll(filepath.Dir(s.store.StateFile()), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	fl := flock.New(s.store.StateFile() + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring %s lock: %w", s.name, err)
+	}
+	return fl, nil
+}
+
+// update loads the state under the lock, applies fn, and saves the state if
+// fn reports a change (even if fn also returns an error).
+func (s *runtimeStore[T]) update(fn func(state *T) (bool, error)) error {
+	fl, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := s.store.Load()
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", s.name, err)
+	}
+	changed, err := fn(state)
+	if changed {
+		if saveErr := s.store.Save(state); saveErr != nil && err == nil {
+			err = fmt.Errorf("saving %s: %w", s.name, saveErr)
+		}
+	}
+	return err
+}
+
+// peek loads the state without the lock, for cheap checks before taking it.
+// The file is written atomically, so it is whole, if possibly stale.
+func (s *runtimeStore[T]) peek() (*T, error) {
+	state, err := s.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", s.name, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,372 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// scheduleFile is the scheduled mail list's file name under <town>/.runtime/.
+const scheduleFile = "mail-scheduled.json"
+
+// MaxScheduledAttempts is how many releases may fail to deliver to a
+// recipient before the delivery is marked failed and no longer retried.
+const MaxScheduledAttempts = 10
+
+// scheduleClaimTTL bounds how long a release may hold a message.
+const scheduleClaimTTL = 10 * time.Minute
+
+// ScheduledDelivery is the delivery of a scheduled message to one of its
+// recipients.
+type ScheduledDelivery struct {
+	Address     string    `json:"address"`
+	DeliveredAt time.Time `json:"delivered_at,omitempty"`
+
+	// Attempts and LastError record failed sends.
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Failed reports whether the delivery has used up its attempts.
+func (d *ScheduledDelivery) Failed() bool {
+	return d.DeliveredAt.IsZero() && d.Attempts >= MaxScheduledAttempts
+}
+
+// pending reports whether the delivery is still to be sent.
+func (d *ScheduledDelivery) pending() bool {
+	return d.DeliveredAt.IsZero() && !d.Failed()
+}
+
+// ScheduledMessage is a message held for later delivery: at a time, or when
+// a bead closes.
+type ScheduledMessage struct {
+	ID      string   `json:"id"`
+	Message *Message `json:"message"`
+
+	// SuppressNotify is carried separately because Message doesn't
+	// serialize it.
+	SuppressNotify bool `json:"suppress_notify,omitempty"`
+
+	// DeliverAt is when the message is released. Zero for OnClose messages.
+	DeliverAt time.Time `json:"deliver_at,omitempty"`
+
+	// OnClose is a bead ID; the message is released once it closes.
+	OnClose string `json:"on_close,omitempty"`
+
+	ScheduledAt time.Time `json:"scheduled_at"`
+
+	// Recipients are the message's deliveries, expanded from its address
+	// when it was scheduled (see ExpandRecipients), so a release that
+	// reaches only some of them retries just the rest.
+	Recipients []*ScheduledDelivery `json:"recipients"`
+
+	// LastError records a failure to check the OnClose bead.
+	LastError string `json:"last_error,omitempty"`
+
+	// ClaimedUntil is set while a release is sending the message, so a
+	// concurrent release skips it. A claim that outlives a crashed release
+	// expires.
+	ClaimedUntil time.Time `json:"claimed_until,omitempty"`
+}
+
+// Failed reports whether every delivery is done and some failed. Failed
+// messages stay listed until cancelled.
+func (s *ScheduledMessage) Failed() bool {
+	failed := false
+	for _, d := range s.Recipients {
+		if d.pending() {
+			return false
+		}
+		failed = failed || d.Failed()
+	}
+	return failed
+}
+
+// Errors returns the message's most recent errors, one per line.
+func (s *ScheduledMessage) Errors() []string {
+	var errs []string
+	if s.LastError != "" {
+		errs = append(errs, s.LastError)
+	}
+	for _, d := range s.Recipients {
+		if d.DeliveredAt.IsZero() && d.LastError != "" {
+			errs = append(errs, fmt.Sprintf("%s (attempt %d/%d): %s", d.Address, d.Attempts, MaxScheduledAttempts, d.LastError))
+		}
+	}
+	return errs
+}
+
+// copy returns a copy of s whose deliveries can be updated independently.
+func (s *ScheduledMessage) copy() *ScheduledMessage {
+	c := *s
+	c.Recipients = make([]*ScheduledDelivery, len(s.Recipients))
+	for i, d := range s.Recipients {
+		dc := *d
+		c.Recipients[i] = &dc
+	}
+	return &c
+}
+
+// Trigger describes when the message will be delivered.
+func (s *ScheduledMessage) Trigger() string {
+	if s.OnClose != "" {
+		return "when " + s.OnClose + " closes"
+	}
+	return "at " + s.DeliverAt.Local().Format("2006-01-02 15:04")
+}
+
+// scheduleState is the on-disk scheduled mail list.
+type scheduleState struct {
+	Messages []*ScheduledMessage `json:"messages"`
+}
+
+func newScheduleState() *scheduleState {
+	return &scheduleState{}
+}
+
+// Schedule is a town's scheduled mail, released by the daemon heartbeat.
+type Schedule struct {
+	*runtimeStore[scheduleState]
+}
+
+// NewSchedule returns the scheduled mail list for the town at townRoot.
+func NewSchedule(townRoot string) *Schedule {
+	return &Schedule{newRuntimeStore(townRoot, scheduleFile, "scheduled mail", newScheduleState)}
+}
+
+// Add schedules sm, assigning its ID. Without Recipients, sm is delivered
+// to its message's address as is.
+func (s *Schedule) Add(sm *ScheduledMessage) error {
+	if sm.Message == nil {
+		return fmt.Errorf("scheduled mail has no message")
+	}
+	if sm.DeliverAt.IsZero() == (sm.OnClose == "") {
+		return fmt.Errorf("scheduled mail needs exactly one of a delivery time or a bead to wait on")
+	}
+	if len(sm.Recipients) == 0 {
+		sm.Recipients = []*ScheduledDelivery{{Address: sm.Message.To}}
+	}
+	sm.ID = generateScheduleID()
+	if sm.ScheduledAt.IsZero() {
+		sm.ScheduledAt = timeNow()
+	}
+	return s.update(func(state *scheduleState) (bool, error) {
+		state.Messages = append(state.Messages, sm)
+		return true, nil
+	})
+}
+
+// List returns the scheduled messages, soonest first; messages waiting on a
+// bead come last.
+func (s *Schedule) List() ([]*ScheduledMessage, error) {
+	var out []*ScheduledMessage
+	err := s.update(func(state *scheduleState) (bool, error) {
+		out = state.Messages
+		return false, nil
+	})
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (a.OnClose == "") != (b.OnClose == "") {
+			return a.OnClose == ""
+		}
+		return a.DeliverAt.Before(b.DeliverAt)
+	})
+	return out, err
+}
+
+// Cancel removes a scheduled message and returns it.
+func (s *Schedule) Cancel(id string) (*ScheduledMessage, error) {
+	var cancelled *ScheduledMessage
+	err := s.update(func(state *scheduleState) (bool, error) {
+		for i, sm := range state.Messages {
+			if sm.ID == id {
+				cancelled = sm
+				state.Messages = append(state.Messages[:i], state.Messages[i+1:]...)
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%w: scheduled %s", ErrMessageNotFound, id)
+	})
+	return cancelled, err
+}
+
+// Release sends every message that is due at now: its time has come, or
+// isClosed reports its bead closed. Each pending recipient gets its own
+// send, outside the lock; a recipient whose send fails is retried on the
+// next release, up to MaxScheduledAttempts. Messages leave the list once
+// delivered to every recipient. Returns the messages delivered.
+func (s *Schedule) Release(now time.Time, isClosed func(beadID string) (bool, error), send func(*Message) error) ([]*ScheduledMessage, error) {
+	var claimed []*ScheduledMessage
+	err := s.update(func(state *scheduleState) (bool, error) {
+		for _, sm := range state.Messages {
+			if now.Before(sm.ClaimedUntil) {
+				continue
+			}
+			if sm.OnClose == "" && now.Before(sm.DeliverAt) {
+				continue
+			}
+			if len(sm.Recipients) == 0 {
+				sm.Recipients = []*ScheduledDelivery{{Address: sm.Message.To}}
+			}
+			if !slices.ContainsFunc(sm.Recipients, (*ScheduledDelivery).pending) {
+				continue
+			}
+			sm.ClaimedUntil = now.Add(scheduleClaimTTL)
+			claimed = append(claimed, sm.copy())
+		}
+		return len(claimed) > 0, nil
+	})
+	if err != nil || len(claimed) == 0 {
+		return nil, err
+	}
+
+	// Check triggers and send outside the lock
+	for _, sm := range claimed {
+		if sm.OnClose != "" {
+			closed, err := isClosed(sm.OnClose)
+			sm.LastError = ""
+			if err != nil {
+				sm.LastError = err.Error()
+			}
+			if !closed {
+				continue
+			}
+		}
+		for _, d := range sm.Recipients {
+			if !d.pending() {
+				continue
+			}
+			msg := *sm.Message
+			msg.To = d.Address
+			if len(sm.Recipients) > 1 {
+				msg.ID = "" // Each fan-out copy gets its own ID
+			}
+			msg.Timestamp = now
+			msg.SuppressNotify = sm.SuppressNotify
+			if err := send(&msg); err != nil {
+				d.Attempts++
+				d.LastError = err.Error()
+				continue
+			}
+			d.DeliveredAt = now
+			d.LastError = ""
+		}
+	}
+
+	outcomes := make(map[string]*ScheduledMessage, len(claimed))
+	for _, sm := range claimed {
+		outcomes[sm.ID] = sm
+	}
+	var released []*ScheduledMessage
+	err = s.update(func(state *scheduleState) (bool, error) {
+		kept := state.Messages[:0]
+		for _, sm := range state.Messages {
+			out, ok := outcomes[sm.ID]
+			if !ok {
+				kept = append(kept, sm)
+				continue
+			}
+			out.ClaimedUntil = time.Time{}
+			if slices.ContainsFunc(out.Recipients, func(d *ScheduledDelivery) bool { return d.DeliveredAt.IsZero() }) {
+				kept = append(kept, out)
+				continue
+			}
+			released = append(released, out)
+		}
+		state.Messages = kept
+		return true, nil
+	})
+	return released, err
+}
+
+// ExpandRecipients resolves to into the addresses a scheduled message is
+// sent to, one send each, and checks that each agent among them exists:
+// scheduling is when the sender can still fix a bad address. Lists and
+// groups are expanded now, so retries reach only the recipients that
+// failed.
+func (r *Router) ExpandRecipients(to string) ([]string, error) {
+	var addrs []string
+	add := func(addr string) {
+		if !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	recipients, err := NewResolver(beads.New(r.townRoot), r.townRoot).Resolve(to)
+	if err != nil {
+		if !isGroupAddress(to) {
+			return nil, fmt.Errorf("resolving %s: %w", to, err)
+		}
+		// Legacy @group addresses are resolved by the router
+		members, groupErr := r.ResolveGroupAddress(to)
+		if groupErr != nil {
+			return nil, groupErr
+		}
+		for _, member := range members {
+			recipients = append(recipients, Recipient{Address: member, Type: RecipientAgent})
+		}
+	}
+
+	for _, rec := range recipients {
+		if !isListAddress(rec.Address) {
+			add(rec.Address)
+			continue
+		}
+		members, err := r.ExpandListAddress(rec.Address)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			add(member)
+		}
+	}
+
+	var invalid []string
+	for _, addr := range addrs {
+		if isQueueAddress(addr) || isChannelAddress(addr) || isAnnounceAddress(addr) {
+			continue
+		}
+		if err := r.validateRecipient(AddressToIdentity(addr)); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", addr, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid recipients: %s", strings.Join(invalid, "; "))
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no recipients found for %s", to)
+	}
+	return addrs, nil
+}
+
+// ReleaseScheduled sends the town's scheduled mail that is due at now. Bead
+// triggers are checked against the town's beads, which route to rig beads
+// by prefix.
+func (r *Router) ReleaseScheduled(now time.Time) ([]*ScheduledMessage, error) {
+	if r.townRoot == "" {
+		return nil, fmt.Errorf("releasing scheduled mail: no town root")
+	}
+	bd := beads.New(r.townRoot)
+	isClosed := func(id string) (bool, error) {
+		issue, err := bd.Show(id)
+		if err != nil {
+			return false, err
+		}
+		return issue.Status == "closed", nil
+	}
+	return NewSchedule(r.townRoot).Release(now, isClosed, r.Send)
+}
+
+func generateScheduleID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("sched-%x", time.Now().UnixNano())
+	}
+	return "sched-" + hex.EncodeToString(b)
+}
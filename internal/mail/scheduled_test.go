@@ -0,0 +1,203 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedule_AddListCancel(t *testing.T) {
+	s := NewSchedule(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	later := &ScheduledMessage{Message: NewMessage("mayor/", "gastown/witness", "later", ""), DeliverAt: now.Add(2 * time.Hour)}
+	sooner := &ScheduledMessage{Message: NewMessage("mayor/", "gastown/witness", "sooner", ""), DeliverAt: now.Add(time.Hour)}
+	onClose := &ScheduledMessage{Message: NewMessage("mayor/", "gastown/witness", "on close", ""), OnClose: "gt-abc"}
+	for _, sm := range []*ScheduledMessage{onClose, later, sooner} {
+		if err := s.Add(sm); err != nil {
+			t.Fatalf("Add(%s): %v", sm.Message.Subject, err)
+		}
+	}
+
+	if err := s.Add(&ScheduledMessage{Message: NewMessage("a", "b", "c", "")}); err == nil {
+		t.Error("expected Add without a trigger to fail")
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, sm := range list {
+		subjects = append(subjects, sm.Message.Subject)
+	}
+	if len(subjects) != 3 || subjects[0] != "sooner" || subjects[1] != "later" || subjects[2] != "on close" {
+		t.Errorf("List order = %v, want [sooner later on close]", subjects)
+	}
+
+	if _, err := s.Cancel(later.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if _, err := s.Cancel(later.ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("second Cancel error = %v, want ErrMessageNotFound", err)
+	}
+	if list, _ = s.List(); len(list) != 2 {
+		t.Errorf("expected 2 scheduled messages after cancel, got %d", len(list))
+	}
+}
+
+func TestSchedule_Release(t *testing.T) {
+	s := NewSchedule(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	add := func(subject string, at time.Time, bead string) {
+		t.Helper()
+		msg := NewMessage("mayor/", "gastown/witness", subject, "")
+		if err := s.Add(&ScheduledMessage{Message: msg, DeliverAt: at, OnClose: bead, SuppressNotify: subject == "due"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("due", now.Add(-time.Minute), "")
+	add("not yet", now.Add(time.Hour), "")
+	add("bead closed", time.Time{}, "gt-closed")
+	add("bead open", time.Time{}, "gt-open")
+	add("send fails", now.Add(-time.Hour), "")
+
+	closed := func(id string) (bool, error) { return id == "gt-closed", nil }
+	var sent []*Message
+	send := func(msg *Message) error {
+		if msg.Subject == "send fails" {
+			return errors.New("no agent found")
+		}
+		sent = append(sent, msg)
+		return nil
+	}
+
+	released, err := s.Release(now, closed, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(released) != 2 || len(sent) != 2 {
+		t.Fatalf("released %d, sent %d; want 2 each", len(released), len(sent))
+	}
+	if sent[0].Subject != "due" || !sent[0].SuppressNotify || !sent[0].Timestamp.Equal(now) {
+		t.Errorf("first sent = %+v, want 'due' stamped now with notify suppressed", sent[0])
+	}
+	if sent[1].Subject != "bead closed" {
+		t.Errorf("second sent = %q, want 'bead closed'", sent[1].Subject)
+	}
+
+	list, _ := s.List()
+	if len(list) != 3 {
+		t.Fatalf("expected 3 still scheduled, got %d", len(list))
+	}
+	for _, sm := range list {
+		if sm.Message.Subject == "send fails" && (sm.Recipients[0].Attempts != 1 || sm.Recipients[0].LastError == "") {
+			t.Errorf("failed release not recorded: %+v", sm.Recipients[0])
+		}
+	}
+
+	// Released messages are not sent again.
+	sent = nil
+	if released, _ = s.Release(now, closed, send); len(released) != 0 {
+		t.Errorf("second release sent %d messages, want 0", len(released))
+	}
+}
+
+func TestSchedule_ReleaseRetriesOnlyFailedRecipients(t *testing.T) {
+	s := NewSchedule(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sm := &ScheduledMessage{
+		Message:   NewMessage("mayor/", "@witnesses", "heads up", ""),
+		DeliverAt: now.Add(-time.Minute),
+		Recipients: []*ScheduledDelivery{
+			{Address: "gastown/witness"},
+			{Address: "beads/witness"},
+		},
+	}
+	if err := s.Add(sm); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := map[string]int{}
+	failing := "beads/witness"
+	send := func(msg *Message) error {
+		// The schedule's lock is not held while sending
+		done := make(chan error, 1)
+		go func() { _, err := s.List(); done <- err }()
+		select {
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("send called with the schedule locked")
+		}
+		if msg.ID != "" {
+			t.Errorf("fan-out copy to %s kept the message ID", msg.To)
+		}
+		if msg.To == failing {
+			return errors.New("no agent found")
+		}
+		sent[msg.To]++
+		return nil
+	}
+	notClosed := func(string) (bool, error) { return false, nil }
+
+	if released, err := s.Release(now, notClosed, send); err != nil || len(released) != 0 {
+		t.Fatalf("first release: released %d, err %v; want partial delivery", len(released), err)
+	}
+	list, _ := s.List()
+	if len(list) != 1 || len(list[0].Errors()) != 1 || list[0].Failed() {
+		t.Fatalf("expected message kept with one retrying recipient, got %+v", list)
+	}
+
+	// Only the failed recipient is retried, until it runs out of attempts.
+	for i := 1; i < MaxScheduledAttempts; i++ {
+		if _, err := s.Release(now, notClosed, send); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sent["gastown/witness"] != 1 {
+		t.Errorf("delivered recipient sent %d times, want 1", sent["gastown/witness"])
+	}
+	list, _ = s.List()
+	if len(list) != 1 || !list[0].Failed() || list[0].Recipients[1].Attempts != MaxScheduledAttempts {
+		t.Fatalf("expected message failed after %d attempts, got %+v", MaxScheduledAttempts, list[0].Recipients[1])
+	}
+	failing = ""
+	if released, _ := s.Release(now, notClosed, send); len(released) != 0 || sent["beads/witness"] != 0 {
+		t.Error("failed delivery was retried")
+	}
+}
+
+func TestRouter_ExpandRecipients(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // No bd, so no agents exist
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	messaging := `{"type": "messaging", "version": 1, "lists": {"ops": ["overseer", "queue:work", "overseer"]}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "config", "messaging.json"), []byte(messaging), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	got, err := r.ExpandRecipients("list:ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, " ") != "overseer queue:work" {
+		t.Errorf("list:ops expanded to %v, want [overseer queue:work]", got)
+	}
+
+	if _, err := r.ExpandRecipients("gastown/nobody"); err == nil || !strings.Contains(err.Error(), "gastown/nobody") {
+		t.Errorf("expected unknown agent to be rejected, got %v", err)
+	}
+	if _, err := r.ExpandRecipients("list:missing"); err == nil {
+		t.Error("expected unknown list to be rejected")
+	}
+}
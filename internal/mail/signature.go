@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// signingKeyFile is the town's mail signing key under <town>/.runtime/.
+const signingKeyFile = "mail-signing.key"
+
+// signingKeySize is the length of a signing key in bytes.
+const signingKeySize = 32
+
+var (
+	// ErrUnsigned is returned when verifying a message without a signature.
+	ErrUnsigned = errors.New("message is not signed")
+
+	// ErrBadSignature is returned when a message's signature doesn't match
+	// its sender and content.
+	ErrBadSignature = errors.New("message signature does not match")
+
+	// ErrWrongSender is returned when a message is validly signed, but as a
+	// role other than the one expected.
+	ErrWrongSender = errors.New("message signed by the wrong role")
+)
+
+// SigningKey returns the mail signing key for the town at townRoot,
+// creating it on first use. The router signs the mail it sends with it, so
+// protocol handlers can tell mail the router sent on a role's behalf from a
+// bead forged to look like it.
+//
+// The key is a file readable by every agent in the town, and Sign trusts
+// the From address it is given, which gt derives from GT_ROLE and the
+// working directory. Signatures therefore guard against misrouted mail and
+// hand-made beads, not against an agent deliberately posing as another
+// role.
+func SigningKey(townRoot string) ([]byte, error) {
+	path := filepath.Join(townRoot, ".runtime", signingKeyFile)
+	if key, err := readSigningKey(path); err == nil || !errors.Is(err, os.ErrNotExist) {
+		return key, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	key := make([]byte, signingKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating mail signing key: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		// Another process created it first; use theirs.
+		return readSigningKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating mail signing key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("writing mail signing key: %w", err)
+	}
+	return key, nil
+}
+
+func readSigningKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the town's key file
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) < signingKeySize {
+		return nil, fmt.Errorf("mail signing key %s is malformed", path)
+	}
+	return key, nil
+}
+
+// SenderRole returns the role of the agent at address (witness, refinery,
+// polecat, ...), or "" if the address isn't an agent's.
+func SenderRole(address string) session.Role {
+	if AddressToIdentity(address) == "overseer" {
+		return session.RoleOverseer
+	}
+	id, err := session.ParseAddress(address)
+	if err != nil {
+		return ""
+	}
+	return id.Role
+}
+
+// Sign signs msg as sent by its From address, setting msg.Signature to
+// "<role>:<mac>". The MAC covers the role, sender, recipient, subject, and
+// body. The role comes from msg.From as given; see SigningKey for what that
+// does and doesn't protect against.
+func Sign(key []byte, msg *Message) {
+	role := SenderRole(msg.From)
+	msg.Signature = string(role) + ":" + hex.EncodeToString(signatureMAC(key, role, msg))
+}
+
+// VerifySignature checks msg's signature against key and returns the role
+// it was signed as.
+func VerifySignature(key []byte, msg *Message) (session.Role, error) {
+	if msg.Signature == "" {
+		return "", ErrUnsigned
+	}
+	roleStr, macHex, ok := strings.Cut(msg.Signature, ":")
+	if !ok {
+		return "", ErrBadSignature
+	}
+	mac, err := hex.DecodeString(macHex)
+	if err != nil {
+		return "", ErrBadSignature
+	}
+	role := session.Role(roleStr)
+	if !hmac.Equal(mac, signatureMAC(key, role, msg)) {
+		return "", ErrBadSignature
+	}
+	return role, nil
+}
+
+// VerifySender checks msg's signature against key and that it was signed as
+// role.
+func VerifySender(key []byte, msg *Message, role session.Role) error {
+	got, err := VerifySignature(key, msg)
+	if err != nil {
+		return err
+	}
+	if got != role {
+		return fmt.Errorf("%w: must come from the %s, but was signed by %q", ErrWrongSender, role, got)
+	}
+	return nil
+}
+
+// signatureMAC computes the HMAC of msg's signed fields. Each field is
+// length-prefixed so that moving text between fields changes the MAC.
+// Subject and body are trimmed, as bd may not keep surrounding whitespace.
+func signatureMAC(key []byte, role session.Role, msg *Message) []byte {
+	h := hmac.New(sha256.New, key)
+	for _, field := range []string{
+		string(role),
+		AddressToIdentity(msg.From),
+		AddressToIdentity(msg.To),
+		strings.TrimSpace(msg.Subject),
+		strings.TrimSpace(msg.Body),
+	} {
+		h.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return h.Sum(nil)
+}
+
+// signatureLabels returns the label carrying direct mail's signature by
+// the town's key. Best-effort: unsigned mail is still delivered, but protocol
+// handlers will reject it.
+func (r *Router) signatureLabels(msg *Message) []string {
+	if r.townRoot == "" {
+		return nil
+	}
+	key, err := SigningKey(r.townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mail signing: %v\n", err)
+		return nil
+	}
+	signed := *msg
+	Sign(key, &signed)
+	return []string{"sig:" + signed.Signature}
+}
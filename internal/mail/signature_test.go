@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestSigningKey_CreatedOnce(t *testing.T) {
+	townRoot := t.TempDir()
+	key, err := SigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != signingKeySize {
+		t.Errorf("key length = %d, want %d", len(key), signingKeySize)
+	}
+	info, err := os.Stat(filepath.Join(townRoot, ".runtime", signingKeyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("key file mode = %o, want 600", perm)
+	}
+
+	again, err := SigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, again) {
+		t.Error("second SigningKey returned a different key")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, signingKeySize)
+	msg := NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "Branch: polecat/nux\n")
+	Sign(key, msg)
+
+	role, err := VerifySignature(key, msg)
+	if err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+	if role != session.RoleRefinery {
+		t.Errorf("role = %q, want refinery", role)
+	}
+
+	// Read back from beads: addresses normalized, trailing newline gone.
+	bm := &BeadsMessage{
+		Title:       msg.Subject,
+		Description: "Branch: polecat/nux",
+		Assignee:    AddressToIdentity(msg.To),
+		Labels:      []string{"from:" + msg.From, "sig:" + msg.Signature},
+	}
+	if _, err := VerifySignature(key, bm.ToMessage()); err != nil {
+		t.Errorf("round-tripped message: %v", err)
+	}
+
+	tests := map[string]func(m *Message){
+		"subject": func(m *Message) { m.Subject = "MERGED furiosa" },
+		"body":    func(m *Message) { m.Body = "Branch: polecat/furiosa" },
+		"sender":  func(m *Message) { m.From = "gastown/witness" },
+		"role":    func(m *Message) { m.Signature = "witness" + m.Signature[len("refinery"):] },
+	}
+	for name, tamper := range tests {
+		forged := *msg
+		tamper(&forged)
+		if _, err := VerifySignature(key, &forged); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("tampered %s: err = %v, want ErrBadSignature", name, err)
+		}
+	}
+
+	unsigned := NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "")
+	if _, err := VerifySignature(key, unsigned); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unsigned: err = %v, want ErrUnsigned", err)
+	}
+}
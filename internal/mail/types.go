@@ -130,10 +130,23 @@ type Message struct {
 	// DeliveryAckedAt is when receipt was acknowledged.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
 
+	// Labels are free-form labels on the message, such as those added by
+	// the recipient's mail rules. Stored as label:X bead labels.
+	Labels []string `json:"labels,omitempty"`
+
+	// Signature is "<role>:<mac>", the router's signature of the sender's
+	// role, addresses, subject, and body with the town's key (see Sign).
+	// Stored as a sig:X bead label.
+	Signature string `json:"signature,omitempty"`
+
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
 	SuppressNotify bool `json:"-"`
+
+	// rulesApplied marks copies forwarded by mail rules, which aren't
+	// filtered again, so rules can't forward in a loop.
+	rulesApplied bool
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -299,7 +312,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, label:X, sig:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -313,6 +326,8 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	labels    []string   // Free-form labels (label:X)
+	signature string     // Router signature (sig:X)
 	// Two-phase delivery metadata
 	deliveryState   string
 	deliveryAckedBy string
@@ -331,6 +346,8 @@ func (bm *BeadsMessage) ParseLabels() {
 	bm.channel = ""
 	bm.claimedBy = ""
 	bm.claimedAt = nil
+	bm.labels = nil
+	bm.signature = ""
 	bm.deliveryState = ""
 	bm.deliveryAckedBy = ""
 	bm.deliveryAckedAt = nil
@@ -357,6 +374,10 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "label:") {
+			bm.labels = append(bm.labels, strings.TrimPrefix(label, "label:"))
+		} else if strings.HasPrefix(label, "sig:") {
+			bm.signature = strings.TrimPrefix(label, "sig:")
 		}
 	}
 
@@ -427,6 +448,8 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:         bm.channel,
 		ClaimedBy:       bm.claimedBy,
 		ClaimedAt:       bm.claimedAt,
+		Labels:          bm.labels,
+		Signature:       bm.signature,
 		DeliveryState:   bm.deliveryState,
 		DeliveryAckedBy: bm.deliveryAckedBy,
 		DeliveryAckedAt: bm.deliveryAckedAt,
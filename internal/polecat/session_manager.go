@@ -21,6 +21,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tracing"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -73,6 +74,10 @@ type SessionStartOptions struct {
 	// DoltBranch is the polecat-specific Dolt branch for write isolation.
 	// If set, BD_BRANCH env var is injected into the polecat session.
 	DoltBranch string
+
+	// Traceparent is the W3C traceparent of the gt sling that spawned the
+	// polecat. If set, the start is traced as a child span of it.
+	Traceparent string
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -182,11 +187,19 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (retErr error) {
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
 
+	if parent, ok := tracing.ParseTraceparent(opts.Traceparent); ok {
+		span := tracing.New(filepath.Dir(m.rig.Path)).Start("polecat.start", parent)
+		span.SetAttr("gt.rig", m.rig.Name)
+		span.SetAttr("gt.polecat", polecat)
+		span.SetAttr("gt.bead", opts.Issue)
+		defer func() { span.End(retErr) }()
+	}
+
 	sessionID := m.SessionName(polecat)
 
 	// Check if session already exists.
@@ -3,8 +3,13 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/witness"
 )
 
 // ErrNoHandler is returned when a message is a recognized protocol message
@@ -13,21 +18,73 @@ import (
 // misrouted/unhandled" (true, ErrNoHandler).
 var ErrNoHandler = errors.New("no handler registered for protocol message type")
 
+// ErrRejected is returned when a protocol message isn't signed by the role
+// that sends its type: mail the router didn't send, or that claims to be
+// from another agent.
+var ErrRejected = errors.New("protocol message rejected")
+
+// ErrNoSigningKey is returned when a registry without a signing key is asked
+// to process a message. It is a configuration error, not a rejection of the
+// message: see SetSigningKey and NewRigRegistry.
+var ErrNoSigningKey = errors.New("handler registry has no signing key")
+
+// senderRoles maps each protocol message type to the role that sends it.
+var senderRoles = map[MessageType]session.Role{
+	TypeMergeReady:         session.RoleWitness,
+	TypeMerged:             session.RoleRefinery,
+	TypeMergeFailed:        session.RoleRefinery,
+	TypeReworkRequest:      session.RoleRefinery,
+	TypeConvoyNeedsFeeding: session.RoleRefinery,
+	TypePolecatDone:        session.RolePolecat,
+}
+
 // Handler processes a protocol message and returns an error if processing failed.
 type Handler func(msg *mail.Message) error
 
 // HandlerRegistry maps message types to their handlers.
 type HandlerRegistry struct {
 	handlers map[MessageType]Handler
+
+	// signingKey is the town's mail signing key that protocol messages
+	// are verified against.
+	signingKey []byte
+
+	// onReject is called for each message ProcessProtocolMessage rejects.
+	onReject func(msg *mail.Message, err error)
+
+	// deadLetters, if set, keeps messages that no handler took or whose
+	// handler failed, for retry.
+	deadLetters *mail.DeadLetters
 }
 
-// NewHandlerRegistry creates a new handler registry.
+// NewHandlerRegistry creates a new handler registry. Set its signing key
+// before processing messages; NewRigRegistry loads the town's.
 func NewHandlerRegistry() *HandlerRegistry {
 	return &HandlerRegistry{
 		handlers: make(map[MessageType]Handler),
+		onReject: reportRejected,
 	}
 }
 
+// SetSigningKey sets the town's mail signing key (see mail.SigningKey).
+// Until it is set, ProcessProtocolMessage and RetryDeadLetters fail with
+// ErrNoSigningKey.
+func (r *HandlerRegistry) SetSigningKey(key []byte) {
+	r.signingKey = key
+}
+
+// OnReject sets the function called when ProcessProtocolMessage rejects a
+// message. By default rejections are reported to the activity feed.
+func (r *HandlerRegistry) OnReject(fn func(msg *mail.Message, err error)) {
+	r.onReject = fn
+}
+
+// SetDeadLetters makes ProcessProtocolMessage record messages that no
+// handler takes, or whose handler fails, in dl. See RetryDeadLetters.
+func (r *HandlerRegistry) SetDeadLetters(dl *mail.DeadLetters) {
+	r.deadLetters = dl
+}
+
 // Register adds a handler for a specific message type.
 func (r *HandlerRegistry) Register(msgType MessageType, handler Handler) {
 	r.handlers[msgType] = handler
@@ -111,6 +168,37 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	return registry
 }
 
+// NewRigRegistry creates a registry with the default Witness and Refinery
+// handlers for a rig. POLECAT_DONE, MERGED, and MERGE_FAILED are handled by
+// the witness's live handlers (see witness.Redeliver), which dead-letter
+// their failures to the same queue. It verifies senders against the town's
+// signing key and dead-letters failures to the rig's queue. Handler status
+// messages go to out.
+func NewRigRegistry(townRoot, rigName, rigPath string, out io.Writer) (*HandlerRegistry, error) {
+	key, err := mail.SigningKey(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessHandler := NewWitnessHandler(rigName, rigPath)
+	witnessHandler.SetOutput(out)
+	refinery := NewRefineryHandler(rigName, rigPath)
+	refinery.SetOutput(out)
+
+	registry := WrapWitnessHandlers(witnessHandler)
+	for msgType, handler := range WrapRefineryHandlers(refinery).handlers {
+		registry.Register(msgType, handler)
+	}
+	for _, msgType := range []MessageType{TypePolecatDone, TypeMerged, TypeMergeFailed} {
+		registry.Register(msgType, func(msg *mail.Message) error {
+			return witness.Redeliver(rigPath, rigName, msg, witnessHandler.Router)
+		})
+	}
+	registry.SetSigningKey(key)
+	registry.SetDeadLetters(mail.OpenDeadLetters(rigPath))
+	return registry, nil
+}
+
 // WrapRefineryHandlers creates mail handlers from a RefineryHandler.
 func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
@@ -128,18 +216,76 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 
 // ProcessProtocolMessage processes a protocol message using the registry.
 // It returns (true, nil) if the message was handled successfully,
-// (true, error) if handling failed, (true, ErrNoHandler) if the message is
-// a recognized protocol message but no handler is registered, or
-// (false, nil) if not a protocol message.
+// (true, error) if handling failed, (true, ErrRejected) if the message
+// isn't signed by the role that sends its type, (true, ErrNoHandler) if the
+// message is a recognized protocol message but no handler is registered,
+// (true, ErrNoSigningKey) if the registry has no key to verify it with,
+// or (false, nil) if not a protocol message.
+//
+// With a dead-letter queue set, unhandled and failed messages are also
+// recorded there for retry; rejected ones are not.
 func (r *HandlerRegistry) ProcessProtocolMessage(msg *mail.Message) (bool, error) {
 	if !IsProtocolMessage(msg.Subject) {
 		return false, nil
 	}
+	if len(r.signingKey) == 0 {
+		return true, ErrNoSigningKey
+	}
 
-	if !r.CanHandle(msg) {
-		return true, ErrNoHandler
+	if err := r.verifySender(msg); err != nil {
+		if r.onReject != nil {
+			r.onReject(msg, err)
+		}
+		return true, err
 	}
 
-	err := r.Handle(msg)
+	err := r.dispatch(msg)
+	if err != nil && r.deadLetters != nil {
+		if _, dlErr := r.deadLetters.Add(msg, err, time.Now()); dlErr != nil {
+			return true, fmt.Errorf("%w (recording dead letter: %v)", err, dlErr)
+		}
+	}
 	return true, err
 }
+
+// RetryDeadLetters retries the registry's dead letters: those with the
+// given IDs, or with none, those whose backoff has passed at now. Returns
+// the letters handled this time and those that failed again.
+func (r *HandlerRegistry) RetryDeadLetters(now time.Time, ids ...string) (resolved, failed []*mail.DeadLetter, err error) {
+	if r.deadLetters == nil {
+		return nil, nil, fmt.Errorf("no dead-letter queue")
+	}
+	if len(r.signingKey) == 0 {
+		return nil, nil, ErrNoSigningKey
+	}
+	return r.deadLetters.Retry(now, ids, func(msg *mail.Message) error {
+		if err := r.verifySender(msg); err != nil {
+			return err
+		}
+		return r.dispatch(msg)
+	})
+}
+
+// dispatch hands a verified protocol message to its handler.
+func (r *HandlerRegistry) dispatch(msg *mail.Message) error {
+	if !r.CanHandle(msg) {
+		return ErrNoHandler
+	}
+	return r.Handle(msg)
+}
+
+// verifySender checks that msg carries the router's signature and that it
+// was signed as the role that sends its type.
+func (r *HandlerRegistry) verifySender(msg *mail.Message) error {
+	msgType := ParseMessageType(msg.Subject)
+	if err := mail.VerifySender(r.signingKey, msg, senderRoles[msgType]); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrRejected, msgType, err)
+	}
+	return nil
+}
+
+// reportRejected reports a rejected protocol message to the activity feed.
+func reportRejected(msg *mail.Message, err error) {
+	_ = events.LogFeed(events.TypeMailRejected, msg.From,
+		events.MailRejectedPayload(msg.ID, msg.To, msg.Subject, err.Error()))
+}
@@ -44,13 +44,14 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 	if p.Verified != "" {
 		sb.WriteString(fmt.Sprintf("Verified: %s\n", p.Verified))
 	}
+	writeTrace(&sb, p.TraceID)
 	return sb.String()
 }
 
 // NewMergedMessage creates a MERGED protocol message.
 // Sent by Refinery to Witness when a branch is successfully merged.
 func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) *mail.Message {
-	payload := MergedPayload{
+	return newMergedMessage(MergedPayload{
 		Branch:       branch,
 		Issue:        issue,
 		Polecat:      polecat,
@@ -58,14 +59,17 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		MergedAt:     time.Now(),
 		MergeCommit:  mergeCommit,
 		TargetBranch: targetBranch,
-	}
+	})
+}
 
+// newMergedMessage creates a MERGED protocol message from a full payload.
+func newMergedMessage(payload MergedPayload) *mail.Message {
 	body := formatMergedBody(payload)
 
 	msg := mail.NewMessage(
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGED %s", polecat),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("MERGED %s", payload.Polecat),
 		body,
 	)
 	msg.Priority = mail.PriorityHigh
@@ -86,13 +90,14 @@ func formatMergedBody(p MergedPayload) string {
 	if p.MergeCommit != "" {
 		sb.WriteString(fmt.Sprintf("Merge-Commit: %s\n", p.MergeCommit))
 	}
+	writeTrace(&sb, p.TraceID)
 	return sb.String()
 }
 
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
 func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
-	payload := MergeFailedPayload{
+	return NewMergeFailedReportMessage(MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
 		Polecat:      polecat,
@@ -101,14 +106,23 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+	})
+}
+
+// NewMergeFailedReportMessage creates a MERGE_FAILED protocol message from a
+// full payload, including the structured gate failure report fields.
+// FailedAt defaults to now.
+func NewMergeFailedReportMessage(payload MergeFailedPayload) *mail.Message {
+	if payload.FailedAt.IsZero() {
+		payload.FailedAt = time.Now()
 	}
 
 	body := formatMergeFailedBody(payload)
 
 	msg := mail.NewMessage(
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGE_FAILED %s", polecat),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("MERGE_FAILED %s", payload.Polecat),
 		body,
 	)
 	msg.Priority = mail.PriorityHigh
@@ -128,13 +142,47 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	// One line per test: names may contain commas (JUnit test names are
+	// free-form), so a joined list can't be split back apart.
+	for _, t := range p.FailingTests {
+		sb.WriteString(fmt.Sprintf("Failing-Test: %s\n", t))
+	}
+	if p.Artifact != "" {
+		sb.WriteString(fmt.Sprintf("Artifact: %s\n", p.Artifact))
+	}
+	if p.MergeCommit != "" {
+		sb.WriteString(fmt.Sprintf("Merge-Commit: %s\n", p.MergeCommit))
+	}
+	if p.RevertCommit != "" {
+		sb.WriteString(fmt.Sprintf("Revert-Commit: %s\n", p.RevertCommit))
+	}
+	writeTrace(&sb, p.TraceID)
+	// The excerpt is free-form multi-line output, so it goes last, after a
+	// marker line; parsers stop reading header fields there.
+	if p.Excerpt != "" {
+		sb.WriteString("\n" + excerptMarker + "\n")
+		sb.WriteString(p.Excerpt)
+		sb.WriteString("\n")
+	}
 	return sb.String()
 }
 
+// excerptMarker introduces the gate output excerpt in a MERGE_FAILED body.
+const excerptMarker = "Excerpt:"
+
+// splitExcerpt splits a MERGE_FAILED body into its header fields and the
+// trailing gate output excerpt, if any.
+func splitExcerpt(body string) (header, excerpt string) {
+	if i := strings.Index(body, "\n"+excerptMarker+"\n"); i >= 0 {
+		return body[:i], strings.TrimRight(body[i+len(excerptMarker)+2:], "\n")
+	}
+	return body, ""
+}
+
 // NewReworkRequestMessage creates a REWORK_REQUEST protocol message.
 // Sent by Refinery to Witness when a branch needs rebasing due to conflicts.
 func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, conflictFiles []string) *mail.Message {
-	payload := ReworkRequestPayload{
+	return newReworkRequestMessage(ReworkRequestPayload{
 		Branch:        branch,
 		Issue:         issue,
 		Polecat:       polecat,
@@ -143,14 +191,18 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 		TargetBranch:  targetBranch,
 		ConflictFiles: conflictFiles,
 		Instructions:  formatRebaseInstructions(targetBranch),
-	}
+	})
+}
 
+// newReworkRequestMessage creates a REWORK_REQUEST protocol message from a
+// full payload.
+func newReworkRequestMessage(payload ReworkRequestPayload) *mail.Message {
 	body := formatReworkRequestBody(payload)
 
 	msg := mail.NewMessage(
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("REWORK_REQUEST %s", polecat),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("REWORK_REQUEST %s", payload.Polecat),
 		body,
 	)
 	msg.Priority = mail.PriorityHigh
@@ -172,6 +224,7 @@ func formatReworkRequestBody(p ReworkRequestPayload) string {
 	if len(p.ConflictFiles) > 0 {
 		sb.WriteString(fmt.Sprintf("Conflict-Files: %s\n", strings.Join(p.ConflictFiles, ", ")))
 	}
+	writeTrace(&sb, p.TraceID)
 
 	sb.WriteString("\n")
 	sb.WriteString(p.Instructions)
@@ -265,6 +318,7 @@ func ParseMergeReadyPayload(body string) (*MergeReadyPayload, error) {
 		Rig:       parseField(body, "Rig"),
 		Verified:  parseField(body, "Verified"),
 		Timestamp: time.Now(), // Use current time if not parseable
+		TraceID:   ParseTraceID(body),
 	}
 
 	var errs []string
@@ -294,6 +348,7 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
 		MergeCommit:  parseField(body, "Merge-Commit"),
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
@@ -323,6 +378,7 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 // ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
+	body, excerpt := splitExcerpt(body)
 	payload := &MergeFailedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -331,6 +387,12 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		Artifact:     parseField(body, "Artifact"),
+		MergeCommit:  parseField(body, "Merge-Commit"),
+		RevertCommit: parseField(body, "Revert-Commit"),
+		FailingTests: parseFields(body, "Failing-Test"),
+		Excerpt:      excerpt,
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
@@ -366,6 +428,7 @@ func ParseReworkRequestPayload(body string) (*ReworkRequestPayload, error) {
 		Polecat:      parseField(body, "Polecat"),
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
@@ -410,6 +473,7 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 		ConvoyID:      parseField(body, "ConvoyID"),
 		MergeStrategy: parseField(body, "MergeStrategy"),
 		Errors:        parseField(body, "Errors"),
+		TraceID:       ParseTraceID(body),
 	}
 
 	if parseField(body, "ConvoyOwned") == "true" {
@@ -419,6 +483,22 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 	return payload
 }
 
+// traceField is the body field carrying a message's trace ID.
+const traceField = "Trace"
+
+// writeTrace writes the trace ID field, if set.
+func writeTrace(sb *strings.Builder, traceID string) {
+	if traceID != "" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", traceField, traceID))
+	}
+}
+
+// ParseTraceID returns the trace ID recorded in a protocol message body,
+// or "" if it has none.
+func ParseTraceID(body string) string {
+	return parseField(body, traceField)
+}
+
 // parseField extracts a field value from a key-value body format.
 // Format: "Key: value"
 func parseField(body, key string) string {
@@ -434,3 +514,16 @@ func parseField(body, key string) string {
 
 	return ""
 }
+
+// parseFields extracts every value of a field that may repeat, one per line.
+func parseFields(body, key string) []string {
+	var values []string
+	prefix := key + ": "
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			values = append(values, strings.TrimPrefix(line, prefix))
+		}
+	}
+	return values
+}
@@ -371,6 +371,104 @@ Error: Test failed`
 	}
 }
 
+func TestMergeFailedReport_RoundTrip(t *testing.T) {
+	msg := NewMergeFailedReportMessage(MergeFailedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		TargetBranch: "main",
+		FailureType:  "tests",
+		Error:        "quality gates failed",
+		FailingTests: []string{"TestServer/timeout (example.com/app/api)", "auth.LoginTest.test_login[user, admin]"},
+		Excerpt:      "[test]\nserver_test.go:42: expected 504\nError: not a header field",
+		Artifact:     "/rigs/gastown/.runtime/gate-logs/gt-mr1.log",
+	})
+
+	if msg.Subject != "MERGE_FAILED nux" {
+		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGE_FAILED nux")
+	}
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payload.FailingTests) != 2 || payload.FailingTests[1] != "auth.LoginTest.test_login[user, admin]" {
+		t.Errorf("FailingTests = %v", payload.FailingTests)
+	}
+	if payload.Artifact != "/rigs/gastown/.runtime/gate-logs/gt-mr1.log" {
+		t.Errorf("Artifact = %q", payload.Artifact)
+	}
+	if payload.Excerpt != "[test]\nserver_test.go:42: expected 504\nError: not a header field" {
+		t.Errorf("Excerpt = %q", payload.Excerpt)
+	}
+	// Header fields must not be picked up from the excerpt
+	if payload.Error != "quality gates failed" {
+		t.Errorf("Error = %q, want %q", payload.Error, "quality gates failed")
+	}
+	if payload.FailedAt.IsZero() {
+		t.Error("FailedAt should default to now")
+	}
+}
+
+func TestMergeFailedReport_PostMergeRoundTrip(t *testing.T) {
+	msg := NewMergeFailedReportMessage(MergeFailedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		TargetBranch: "main",
+		FailureType:  FailureTypePostMerge,
+		Error:        "post-merge gates failed: smoke: exit status 1",
+		MergeCommit:  "abc123",
+		RevertCommit: "def456",
+	})
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.FailureType != FailureTypePostMerge {
+		t.Errorf("FailureType = %q, want %q", payload.FailureType, FailureTypePostMerge)
+	}
+	if payload.MergeCommit != "abc123" || payload.RevertCommit != "def456" {
+		t.Errorf("MergeCommit/RevertCommit = %q/%q", payload.MergeCommit, payload.RevertCommit)
+	}
+}
+
+func TestTraceID_RoundTrip(t *testing.T) {
+	const trace = "tr-1a2b3c4d"
+
+	merged, err := ParseMergedPayload(newMergedMessage(MergedPayload{
+		Branch: "polecat/nux/gt-abc", Polecat: "nux", Rig: "gastown", TraceID: trace,
+	}).Body)
+	if err != nil || merged.TraceID != trace {
+		t.Errorf("MERGED TraceID = %v (err %v), want %q", merged, err, trace)
+	}
+
+	// The trace is a header field, not read from the excerpt or instructions.
+	failed, err := ParseMergeFailedPayload(NewMergeFailedReportMessage(MergeFailedPayload{
+		Branch: "polecat/nux/gt-abc", Polecat: "nux", Rig: "gastown",
+		Excerpt: "Trace: tr-00000000", TraceID: trace,
+	}).Body)
+	if err != nil || failed.TraceID != trace {
+		t.Errorf("MERGE_FAILED TraceID = %v (err %v), want %q", failed, err, trace)
+	}
+
+	rework := newReworkRequestMessage(ReworkRequestPayload{
+		Branch: "polecat/nux/gt-abc", Polecat: "nux", Rig: "gastown",
+		Instructions: formatRebaseInstructions("main"), TraceID: trace,
+	})
+	if got := ParseTraceID(rework.Body); got != trace {
+		t.Errorf("REWORK_REQUEST trace = %q, want %q", got, trace)
+	}
+
+	untraced := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	if strings.Contains(untraced.Body, "Trace:") || ParseTraceID(untraced.Body) != "" {
+		t.Errorf("untraced body has a trace:\n%s", untraced.Body)
+	}
+}
+
 func TestParseMergeFailedPayload_InvalidInput(t *testing.T) {
 	payload, err := ParseMergeFailedPayload("")
 	if err == nil {
@@ -446,7 +544,9 @@ func TestHandlerRegistry(t *testing.T) {
 }
 
 func TestProcessProtocolMessage(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
 	registry := NewHandlerRegistry()
+	registry.SetSigningKey(key)
 
 	handled := false
 	registry.Register(TypeMergeReady, func(msg *mail.Message) error {
@@ -462,7 +562,8 @@ func TestProcessProtocolMessage(t *testing.T) {
 	}
 
 	// Test 2: Recognized protocol message with handler returns (true, nil)
-	readyMsg := &mail.Message{Subject: "MERGE_READY nux"}
+	readyMsg := &mail.Message{From: "gastown/witness", To: "gastown/refinery", Subject: "MERGE_READY nux"}
+	mail.Sign(key, readyMsg)
 	isProto, err = registry.ProcessProtocolMessage(readyMsg)
 	if !isProto || err != nil {
 		t.Errorf("Handled protocol message: got (%v, %v), want (true, nil)", isProto, err)
@@ -473,7 +574,8 @@ func TestProcessProtocolMessage(t *testing.T) {
 
 	// Test 3: Recognized protocol message WITHOUT handler returns (true, ErrNoHandler)
 	// MERGED is a valid protocol type but no handler is registered for it
-	misrouted := &mail.Message{Subject: "MERGED nux"}
+	misrouted := &mail.Message{From: "gastown/refinery", To: "gastown/refinery", Subject: "MERGED nux"}
+	mail.Sign(key, misrouted)
 	isProto, err = registry.ProcessProtocolMessage(misrouted)
 	if !isProto {
 		t.Error("Recognized protocol message should return isProtocol=true even without handler")
@@ -483,6 +585,131 @@ func TestProcessProtocolMessage(t *testing.T) {
 	}
 }
 
+func TestProcessProtocolMessage_RejectsForgedSender(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	handler := &mockWitnessHandler{}
+	registry := WrapWitnessHandlers(handler)
+	registry.SetSigningKey(key)
+	var rejected []string
+	registry.OnReject(func(msg *mail.Message, err error) {
+		rejected = append(rejected, msg.Subject)
+	})
+
+	body := "Branch: polecat/nux\nIssue: gt-abc\nPolecat: nux\nRig: gastown\nTarget: main"
+	merged := func(from string) *mail.Message {
+		return &mail.Message{From: from, To: "gastown/witness", Subject: "MERGED nux", Body: body}
+	}
+
+	unsigned := merged("gastown/refinery")
+
+	// A polecat's signed mail can't pass as the refinery's.
+	wrongRole := merged("gastown/nux")
+	mail.Sign(key, wrongRole)
+
+	// Nor can the refinery's signature be reused on other content.
+	tampered := merged("gastown/refinery")
+	mail.Sign(key, tampered)
+	tampered.Subject = "MERGED furiosa"
+
+	otherKey := merged("gastown/refinery")
+	mail.Sign([]byte("fedcba9876543210fedcba9876543210"), otherKey)
+
+	for name, msg := range map[string]*mail.Message{
+		"unsigned":   unsigned,
+		"wrong role": wrongRole,
+		"tampered":   tampered,
+		"other key":  otherKey,
+	} {
+		isProto, err := registry.ProcessProtocolMessage(msg)
+		if !isProto || !errors.Is(err, ErrRejected) {
+			t.Errorf("%s: got (%v, %v), want (true, ErrRejected)", name, isProto, err)
+		}
+	}
+	if handler.mergedCalled {
+		t.Error("HandleMerged was called for a rejected message")
+	}
+	if len(rejected) != 4 {
+		t.Errorf("reported %d rejections, want 4", len(rejected))
+	}
+
+	signed := merged("gastown/refinery")
+	mail.Sign(key, signed)
+	if _, err := registry.ProcessProtocolMessage(signed); err != nil {
+		t.Fatalf("signed MERGED from the refinery: %v", err)
+	}
+	if !handler.mergedCalled {
+		t.Error("HandleMerged was not called for a signed message")
+	}
+
+	// Without a key nothing is trusted, and the caller is told why.
+	noKey := WrapWitnessHandlers(handler)
+	noKey.OnReject(func(msg *mail.Message, err error) {
+		t.Errorf("registry without a key reported %s as rejected", msg.Subject)
+	})
+	if _, err := noKey.ProcessProtocolMessage(signed); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("registry without a key: got %v, want ErrNoSigningKey", err)
+	}
+}
+
+func TestProcessProtocolMessage_DeadLetters(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	registry := NewHandlerRegistry()
+	registry.SetSigningKey(key)
+	registry.OnReject(nil)
+	dl := mail.OpenDeadLetters(t.TempDir())
+	registry.SetDeadLetters(dl)
+
+	doltUp := false
+	registry.Register(TypeMerged, func(msg *mail.Message) error {
+		if !doltUp {
+			return errors.New("dolt: connection refused")
+		}
+		return nil
+	})
+
+	signed := func(from, subject string) *mail.Message {
+		msg := mail.NewMessage(from, "gastown/witness", subject, "")
+		mail.Sign(key, msg)
+		return msg
+	}
+	failing := signed("gastown/refinery", "MERGED nux")
+	unhandled := signed("gastown/refinery", "REWORK_REQUEST nux")
+	forged := signed("gastown/nux", "MERGED nux")
+
+	if _, err := registry.ProcessProtocolMessage(failing); err == nil {
+		t.Error("expected handler error")
+	}
+	if _, err := registry.ProcessProtocolMessage(unhandled); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("unhandled: err = %v, want ErrNoHandler", err)
+	}
+	if _, err := registry.ProcessProtocolMessage(forged); !errors.Is(err, ErrRejected) {
+		t.Errorf("forged: err = %v, want ErrRejected", err)
+	}
+
+	letters, err := dl.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("dead letters = %d, want 2 (rejected mail is not queued)", len(letters))
+	}
+	if letters[0].Error != "dolt: connection refused" || letters[0].Attempts != 1 {
+		t.Errorf("dead letter = %+v", letters[0])
+	}
+
+	doltUp = true
+	resolved, failed, err := registry.RetryDeadLetters(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 1 || resolved[0].Message.Subject != "MERGED nux" {
+		t.Errorf("resolved = %+v, want the MERGED message", resolved)
+	}
+	if len(failed) != 1 || !strings.Contains(failed[0].Error, "no handler") {
+		t.Errorf("failed = %+v, want the unhandled REWORK_REQUEST", failed)
+	}
+}
+
 func TestWrapWitnessHandlers(t *testing.T) {
 	handler := &mockWitnessHandler{}
 	registry := WrapWitnessHandlers(handler)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
 )
@@ -106,24 +107,71 @@ type MergeOutcome struct {
 	// Error is the error message if the merge failed.
 	Error string
 
-	// MergeCommit is the SHA of the merge commit on success.
+	// MergeCommit is the SHA of the merge commit on success, or of the
+	// reverted merge for post_merge failures.
 	MergeCommit string
 
+	// RevertCommit is the revert pushed after a post_merge failure.
+	RevertCommit string
+
 	// ConflictFiles lists files with conflicts (if Conflict is true).
 	ConflictFiles []string
+
+	// FailingTests, Excerpt, and Artifact carry the structured gate failure
+	// report into MERGE_FAILED (see MergeFailedPayload).
+	FailingTests []string
+	Excerpt      string
+	Artifact     string
+
+	// TraceID is the MR's trace ID, carried into the message body.
+	TraceID string
 }
 
 // NotifyMergeOutcome sends the appropriate protocol message based on the outcome.
 func (h *DefaultRefineryHandler) NotifyMergeOutcome(polecat, branch, issue, targetBranch string, outcome MergeOutcome) error {
 	if outcome.Success {
-		return h.SendMerged(polecat, branch, issue, targetBranch, outcome.MergeCommit)
+		return h.Router.Send(newMergedMessage(MergedPayload{
+			Branch:       branch,
+			Issue:        issue,
+			Polecat:      polecat,
+			Rig:          h.Rig,
+			MergedAt:     time.Now(),
+			MergeCommit:  outcome.MergeCommit,
+			TargetBranch: targetBranch,
+			TraceID:      outcome.TraceID,
+		}))
 	}
 
 	if outcome.Conflict {
-		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles)
+		return h.Router.Send(newReworkRequestMessage(ReworkRequestPayload{
+			Branch:        branch,
+			Issue:         issue,
+			Polecat:       polecat,
+			Rig:           h.Rig,
+			RequestedAt:   time.Now(),
+			TargetBranch:  targetBranch,
+			ConflictFiles: outcome.ConflictFiles,
+			Instructions:  formatRebaseInstructions(targetBranch),
+			TraceID:       outcome.TraceID,
+		}))
 	}
 
-	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error)
+	msg := NewMergeFailedReportMessage(MergeFailedPayload{
+		Branch:       branch,
+		Issue:        issue,
+		Polecat:      polecat,
+		Rig:          h.Rig,
+		FailureType:  outcome.FailureType,
+		Error:        outcome.Error,
+		TargetBranch: targetBranch,
+		FailingTests: outcome.FailingTests,
+		Excerpt:      outcome.Excerpt,
+		Artifact:     outcome.Artifact,
+		MergeCommit:  outcome.MergeCommit,
+		RevertCommit: outcome.RevertCommit,
+		TraceID:      outcome.TraceID,
+	})
+	return h.Router.Send(msg)
 }
 
 // Ensure DefaultRefineryHandler implements RefineryHandler.
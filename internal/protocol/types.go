@@ -8,6 +8,7 @@
 //   - MERGED: Refinery → Witness (merge succeeded, cleanup ok)
 //   - MERGE_FAILED: Refinery → Witness (merge failed, needs rework)
 //   - REWORK_REQUEST: Refinery → Witness (rebase needed)
+//   - POLECAT_DONE: Polecat → Witness (work done, via gt done)
 package protocol
 
 import (
@@ -44,8 +45,21 @@ const (
 	// feeding instead of waiting for the next deacon patrol cycle.
 	// Subject format: "CONVOY_NEEDS_FEEDING <convoy-id>"
 	TypeConvoyNeedsFeeding MessageType = "CONVOY_NEEDS_FEEDING"
+
+	// TypePolecatDone is sent from a polecat to its Witness by gt done when
+	// the polecat's work is finished.
+	// Subject format: "POLECAT_DONE <polecat-name>"
+	TypePolecatDone MessageType = "POLECAT_DONE"
 )
 
+// FailureTypePostMerge is the MERGE_FAILED failure type for a merge that
+// landed but failed post-merge gates and was reverted.
+const FailureTypePostMerge = "post_merge"
+
+// FailureTypeReview is the MERGE_FAILED failure type for an MR whose
+// review gate reviewer requested changes.
+const FailureTypeReview = "review"
+
 // ParseMessageType extracts the protocol message type from a mail subject.
 // Returns empty string if subject doesn't match a known protocol type.
 func ParseMessageType(subject string) MessageType {
@@ -58,6 +72,7 @@ func ParseMessageType(subject string) MessageType {
 		TypeMergeFailed,
 		TypeReworkRequest,
 		TypeConvoyNeedsFeeding,
+		TypePolecatDone,
 	}
 
 	for _, prefix := range prefixes {
@@ -90,6 +105,9 @@ type MergeReadyPayload struct {
 
 	// Timestamp is when the message was created.
 	Timestamp time.Time `json:"timestamp"`
+
+	// TraceID is the unit of work's trace ID, if it has one (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergedPayload contains the data for a MERGED message.
@@ -115,6 +133,9 @@ type MergedPayload struct {
 
 	// TargetBranch is the branch merged into (e.g., "main").
 	TargetBranch string `json:"target_branch"`
+
+	// TraceID is the unit of work's trace ID, if it has one (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergeFailedPayload contains the data for a MERGE_FAILED message.
@@ -143,6 +164,26 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// FailingTests lists the tests that failed, when the gate output was
+	// go test -json or JUnit XML. Lets the rework polecat start from them
+	// instead of re-running the whole suite.
+	FailingTests []string `json:"failing_tests,omitempty"`
+
+	// Excerpt is a short slice of the gate output around the failures.
+	Excerpt string `json:"excerpt,omitempty"`
+
+	// Artifact is the path to the full gate output log recorded on the MR bead.
+	Artifact string `json:"artifact,omitempty"`
+
+	// MergeCommit and RevertCommit are set for post_merge failures: the
+	// commit that landed, and the revert pushed to undo it (empty if the
+	// revert could not be pushed).
+	MergeCommit  string `json:"merge_commit,omitempty"`
+	RevertCommit string `json:"revert_commit,omitempty"`
+
+	// TraceID is the unit of work's trace ID, if it has one (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -171,11 +212,14 @@ type ReworkRequestPayload struct {
 
 	// Instructions provides specific rebase instructions.
 	Instructions string `json:"instructions,omitempty"`
+
+	// TraceID is the unit of work's trace ID, if it has one (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // PolecatDonePayload contains the data from a POLECAT_DONE notification.
-// This is not a formal protocol message (it's a mail convention), but the
-// payload is structured for programmatic parsing by witness handlers.
+// The body is a mail convention rather than a formal protocol payload, but
+// it is structured for programmatic parsing by witness handlers.
 type PolecatDonePayload struct {
 	// Polecat is the worker name.
 	Polecat string `json:"polecat"`
@@ -203,6 +247,9 @@ type PolecatDonePayload struct {
 
 	// Errors contains any non-fatal errors encountered during gt done.
 	Errors string `json:"errors,omitempty"`
+
+	// TraceID is the unit of work's trace ID, if it has one (see gt trace).
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // SkipMergeFlow returns true if this polecat's work should bypass the
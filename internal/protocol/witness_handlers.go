@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -88,6 +89,12 @@ func (h *DefaultWitnessHandler) HandleMergeFailed(payload *MergeFailedPayload) e
 	fmt.Fprintf(h.Output, "  Issue: %s\n", payload.Issue)
 	fmt.Fprintf(h.Output, "  Failure type: %s\n", payload.FailureType)
 	fmt.Fprintf(h.Output, "  Error: %s\n", payload.Error)
+	if len(payload.FailingTests) > 0 {
+		fmt.Fprintf(h.Output, "  Failing tests: %s\n", strings.Join(payload.FailingTests, ", "))
+	}
+	if payload.RevertCommit != "" {
+		fmt.Fprintf(h.Output, "  Reverted: %s (merge %s)\n", payload.RevertCommit, payload.MergeCommit)
+	}
 
 	// Notify the polecat about the failure
 	if err := h.notifyPolecatFailed(payload); err != nil {
@@ -204,12 +211,13 @@ Branch: %s
 Issue: %s
 Failure: %s
 Error: %s
-
+%s
 Please fix the issue and resubmit your work with 'gt done'.`,
 			payload.Branch,
 			payload.Issue,
 			payload.FailureType,
 			payload.Error,
+			witness.FormatFailureReport(payload.FailingTests, payload.Excerpt, payload.Artifact, payload.RevertCommit),
 		),
 	)
 	msg.Priority = mail.PriorityHigh
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -18,12 +19,14 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tracing"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -55,6 +58,19 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// Include limits the gate to MRs that change at least one file matching
+	// one of these globs (e.g. "web/**"). Empty means every file counts.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude lists globs for files that never trigger the gate
+	// (e.g. "**/*.md"). Checked after Include.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Quarantine makes the gate ignore failures of tests quarantined in the
+	// rig's flake ledger. The gate still fails if any other test fails, or if
+	// its output names no tests at all.
+	Quarantine bool `json:"quarantine,omitempty"`
 }
 
 // GateResult holds the outcome of a single gate execution.
@@ -63,6 +79,22 @@ type GateResult struct {
 	Success bool
 	Error   string
 	Elapsed time.Duration
+
+	// Cached is true when the gate was not run because it already passed on
+	// an identical merged tree (see gateCache). Elapsed is then zero.
+	Cached bool
+
+	// Skipped is true when the gate's Include/Exclude globs matched none of
+	// the changed files, so it was not run. A skipped gate is not a pass.
+	Skipped bool
+
+	// Stdout and Stderr hold the gate's output (capped) when it failed.
+	Stdout string
+	Stderr string
+
+	// IgnoredTests lists quarantined tests whose failures were ignored to
+	// let the gate pass (see GateConfig.Quarantine).
+	IgnoredTests []string
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -76,8 +108,13 @@ type MergeQueueConfig struct {
 	Enabled bool `json:"enabled"`
 
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
+	// See OnConflictAssignBack and OnConflictAutoRebase.
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is how MRs land on the target by default. An MR can
+	// override it with its merge_strategy field. See MergeStrategySquash.
+	MergeStrategy string `json:"merge_strategy"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -88,6 +125,9 @@ type MergeQueueConfig struct {
 	DeleteMergedBranches bool `json:"delete_merged_branches"`
 
 	// RetryFlakyTests is the number of times to retry flaky tests.
+	// Applies to each quality gate as well as the legacy test command.
+	// Tests that fail and then pass on retry are recorded in the rig's
+	// flake ledger (see FlakeLedger).
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
 	// PollInterval is how often to check for new MRs.
@@ -112,13 +152,119 @@ type MergeQueueConfig struct {
 	// GatesParallel controls whether gates run concurrently.
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// TrainSize enables merge trains when greater than 1: up to TrainSize
+	// ready MRs for the same target are stacked and gated together, with
+	// bisection on failure (see ProcessTrain). 0 or 1 processes MRs one at a time.
+	TrainSize int `json:"train_size"`
+
+	// GateCacheTTL is how long a gate pass is remembered for the merged tree
+	// it ran on. A retry that produces a byte-identical tree (e.g. after a
+	// push-slot race) skips gates that already passed. Zero disables caching.
+	GateCacheTTL time.Duration `json:"gate_cache_ttl"`
+
+	// FlakeQuarantineAfter quarantines a test automatically once it has
+	// flaked this many times. Zero means tests are only quarantined by hand
+	// (gt mq flakes --quarantine).
+	FlakeQuarantineAfter int `json:"flake_quarantine_after"`
+
+	// PostMergeGates run on the target's new head after a push lands. If any
+	// fails, the refinery pushes a revert, reopens the source issue, and
+	// sends MERGE_FAILED with failure type post_merge (see verifyPostMerge).
+	// They never use the gate cache. Empty disables post-merge verification.
+	PostMergeGates map[string]*GateConfig `json:"post_merge_gates"`
+
+	// Scoring weighs ready MRs to decide processing order. It comes from
+	// merge_queue.scoring in the rig settings (see LoadScoreConfig), not
+	// config.json.
+	Scoring ScoreConfig `json:"-"`
+
+	// FreezeWindows hold pushes to matching targets while they are in
+	// effect. They come from merge_queue.freeze_windows in the rig settings
+	// (see LoadFreezeWindows); manual freezes are read from the rig's
+	// FreezeStore at push time.
+	FreezeWindows []FreezeWindow `json:"-"`
+
+	// Review is the review gate from merge_queue.review in the rig settings
+	// (see LoadReviewConfig). Nil disables it.
+	Review *config.ReviewConfig `json:"-"`
+}
+
+// OnConflict strategies. These mirror config.OnConflictAssignBack and
+// config.OnConflictAutoRebase, which validate the same field in rig settings.
+const (
+	// OnConflictAssignBack sends every conflict back to a polecat via a
+	// conflict-resolution task.
+	OnConflictAssignBack = "assign_back"
+
+	// OnConflictAutoRebase has the refinery rebase the MR onto the target
+	// itself, re-run gates on the result, and only assign back if the
+	// rebase fails.
+	OnConflictAutoRebase = "auto_rebase"
+)
+
+// Merge strategies. These mirror config.MergeStrategySquash and friends,
+// which validate the same field in rig settings.
+const (
+	// MergeStrategySquash lands the MR as a single commit carrying the
+	// branch's last commit message.
+	MergeStrategySquash = "squash"
+
+	// MergeStrategyMerge lands the MR with a merge commit (--no-ff),
+	// keeping the branch's commits.
+	MergeStrategyMerge = "merge"
+
+	// MergeStrategyRebase replays the MR's commits onto the target and
+	// fast-forwards, for linear history without squashing.
+	MergeStrategyRebase = "rebase"
+
+	// MergeStrategyFF only fast-forwards. An MR that isn't based on the
+	// current target is treated as a conflict and sent back for rebase.
+	MergeStrategyFF = "ff"
+)
+
+// LoadMergeStrategy returns the rig's default merge strategy from
+// merge_queue.merge_strategy in its settings, or "" if it isn't set.
+func LoadMergeStrategy(rigPath string) (string, error) {
+	mq, err := loadMergeQueueSettings(rigPath)
+	if err != nil || mq == nil || mq.MergeStrategy == "" {
+		return "", err
+	}
+	if err := checkMergeStrategy(mq.MergeStrategy); err != nil {
+		return "", err
+	}
+	return mq.MergeStrategy, nil
 }
 
+// checkMergeStrategy returns an error naming the valid strategies if s
+// isn't one.
+func checkMergeStrategy(s string) error {
+	if validMergeStrategy(s) {
+		return nil
+	}
+	return fmt.Errorf("invalid merge_strategy %q (want %q, %q, %q, or %q)", s,
+		MergeStrategySquash, MergeStrategyMerge, MergeStrategyRebase, MergeStrategyFF)
+}
+
+// validMergeStrategy reports whether s is a known merge strategy.
+func validMergeStrategy(s string) bool {
+	switch s {
+	case MergeStrategySquash, MergeStrategyMerge, MergeStrategyRebase, MergeStrategyFF:
+		return true
+	}
+	return false
+}
+
+// rebaseBranchPrefix namespaces the temporary branches created by auto-rebase.
+// They live only for the duration of a single doMerge call.
+const rebaseBranchPrefix = "refinery/rebase/"
+
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
 		Enabled:              true,
-		OnConflict:           "assign_back",
+		OnConflict:           OnConflictAssignBack,
+		MergeStrategy:        MergeStrategySquash,
 		RunTests:             true,
 		TestCommand:          "",
 		DeleteMergedBranches: true,
@@ -126,6 +272,9 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
 		StaleClaimTimeout:    DefaultStaleClaimTimeout,
+		GateCacheTTL:         DefaultGateCacheTTL,
+		FlakeQuarantineAfter: DefaultFlakeQuarantineAfter,
+		Scoring:              DefaultScoreConfig(),
 	}
 }
 
@@ -144,8 +293,15 @@ type MRInfo struct {
 	RetryCount      int        // Conflict retry count
 	ConvoyID        string     // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt *time.Time // Convoy creation time
+	ConvoyDeadline  *time.Time // Convoy deadline, if it has one
 	CreatedAt       time.Time  // MR creation time
+	Labels          []string   // MR bead labels (e.g., "hotfix")
+	DiffLines       int        // Lines changed, recorded at submission (0 = unknown)
 	BlockedBy       string     // Task ID blocking this MR
+	ReviewBead      string     // Review bead for the branch, if the review gate requested one
+	MergeStrategy   string     // Per-MR merge strategy override (empty = rig default)
+	TraceID         string     // Trace ID of the work, from the hook bead (see gt trace)
+	Traceparent     string     // gt done's span, parent of the refinery's spans (see internal/tracing)
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -189,13 +345,23 @@ type Engineer struct {
 	git                   *git.Git
 	config                *MergeQueueConfig
 	workDir               string
-	output                io.Writer    // Output destination for user-facing messages
-	router                *mail.Router // Mail router for sending protocol messages
+	output                io.Writer       // Output destination for user-facing messages
+	router                *mail.Router    // Mail router for sending protocol messages
+	tracer                *tracing.Tracer // Exports spans for MRs traced from gt sling
 	mergeSlotEnsureExists func() (string, error)
 	mergeSlotAcquire      func(holder string, addWaiter bool) (*beads.MergeSlotStatus, error)
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+
+	// pushLock serializes Steps 7-9 of doMerge between this engineer and
+	// its parallel workers; the merge slot does the same across processes.
+	pushLock *sync.Mutex
+
+	// localTarget is set on parallel workers (see newWorker): the worker's
+	// own branch standing in for the target, which is checked out in the
+	// refinery worktree and can't be checked out twice.
+	localTarget string
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -219,6 +385,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		workDir: gitDir,
 		output:  os.Stdout,
 		router:  mail.NewRouter(r.Path),
+		tracer:  tracing.New(filepath.Dir(r.Path)),
 		mergeSlotEnsureExists: func() (string, error) {
 			return beadsClient.MergeSlotEnsureExists()
 		},
@@ -230,6 +397,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		pushLock:              &sync.Mutex{},
 	}
 }
 
@@ -239,8 +407,37 @@ func (e *Engineer) SetOutput(w io.Writer) {
 	e.output = w
 }
 
-// LoadConfig loads merge queue configuration from the rig's config.json.
+// LoadConfig loads merge queue configuration from the rig's config.json,
+// and scoring weights, freeze windows, review, and the default merge
+// strategy from its settings. A merge_strategy in config.json overrides the
+// settings one.
 func (e *Engineer) LoadConfig() error {
+	scoring, err := LoadScoreConfig(e.rig.Path)
+	if err != nil {
+		return err
+	}
+	e.config.Scoring = scoring
+
+	windows, err := LoadFreezeWindows(e.rig.Path)
+	if err != nil {
+		return err
+	}
+	e.config.FreezeWindows = windows
+
+	review, err := LoadReviewConfig(e.rig.Path)
+	if err != nil {
+		return err
+	}
+	e.config.Review = review
+
+	strategy, err := LoadMergeStrategy(e.rig.Path)
+	if err != nil {
+		return err
+	}
+	if strategy != "" {
+		e.config.MergeStrategy = strategy
+	}
+
 	configPath := filepath.Join(e.rig.Path, "config.json")
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -267,17 +464,22 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool                      `json:"enabled"`
-		OnConflict           *string                    `json:"on_conflict"`
-		RunTests             *bool                      `json:"run_tests"`
-		TestCommand          *string                    `json:"test_command"`
-		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
-		RetryFlakyTests      *int                       `json:"retry_flaky_tests"`
-		PollInterval         *string                    `json:"poll_interval"`
-		MaxConcurrent        *int                       `json:"max_concurrent"`
-		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
-		Gates                map[string]*gateConfigRaw  `json:"gates"`
-		GatesParallel        *bool                      `json:"gates_parallel"`
+		Enabled              *bool                     `json:"enabled"`
+		OnConflict           *string                   `json:"on_conflict"`
+		MergeStrategy        *string                   `json:"merge_strategy"`
+		RunTests             *bool                     `json:"run_tests"`
+		TestCommand          *string                   `json:"test_command"`
+		DeleteMergedBranches *bool                     `json:"delete_merged_branches"`
+		RetryFlakyTests      *int                      `json:"retry_flaky_tests"`
+		PollInterval         *string                   `json:"poll_interval"`
+		MaxConcurrent        *int                      `json:"max_concurrent"`
+		StaleClaimTimeout    *string                   `json:"stale_claim_timeout"`
+		Gates                map[string]*gateConfigRaw `json:"gates"`
+		GatesParallel        *bool                     `json:"gates_parallel"`
+		TrainSize            *int                      `json:"train_size"`
+		GateCacheTTL         *string                   `json:"gate_cache_ttl"`
+		FlakeQuarantineAfter *int                      `json:"flake_quarantine_after"`
+		PostMergeGates       map[string]*gateConfigRaw `json:"post_merge_gates"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -289,8 +491,19 @@ func (e *Engineer) LoadConfig() error {
 		e.config.Enabled = *mqRaw.Enabled
 	}
 	if mqRaw.OnConflict != nil {
+		switch *mqRaw.OnConflict {
+		case "", OnConflictAssignBack, OnConflictAutoRebase:
+		default:
+			return fmt.Errorf("invalid on_conflict %q (want %q or %q)", *mqRaw.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
+		}
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.MergeStrategy != nil && *mqRaw.MergeStrategy != "" {
+		if err := checkMergeStrategy(*mqRaw.MergeStrategy); err != nil {
+			return err
+		}
+		e.config.MergeStrategy = *mqRaw.MergeStrategy
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -326,25 +539,44 @@ func (e *Engineer) LoadConfig() error {
 
 	// Parse gates configuration
 	if mqRaw.Gates != nil {
-		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
-		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
-			if raw.Timeout != "" {
-				dur, err := time.ParseDuration(raw.Timeout)
-				if err != nil {
-					return fmt.Errorf("invalid timeout for gate %q: %w", name, err)
-				}
-				if dur <= 0 {
-					return fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
-				}
-				gc.Timeout = dur
-			}
-			e.config.Gates[name] = gc
+		gates, err := parseGateConfigs(mqRaw.Gates)
+		if err != nil {
+			return err
+		}
+		e.config.Gates = gates
+	}
+	if mqRaw.PostMergeGates != nil {
+		gates, err := parseGateConfigs(mqRaw.PostMergeGates)
+		if err != nil {
+			return fmt.Errorf("post_merge_gates: %w", err)
 		}
+		e.config.PostMergeGates = gates
 	}
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.TrainSize != nil {
+		if *mqRaw.TrainSize < 0 {
+			return fmt.Errorf("train_size must not be negative, got %d", *mqRaw.TrainSize)
+		}
+		e.config.TrainSize = *mqRaw.TrainSize
+	}
+	if mqRaw.GateCacheTTL != nil {
+		dur, err := time.ParseDuration(*mqRaw.GateCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid gate_cache_ttl %q: %w", *mqRaw.GateCacheTTL, err)
+		}
+		if dur < 0 {
+			return fmt.Errorf("gate_cache_ttl must not be negative, got %v", dur)
+		}
+		e.config.GateCacheTTL = dur
+	}
+	if mqRaw.FlakeQuarantineAfter != nil {
+		if *mqRaw.FlakeQuarantineAfter < 0 {
+			return fmt.Errorf("flake_quarantine_after must not be negative, got %d", *mqRaw.FlakeQuarantineAfter)
+		}
+		e.config.FlakeQuarantineAfter = *mqRaw.FlakeQuarantineAfter
+	}
 
 	return nil
 }
@@ -352,8 +584,36 @@ func (e *Engineer) LoadConfig() error {
 // gateConfigRaw is the JSON-friendly representation of a gate config
 // with timeout as a string duration.
 type gateConfigRaw struct {
-	Cmd     string `json:"cmd"`
-	Timeout string `json:"timeout"`
+	Cmd        string   `json:"cmd"`
+	Timeout    string   `json:"timeout"`
+	Include    []string `json:"include"`
+	Exclude    []string `json:"exclude"`
+	Quarantine bool     `json:"quarantine"`
+}
+
+// parseGateConfigs converts and validates raw gate configs from config.json.
+func parseGateConfigs(raws map[string]*gateConfigRaw) (map[string]*GateConfig, error) {
+	gates := make(map[string]*GateConfig, len(raws))
+	for name, raw := range raws {
+		gc := &GateConfig{Cmd: raw.Cmd, Include: raw.Include, Exclude: raw.Exclude, Quarantine: raw.Quarantine}
+		for _, pattern := range slices.Concat(raw.Include, raw.Exclude) {
+			if err := validateGlob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid path glob %q for gate %q: %w", pattern, name, err)
+			}
+		}
+		if raw.Timeout != "" {
+			dur, err := time.ParseDuration(raw.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for gate %q: %w", name, err)
+			}
+			if dur <= 0 {
+				return nil, fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
+			}
+			gc.Timeout = dur
+		}
+		gates[name] = gc
+	}
+	return gates, nil
 }
 
 // Config returns the current merge queue configuration.
@@ -369,10 +629,33 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	Frozen      bool // Gates passed but the target is frozen; the push is held
+
+	// ReviewPending means the MR touches owned paths and is blocked on a
+	// review bead; ReviewRejected means its reviewer requested changes.
+	ReviewPending  bool
+	ReviewRejected bool
+
+	// Structured gate failure report (set when quality gates fail).
+	// GateLog is the full output of the failed gates, saved as an artifact
+	// on the MR bead by HandleMRInfoFailure.
+	FailingTests []string
+	Excerpt      string
+	GateLog      string
+
+	// PostMergeFailed means the merge landed (MergeCommit) but post-merge
+	// gates failed on it and RevertCommit was pushed to undo it.
+	PostMergeFailed bool
+	RevertCommit    string
 }
 
-// doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+// doMerge performs the actual git merge operation, landing branch on target
+// with the given merge strategy.
+func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue, strategy string) ProcessResult {
+	// local is the branch the merge is built on: target itself, or a
+	// parallel worker's stand-in for it.
+	local := e.localBranch(target)
+
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -391,7 +674,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	// Step 2: Checkout the target branch
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
-	if err := e.git.Checkout(target); err != nil {
+	if err := e.git.Checkout(local); err != nil {
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
@@ -406,7 +689,10 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
-	conflicts, err := e.git.CheckConflicts(branch, target)
+	// mergeRef is what actually gets merged: the MR branch itself, or a
+	// refinery-owned rebased copy of it when auto_rebase resolved a conflict.
+	mergeRef := branch
+	conflicts, err := e.git.CheckConflicts(branch, local)
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -415,93 +701,72 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 	if len(conflicts) > 0 {
-		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
-		}
-	}
-
-	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
-	// The refinery owns all remote pushes — submodule commits must land before the
-	// parent pointer is merged, otherwise main gets dangling submodule references.
-	subChanges, err := e.git.SubmoduleChanges(target, branch)
-	if err != nil {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
-	}
-	if len(subChanges) > 0 {
-		// Ensure submodules are initialized in the refinery worktree
-		if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+		if e.config.OnConflict != OnConflictAutoRebase {
 			return ProcessResult{
-				Success: false,
-				Error:   fmt.Sprintf("failed to init submodules in refinery worktree: %v", initErr),
-			}
-		}
-		for _, sc := range subChanges {
-			if sc.NewSHA == "" {
-				continue // Submodule removed, nothing to push
-			}
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
-			if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
-				return ProcessResult{
-					Success: false,
-					Error:   fmt.Sprintf("failed to push submodule %s: %v", sc.Path, pushErr),
-				}
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
 			}
 		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
-	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
-		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
-		if !gateResult.Success {
-			return gateResult
-		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
-		// Legacy test command path (backward compatible)
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
+		// auto_rebase: try to replay the MR onto the target ourselves before
+		// handing the conflict back to a polecat. The rebase happens on a
+		// refinery-owned temp branch so the polecat's branch is never rewritten.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Conflicts in %v, attempting auto-rebase onto %s...\n", conflicts, target)
+		rebased, rebaseErr := e.autoRebase(branch, local)
+		if rebaseErr != nil {
 			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v (auto-rebase failed: %v)", conflicts, rebaseErr),
 			}
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		defer e.deleteRebaseBranch(rebased)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-rebase succeeded, merging %s\n", rebased)
+		mergeRef = rebased
 	}
 
-	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
-		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
-		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
-		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+	// Step 3.2: The rebase strategy lands a linear copy of the MR: replay it
+	// onto the target on a refinery-owned branch (unless auto-rebase already
+	// did) and fast-forward to that in Step 4.
+	if strategy == MergeStrategyRebase && mergeRef == branch {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Rebasing %s onto %s...\n", branch, target)
+		rebased, rebaseErr := e.autoRebase(branch, local)
+		if rebaseErr != nil {
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
-				Error:    "merge conflict during actual merge",
+				Error:    fmt.Sprintf("rebase onto %s failed: %v", target, rebaseErr),
 			}
 		}
+		defer e.deleteRebaseBranch(rebased)
+		mergeRef = rebased
+	}
+
+	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
+	// The refinery owns all remote pushes — submodule commits must land before the
+	// parent pointer is merged, otherwise main gets dangling submodule references.
+	if err := e.pushSubmoduleChanges(local, mergeRef); err != nil {
 		return ProcessResult{
 			Success: false,
-			Error:   fmt.Sprintf("merge failed: %v", err),
+			Error:   err.Error(),
+		}
+	}
+
+	// Step 4: Perform the actual merge with the MR's strategy
+	if result := e.mergeWithStrategy(mergeRef, branch, target, sourceIssue, strategy); !result.Success {
+		return result
+	}
+
+	// Step 5: Run quality gates (or legacy tests) if configured.
+	// Gates run on the merged tree, so what lands is exactly what was gated
+	// (and the gate cache can key on that tree).
+	if result := e.runQualityChecks(ctx, "origin/"+target); !result.Success {
+		// Undo the local merge so the next MR starts from origin.
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after gate failure: %v\n", target, resetErr)
 		}
+		return result
 	}
 
 	// Step 6: Get the merge commit SHA
@@ -513,15 +778,32 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 6.5: Hold the push while the target is frozen. The gates passed
+	// and are cached, so the MR lands without re-gating once the freeze lifts.
+	if freeze := e.activeFreeze(target); freeze != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Holding push: %s\n", freeze)
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after freeze hold: %v\n", target, resetErr)
+		}
+		return ProcessResult{
+			Success: false,
+			Frozen:  true,
+			Error:   freeze.String(),
+		}
+	}
+
 	// Step 7: Acquire merge slot before push to serialize writes to the default branch.
 	// Only serialize pushes to the rig's default branch (typically main).
 	// Integration-branch and feature-branch pushes don't need serialization.
+	// Parallel workers of this engineer always take turns here.
+	e.pushLock.Lock()
+	defer e.pushLock.Unlock()
 	var pushHolder string
 	if target == e.rig.DefaultBranch() {
 		var slotErr error
 		pushHolder, slotErr = e.acquireMainPushSlot(ctx)
 		if slotErr != nil {
-			// Reset the checked-out target branch to origin to undo the local squash commit.
+			// Reset the checked-out target branch to origin to undo the local merge.
 			// ResetHard is required because target is the current branch (checked out in Step 2).
 			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after slot failure: %v\n", target, resetErr)
@@ -546,10 +828,35 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}()
 	}
 
-	// Step 8: Push to origin
+	// Step 7.5: A parallel worker built its merge on the target as it was
+	// when the worker started. If another worker landed since, redo the
+	// merge on the new head and gate it again.
+	if e.localTarget != "" {
+		caughtUp, result := e.catchUpTarget(ctx, mergeRef, branch, target, sourceIssue, strategy)
+		if caughtUp != mergeRef {
+			defer e.deleteRebaseBranch(caughtUp)
+			mergeRef = caughtUp
+		}
+		if !result.Success {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after re-merge failure: %v\n", target, resetErr)
+			}
+			return result
+		}
+		if mergeCommit, err = e.git.Rev("HEAD"); err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to get merge commit SHA: %v", err),
+			}
+		}
+	}
+
+	// Step 8: Push to origin. Remember the pre-push head first: it is what
+	// a failed post-merge verification reverts to.
+	base, baseErr := e.git.Rev("origin/" + target)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
-		// Reset the checked-out target branch to undo the local squash commit.
+	if err := e.git.Push("origin", e.pushRefspec(target), false); err != nil {
+		// Reset the checked-out target branch to undo the local merge.
 		// Without this, the next retry could see stale local state from the failed push.
 		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after push failure: %v\n", target, resetErr)
@@ -560,6 +867,17 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 9: Verify the pushed head with post-merge gates, still holding
+	// the push slot so a revert lands directly on top of the merge.
+	if len(e.config.PostMergeGates) > 0 {
+		if baseErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: skipping post-merge gates, no pre-push head of %s: %v\n", target, baseErr)
+		} else if result := e.verifyPostMerge(ctx, target, base, branch); !result.Success {
+			result.MergeCommit = mergeCommit
+			return result
+		}
+	}
+
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
 	return ProcessResult{
 		Success:     true,
@@ -567,6 +885,185 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 }
 
+// mergeWithStrategy lands mergeRef on the checked-out target branch as a
+// local commit (or fast-forward), ready for gating and push. branch and
+// sourceIssue are only used for commit messages.
+func (e *Engineer) mergeWithStrategy(mergeRef, branch, target, sourceIssue, strategy string) ProcessResult {
+	var err error
+	switch strategy {
+	case MergeStrategyMerge:
+		msg := fmt.Sprintf("Merge %s into %s", branch, target)
+		if sourceIssue != "" {
+			msg = fmt.Sprintf("Merge %s into %s (%s)", branch, target, sourceIssue)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merging with merge commit: %s\n", msg)
+		err = e.git.MergeNoFF(mergeRef, msg)
+
+	case MergeStrategyRebase, MergeStrategyFF:
+		// For rebase, mergeRef was already replayed onto target in Step 3.2,
+		// so this can only fail if target moved underneath us.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Fast-forwarding %s to %s\n", target, mergeRef)
+		if ffErr := e.git.MergeFFOnly(mergeRef); ffErr != nil {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("cannot fast-forward %s to %s: branch is not based on the latest %s (%v)", target, branch, target, ffErr),
+			}
+		}
+		return ProcessResult{Success: true}
+
+	default:
+		// Get the original commit message from the polecat branch to preserve the
+		// conventional commit format (feat:/fix:) instead of creating redundant merge commits
+		originalMsg, msgErr := e.git.GetBranchCommitMessage(mergeRef)
+		if msgErr != nil {
+			// Fallback to a descriptive message if we can't get the original
+			originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
+			if sourceIssue != "" {
+				originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", msgErr)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
+		err = e.git.MergeSquash(mergeRef, originalMsg)
+	}
+
+	if err != nil {
+		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
+		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
+		conflicts, conflictErr := e.git.GetConflictingFiles()
+		if conflictErr == nil && len(conflicts) > 0 {
+			_ = e.git.AbortMerge()
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    "merge conflict during actual merge",
+			}
+		}
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("merge failed: %v", err),
+		}
+	}
+	return ProcessResult{Success: true}
+}
+
+// deleteRebaseBranch removes a temp branch created by autoRebase.
+func (e *Engineer) deleteRebaseBranch(rebased string) {
+	if err := e.git.DeleteBranch(rebased, true); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete rebase branch %s: %v\n", rebased, err)
+	}
+}
+
+// pushSubmoduleChanges pushes any submodule commits that ref's submodule
+// pointers reference relative to base, so they exist on the remote before the
+// parent pointer lands.
+func (e *Engineer) pushSubmoduleChanges(base, ref string) error {
+	subChanges, err := e.git.SubmoduleChanges(base, ref)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
+	}
+	if len(subChanges) == 0 {
+		return nil
+	}
+
+	// Ensure submodules are initialized in the refinery worktree
+	if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+		return fmt.Errorf("failed to init submodules in refinery worktree: %v", initErr)
+	}
+	for _, sc := range subChanges {
+		if sc.NewSHA == "" {
+			continue // Submodule removed, nothing to push
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
+		if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
+			return fmt.Errorf("failed to push submodule %s: %v", sc.Path, pushErr)
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
+	return nil
+}
+
+// runQualityChecks runs the configured quality gates, or the legacy
+// RunTests/TestCommand path when no gates are configured, against the
+// current worktree. base is the ref the worktree is compared with to decide
+// which path-filtered gates apply.
+func (e *Engineer) runQualityChecks(ctx context.Context, base string) ProcessResult {
+	if len(e.config.Gates) > 0 {
+		// New gates system: run configured quality gates.
+		// If the diff can't be computed, changed stays nil and every gate runs.
+		changed, err := e.git.ChangedFiles(base, "HEAD")
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files against %s, running all gates: %v\n", base, err)
+		}
+		return e.runGates(ctx, changed)
+	}
+	if e.config.RunTests && e.config.TestCommand != "" {
+		// Legacy test command path (backward compatible)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
+		start := time.Now()
+		result := e.runTests(ctx)
+		NewGateStats(e.rig.Path).Record("quality", map[string]time.Duration{legacyTestGate: time.Since(start)})
+		if !result.Success {
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	}
+	return ProcessResult{Success: true}
+}
+
+// autoRebase replays branch onto target on a temporary refinery-owned branch
+// and returns that branch's name. The MR branch itself is left untouched: it
+// lives in the .repo.git shared with polecats and may be checked out in a
+// polecat worktree. On failure the rebase is aborted, the temp branch deleted,
+// and the target branch checked out again so doMerge's invariants hold.
+func (e *Engineer) autoRebase(branch, target string) (string, error) {
+	tmp := rebaseBranchPrefix + strings.ReplaceAll(branch, "/", "-")
+
+	// A leftover from a crashed run would make CreateBranchFrom fail.
+	if exists, _ := e.git.BranchExists(tmp); exists {
+		_ = e.git.DeleteBranch(tmp, true)
+	}
+	if err := e.git.CreateBranchFrom(tmp, branch); err != nil {
+		return "", fmt.Errorf("creating rebase branch: %w", err)
+	}
+
+	cleanup := func() {
+		if err := e.git.Checkout(target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to checkout %s after rebase: %v\n", target, err)
+		}
+		_ = e.git.DeleteBranch(tmp, true)
+	}
+
+	if err := e.git.Checkout(tmp); err != nil {
+		cleanup()
+		return "", fmt.Errorf("checking out rebase branch: %w", err)
+	}
+	if err := e.git.Rebase(target); err != nil {
+		_ = e.git.AbortRebase()
+		cleanup()
+		return "", err
+	}
+
+	// Verify the rebased branch now merges cleanly. CheckConflicts leaves
+	// the target checked out, which is what the rest of doMerge expects.
+	conflicts, err := e.git.CheckConflicts(tmp, target)
+	if err != nil {
+		cleanup()
+		return "", fmt.Errorf("conflict check after rebase: %w", err)
+	}
+	if len(conflicts) > 0 {
+		cleanup()
+		return "", fmt.Errorf("conflicts remain after rebase in: %v", conflicts)
+	}
+
+	return tmp, nil
+}
+
 func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	slotID, err := e.mergeSlotEnsureExists()
 	if err != nil {
@@ -577,9 +1074,10 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	holder := fmt.Sprintf("%s/refinery/push/%d-%d", e.rig.Name, time.Now().UnixNano(), seq)
 
 	// The conflict-resolution path holds the slot with holder "rigName/refinery".
-	// Both push and conflict-resolution run in the same single-threaded refinery
-	// agent, so if our own rig holds the slot for conflict resolution, we can
-	// safely proceed without re-acquiring — no concurrent push is possible.
+	// Both push and conflict-resolution run in the same refinery agent, and its
+	// parallel workers take turns through pushLock, so if our own rig holds the
+	// slot for conflict resolution, we can safely proceed without re-acquiring —
+	// no concurrent push is possible.
 	selfConflictHolder := e.rig.Name + "/refinery"
 
 	backoff := e.mergeSlotRetryBackoff
@@ -645,6 +1143,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var flaked []string
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
@@ -662,9 +1161,15 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 		err := cmd.Run()
 		if err == nil {
+			e.recordFlakes(e.flakeLedger(), testCommandFlakeGate, flaked)
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
+		failing := parseGateOutput(stdout.String(), stderr.String()).FailingTests
+		if len(failing) == 0 {
+			failing = []string{gateFlakeName(testCommandFlakeGate)}
+		}
+		flaked = append(flaked, failing...)
 
 		// Check if context was canceled
 		if ctx.Err() != nil {
@@ -682,10 +1187,27 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 }
 
+// testCommandFlakeGate is the gate name flakes from the legacy TestCommand
+// are recorded under.
+const testCommandFlakeGate = "test_command"
+
 // runGate executes a single quality gate command and returns the result.
-func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) (result GateResult) {
 	start := time.Now()
 
+	// Traced as a child of the MR's span, if it has one
+	if span := e.tracer.StartFromContext(ctx, "gate "+name); span != nil {
+		span.SetAttr("gt.gate", name)
+		span.SetAttr("gt.gate.cmd", gate.Cmd)
+		defer func() {
+			var err error
+			if !result.Success {
+				err = errors.New(result.Error)
+			}
+			span.End(err)
+		}()
+	}
+
 	if strings.TrimSpace(gate.Cmd) == "" {
 		return GateResult{
 			Name:    name,
@@ -705,9 +1227,10 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
 	cmd.Dir = e.workDir
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	stdout := &cappedBuffer{max: gateOutputMaxBytes}
+	stderr := &cappedBuffer{max: gateOutputMaxBytes}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	err := cmd.Run()
 	elapsed := time.Since(start)
@@ -737,14 +1260,103 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		Success: false,
 		Error:   errMsg,
 		Elapsed: elapsed,
+		Stdout:  stdout.String(),
+		Stderr:  stderr.String(),
 	}
 }
 
+// runGateWithRetries runs a gate up to RetryFlakyTests times until it passes.
+// Tests that failed on an earlier attempt of a gate that then passed are
+// recorded in the flake ledger. If the gate has Quarantine set and every test
+// that failed is quarantined, the failure is ignored and the gate passes.
+func (e *Engineer) runGateWithRetries(ctx context.Context, name string, gate *GateConfig, flakes *FlakeLedger) GateResult {
+	attempts := e.config.RetryFlakyTests
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var flaked []string
+	var result GateResult
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: retrying (attempt %d/%d)\n", name, attempt, attempts)
+		}
+		result = e.runGate(ctx, name, gate)
+		if result.Success {
+			e.recordFlakes(flakes, name, flaked)
+			return result
+		}
+
+		failing := parseGateOutput(result.Stdout, result.Stderr).FailingTests
+		if gate.Quarantine && len(failing) > 0 && flakes != nil {
+			quarantined, err := flakes.Quarantined()
+			if err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+			} else if allIn(failing, quarantined) {
+				result.Success = true
+				result.Error = ""
+				result.IgnoredTests = failing
+				return result
+			}
+		}
+
+		if len(failing) == 0 {
+			failing = []string{gateFlakeName(name)}
+		}
+		flaked = append(flaked, failing...)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return result
+}
+
+// recordFlakes adds tests to the flake ledger, logging any that were
+// quarantined as a result.
+func (e *Engineer) recordFlakes(flakes *FlakeLedger, gate string, tests []string) {
+	if flakes == nil || len(tests) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: flaky (passed on retry): %s\n", gate, strings.Join(tests, ", "))
+	quarantined, err := flakes.RecordFlakes(gate, dedupe(tests), time.Now())
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record flakes: %v\n", err)
+		return
+	}
+	for _, t := range quarantined {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantined flaky test: %s\n", t)
+	}
+}
+
+// flakeLedger returns the rig's flake ledger, or nil if the rig path is unknown.
+func (e *Engineer) flakeLedger() *FlakeLedger {
+	if e.rig == nil || e.rig.Path == "" {
+		return nil
+	}
+	return NewFlakeLedger(e.rig.Path, e.config.FlakeQuarantineAfter)
+}
+
+// gateCache returns the rig's gate result cache, or nil if caching is disabled.
+// All gateCache methods are nil-safe.
+func (e *Engineer) gateCache() *gateCache {
+	if e.config.GateCacheTTL <= 0 || e.rig == nil || e.rig.Path == "" {
+		return nil
+	}
+	return newGateCache(e.rig.Path, e.config.GateCacheTTL)
+}
+
 // runGates executes all configured quality gates and returns a ProcessResult.
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
-// Any single gate failure means overall failure.
-func (e *Engineer) runGates(ctx context.Context) ProcessResult {
-	gates := e.config.Gates
+// Any single gate failure means overall failure. changed lists the files the
+// merge touches; gates whose path globs match none of them are skipped.
+// A nil changed runs every gate.
+func (e *Engineer) runGates(ctx context.Context, changed []string) ProcessResult {
+	return e.runGateSet(ctx, "quality", e.config.Gates, changed, true)
+}
+
+// runGateSet runs gates against HEAD. kind labels output and errors
+// ("quality", "post-merge"); useCache enables the per-tree gate cache.
+func (e *Engineer) runGateSet(ctx context.Context, kind string, gates map[string]*GateConfig, changed []string, useCache bool) ProcessResult {
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
 	}
@@ -756,7 +1368,34 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	}
 	sort.Strings(names)
 
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(names), e.config.GatesParallel)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d %s gate(s) (parallel=%v)\n", len(names), kind, e.config.GatesParallel)
+
+	// Gate passes are cached per merged tree. A tree lookup failure just
+	// means every gate runs.
+	var cache *gateCache
+	if useCache {
+		cache = e.gateCache()
+	}
+	tree, _ := e.git.Rev("HEAD^{tree}")
+	flakes := e.flakeLedger()
+
+	runOne := func(name string) GateResult {
+		gate := gates[name]
+		if !gate.appliesTo(changed) {
+			return GateResult{Name: name, Success: true, Skipped: true}
+		}
+		if cache.Lookup(tree, gate.Cmd, time.Now()) {
+			return GateResult{Name: name, Success: true, Cached: true}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gate.Cmd)
+		result := e.runGateWithRetries(ctx, name, gate, flakes)
+		// A pass that ignored quarantined failures isn't cached: releasing
+		// the test from quarantine must make the gate fail again.
+		if result.Success && len(result.IgnoredTests) == 0 {
+			cache.RecordPass(tree, name, gate.Cmd, result.Elapsed, time.Now())
+		}
+		return result
+	}
 
 	var results []GateResult
 
@@ -767,15 +1406,13 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			wg.Add(1)
 			go func(idx int, gateName string) {
 				defer wg.Done()
-				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
+				results[idx] = runOne(gateName)
 			}(i, name)
 		}
 		wg.Wait()
 	} else {
 		for _, name := range names {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
+			result := runOne(name)
 			results = append(results, result)
 			if !result.Success {
 				// Sequential mode: stop on first failure
@@ -784,10 +1421,25 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 		}
 	}
 
+	// Feed the duration history behind gt mq plan's ETAs.
+	runs := make(map[string]time.Duration)
+	for _, r := range results {
+		if !r.Skipped && !r.Cached {
+			runs[r.Name] = r.Elapsed
+		}
+	}
+	NewGateStats(e.rig.Path).Record(kind, runs)
+
 	// Report results
 	var failures []string
 	for _, r := range results {
-		if r.Success {
+		if r.Skipped {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: skipped (no matching changes)\n", r.Name)
+		} else if len(r.IgnoredTests) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed with quarantined failures ignored: %s\n", r.Name, strings.Join(r.IgnoredTests, ", "))
+		} else if r.Cached {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (cached for tree %.8s)\n", r.Name, tree)
+		} else if r.Success {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
@@ -796,14 +1448,28 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	}
 
 	if len(failures) > 0 {
-		return ProcessResult{
+		result := ProcessResult{
 			Success:     false,
 			TestsFailed: true,
-			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			Error:       fmt.Sprintf("%s gates failed: %s", kind, strings.Join(failures, "; ")),
+			GateLog:     formatGateLog(results),
 		}
+		var excerpts []string
+		for _, r := range results {
+			if r.Success {
+				continue
+			}
+			report := parseGateOutput(r.Stdout, r.Stderr)
+			result.FailingTests = append(result.FailingTests, report.FailingTests...)
+			if report.Excerpt != "" {
+				excerpts = append(excerpts, fmt.Sprintf("[%s]\n%s", r.Name, report.Excerpt))
+			}
+		}
+		result.Excerpt = strings.Join(excerpts, "\n")
+		return result
 	}
 
-	_, _ = fmt.Fprintln(e.output, "[Engineer] All quality gates passed")
+	_, _ = fmt.Fprintf(e.output, "[Engineer] All %s gates passed\n", kind)
 	return ProcessResult{Success: true}
 }
 
@@ -840,7 +1506,13 @@ func (e *Engineer) syncCrewWorkspaces() {
 }
 
 // ProcessMRInfo processes a merge request from MRInfo.
-func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
+func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) (result ProcessResult) {
+	// Trace the merge as a child of gt done's span; gates are traced under it
+	if span := e.startProcessSpan(mr); span != nil {
+		ctx = tracing.ContextWithSpan(ctx, span)
+		defer func() { endProcessSpan(span, result) }()
+	}
+
 	// MR fields are directly on the struct
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mr.Branch)
@@ -848,8 +1520,69 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	strategy := e.mergeStrategyFor(mr)
+	if !validMergeStrategy(strategy) {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid merge_strategy %q on MR %s", strategy, mr.ID),
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "  Strategy: %s\n", strategy)
+
+	if result, ok := e.checkReview(mr); !ok {
+		return result
+	}
+
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue, strategy)
+}
+
+// startProcessSpan starts mr's refinery.process_mr span as a child of gt
+// done's span, or returns nil if the MR isn't traced.
+func (e *Engineer) startProcessSpan(mr *MRInfo) *tracing.Span {
+	parent, ok := tracing.ParseTraceparent(mr.Traceparent)
+	if !ok {
+		return nil
+	}
+	span := e.tracer.Start("refinery.process_mr", parent)
+	span.SetAttr("gt.mr", mr.ID)
+	span.SetAttr("gt.bead", mr.SourceIssue)
+	span.SetAttr("gt.branch", mr.Branch)
+	span.SetAttr("gt.target", mr.Target)
+	span.SetAttr("gt.trace_id", mr.TraceID)
+	return span
+}
+
+// endProcessSpan ends an MR's span with what became of it.
+func endProcessSpan(span *tracing.Span, result ProcessResult) {
+	span.SetAttr("gt.merge_commit", result.MergeCommit)
+	switch {
+	case result.Conflict:
+		span.SetAttr("gt.outcome", "conflict")
+	case result.TestsFailed:
+		span.SetAttr("gt.outcome", "gates_failed")
+	case result.ReviewPending, result.ReviewRejected, result.Frozen, result.SlotTimeout:
+		span.SetAttr("gt.outcome", "held")
+	case result.Success:
+		span.SetAttr("gt.outcome", "merged")
+	}
+	var err error
+	if !result.Success && result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	span.End(err)
+}
+
+// mergeStrategyFor returns how mr should land: its own merge_strategy if
+// set, otherwise the rig default.
+func (e *Engineer) mergeStrategyFor(mr *MRInfo) string {
+	if mr.MergeStrategy != "" {
+		return mr.MergeStrategy
+	}
+	if e.config.MergeStrategy != "" {
+		return e.config.MergeStrategy
+	}
+	return MergeStrategySquash
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -931,11 +1664,15 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 
 	// 4. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery",
+		events.WithTrace(events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""), mr.TraceID))
 }
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
-// For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
+// For post-merge failures, closes the MR and reopens its source issue.
+// For rejected reviews, closes the MR so the worker resubmits.
+// For slot timeouts, freeze holds and pending reviews, the MR stays in queue for automatic retry without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
@@ -947,19 +1684,41 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// A frozen target held an MR that passed its gates. It stays ready and
+	// lands on the first pass after the freeze lifts.
+	if result.Frozen {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Held (ready, frozen): %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// The MR is blocked on its review bead and re-enters the ready queue
+	// when the reviewer closes it; the owners were already mailed.
+	if result.ReviewPending {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Awaiting review: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// Save the failed gates' output as an artifact on the MR bead so the
+	// rework polecat can read it without re-running the suite.
+	artifact := ""
+	if result.GateLog != "" {
+		artifact = e.recordGateLog(mr, result.GateLog)
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
-	// Determine failure type from result
-	failureType := "build"
-	if result.Conflict {
-		failureType = "conflict"
-	} else if result.TestsFailed {
-		failureType = "tests"
+	e.notifyMergeOutcome(mr, result, artifact)
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery",
+		events.WithTrace(events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error), mr.TraceID))
+
+	// A post-merge failure already landed (and was reverted): the MR is
+	// done either way, so it leaves the queue instead of being retried.
+	if result.PostMergeFailed {
+		e.handlePostMergeFailure(mr, result)
+		return
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
-	if err := e.router.Send(msg); err != nil {
-		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
-	} else {
-		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
+	if result.ReviewRejected {
+		e.handleReviewRejected(mr, result)
+		return
 	}
 
 	// If this was a conflict, create a conflict-resolution task for dispatch
@@ -988,6 +1747,83 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// notifyMergeOutcome sends the witness the protocol message for an MR's
+// outcome via protocol.DefaultRefineryHandler.NotifyMergeOutcome: MERGED on
+// success, MERGE_FAILED otherwise.
+//
+// Conflicts are reported as MERGE_FAILED with failure type "conflict" rather
+// than REWORK_REQUEST, because the refinery creates the conflict-resolution
+// task itself (see createConflictResolutionTaskForMR).
+//
+// artifact is the path of the saved gate log, if any.
+func (e *Engineer) notifyMergeOutcome(mr *MRInfo, result ProcessResult, artifact string) {
+	handler := &protocol.DefaultRefineryHandler{
+		Rig:     e.rig.Name,
+		WorkDir: e.rig.Path,
+		Router:  e.router,
+		Output:  e.output,
+	}
+
+	outcome := protocol.MergeOutcome{
+		Success:      result.Success,
+		MergeCommit:  result.MergeCommit,
+		RevertCommit: result.RevertCommit,
+		Error:        result.Error,
+		FailureType:  "build",
+		FailingTests: result.FailingTests,
+		Excerpt:      result.Excerpt,
+		Artifact:     artifact,
+		TraceID:      mr.TraceID,
+	}
+	if result.PostMergeFailed {
+		outcome.FailureType = protocol.FailureTypePostMerge
+	} else if result.ReviewRejected {
+		outcome.FailureType = protocol.FailureTypeReview
+	} else if result.Conflict {
+		outcome.FailureType = "conflict"
+	} else if result.TestsFailed {
+		outcome.FailureType = "tests"
+	}
+
+	kind := "merge failure"
+	if result.Success {
+		kind = "merge"
+	}
+	if err := handler.NotifyMergeOutcome(mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, outcome); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to notify witness of %s: %v\n", kind, err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Notified witness of %s for %s\n", kind, mr.Worker)
+	}
+}
+
+// recordGateLog saves gate output for mr and records its path in the MR
+// bead's gate_log field. Returns the path, or "" if the log couldn't be saved.
+// Bead update failures are non-fatal: the log is still on disk.
+func (e *Engineer) recordGateLog(mr *MRInfo, content string) string {
+	path, err := e.saveGateLog(mr.ID, content)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to save gate log for %s: %v\n", mr.ID, err)
+		return ""
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Saved gate output: %s\n", path)
+
+	mrBead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+		return path
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	mrFields.GateLog = path
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record gate log on MR %s: %v\n", mr.ID, err)
+	}
+	return path
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1131,13 +1967,18 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 // issueToMRInfo converts a beads issue (with parsed MR fields) into an MRInfo.
 // Shared by ListReadyMRs, ListBlockedMRs, and ListAllOpenMRs.
 func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
-	// Parse convoy created_at if present
-	var convoyCreatedAt *time.Time
+	// Parse convoy created_at and deadline if present
+	var convoyCreatedAt, convoyDeadline *time.Time
 	if fields.ConvoyCreatedAt != "" {
 		if t, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
 			convoyCreatedAt = &t
 		}
 	}
+	if fields.ConvoyDeadline != "" {
+		if t, err := time.Parse(time.RFC3339, fields.ConvoyDeadline); err == nil {
+			convoyDeadline = &t
+		}
+	}
 
 	// Parse issue timestamps
 	var createdAt, updatedAt time.Time
@@ -1165,9 +2006,16 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		RetryCount:      fields.RetryCount,
 		ConvoyID:        fields.ConvoyID,
 		ConvoyCreatedAt: convoyCreatedAt,
+		ConvoyDeadline:  convoyDeadline,
 		CreatedAt:       createdAt,
+		Labels:          issue.Labels,
+		DiffLines:       fields.DiffLines,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		MergeStrategy:   fields.MergeStrategy,
+		ReviewBead:      fields.ReviewBead,
+		TraceID:         fields.TraceID,
+		Traceparent:     fields.Traceparent,
 	}
 }
 
@@ -1492,7 +2340,7 @@ func (e *Engineer) notifyDeaconConvoyFeeding(mr *MRInfo) {
 
 	// Emit event to wake deacon from await-signal (router.Send doesn't write
 	// to .events.jsonl, but await-signal watches the events file).
-	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID))
+	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.WithTrace(events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID), mr.TraceID))
 }
 
 // convoyInfo holds minimal info about a closed convoy for post-merge processing.
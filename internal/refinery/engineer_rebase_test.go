@@ -0,0 +1,77 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestAutoRebase_CleanRebase(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+
+	// Branch edits a different file than main, but main moved on
+	runGit(t, dir, "checkout", "-b", "polecat/nux")
+	commitFile(t, dir, "feature.txt", "feature\n", "feat: add feature")
+	runGit(t, dir, "checkout", "main")
+	commitFile(t, dir, "other.txt", "other\n", "chore: other")
+	branchTip := runGit(t, dir, "rev-parse", "polecat/nux")
+
+	rebased, err := e.autoRebase("polecat/nux", "main")
+	if err != nil {
+		t.Fatalf("autoRebase: %v", err)
+	}
+	if !strings.HasPrefix(rebased, rebaseBranchPrefix) {
+		t.Errorf("expected rebase branch under %q, got %q", rebaseBranchPrefix, rebased)
+	}
+
+	// Rebased branch sits on top of main
+	if ok, _ := e.git.IsAncestor("main", rebased); !ok {
+		t.Error("expected main to be an ancestor of the rebased branch")
+	}
+	// Polecat branch is untouched
+	if got := runGit(t, dir, "rev-parse", "polecat/nux"); got != branchTip {
+		t.Errorf("polecat branch was rewritten: %s != %s", got, branchTip)
+	}
+	// Target is checked out for the rest of doMerge
+	if cur, _ := e.git.CurrentBranch(); cur != "main" {
+		t.Errorf("expected main checked out, got %q", cur)
+	}
+}
+
+func TestAutoRebase_RealConflict(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+
+	runGit(t, dir, "checkout", "-b", "polecat/nux")
+	commitFile(t, dir, "README.md", "line1\nbranch\nline3\n", "feat: branch edit")
+	runGit(t, dir, "checkout", "main")
+	commitFile(t, dir, "README.md", "line1\nmain\nline3\n", "fix: main edit")
+
+	if _, err := e.autoRebase("polecat/nux", "main"); err == nil {
+		t.Fatal("expected autoRebase to fail on a real conflict")
+	}
+
+	// Temp branch cleaned up and target restored
+	branches, _ := e.git.ListBranches(rebaseBranchPrefix + "*")
+	if len(branches) != 0 {
+		t.Errorf("expected rebase branch to be deleted, got %v", branches)
+	}
+	if cur, _ := e.git.CurrentBranch(); cur != "main" {
+		t.Errorf("expected main checked out, got %q", cur)
+	}
+}
+
+func TestEngineer_LoadConfig_InvalidOnConflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := []byte(`{"merge_queue": {"on_conflict": "yolo"}}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err == nil {
+		t.Error("expected error for unknown on_conflict strategy")
+	}
+}
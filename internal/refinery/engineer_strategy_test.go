@@ -0,0 +1,210 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// addTwoCommitBranch creates polecat/nux off main with two commits.
+func addTwoCommitBranch(t *testing.T, dir string) {
+	t.Helper()
+	runGit(t, dir, "checkout", "-q", "-b", "polecat/nux", "main")
+	commitFile(t, dir, "a.txt", "a\n", "feat: part one")
+	commitFile(t, dir, "b.txt", "b\n", "feat: part two")
+	runGit(t, dir, "checkout", "-q", "main")
+}
+
+func TestProcessMRInfo_SquashIsDefault(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addTwoCommitBranch(t, dir)
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}
+	if r := e.ProcessMRInfo(context.Background(), mr); !r.Success {
+		t.Fatalf("merge failed: %s", r.Error)
+	}
+
+	log := runGit(t, dir, "log", "--format=%s", "origin/main")
+	if log != "feat: part two\ninitial" {
+		t.Errorf("expected a single squash commit, got:\n%s", log)
+	}
+}
+
+func TestProcessMRInfo_MergeCommit(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	e.config.MergeStrategy = MergeStrategyMerge
+	addTwoCommitBranch(t, dir)
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main", SourceIssue: "gt-abc"}
+	r := e.ProcessMRInfo(context.Background(), mr)
+	if !r.Success {
+		t.Fatalf("merge failed: %s", r.Error)
+	}
+
+	parents := strings.Fields(runGit(t, dir, "log", "-1", "--format=%P", "origin/main"))
+	if len(parents) != 2 {
+		t.Fatalf("expected a merge commit with 2 parents, got %v", parents)
+	}
+	if msg := runGit(t, dir, "log", "-1", "--format=%s", "origin/main"); msg != "Merge polecat/nux into main (gt-abc)" {
+		t.Errorf("unexpected merge message %q", msg)
+	}
+	if got := runGit(t, dir, "log", "--format=%s", "origin/main^2"); !strings.HasPrefix(got, "feat: part two\nfeat: part one") {
+		t.Errorf("expected branch commits preserved, got:\n%s", got)
+	}
+}
+
+func TestProcessMRInfo_RebaseKeepsHistoryLinear(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addTwoCommitBranch(t, dir)
+	commitFile(t, dir, "other.txt", "other\n", "chore: other")
+	runGit(t, dir, "push", "-q", "origin", "main")
+	branchTip := runGit(t, dir, "rev-parse", "polecat/nux")
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main", MergeStrategy: MergeStrategyRebase}
+	r := e.ProcessMRInfo(context.Background(), mr)
+	if !r.Success {
+		t.Fatalf("merge failed: %s", r.Error)
+	}
+
+	log := runGit(t, dir, "log", "--format=%s", "origin/main")
+	if log != "feat: part two\nfeat: part one\nchore: other\ninitial" {
+		t.Errorf("expected branch commits replayed on main, got:\n%s", log)
+	}
+	if merges := runGit(t, dir, "rev-list", "--merges", "origin/main"); merges != "" {
+		t.Errorf("expected no merge commits, got %s", merges)
+	}
+	if r.MergeCommit != runGit(t, dir, "rev-parse", "origin/main") {
+		t.Error("expected MergeCommit to be the new origin/main")
+	}
+	if got := runGit(t, dir, "rev-parse", "polecat/nux"); got != branchTip {
+		t.Errorf("polecat branch was rewritten: %s != %s", got, branchTip)
+	}
+	if branches := runGit(t, dir, "branch", "--list", rebaseBranchPrefix+"*"); branches != "" {
+		t.Errorf("expected rebase branch cleaned up, got %q", branches)
+	}
+}
+
+func TestProcessMRInfo_FastForwardOnly(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	e.config.MergeStrategy = MergeStrategyFF
+	addTwoCommitBranch(t, dir)
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}
+	r := e.ProcessMRInfo(context.Background(), mr)
+	if !r.Success {
+		t.Fatalf("fast-forward failed: %s", r.Error)
+	}
+	if got := runGit(t, dir, "rev-parse", "origin/main"); got != runGit(t, dir, "rev-parse", "polecat/nux") {
+		t.Error("expected origin/main fast-forwarded to the branch tip")
+	}
+
+	// Once main moves on, a branch that isn't rebased can't fast-forward.
+	addBranch(t, dir, "polecat/slit", "slit.txt", "slit\n")
+	commitFile(t, dir, "other.txt", "other\n", "chore: other")
+	runGit(t, dir, "push", "-q", "origin", "main")
+
+	r = e.ProcessMRInfo(context.Background(), &MRInfo{ID: "mr-2", Branch: "polecat/slit", Target: "main"})
+	if r.Success || !r.Conflict {
+		t.Fatalf("expected conflict for non-fast-forward branch, got %+v", r)
+	}
+}
+
+func TestProcessMRInfo_InvalidStrategy(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addTwoCommitBranch(t, dir)
+	before := runGit(t, dir, "rev-parse", "origin/main")
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main", MergeStrategy: "octopus"}
+	if r := e.ProcessMRInfo(context.Background(), mr); r.Success || r.Conflict {
+		t.Fatalf("expected plain failure for unknown strategy, got %+v", r)
+	}
+	if after := runGit(t, dir, "rev-parse", "origin/main"); after != before {
+		t.Error("origin/main changed despite invalid strategy")
+	}
+}
+
+func TestEngineer_LoadConfig_MergeStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"merge_queue": {"merge_strategy": "rebase"}}`)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.Config().MergeStrategy != MergeStrategyRebase {
+		t.Errorf("MergeStrategy = %q, want %q", e.Config().MergeStrategy, MergeStrategyRebase)
+	}
+
+	write(`{"merge_queue": {"merge_strategy": "octopus"}}`)
+	e = NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err == nil {
+		t.Error("expected error for unknown merge_strategy")
+	}
+}
+
+func TestSelectTrain_OnlyStacksSquashMRs(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "p0-rebase", Target: "main", Priority: 0, CreatedAt: now, MergeStrategy: MergeStrategyRebase},
+		{ID: "p1", Target: "main", Priority: 1, CreatedAt: now},
+		{ID: "p2-merge", Target: "main", Priority: 2, CreatedAt: now, MergeStrategy: MergeStrategyMerge},
+		{ID: "p3", Target: "main", Priority: 3, CreatedAt: now},
+	}
+
+	if got := selectTrain(mrs, 3, MergeStrategySquash, DefaultScoreConfig(), now); len(got) != 1 || got[0].ID != "p0-rebase" {
+		t.Errorf("expected non-squash top MR to travel alone, got %v", got)
+	}
+
+	got := selectTrain(mrs[1:], 3, MergeStrategySquash, DefaultScoreConfig(), now)
+	if len(got) != 2 || got[0].ID != "p1" || got[1].ID != "p3" {
+		t.Errorf("expected [p1 p3], got %v", got)
+	}
+
+	// With a non-squash rig default, only explicit squash MRs could stack.
+	if got := selectTrain(mrs[1:], 3, MergeStrategyFF, DefaultScoreConfig(), now); len(got) != 1 || got[0].ID != "p1" {
+		t.Errorf("expected p1 alone under ff default, got %v", got)
+	}
+}
+
+func TestEngineer_LoadConfig_MergeStrategyFromSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	settings := `{"type": "rig-settings", "version": 1, "merge_queue": {"merge_strategy": "merge"}}`
+	if err := os.MkdirAll(filepath.Join(tmpDir, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.RigSettingsPath(tmpDir), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.Config().MergeStrategy != MergeStrategyMerge {
+		t.Errorf("MergeStrategy = %q, want %q from settings", e.Config().MergeStrategy, MergeStrategyMerge)
+	}
+
+	// config.json still overrides it.
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(`{"merge_queue": {"merge_strategy": "ff"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e = NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.Config().MergeStrategy != MergeStrategyFF {
+		t.Errorf("MergeStrategy = %q, want config.json's %q", e.Config().MergeStrategy, MergeStrategyFF)
+	}
+}
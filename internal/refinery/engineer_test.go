@@ -13,7 +13,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tracing"
 )
 
 func TestDefaultMergeQueueConfig(t *testing.T) {
@@ -426,6 +428,60 @@ func TestRunGate_Timeout(t *testing.T) {
 	}
 }
 
+func TestRunGate_Traced(t *testing.T) {
+	townRoot := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: filepath.Join(townRoot, "test-rig")}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.tracer = tracing.NewWithConfig(townRoot, &config.TracingConfig{Exporter: tracing.ExporterFile})
+
+	// Untraced MRs don't get gate spans
+	e.runGate(context.Background(), "untraced", &GateConfig{Cmd: "true"})
+
+	mrSpan := e.tracer.Start("refinery.process_mr", tracing.SpanContext{})
+	ctx := tracing.ContextWithSpan(context.Background(), mrSpan)
+	e.runGate(ctx, "test", &GateConfig{Cmd: "true"})
+	e.runGate(ctx, "lint", &GateConfig{Cmd: "exit 1"})
+
+	data, err := os.ReadFile(filepath.Join(townRoot, tracing.DefaultFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d spans, want 2:\n%s", len(lines), data)
+	}
+	for i, want := range []struct {
+		name   string
+		failed bool
+	}{{"gate test", false}, {"gate lint", true}} {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						TraceID      string `json:"traceId"`
+						ParentSpanID string `json:"parentSpanId"`
+						Name         string `json:"name"`
+						Status       struct {
+							Code int `json:"code"`
+						} `json:"status"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.Unmarshal([]byte(lines[i]), &req); err != nil {
+			t.Fatal(err)
+		}
+		span := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		if span.Name != want.name || span.TraceID != mrSpan.Context().TraceID || span.ParentSpanID != mrSpan.Context().SpanID {
+			t.Errorf("span %d = %+v, want %q under %+v", i, span, want.name, mrSpan.Context())
+		}
+		if failed := span.Status.Code == 2; failed != want.failed {
+			t.Errorf("span %q failed = %v, want %v", span.Name, failed, want.failed)
+		}
+	}
+}
+
 func TestRunGates_Sequential_AllPass(t *testing.T) {
 	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
 	e := NewEngineer(r)
@@ -438,7 +494,7 @@ func TestRunGates_Sequential_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -462,7 +518,7 @@ func TestRunGates_Sequential_StopsOnFirstFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if result.Success {
 		t.Error("expected failure")
 	}
@@ -489,7 +545,7 @@ func TestRunGates_Parallel_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -507,7 +563,7 @@ func TestRunGates_Parallel_AnyFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if result.Success {
 		t.Error("expected failure when any gate fails")
 	}
@@ -526,7 +582,7 @@ func TestRunGates_Empty(t *testing.T) {
 	e.output = io.Discard
 	e.config.Gates = nil
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Error("expected success with no gates configured")
 	}
@@ -0,0 +1,212 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the per-rig flaky test ledger.
+
+package refinery
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+)
+
+// DefaultFlakeQuarantineAfter is how many recorded flakes put a test in
+// quarantine automatically. Can be overridden per-rig via
+// MergeQueueConfig.FlakeQuarantineAfter; zero disables auto-quarantine.
+const DefaultFlakeQuarantineAfter = 3
+
+// flakeLedgerFile is the ledger's file name under <rig>/.runtime/.
+const flakeLedgerFile = "refinery-flakes.json"
+
+// FlakeRecord tracks one test that failed and then passed on retry.
+type FlakeRecord struct {
+	// Test is the failing test as reported in MERGE_FAILED (e.g.
+	// "TestFoo (example.com/pkg)"), or "[gate <name>]" when the gate's
+	// output had no parseable test names.
+	Test string `json:"test"`
+
+	// Gate is the gate the test last flaked in.
+	Gate string `json:"gate"`
+
+	// Count is how many times the test has flaked.
+	Count int `json:"count"`
+
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+
+	// Quarantined tests are ignored by gates with quarantine enabled.
+	Quarantined bool `json:"quarantined,omitempty"`
+}
+
+// flakeLedgerState is the on-disk ledger, keyed by test name.
+type flakeLedgerState struct {
+	Tests map[string]*FlakeRecord `json:"tests"`
+}
+
+func newFlakeLedgerState() *flakeLedgerState {
+	return &flakeLedgerState{Tests: make(map[string]*FlakeRecord)}
+}
+
+// FlakeLedger is a rig's flaky test ledger. Safe for use by parallel gates
+// and concurrent processes.
+type FlakeLedger struct {
+	mu    sync.Mutex
+	store *agent.StateManager[flakeLedgerState]
+
+	// quarantineAfter auto-quarantines a test once its Count reaches it.
+	// Zero means tests are only quarantined by hand.
+	quarantineAfter int
+}
+
+// NewFlakeLedger returns the flake ledger for the rig at rigPath.
+func NewFlakeLedger(rigPath string, quarantineAfter int) *FlakeLedger {
+	return &FlakeLedger{
+		store:           agent.NewStateManager[flakeLedgerState](rigPath, flakeLedgerFile, newFlakeLedgerState),
+		quarantineAfter: quarantineAfter,
+	}
+}
+
+// gateFlakeName is the ledger key for a flaky gate whose output named no tests.
+func gateFlakeName(gate string) string {
+	return fmt.Sprintf("[gate %s]", gate)
+}
+
+// RecordFlakes records that tests failed in gate and then passed on retry.
+// Returns the tests that were newly quarantined by this call.
+func (l *FlakeLedger) RecordFlakes(gate string, tests []string, now time.Time) ([]string, error) {
+	if len(tests) == 0 {
+		return nil, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fl, err := lockStateFile(l.store.StateFile())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var quarantined []string
+	for _, test := range tests {
+		rec, ok := state.Tests[test]
+		if !ok {
+			rec = &FlakeRecord{Test: test, FirstSeen: now}
+			state.Tests[test] = rec
+		}
+		rec.Gate = gate
+		rec.Count++
+		rec.LastSeen = now
+		if !rec.Quarantined && l.quarantineAfter > 0 && rec.Count >= l.quarantineAfter {
+			rec.Quarantined = true
+			quarantined = append(quarantined, test)
+		}
+	}
+	return quarantined, l.store.Save(state)
+}
+
+// List returns all flake records, worst offenders (highest count) first.
+func (l *FlakeLedger) List() ([]*FlakeRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*FlakeRecord, 0, len(state.Tests))
+	for _, rec := range state.Tests {
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Count != records[j].Count {
+			return records[i].Count > records[j].Count
+		}
+		return records[i].LastSeen.After(records[j].LastSeen)
+	})
+	return records, nil
+}
+
+// Quarantined returns the set of quarantined test names.
+func (l *FlakeLedger) Quarantined() (map[string]bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	for name, rec := range state.Tests {
+		if rec.Quarantined {
+			set[name] = true
+		}
+	}
+	return set, nil
+}
+
+// SetQuarantined puts a test in or out of quarantine. Quarantining a test that
+// has never flaked adds it to the ledger with a zero count.
+func (l *FlakeLedger) SetQuarantined(test string, quarantined bool, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fl, err := lockStateFile(l.store.StateFile())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := l.load()
+	if err != nil {
+		return err
+	}
+	rec, ok := state.Tests[test]
+	if !ok {
+		if !quarantined {
+			return fmt.Errorf("test %q is not in the flake ledger", test)
+		}
+		rec = &FlakeRecord{Test: test, FirstSeen: now, LastSeen: now}
+		state.Tests[test] = rec
+	}
+	rec.Quarantined = quarantined
+	return l.store.Save(state)
+}
+
+func (l *FlakeLedger) load() (*flakeLedgerState, error) {
+	state, err := l.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading flake ledger: %w", err)
+	}
+	if state.Tests == nil {
+		state.Tests = make(map[string]*FlakeRecord)
+	}
+	return state, nil
+}
+
+// allIn reports whether every name is in set.
+func allIn(names []string, set map[string]bool) bool {
+	for _, n := range names {
+		if !set[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupe returns names with duplicates removed, keeping first occurrences.
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
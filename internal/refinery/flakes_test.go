@@ -0,0 +1,164 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFlakeLedger_RecordAndAutoQuarantine(t *testing.T) {
+	l := NewFlakeLedger(t.TempDir(), 2)
+	now := time.Now()
+
+	q, err := l.RecordFlakes("test", []string{"TestA (pkg)", "TestB (pkg)"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q) != 0 {
+		t.Errorf("expected nothing quarantined after one flake, got %v", q)
+	}
+
+	q, err = l.RecordFlakes("test", []string{"TestA (pkg)"}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q) != 1 || q[0] != "TestA (pkg)" {
+		t.Errorf("expected TestA quarantined at threshold, got %v", q)
+	}
+
+	records, err := l.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Test != "TestA (pkg)" || records[0].Count != 2 {
+		t.Fatalf("expected TestA first with count 2, got %+v", records)
+	}
+	if !records[0].LastSeen.Equal(now.Add(time.Minute)) || !records[0].FirstSeen.Equal(now) {
+		t.Errorf("unexpected timestamps: %+v", records[0])
+	}
+
+	if err := l.SetQuarantined("TestA (pkg)", false, now); err != nil {
+		t.Fatal(err)
+	}
+	set, err := l.Quarantined()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 0 {
+		t.Errorf("expected no quarantined tests after release, got %v", set)
+	}
+
+	if err := l.SetQuarantined("TestNever (pkg)", false, now); err == nil {
+		t.Error("expected error releasing a test that isn't in the ledger")
+	}
+}
+
+func TestFlakeLedger_ConcurrentLedgersShareFile(t *testing.T) {
+	// Parallel workers each open their own ledger on the rig's file
+	rigPath := t.TempDir()
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := NewFlakeLedger(rigPath, 0)
+			for j := 0; j < 10; j++ {
+				if _, err := l.RecordFlakes("test", []string{fmt.Sprintf("Test%d_%d (pkg)", i, j)}, now); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := NewFlakeLedger(rigPath, 0).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 80 {
+		t.Errorf("expected 80 recorded flakes, got %d", len(records))
+	}
+}
+
+// flakyGateCmd fails with a go test -json failure for TestFlaky on its first
+// run and passes afterwards, using marker as its memory.
+func flakyGateCmd(marker string) string {
+	return fmt.Sprintf(`if [ -f %[1]s ]; then exit 0; fi; touch %[1]s; `+
+		`echo '{"Action":"fail","Package":"example.com/app","Test":"TestFlaky"}'; exit 1`, marker)
+}
+
+func TestRunGates_RecordsFlakeOnRetryPass(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+	e.config.RetryFlakyTests = 2
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: flakyGateCmd(filepath.Join(t.TempDir(), "ran"))},
+	}
+
+	if r := e.runGates(context.Background(), nil); !r.Success {
+		t.Fatalf("expected pass on retry, got %s", r.Error)
+	}
+
+	records, err := e.flakeLedger().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Test != "TestFlaky (example.com/app)" || records[0].Gate != "test" {
+		t.Errorf("expected TestFlaky recorded for gate test, got %+v", records)
+	}
+}
+
+func TestRunGates_NoRetryMeansNoFlake(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+	e.config.RetryFlakyTests = 1
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: flakyGateCmd(filepath.Join(t.TempDir(), "ran"))},
+	}
+
+	if r := e.runGates(context.Background(), nil); r.Success {
+		t.Fatal("expected failure without retries")
+	}
+	if records, _ := e.flakeLedger().List(); len(records) != 0 {
+		t.Errorf("expected no flakes recorded, got %+v", records)
+	}
+}
+
+func TestRunGates_QuarantineIgnoresOnlyQuarantinedFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+	if err := e.flakeLedger().SetQuarantined("TestFlaky (example.com/app)", true, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	failFlaky := `echo '{"Action":"fail","Package":"example.com/app","Test":"TestFlaky"}'; exit 1`
+	failBoth := `echo '{"Action":"fail","Package":"example.com/app","Test":"TestFlaky"}'; ` +
+		`echo '{"Action":"fail","Package":"example.com/app","Test":"TestReal"}'; exit 1`
+
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: failFlaky, Quarantine: true}}
+	r := e.runGates(context.Background(), nil)
+	if !r.Success {
+		t.Errorf("expected quarantined-only failure to pass, got %s", r.Error)
+	}
+
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: failBoth, Quarantine: true}}
+	if r := e.runGates(context.Background(), nil); r.Success {
+		t.Error("expected failure when a non-quarantined test fails")
+	}
+
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: failFlaky}}
+	if r := e.runGates(context.Background(), nil); r.Success {
+		t.Error("expected gate without quarantine to fail")
+	}
+}
@@ -0,0 +1,390 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains merge freezes: declarative windows from rig settings
+// and manual freezes set with gt mq freeze.
+
+package refinery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// freezeStoreFile is the manual freeze list's file name under <rig>/.runtime/.
+const freezeStoreFile = "refinery-freezes.json"
+
+// Freeze sources, as reported in FreezeStatus.Source.
+const (
+	FreezeSourceWindow = "window"
+	FreezeSourceManual = "manual"
+)
+
+// FreezeWindow is a compiled config.FreezeWindow from merge_queue.freeze_windows.
+type FreezeWindow struct {
+	// Branches are target branch globs; empty matches every target.
+	Branches []string
+	Reason   string
+
+	// Start and End bound a one-off window. Start is zero when the window
+	// was already in effect.
+	Start time.Time
+	End   time.Time
+
+	// Recurring windows start on Days (all days if empty) at From and end
+	// at To, both minutes after midnight in Location. A To before From
+	// wraps past midnight.
+	Recurring bool
+	Days      map[time.Weekday]bool
+	From      int
+	To        int
+	Location  *time.Location
+}
+
+// CompileFreezeWindow parses a freeze window from rig settings.
+func CompileFreezeWindow(w config.FreezeWindow) (FreezeWindow, error) {
+	out := FreezeWindow{Branches: w.Branches, Reason: w.Reason}
+	if !w.Recurring() {
+		end, err := time.Parse(time.RFC3339, w.End)
+		if err != nil {
+			return out, fmt.Errorf("invalid end %q: %w", w.End, err)
+		}
+		out.End = end
+		if w.Start != "" {
+			if out.Start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+				return out, fmt.Errorf("invalid start %q: %w", w.Start, err)
+			}
+		}
+		return out, nil
+	}
+
+	out.Recurring = true
+	out.Days = make(map[time.Weekday]bool, len(w.Days))
+	for _, d := range w.Days {
+		day, ok := config.FreezeWeekdays[strings.ToLower(d)]
+		if !ok {
+			return out, fmt.Errorf("invalid day %q", d)
+		}
+		out.Days[day] = true
+	}
+	out.From, out.To = 0, 24*60
+	if w.From != "" {
+		var err error
+		if out.From, err = config.ParseFreezeClock(w.From); err != nil {
+			return out, err
+		}
+		if out.To, err = config.ParseFreezeClock(w.To); err != nil {
+			return out, err
+		}
+	}
+	out.Location = time.Local
+	if w.Timezone != "" {
+		loc, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return out, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+		out.Location = loc
+	}
+	return out, nil
+}
+
+// LoadFreezeWindows returns the compiled merge_queue.freeze_windows of the
+// rig at rigPath. A rig without settings has none.
+func LoadFreezeWindows(rigPath string) ([]FreezeWindow, error) {
+	mq, err := loadMergeQueueSettings(rigPath)
+	if err != nil || mq == nil {
+		return nil, err
+	}
+	windows := make([]FreezeWindow, 0, len(mq.FreezeWindows))
+	for i, w := range mq.FreezeWindows {
+		compiled, err := CompileFreezeWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("freeze_windows[%d]: %w", i, err)
+		}
+		windows = append(windows, compiled)
+	}
+	return windows, nil
+}
+
+// appliesTo reports whether the window freezes the target branch.
+func (w *FreezeWindow) appliesTo(branch string) bool {
+	return freezeMatches(w.Branches, branch)
+}
+
+// Active reports whether the window is in effect at now, and if so when the
+// current occurrence ends.
+func (w *FreezeWindow) Active(now time.Time) (bool, time.Time) {
+	if !w.Recurring {
+		if (w.Start.IsZero() || !now.Before(w.Start)) && now.Before(w.End) {
+			return true, w.End
+		}
+		return false, time.Time{}
+	}
+
+	t := now.In(w.Location)
+	y, m, d := t.Date()
+	minute := t.Hour()*60 + t.Minute()
+	onDay := func(day time.Weekday) bool {
+		return len(w.Days) == 0 || w.Days[day]
+	}
+	// time.Date normalizes minutes past 59, so this is DST-safe enough for
+	// wall-clock windows.
+	at := func(dayOffset, minutes int) time.Time {
+		return time.Date(y, m, d+dayOffset, 0, minutes, 0, 0, w.Location)
+	}
+
+	if w.From < w.To {
+		if onDay(t.Weekday()) && minute >= w.From && minute < w.To {
+			return true, at(0, w.To)
+		}
+		return false, time.Time{}
+	}
+	// Wraps past midnight: started today, or started yesterday and runs
+	// into this morning.
+	if onDay(t.Weekday()) && minute >= w.From {
+		return true, at(1, w.To)
+	}
+	if onDay((t.Weekday()+6)%7) && minute < w.To {
+		return true, at(0, w.To)
+	}
+	return false, time.Time{}
+}
+
+// Freeze is a manual freeze set with gt mq freeze.
+type Freeze struct {
+	// Branch is the frozen target, or a glob of targets.
+	Branch string `json:"branch"`
+
+	// Until is when the freeze lifts on its own. Zero means it holds until
+	// lifted with gt mq unfreeze.
+	Until time.Time `json:"until,omitempty"`
+
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// expired reports whether the freeze has lifted on its own by now.
+func (f *Freeze) expired(now time.Time) bool {
+	return !f.Until.IsZero() && !now.Before(f.Until)
+}
+
+// freezeStoreState is the on-disk manual freeze list.
+type freezeStoreState struct {
+	Freezes []Freeze `json:"freezes"`
+}
+
+func newFreezeStoreState() *freezeStoreState {
+	return &freezeStoreState{}
+}
+
+// FreezeStore is a rig's list of manual freezes. gt mq freeze and gt mq
+// unfreeze change it while the refinery reads it, so Add and Lift hold the
+// state file lock and List never writes.
+type FreezeStore struct {
+	mu    sync.Mutex
+	store *agent.StateManager[freezeStoreState]
+}
+
+// NewFreezeStore returns the manual freeze list for the rig at rigPath.
+func NewFreezeStore(rigPath string) *FreezeStore {
+	return &FreezeStore{
+		store: agent.NewStateManager[freezeStoreState](rigPath, freezeStoreFile, newFreezeStoreState),
+	}
+}
+
+// Add freezes f.Branch, replacing any existing manual freeze on it.
+func (s *FreezeStore) Add(f Freeze) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fl, err := lockStateFile(s.store.StateFile())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := state.Freezes[:0]
+	for _, existing := range state.Freezes {
+		if existing.Branch != f.Branch && !existing.expired(f.At) {
+			kept = append(kept, existing)
+		}
+	}
+	state.Freezes = append(kept, f)
+	return s.store.Save(state)
+}
+
+// Lift removes the manual freeze on branch (all manual freezes if branch is
+// empty) and returns the freezes removed. Expired freezes are pruned too.
+func (s *FreezeStore) Lift(branch string) ([]Freeze, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fl, err := lockStateFile(s.store.StateFile())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var kept, lifted []Freeze
+	for _, f := range state.Freezes {
+		switch {
+		case f.expired(now):
+		case branch == "" || f.Branch == branch:
+			lifted = append(lifted, f)
+		default:
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) == len(state.Freezes) {
+		return nil, nil
+	}
+	state.Freezes = kept
+	return lifted, s.store.Save(state)
+}
+
+// List returns the manual freezes still in effect at now, oldest first.
+// Expired freezes are left out but stay in the file until the next Add or
+// Lift prunes them, so readers never write.
+func (s *FreezeStore) List(now time.Time) ([]Freeze, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var active []Freeze
+	for _, f := range state.Freezes {
+		if !f.expired(now) {
+			active = append(active, f)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool { return active[i].At.Before(active[j].At) })
+	return active, nil
+}
+
+func (s *FreezeStore) load() (*freezeStoreState, error) {
+	state, err := s.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading freezes: %w", err)
+	}
+	return state, nil
+}
+
+// FreezeStatus describes a freeze holding pushes to a target branch.
+type FreezeStatus struct {
+	Branch string `json:"branch"`
+	Reason string `json:"reason,omitempty"`
+
+	// Source is FreezeSourceWindow or FreezeSourceManual.
+	Source string `json:"source"`
+
+	// Until is when the freeze is expected to lift; zero means it holds
+	// until lifted by hand.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// String describes the freeze for logs and held MRs.
+func (s *FreezeStatus) String() string {
+	msg := fmt.Sprintf("%s is frozen (%s", s.Branch, s.Source)
+	if s.Reason != "" {
+		msg += ": " + s.Reason
+	}
+	msg += ")"
+	if s.Until.IsZero() {
+		return msg + " until lifted"
+	}
+	return msg + " until " + s.Until.Format(time.RFC3339)
+}
+
+// ActiveFreeze returns the freeze holding pushes to target at now, or nil if
+// target isn't frozen. It checks the rig's manual freezes and windows; when
+// several apply, the one lasting longest wins.
+func ActiveFreeze(rigPath string, windows []FreezeWindow, target string, now time.Time) (*FreezeStatus, error) {
+	var best *FreezeStatus
+	consider := func(s *FreezeStatus) {
+		switch {
+		case best == nil, s.Until.IsZero() && !best.Until.IsZero():
+			best = s
+		case !best.Until.IsZero() && s.Until.After(best.Until):
+			best = s
+		}
+	}
+
+	for i := range windows {
+		w := &windows[i]
+		if !w.appliesTo(target) {
+			continue
+		}
+		if ok, until := w.Active(now); ok {
+			consider(&FreezeStatus{Branch: target, Reason: w.Reason, Source: FreezeSourceWindow, Until: until})
+		}
+	}
+
+	freezes, err := NewFreezeStore(rigPath).List(now)
+	if err != nil {
+		return best, err
+	}
+	for _, f := range freezes {
+		if freezeMatches([]string{f.Branch}, target) {
+			consider(&FreezeStatus{Branch: target, Reason: f.Reason, Source: FreezeSourceManual, Until: f.Until})
+		}
+	}
+	return best, nil
+}
+
+// freezeMatches reports whether branch matches any of patterns; no patterns
+// match every branch.
+func freezeMatches(patterns []string, branch string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if matchGlob(p, branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeFreeze returns the freeze holding pushes to target now, or nil. A
+// manual freeze list that can't be read is reported and ignored, so a broken
+// file can't stall the queue.
+func (e *Engineer) activeFreeze(target string) *FreezeStatus {
+	status, err := ActiveFreeze(e.rig.Path, e.config.FreezeWindows, target, time.Now())
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: checking merge freezes: %v\n", err)
+	}
+	return status
+}
+
+// withoutFrozen drops MRs whose target is frozen now, so a batch or train
+// isn't filled with MRs that can't land while better candidates for other
+// targets wait. The dropped MRs stay queued until the freeze lifts.
+func (e *Engineer) withoutFrozen(mrs []*MRInfo) []*MRInfo {
+	frozen := make(map[string]bool)
+	var open []*MRInfo
+	for _, mr := range mrs {
+		isFrozen, seen := frozen[mr.Target]
+		if !seen {
+			isFrozen = e.activeFreeze(mr.Target) != nil
+			frozen[mr.Target] = isFrozen
+		}
+		if !isFrozen {
+			open = append(open, mr)
+		}
+	}
+	return open
+}
@@ -0,0 +1,226 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func mustCompileFreezeWindow(t *testing.T, w config.FreezeWindow) FreezeWindow {
+	t.Helper()
+	compiled, err := CompileFreezeWindow(w)
+	if err != nil {
+		t.Fatalf("CompileFreezeWindow(%+v): %v", w, err)
+	}
+	return compiled
+}
+
+func TestFreezeWindow_OneOff(t *testing.T) {
+	w := mustCompileFreezeWindow(t, config.FreezeWindow{
+		Start: "2026-12-20T00:00:00Z",
+		End:   "2027-01-04T00:00:00Z",
+	})
+
+	for _, tc := range []struct {
+		at     string
+		active bool
+	}{
+		{"2026-12-19T23:59:00Z", false},
+		{"2026-12-20T00:00:00Z", true},
+		{"2027-01-03T12:00:00Z", true},
+		{"2027-01-04T00:00:00Z", false},
+	} {
+		now, _ := time.Parse(time.RFC3339, tc.at)
+		active, until := w.Active(now)
+		if active != tc.active {
+			t.Errorf("Active(%s) = %v, want %v", tc.at, active, tc.active)
+		}
+		if active && !until.Equal(w.End) {
+			t.Errorf("Active(%s) until %v, want %v", tc.at, until, w.End)
+		}
+	}
+}
+
+func TestFreezeWindow_Recurring(t *testing.T) {
+	// No merges from Friday 16:00 until Saturday 09:00.
+	w := mustCompileFreezeWindow(t, config.FreezeWindow{
+		Days:     []string{"fri"},
+		From:     "16:00",
+		To:       "09:00",
+		Timezone: "UTC",
+	})
+
+	at := func(day, hour int) time.Time {
+		// 2026-01-02 is a Friday.
+		return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		now    time.Time
+		active bool
+		until  time.Time
+	}{
+		{at(2, 15), false, time.Time{}},
+		{at(2, 16), true, at(3, 9)},
+		{at(3, 8), true, at(3, 9)}, // Saturday morning, started Friday
+		{at(3, 9), false, time.Time{}},
+		{at(3, 17), false, time.Time{}}, // Saturday evening: not a freeze day
+		{at(1, 17), false, time.Time{}}, // Thursday
+	} {
+		active, until := w.Active(tc.now)
+		if active != tc.active || !until.Equal(tc.until) {
+			t.Errorf("Active(%v) = %v until %v, want %v until %v", tc.now, active, until, tc.active, tc.until)
+		}
+	}
+
+	weekend := mustCompileFreezeWindow(t, config.FreezeWindow{Days: []string{"sat", "sun"}, Timezone: "UTC"})
+	if active, until := weekend.Active(at(4, 13)); !active || !until.Equal(at(5, 0)) {
+		t.Errorf("whole-day window on Sunday = %v until %v, want active until midnight", active, until)
+	}
+	if active, _ := weekend.Active(at(5, 13)); active {
+		t.Error("whole-day weekend window active on Monday")
+	}
+}
+
+func TestFreezeStore_AddLiftExpire(t *testing.T) {
+	store := NewFreezeStore(t.TempDir())
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	if err := store.Add(Freeze{Branch: "main", Reason: "release", At: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(Freeze{Branch: "release/*", Until: now.Add(time.Hour), At: now}); err != nil {
+		t.Fatal(err)
+	}
+	// Re-freezing a branch replaces its freeze.
+	if err := store.Add(Freeze{Branch: "main", Reason: "incident", At: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	freezes, err := store.List(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(freezes) != 2 || freezes[1].Reason != "incident" {
+		t.Fatalf("List = %+v, want main (incident) and release/*", freezes)
+	}
+
+	if freezes, _ = store.List(now.Add(2 * time.Hour)); len(freezes) != 1 || freezes[0].Branch != "main" {
+		t.Errorf("expected the timed freeze to expire, got %+v", freezes)
+	}
+
+	lifted, err := store.Lift("main")
+	if err != nil || len(lifted) != 1 {
+		t.Fatalf("Lift(main) = %+v, %v", lifted, err)
+	}
+	if freezes, _ = store.List(now); len(freezes) != 0 {
+		t.Errorf("expected no freezes after lift, got %+v", freezes)
+	}
+}
+
+func TestFreezeStore_ConcurrentStoresShareFile(t *testing.T) {
+	// gt mq freeze and the refinery each open their own store on the file
+	rigPath := t.TempDir()
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store := NewFreezeStore(rigPath)
+			for j := 0; j < 5; j++ {
+				if err := store.Add(Freeze{Branch: fmt.Sprintf("release/%d.%d", i, j), At: now}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	freezes, err := NewFreezeStore(rigPath).List(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(freezes) != 40 {
+		t.Errorf("expected 40 freezes, got %d", len(freezes))
+	}
+}
+
+func TestActiveFreeze(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	windows := []FreezeWindow{
+		mustCompileFreezeWindow(t, config.FreezeWindow{
+			Branches: []string{"release/*"},
+			End:      "2026-01-11T00:00:00Z",
+			Reason:   "release cut",
+		}),
+	}
+
+	if s, err := ActiveFreeze(rigPath, windows, "main", now); err != nil || s != nil {
+		t.Fatalf("main should not be frozen, got %+v, %v", s, err)
+	}
+	s, err := ActiveFreeze(rigPath, windows, "release/v2", now)
+	if err != nil || s == nil || s.Source != FreezeSourceWindow || s.Reason != "release cut" {
+		t.Fatalf("release/v2 freeze = %+v, %v", s, err)
+	}
+
+	// An open-ended manual freeze outlasts the window.
+	if err := NewFreezeStore(rigPath).Add(Freeze{Branch: "release/v2", Reason: "hold", At: now}); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = ActiveFreeze(rigPath, windows, "release/v2", now)
+	if s == nil || s.Source != FreezeSourceManual || !s.Until.IsZero() {
+		t.Errorf("expected the manual freeze to win, got %+v", s)
+	}
+}
+
+func TestWithoutFrozen(t *testing.T) {
+	e, _ := newGitTestEngineer(t)
+	if err := NewFreezeStore(e.rig.Path).Add(Freeze{Branch: "release/*", Reason: "release cut", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	mrs := []*MRInfo{
+		{ID: "mr-1", Target: "release/v2"},
+		{ID: "mr-2", Target: "main"},
+		{ID: "mr-3", Target: "release/v3"},
+		{ID: "mr-4", Target: "main"},
+	}
+	got := e.withoutFrozen(mrs)
+	if len(got) != 2 || got[0].ID != "mr-2" || got[1].ID != "mr-4" {
+		t.Errorf("withoutFrozen() = %v, want mr-2 and mr-4", got)
+	}
+}
+
+func TestProcessMRInfo_FrozenTargetHoldsPush(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addBranch(t, dir, "polecat/nux", "nux.txt", "nux\n")
+	before := runGit(t, dir, "rev-parse", "origin/main")
+
+	if err := NewFreezeStore(e.rig.Path).Add(Freeze{Branch: "main", Reason: "release", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}
+	r := e.ProcessMRInfo(context.Background(), mr)
+	if r.Success || !r.Frozen {
+		t.Fatalf("expected the push held by the freeze, got %+v", r)
+	}
+	if after := runGit(t, dir, "ls-remote", "origin", "refs/heads/main"); after[:40] != before {
+		t.Errorf("origin/main moved during a freeze: %s -> %s", before, after[:40])
+	}
+	if head := runGit(t, dir, "rev-parse", "main"); head != before {
+		t.Errorf("local main not reset after the hold: %s, want %s", head, before)
+	}
+
+	if _, err := NewFreezeStore(e.rig.Path).Lift("main"); err != nil {
+		t.Fatal(err)
+	}
+	if r := e.ProcessMRInfo(context.Background(), mr); !r.Success {
+		t.Fatalf("expected the MR to land once the freeze lifted, got %+v", r)
+	}
+}
@@ -0,0 +1,129 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the per-rig gate result cache.
+
+package refinery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+)
+
+// DefaultGateCacheTTL is how long a gate pass on a given tree is trusted.
+// Can be overridden per-rig via MergeQueueConfig.GateCacheTTL; zero disables
+// the cache.
+const DefaultGateCacheTTL = 24 * time.Hour
+
+// gateCacheFile is the cache's file name under <rig>/.runtime/.
+const gateCacheFile = "refinery-gate-cache.json"
+
+// gateCacheMaxEntries bounds the cache file. Oldest entries are evicted first.
+const gateCacheMaxEntries = 500
+
+// gateCacheEntry records a gate that passed on a specific tree.
+type gateCacheEntry struct {
+	Tree     string        `json:"tree"`
+	Gate     string        `json:"gate"`
+	PassedAt time.Time     `json:"passed_at"`
+	Elapsed  time.Duration `json:"elapsed"`
+}
+
+// gateCacheState is the on-disk cache, keyed by gateCacheKey(tree, cmd).
+// Only passes are recorded: a failure is never trusted as final, since the
+// gate may be flaky or the environment may have been broken.
+type gateCacheState struct {
+	Entries map[string]*gateCacheEntry `json:"entries"`
+}
+
+func newGateCacheState() *gateCacheState {
+	return &gateCacheState{Entries: make(map[string]*gateCacheEntry)}
+}
+
+// gateCache is a rig's gate result cache. Safe for use by parallel gates and
+// concurrent processes.
+type gateCache struct {
+	mu    sync.Mutex
+	store *agent.StateManager[gateCacheState]
+	ttl   time.Duration
+}
+
+func newGateCache(rigPath string, ttl time.Duration) *gateCache {
+	return &gateCache{
+		store: agent.NewStateManager[gateCacheState](rigPath, gateCacheFile, newGateCacheState),
+		ttl:   ttl,
+	}
+}
+
+// gateCacheKey keys a cache entry on the merged tree plus the exact gate
+// command, so editing a gate's command invalidates its cached passes.
+func gateCacheKey(tree, cmd string) string {
+	sum := sha256.Sum256([]byte(cmd))
+	return tree + ":" + hex.EncodeToString(sum[:8])
+}
+
+// Lookup reports whether cmd has a fresh cached pass on tree.
+func (c *gateCache) Lookup(tree, cmd string, now time.Time) bool {
+	if c == nil || tree == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, err := c.store.Load()
+	if err != nil || state.Entries == nil {
+		return false
+	}
+	entry, ok := state.Entries[gateCacheKey(tree, cmd)]
+	return ok && now.Sub(entry.PassedAt) <= c.ttl
+}
+
+// RecordPass stores a gate pass for cmd on tree, pruning expired entries and
+// capping the cache size. Errors are swallowed: the cache is an optimization.
+func (c *gateCache) RecordPass(tree, gate, cmd string, elapsed time.Duration, now time.Time) {
+	if c == nil || tree == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fl, err := lockStateFile(c.store.StateFile())
+	if err != nil {
+		return
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := c.store.Load()
+	if err != nil {
+		state = newGateCacheState()
+	}
+	if state.Entries == nil {
+		state.Entries = make(map[string]*gateCacheEntry)
+	}
+
+	state.Entries[gateCacheKey(tree, cmd)] = &gateCacheEntry{
+		Tree:     tree,
+		Gate:     gate,
+		PassedAt: now,
+		Elapsed:  elapsed,
+	}
+
+	for key, entry := range state.Entries {
+		if now.Sub(entry.PassedAt) > c.ttl {
+			delete(state.Entries, key)
+		}
+	}
+	for len(state.Entries) > gateCacheMaxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range state.Entries {
+			if oldestKey == "" || entry.PassedAt.Before(oldest) {
+				oldestKey, oldest = key, entry.PassedAt
+			}
+		}
+		delete(state.Entries, oldestKey)
+	}
+
+	_ = c.store.Save(state)
+}
@@ -0,0 +1,100 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGateCache_LookupAndExpiry(t *testing.T) {
+	c := newGateCache(t.TempDir(), time.Hour)
+	now := time.Now()
+
+	if c.Lookup("tree1", "go test ./...", now) {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	c.RecordPass("tree1", "test", "go test ./...", time.Second, now)
+	if !c.Lookup("tree1", "go test ./...", now) {
+		t.Error("expected hit for same tree and command")
+	}
+	if c.Lookup("tree1", "go test -race ./...", now) {
+		t.Error("expected miss when the gate command changes")
+	}
+	if c.Lookup("tree2", "go test ./...", now) {
+		t.Error("expected miss for a different tree")
+	}
+	if c.Lookup("tree1", "go test ./...", now.Add(2*time.Hour)) {
+		t.Error("expected miss after TTL")
+	}
+
+	var nilCache *gateCache
+	if nilCache.Lookup("tree1", "go test ./...", now) {
+		t.Error("expected nil cache to always miss")
+	}
+}
+
+func TestRunGates_SkipsGatesCachedForTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+
+	// Each real run appends a line to the counter file (outside the repo)
+	counter := filepath.Join(t.TempDir(), "runs")
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: fmt.Sprintf("echo run >> %s", counter)},
+	}
+
+	if r := e.runGates(context.Background(), nil); !r.Success {
+		t.Fatalf("first run failed: %s", r.Error)
+	}
+	if r := e.runGates(context.Background(), nil); !r.Success {
+		t.Fatalf("second run failed: %s", r.Error)
+	}
+	if got := countLines(t, counter); got != 1 {
+		t.Errorf("expected gate to run once on an unchanged tree, ran %d times", got)
+	}
+
+	// A new tree runs the gate again
+	commitFile(t, dir, "change.txt", "x\n", "change")
+	if r := e.runGates(context.Background(), nil); !r.Success {
+		t.Fatalf("third run failed: %s", r.Error)
+	}
+	if got := countLines(t, counter); got != 2 {
+		t.Errorf("expected gate to re-run on a new tree, ran %d times", got)
+	}
+}
+
+func TestRunGates_CacheDisabled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+	e.config.GateCacheTTL = 0
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: fmt.Sprintf("echo run >> %s", counter)},
+	}
+
+	e.runGates(context.Background(), nil)
+	e.runGates(context.Background(), nil)
+	if got := countLines(t, counter); got != 2 {
+		t.Errorf("expected gate to run every time with caching disabled, ran %d times", got)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
@@ -0,0 +1,93 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains path filtering for quality gates.
+
+package refinery
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// validateGlob checks that pattern is a well-formed gate path glob.
+func validateGlob(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "**" {
+			continue
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchGlob reports whether the slash-separated file path matches pattern.
+// Patterns use path.Match syntax per segment, plus "**" to match any number
+// of directories (including none): "docs/**", "**/*.md", "web/**/*.ts".
+func matchGlob(pattern, file string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pats, segs []string) bool {
+	for len(pats) > 0 {
+		if pats[0] == "**" {
+			// Collapse runs of "**" and try every possible split point.
+			for len(pats) > 0 && pats[0] == "**" {
+				pats = pats[1:]
+			}
+			if len(pats) == 0 {
+				return true
+			}
+			for i := range segs {
+				if matchSegments(pats, segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pats[0], segs[0]); !ok {
+			return false
+		}
+		pats, segs = pats[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+func matchAnyGlob(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo reports whether the gate should run for a change touching files.
+// A gate with no Include/Exclude always applies, as does any gate when the
+// changed files are unknown (nil). Otherwise the gate applies if at least one
+// changed file matches Include (or Include is empty) and no Exclude pattern.
+func (g *GateConfig) appliesTo(files []string) bool {
+	if len(g.Include) == 0 && len(g.Exclude) == 0 {
+		return true
+	}
+	if files == nil {
+		return true
+	}
+	for _, f := range files {
+		if len(g.Include) > 0 && !matchAnyGlob(g.Include, f) {
+			continue
+		}
+		if matchAnyGlob(g.Exclude, f) {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"docs/**", "docs/guide.md", true},
+		{"docs/**", "docs/a/b/c.md", true},
+		{"docs/**", "src/docs.go", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/a/guide.md", true},
+		{"**/*.md", "main.go", false},
+		{"web/**/*.ts", "web/app.ts", true},
+		{"web/**/*.ts", "web/src/ui/app.ts", true},
+		{"web/**/*.ts", "api/app.ts", false},
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"infra/terraform/*", "infra/terraform/main.tf", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestGateConfig_AppliesTo(t *testing.T) {
+	backend := &GateConfig{Include: []string{"api/**", "go.mod"}, Exclude: []string{"**/*.md"}}
+	docsOnly := []string{"README.md", "api/README.md"}
+	mixed := []string{"api/README.md", "api/server.go"}
+
+	if backend.appliesTo(docsOnly) {
+		t.Error("expected backend gate to skip a docs-only change")
+	}
+	if !backend.appliesTo(mixed) {
+		t.Error("expected backend gate to apply when a Go file changed")
+	}
+	if !backend.appliesTo(nil) {
+		t.Error("expected gate to apply when changed files are unknown")
+	}
+	if !(&GateConfig{}).appliesTo(docsOnly) {
+		t.Error("expected unfiltered gate to always apply")
+	}
+}
+
+func TestRunGates_SkipsNonMatchingGates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; touch with Windows paths breaks under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+
+	markerDir := t.TempDir()
+	e.config.Gates = map[string]*GateConfig{
+		"backend":  {Cmd: fmt.Sprintf("touch %s/backend", markerDir), Include: []string{"api/**"}},
+		"frontend": {Cmd: fmt.Sprintf("touch %s/frontend", markerDir), Include: []string{"web/**"}},
+		"lint":     {Cmd: fmt.Sprintf("touch %s/lint", markerDir), Exclude: []string{"**/*.md"}},
+	}
+
+	result := e.runGates(context.Background(), []string{"web/app.ts", "docs/guide.md"})
+	if !result.Success {
+		t.Fatalf("expected success, got %s", result.Error)
+	}
+
+	for gate, wantRun := range map[string]bool{"backend": false, "frontend": true, "lint": true} {
+		_, err := os.Stat(filepath.Join(markerDir, gate))
+		if ran := err == nil; ran != wantRun {
+			t.Errorf("gate %q ran=%v, want %v", gate, ran, wantRun)
+		}
+	}
+}
+
+func TestRunQualityChecks_FiltersOnMergedChanges(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; touch with Windows paths breaks under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+
+	markerDir := t.TempDir()
+	e.config.Gates = map[string]*GateConfig{
+		"backend": {Cmd: fmt.Sprintf("touch %s/backend", markerDir), Include: []string{"api/**"}},
+	}
+
+	// A docs-only commit on top of origin/main skips the backend gate
+	commitFile(t, dir, "docs/guide.md", "guide\n", "docs: guide")
+	if r := e.runQualityChecks(context.Background(), "origin/main"); !r.Success {
+		t.Fatalf("expected success, got %s", r.Error)
+	}
+	if _, err := os.Stat(filepath.Join(markerDir, "backend")); err == nil {
+		t.Error("expected backend gate to be skipped for a docs-only change")
+	}
+
+	commitFile(t, dir, "api/server.go", "package api\n", "feat: api")
+	if r := e.runQualityChecks(context.Background(), "origin/main"); !r.Success {
+		t.Fatalf("expected success, got %s", r.Error)
+	}
+	if _, err := os.Stat(filepath.Join(markerDir, "backend")); err != nil {
+		t.Error("expected backend gate to run once api/ changed")
+	}
+}
+
+func TestEngineer_LoadConfig_GatePathGlobs(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := []byte(`{"merge_queue": {"gates": {
+		"web": {"cmd": "npm test", "include": ["web/**"], "exclude": ["**/*.md"]}
+	}}}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	gate := e.Config().Gates["web"]
+	if len(gate.Include) != 1 || gate.Include[0] != "web/**" || len(gate.Exclude) != 1 || gate.Exclude[0] != "**/*.md" {
+		t.Errorf("unexpected globs: include=%v exclude=%v", gate.Include, gate.Exclude)
+	}
+
+	data = []byte(`{"merge_queue": {"gates": {"web": {"cmd": "npm test", "include": ["web/[**"]}}}}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for malformed glob")
+	}
+}
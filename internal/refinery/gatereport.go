@@ -0,0 +1,307 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains structured parsing of failed gate output.
+
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// gateOutputMaxBytes caps how much of each gate output stream is kept
+	// in memory for the failure report and log artifact.
+	gateOutputMaxBytes = 1 << 20
+
+	// excerptMaxLines and excerptMaxBytes bound the excerpt carried in
+	// MERGE_FAILED. The full output lives in the gate log artifact.
+	excerptMaxLines = 40
+	excerptMaxBytes = 4000
+
+	// gateLogDir is where gate log artifacts are written, under <rig>/.runtime/.
+	gateLogDir = "gate-logs"
+)
+
+// cappedBuffer is an io.Writer that keeps at most max bytes, dropping the
+// rest, so a runaway gate can't exhaust memory.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.max - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		if room > 0 {
+			c.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+func (c *cappedBuffer) String() string {
+	if c.truncated {
+		return c.buf.String() + "\n[output truncated]\n"
+	}
+	return c.buf.String()
+}
+
+// gateFailureReport is the structured view of a failed gate's output.
+type gateFailureReport struct {
+	// FailingTests lists failing tests as "TestName (package)" for go test
+	// and "classname.name" for JUnit.
+	FailingTests []string
+
+	// Excerpt is a short, human-readable slice of the output around the failures.
+	Excerpt string
+}
+
+// parseGateOutput extracts failing tests and an excerpt from a failed gate's
+// output. go test -json and JUnit XML are recognized; anything else falls
+// back to the tail of the output with no test names.
+func parseGateOutput(stdout, stderr string) gateFailureReport {
+	if r, ok := parseGoTestJSON(stdout); ok {
+		return r
+	}
+	if r, ok := parseJUnitXML(stdout); ok {
+		return r
+	}
+
+	// Unstructured: prefer stderr, which is where most tools report failures.
+	out := stderr
+	if strings.TrimSpace(out) == "" {
+		out = stdout
+	}
+	return gateFailureReport{Excerpt: tailLines(out, excerptMaxLines)}
+}
+
+// goTestEvent is one line of `go test -json` output (see go doc test2json).
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+// parseGoTestJSON parses `go test -json` output. ok is false if the output
+// contains no test events.
+func parseGoTestJSON(out string) (gateFailureReport, bool) {
+	type key struct{ pkg, test string }
+	var (
+		sawEvent bool
+		outputs  = make(map[key][]string)
+		failed   []key
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), gateOutputMaxBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Action == "" {
+			continue
+		}
+		sawEvent = true
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			outputs[k] = append(outputs[k], ev.Output)
+		case "fail":
+			failed = append(failed, k)
+		}
+	}
+	if !sawEvent {
+		return gateFailureReport{}, false
+	}
+
+	// A failing parent test (TestFoo) is implied by its failing subtests
+	// (TestFoo/case), so only report the leaves.
+	isParent := make(map[key]bool)
+	for _, k := range failed {
+		if i := strings.LastIndex(k.test, "/"); i > 0 {
+			isParent[key{k.pkg, k.test[:i]}] = true
+		}
+	}
+
+	var report gateFailureReport
+	var excerpt []string
+	failedTests := make(map[string]bool)
+	for _, k := range failed {
+		if k.test != "" {
+			failedTests[k.pkg] = true
+		}
+	}
+	for _, k := range failed {
+		switch {
+		case k.test == "" && failedTests[k.pkg]:
+			continue // package failed because its tests did
+		case k.test == "":
+			// Package failed with no failing test: build error, panic in init, etc.
+			report.FailingTests = append(report.FailingTests, fmt.Sprintf("%s [package]", k.pkg))
+		case isParent[k]:
+			continue
+		default:
+			report.FailingTests = append(report.FailingTests, fmt.Sprintf("%s (%s)", k.test, k.pkg))
+		}
+		for _, o := range outputs[k] {
+			if strings.HasPrefix(o, "=== RUN") || strings.HasPrefix(o, "=== PAUSE") || strings.HasPrefix(o, "=== CONT") {
+				continue
+			}
+			excerpt = append(excerpt, strings.TrimRight(o, "\n"))
+		}
+	}
+	report.Excerpt = capExcerpt(excerpt)
+	return report, true
+}
+
+// junitTestSuites covers both <testsuites> and bare <testsuite> documents.
+type junitTestSuites struct {
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name   string           `xml:"name,attr"`
+	Cases  []junitTestCase  `xml:"testcase"`
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure"`
+	Error     *junitProblem `xml:"error"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// parseJUnitXML parses a JUnit XML report embedded in out. ok is false if no
+// report is found or it doesn't parse.
+func parseJUnitXML(out string) (gateFailureReport, bool) {
+	start := strings.Index(out, "<testsuite")
+	if start < 0 {
+		return gateFailureReport{}, false
+	}
+	doc := out[start:]
+
+	var suites []junitTestSuite
+	if strings.HasPrefix(doc, "<testsuites") {
+		var ts junitTestSuites
+		if err := xml.NewDecoder(strings.NewReader(doc)).Decode(&ts); err != nil {
+			return gateFailureReport{}, false
+		}
+		suites = ts.Suites
+	} else {
+		var s junitTestSuite
+		if err := xml.NewDecoder(strings.NewReader(doc)).Decode(&s); err != nil {
+			return gateFailureReport{}, false
+		}
+		suites = []junitTestSuite{s}
+	}
+
+	var report gateFailureReport
+	var excerpt []string
+	var walk func([]junitTestSuite)
+	walk = func(suites []junitTestSuite) {
+		for _, s := range suites {
+			for _, c := range s.Cases {
+				problem := c.Failure
+				if problem == nil {
+					problem = c.Error
+				}
+				if problem == nil {
+					continue
+				}
+				name := c.Name
+				if c.Classname != "" {
+					name = c.Classname + "." + c.Name
+				}
+				report.FailingTests = append(report.FailingTests, name)
+				excerpt = append(excerpt, "--- "+name)
+				if msg := strings.TrimSpace(problem.Message); msg != "" {
+					excerpt = append(excerpt, msg)
+				}
+				if text := strings.TrimSpace(problem.Text); text != "" {
+					excerpt = append(excerpt, strings.Split(text, "\n")...)
+				}
+			}
+			walk(s.Suites)
+		}
+	}
+	walk(suites)
+
+	report.Excerpt = capExcerpt(excerpt)
+	return report, true
+}
+
+// capExcerpt joins lines, keeping the first excerptMaxLines lines and at
+// most excerptMaxBytes bytes.
+func capExcerpt(lines []string) string {
+	truncated := false
+	if len(lines) > excerptMaxLines {
+		lines = lines[:excerptMaxLines]
+		truncated = true
+	}
+	s := strings.Join(lines, "\n")
+	if len(s) > excerptMaxBytes {
+		s = s[:excerptMaxBytes]
+		truncated = true
+	}
+	if truncated {
+		s += "\n..."
+	}
+	return s
+}
+
+// tailLines returns the last n lines of s, capped like an excerpt.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return capExcerpt(lines)
+}
+
+// formatGateLog renders the full output of the failed gates for the log artifact.
+func formatGateLog(results []GateResult) string {
+	var sb strings.Builder
+	for _, r := range results {
+		if r.Success {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("=== gate %s: FAILED (%v) - %s\n", r.Name, r.Elapsed, r.Error))
+		sb.WriteString(fmt.Sprintf("--- stdout\n%s\n", strings.TrimRight(r.Stdout, "\n")))
+		sb.WriteString(fmt.Sprintf("--- stderr\n%s\n\n", strings.TrimRight(r.Stderr, "\n")))
+	}
+	return sb.String()
+}
+
+// saveGateLog writes an MR's gate output to <rig>/.runtime/gate-logs/<mr-id>.log
+// and returns the path. Each failure overwrites the previous log for the MR.
+func (e *Engineer) saveGateLog(mrID, content string) (string, error) {
+	if e.rig == nil || e.rig.Path == "" {
+		return "", fmt.Errorf("rig path unknown")
+	}
+	dir := filepath.Join(e.rig.Path, ".runtime", gateLogDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating gate log dir: %w", err)
+	}
+	path := filepath.Join(dir, mrID+".log")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("writing gate log: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,145 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+const goTestJSONFixture = `{"Action":"start","Package":"example.com/app/api"}
+{"Action":"run","Package":"example.com/app/api","Test":"TestServer"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestServer","Output":"=== RUN   TestServer\n"}
+{"Action":"run","Package":"example.com/app/api","Test":"TestServer/timeout"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestServer/timeout","Output":"    server_test.go:42: expected 504, got 200\n"}
+{"Action":"fail","Package":"example.com/app/api","Test":"TestServer/timeout","Elapsed":0.01}
+{"Action":"fail","Package":"example.com/app/api","Test":"TestServer","Elapsed":0.01}
+{"Action":"pass","Package":"example.com/app/api","Test":"TestHealth","Elapsed":0}
+{"Action":"fail","Package":"example.com/app/api","Elapsed":0.02}
+{"Action":"output","Package":"example.com/app/db","Output":"db_test.go:3:1: syntax error\n"}
+{"Action":"fail","Package":"example.com/app/db","Elapsed":0}
+`
+
+func TestParseGateOutput_GoTestJSON(t *testing.T) {
+	report := parseGateOutput(goTestJSONFixture, "")
+
+	want := []string{"TestServer/timeout (example.com/app/api)", "example.com/app/db [package]"}
+	if strings.Join(report.FailingTests, "|") != strings.Join(want, "|") {
+		t.Errorf("FailingTests = %v, want %v", report.FailingTests, want)
+	}
+	if !strings.Contains(report.Excerpt, "expected 504, got 200") {
+		t.Errorf("excerpt missing test output: %q", report.Excerpt)
+	}
+	if !strings.Contains(report.Excerpt, "syntax error") {
+		t.Errorf("excerpt missing package build failure: %q", report.Excerpt)
+	}
+	if strings.Contains(report.Excerpt, "=== RUN") {
+		t.Errorf("excerpt should omit RUN markers: %q", report.Excerpt)
+	}
+}
+
+func TestParseGateOutput_JUnitXML(t *testing.T) {
+	out := `Running tests...
+<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="web">
+    <testcase classname="web.Login" name="rejects bad password"/>
+    <testcase classname="web.Login" name="locks after 5 attempts">
+      <failure message="expected locked">AssertionError: expected locked
+  at login.spec.ts:88</failure>
+    </testcase>
+    <testcase classname="web.Cart" name="totals">
+      <error message="TypeError: undefined is not a function"/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	report := parseGateOutput(out, "")
+
+	want := []string{"web.Login.locks after 5 attempts", "web.Cart.totals"}
+	if strings.Join(report.FailingTests, "|") != strings.Join(want, "|") {
+		t.Errorf("FailingTests = %v, want %v", report.FailingTests, want)
+	}
+	if !strings.Contains(report.Excerpt, "login.spec.ts:88") {
+		t.Errorf("excerpt missing failure text: %q", report.Excerpt)
+	}
+}
+
+func TestParseGateOutput_UnstructuredUsesTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, "noise")
+	}
+	lines = append(lines, "FATAL: lint found 3 problems")
+
+	report := parseGateOutput("stdout chatter", strings.Join(lines, "\n"))
+	if len(report.FailingTests) != 0 {
+		t.Errorf("expected no failing tests, got %v", report.FailingTests)
+	}
+	if !strings.HasSuffix(report.Excerpt, "FATAL: lint found 3 problems") {
+		t.Errorf("expected excerpt to end with the last line, got %q", report.Excerpt)
+	}
+	if n := strings.Count(report.Excerpt, "\n") + 1; n > excerptMaxLines {
+		t.Errorf("excerpt has %d lines, want at most %d", n, excerptMaxLines)
+	}
+}
+
+func TestRunGates_FailureCarriesReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, _ := newGitTestEngineer(t)
+	fixture := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(fixture, []byte(goTestJSONFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: "cat " + fixture + "; exit 1"},
+	}
+
+	result := e.runGates(context.Background(), nil)
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if len(result.FailingTests) != 2 {
+		t.Errorf("expected 2 failing tests, got %v", result.FailingTests)
+	}
+	if !strings.HasPrefix(result.Excerpt, "[test]\n") {
+		t.Errorf("expected excerpt labeled with gate name, got %q", result.Excerpt)
+	}
+	if !strings.Contains(result.GateLog, "=== gate test: FAILED") || !strings.Contains(result.GateLog, `"Action":"fail"`) {
+		t.Errorf("gate log missing header or output:\n%s", result.GateLog)
+	}
+}
+
+func TestSaveGateLog(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+
+	path, err := e.saveGateLog("gt-mr1", "first")
+	if err != nil {
+		t.Fatalf("saveGateLog: %v", err)
+	}
+	if _, err := e.saveGateLog("gt-mr1", "second"); err != nil {
+		t.Fatalf("saveGateLog: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Errorf("expected latest log to overwrite, got %q", data)
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := &cappedBuffer{max: 5}
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Write([]byte("defgh"))
+	if got := b.String(); !strings.HasPrefix(got, "abcde") || !strings.Contains(got, "truncated") {
+		t.Errorf("unexpected capped output %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the per-rig gate duration history used for ETAs.
+
+package refinery
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+)
+
+// gateStatsFile is the history's file name under <rig>/.runtime/.
+const gateStatsFile = "refinery-gate-stats.json"
+
+// gateStatsSamples is how many recent runs are kept per gate.
+const gateStatsSamples = 20
+
+// legacyTestGate is the history key for the legacy RunTests/TestCommand path.
+const legacyTestGate = "test_command"
+
+// gateStatsState is the on-disk history: recent run durations, newest last,
+// keyed by "<kind>/<gate>" (e.g. "quality/test").
+type gateStatsState struct {
+	Gates map[string][]time.Duration `json:"gates"`
+}
+
+func newGateStatsState() *gateStatsState {
+	return &gateStatsState{Gates: make(map[string][]time.Duration)}
+}
+
+// GateStats is a rig's history of gate run durations. Safe for use by
+// parallel gates and concurrent processes.
+type GateStats struct {
+	mu    sync.Mutex
+	store *agent.StateManager[gateStatsState]
+}
+
+// NewGateStats returns the gate duration history for the rig at rigPath.
+func NewGateStats(rigPath string) *GateStats {
+	return &GateStats{
+		store: agent.NewStateManager[gateStatsState](rigPath, gateStatsFile, newGateStatsState),
+	}
+}
+
+func gateStatsKey(kind, gate string) string {
+	return kind + "/" + gate
+}
+
+// Record adds runs of kind gates (name -> duration) to the history. Errors
+// are swallowed: the history only feeds estimates.
+func (s *GateStats) Record(kind string, runs map[string]time.Duration) {
+	if len(runs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fl, err := lockStateFile(s.store.StateFile())
+	if err != nil {
+		return
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := s.store.Load()
+	if err != nil {
+		state = newGateStatsState()
+	}
+	if state.Gates == nil {
+		state.Gates = make(map[string][]time.Duration)
+	}
+	for gate, elapsed := range runs {
+		key := gateStatsKey(kind, gate)
+		samples := append(state.Gates[key], elapsed)
+		if len(samples) > gateStatsSamples {
+			samples = samples[len(samples)-gateStatsSamples:]
+		}
+		state.Gates[key] = samples
+	}
+	_ = s.store.Save(state)
+}
+
+// Averages returns the mean recent duration of each kind gate with history.
+func (s *GateStats) Averages(kind string) (map[string]time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	avgs := make(map[string]time.Duration)
+	for key, samples := range state.Gates {
+		gate, ok := strings.CutPrefix(key, kind+"/")
+		if !ok || len(samples) == 0 {
+			continue
+		}
+		var total time.Duration
+		for _, d := range samples {
+			total += d
+		}
+		avgs[gate] = total / time.Duration(len(samples))
+	}
+	return avgs, nil
+}
@@ -0,0 +1,75 @@
+package refinery
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes content to name in dir and commits it.
+func commitFile(t *testing.T, dir, name, content, msg string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", msg)
+}
+
+// newGitTestEngineer creates an Engineer backed by a real git clone of a
+// bare origin, with a single commit on main. The merge slot is stubbed to
+// always be available. Returns the engineer and the clone directory.
+func newGitTestEngineer(t *testing.T) (*Engineer, string) {
+	t.Helper()
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	runGit(t, t.TempDir(), "init", "--bare", "-b", "main", origin)
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-b", "main")
+	runGit(t, dir, "config", "user.email", "test@test.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+	runGit(t, dir, "remote", "add", "origin", origin)
+	commitFile(t, dir, "README.md", "line1\nline2\nline3\n", "initial")
+	runGit(t, dir, "push", "-u", "origin", "main")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.git = git.NewGit(dir)
+	e.workDir = dir
+	e.output = io.Discard
+	e.mergeSlotEnsureExists = func() (string, error) { return "merge-slot", nil }
+	e.mergeSlotAcquire = func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+		return &beads.MergeSlotStatus{ID: "merge-slot", Available: true, Holder: holder}, nil
+	}
+	e.mergeSlotRelease = func(string) error { return nil }
+	return e, dir
+}
+
+// addBranch creates branch off main with a single commit writing name.
+func addBranch(t *testing.T, dir, branch, name, content string) {
+	t.Helper()
+	runGit(t, dir, "checkout", "-q", "-b", branch, "main")
+	commitFile(t, dir, name, content, "feat: "+branch)
+	runGit(t, dir, "checkout", "-q", "main")
+}
@@ -245,6 +245,10 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	}
 
 	// Score and sort issues by priority score (highest first)
+	scoring, err := LoadScoreConfig(m.rig.Path)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	type scoredIssue struct {
 		issue *beads.Issue
@@ -256,7 +260,7 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 		if issue == nil || issue.Status != "open" {
 			continue
 		}
-		score := m.calculateIssueScore(issue, now)
+		score := ScoreMR(ScoreInputFromIssue(issue, beads.ParseMRFields(issue), now), scoring)
 		scored = append(scored, scoredIssue{issue: issue, score: score})
 	}
 
@@ -282,39 +286,6 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	return items, nil
 }
 
-// calculateIssueScore computes the priority score for an MR issue.
-// Higher scores mean higher priority (process first).
-func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64 {
-	fields := beads.ParseMRFields(issue)
-
-	// Parse MR creation time
-	mrCreatedAt := parseTime(issue.CreatedAt)
-	if mrCreatedAt.IsZero() {
-		mrCreatedAt = now // Fallback
-	}
-
-	// Build score input
-	input := ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
-	}
-
-	return ScoreMRWithDefaults(input)
-}
-
 // issueToMR converts a beads issue to a MergeRequest.
 func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 	if issue == nil {
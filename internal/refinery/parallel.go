@@ -0,0 +1,280 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains parallel MR processing in temporary worktrees.
+
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// workerBranchPrefix names the branch each parallel worker builds its merge
+// on, in place of the target (which is checked out in the refinery worktree).
+const workerBranchPrefix = "refinery/worker/"
+
+// NextBatch returns the MRs to process in the next batch: up to
+// MaxConcurrent ready MRs, best score first, no two of which touch the same
+// files on the same target. MRs for frozen targets are left out.
+func (e *Engineer) NextBatch(now time.Time) ([]*MRInfo, error) {
+	mrs, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	return selectBatch(e.withoutFrozen(mrs), e.config.MaxConcurrent, e.mrChangedFiles, e.config.Scoring, now), nil
+}
+
+// mrChangedFiles returns the files mr changes relative to where its branch
+// forked from the target. ok is false when that can't be determined.
+func (e *Engineer) mrChangedFiles(mr *MRInfo) (files []string, ok bool) {
+	base, err := e.git.MergeBase("origin/"+mr.Target, mr.Branch)
+	if err != nil {
+		return nil, false
+	}
+	files, err = e.git.ChangedFiles(base, mr.Branch)
+	if err != nil {
+		return nil, false
+	}
+	return files, true
+}
+
+// selectBatch orders mrs by score and picks up to size of them that can be
+// merged side by side. An MR joins only if none of its changed files are
+// changed by an MR already in the batch with the same target. An MR whose
+// changes are unknown is assumed to overlap everything on its target.
+func selectBatch(mrs []*MRInfo, size int, changed func(*MRInfo) ([]string, bool), scoring ScoreConfig, now time.Time) []*MRInfo {
+	if len(mrs) == 0 {
+		return nil
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	sorted := make([]*MRInfo, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreWith(scoring, now) > sorted[j].ScoreWith(scoring, now)
+	})
+	if size == 1 {
+		return sorted[:1]
+	}
+
+	touched := make(map[string]map[string]bool) // target -> files in the batch
+	opaque := make(map[string]bool)             // targets with an unknown MR in the batch
+	var batch []*MRInfo
+	for _, mr := range sorted {
+		if opaque[mr.Target] {
+			continue
+		}
+		files, ok := changed(mr)
+		if !ok {
+			if len(touched[mr.Target]) > 0 {
+				continue
+			}
+			opaque[mr.Target] = true
+		} else if overlaps(files, touched[mr.Target]) {
+			continue
+		}
+
+		if touched[mr.Target] == nil {
+			touched[mr.Target] = make(map[string]bool)
+		}
+		for _, f := range files {
+			touched[mr.Target][f] = true
+		}
+		batch = append(batch, mr)
+		if len(batch) == size {
+			break
+		}
+	}
+	return batch
+}
+
+// overlaps reports whether any of files is in set.
+func overlaps(files []string, set map[string]bool) bool {
+	for _, f := range files {
+		if set[f] {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessBatch processes mrs concurrently, each by ProcessMRInfo on a worker
+// with its own temporary worktree off the shared .repo.git. Gates run in
+// parallel; pushes take turns through the push lock and merge slot. A batch
+// of one is processed in the refinery worktree as usual.
+//
+// Results are in the order of mrs and never Deferred; they can be applied
+// with HandleTrainResults.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) []TrainResult {
+	results := make([]TrainResult, len(mrs))
+	for i, mr := range mrs {
+		results[i].MR = mr
+	}
+	if len(mrs) == 0 {
+		return results
+	}
+	if len(mrs) == 1 {
+		results[0].Result = e.ProcessMRInfo(ctx, mrs[0])
+		return results
+	}
+
+	// Workers branch from origin/<target>, so bring it up to date once.
+	if err := e.git.Fetch("origin"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin: %v (continuing)\n", err)
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Processing %d MR(s) in parallel\n", len(mrs))
+	var outMu sync.Mutex
+	var wg sync.WaitGroup
+	var cleanups []func()
+	for i, mr := range mrs {
+		// Workers are set up and torn down one at a time: adding and
+		// deleting their branches writes the shared repo config, which
+		// takes a lock.
+		w, cleanup, err := e.newWorker(mr, &outMu)
+		if err != nil {
+			results[i].Result = ProcessResult{Success: false, Error: err.Error()}
+			continue
+		}
+		cleanups = append(cleanups, cleanup)
+		wg.Add(1)
+		go func(idx int, w *Engineer, mr *MRInfo) {
+			defer wg.Done()
+			results[idx].Result = w.ProcessMRInfo(ctx, mr)
+		}(i, w, mr)
+	}
+	wg.Wait()
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+	return results
+}
+
+// newWorker returns a copy of e that merges mr in a fresh temporary worktree,
+// on a worker branch started from origin/<target>. Its output lines are
+// prefixed with the MR ID. The returned cleanup removes the worktree and
+// branch.
+func (e *Engineer) newWorker(mr *MRInfo, outMu *sync.Mutex) (*Engineer, func(), error) {
+	dir, err := os.MkdirTemp("", "gt-refinery-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating worker directory: %w", err)
+	}
+	branch := fmt.Sprintf("%s%s-%d", workerBranchPrefix, strings.ReplaceAll(mr.ID, "/", "-"), time.Now().UnixNano())
+	if err := e.git.WorktreeAddFromRef(dir, branch, "origin/"+mr.Target); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("creating worker worktree for %s: %w", mr.ID, err)
+	}
+
+	w := *e
+	w.git = git.NewGit(dir)
+	w.workDir = dir
+	w.localTarget = branch
+	w.output = &prefixWriter{mu: outMu, w: e.output, prefix: "[" + mr.ID + "] "}
+
+	cleanup := func() {
+		if err := e.git.WorktreeRemove(dir, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove worker worktree %s: %v\n", dir, err)
+		}
+		if err := e.git.DeleteBranch(branch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete worker branch %s: %v\n", branch, err)
+		}
+		_ = os.RemoveAll(dir)
+	}
+	return &w, cleanup, nil
+}
+
+// localBranch returns the local branch doMerge builds target's merge on.
+func (e *Engineer) localBranch(target string) string {
+	if e.localTarget != "" {
+		return e.localTarget
+	}
+	return target
+}
+
+// pushRefspec returns the refspec that pushes the local merge to origin/target.
+func (e *Engineer) pushRefspec(target string) string {
+	if e.localTarget != "" {
+		return e.localTarget + ":" + target
+	}
+	return target
+}
+
+// catchUpTarget is doMerge's Step 7.5 for parallel workers, run while
+// holding the push lock. If origin/<target> moved since the worker started
+// (another worker landed), the worker branch is reset to the new head and
+// the merge redone there with the same strategy. The batch only pairs MRs
+// that change different files, so this is normally clean, but the new tree
+// was never gated, so the gates run again before it can be pushed. Returns
+// the (possibly new) merge ref.
+func (e *Engineer) catchUpTarget(ctx context.Context, mergeRef, branch, target, sourceIssue, strategy string) (string, ProcessResult) {
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s: %v (continuing)\n", target, err)
+	}
+	current, err := e.git.IsAncestor("origin/"+target, "HEAD")
+	if err != nil {
+		return mergeRef, ProcessResult{Success: false, Error: fmt.Sprintf("checking origin/%s: %v", target, err)}
+	}
+	if current {
+		return mergeRef, ProcessResult{Success: true}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] origin/%s moved while gating, redoing the merge on the new head\n", target)
+	if err := e.git.ResetHard("origin/" + target); err != nil {
+		return mergeRef, ProcessResult{Success: false, Error: fmt.Sprintf("resetting to origin/%s: %v", target, err)}
+	}
+	if strategy == MergeStrategyRebase {
+		rebased, err := e.autoRebase(branch, e.localTarget)
+		if err != nil {
+			return mergeRef, ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("rebase onto %s failed: %v", target, err),
+			}
+		}
+		mergeRef = rebased
+	}
+	if result := e.mergeWithStrategy(mergeRef, branch, target, sourceIssue, strategy); !result.Success {
+		return mergeRef, result
+	}
+	return mergeRef, e.runQualityChecks(ctx, "origin/"+target)
+}
+
+// prefixWriter prefixes each line written to w. Workers share mu so their
+// lines don't interleave mid-line.
+type prefixWriter struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	prefix  string
+	midLine bool
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !p.midLine {
+			buf.WriteString(p.prefix)
+		}
+		buf.Write(line)
+		p.midLine = line[len(line)-1] != '\n'
+	}
+	if _, err := p.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,168 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSelectBatch_SkipsOverlappingMRs(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "p0", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "p1-overlap", Target: "main", Priority: 1, CreatedAt: now},
+		{ID: "p2", Target: "main", Priority: 2, CreatedAt: now},
+		{ID: "p3-other-target", Target: "integration/x", Priority: 3, CreatedAt: now},
+	}
+	files := map[string][]string{
+		"p0":              {"a.go", "b.go"},
+		"p1-overlap":      {"b.go"},
+		"p2":              {"c.go"},
+		"p3-other-target": {"a.go"},
+	}
+	changed := func(mr *MRInfo) ([]string, bool) { return files[mr.ID], true }
+
+	got := selectBatch(mrs, 3, changed, DefaultScoreConfig(), now)
+	if ids := batchIDs(got); ids != "p0 p2 p3-other-target" {
+		t.Errorf("batch = %s, want p0 p2 p3-other-target", ids)
+	}
+
+	if ids := batchIDs(selectBatch(mrs, 1, changed, DefaultScoreConfig(), now)); ids != "p0" {
+		t.Errorf("size 1 batch = %s, want p0", ids)
+	}
+}
+
+func TestSelectBatch_UnknownChangesTravelAlone(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "p0-unknown", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "p1", Target: "main", Priority: 1, CreatedAt: now},
+	}
+	changed := func(mr *MRInfo) ([]string, bool) {
+		if mr.ID == "p0-unknown" {
+			return nil, false
+		}
+		return []string{"a.go"}, true
+	}
+
+	if ids := batchIDs(selectBatch(mrs, 2, changed, DefaultScoreConfig(), now)); ids != "p0-unknown" {
+		t.Errorf("batch = %s, want p0-unknown alone", ids)
+	}
+}
+
+func batchIDs(mrs []*MRInfo) string {
+	ids := make([]string, len(mrs))
+	for i, mr := range mrs {
+		ids[i] = mr.ID
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestProcessBatch_LandsDisjointMRs(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addBranch(t, dir, "polecat/nux", "nux.txt", "nux\n")
+	addBranch(t, dir, "polecat/slit", "slit.txt", "slit\n")
+
+	results := e.ProcessBatch(context.Background(), []*MRInfo{
+		{ID: "mr-1", Branch: "polecat/nux", Target: "main"},
+		{ID: "mr-2", Branch: "polecat/slit", Target: "main"},
+	})
+	for _, tr := range results {
+		if !tr.Result.Success {
+			t.Fatalf("%s failed: %s", tr.MR.ID, tr.Result.Error)
+		}
+	}
+
+	// Whichever worker pushed second had to re-merge onto the first's push.
+	files := runGit(t, dir, "ls-tree", "--name-only", "origin/main")
+	if !strings.Contains(files, "nux.txt") || !strings.Contains(files, "slit.txt") {
+		t.Errorf("expected both MRs on origin/main, got:\n%s", files)
+	}
+	if n := runGit(t, dir, "rev-list", "--count", "origin/main"); n != "3" {
+		t.Errorf("expected initial + 2 squash commits, got %s", n)
+	}
+	head := runGit(t, dir, "rev-parse", "origin/main")
+	if head != results[0].Result.MergeCommit && head != results[1].Result.MergeCommit {
+		t.Error("expected origin/main to be one of the reported merge commits")
+	}
+
+	if branches := runGit(t, dir, "branch", "--list", workerBranchPrefix+"*"); branches != "" {
+		t.Errorf("expected worker branches cleaned up, got %q", branches)
+	}
+	if worktrees := runGit(t, dir, "worktree", "list"); strings.Count(worktrees, "\n") != 0 {
+		t.Errorf("expected worker worktrees removed, got:\n%s", worktrees)
+	}
+	if got := runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Errorf("refinery worktree left on %s", got)
+	}
+}
+
+func TestProcessBatch_RebaseStrategyCatchesUp(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	e.config.MergeStrategy = MergeStrategyRebase
+	addBranch(t, dir, "polecat/nux", "nux.txt", "nux\n")
+	addBranch(t, dir, "polecat/slit", "slit.txt", "slit\n")
+
+	results := e.ProcessBatch(context.Background(), []*MRInfo{
+		{ID: "mr-1", Branch: "polecat/nux", Target: "main"},
+		{ID: "mr-2", Branch: "polecat/slit", Target: "main"},
+	})
+	for _, tr := range results {
+		if !tr.Result.Success {
+			t.Fatalf("%s failed: %s", tr.MR.ID, tr.Result.Error)
+		}
+	}
+	if merges := runGit(t, dir, "rev-list", "--merges", "origin/main"); merges != "" {
+		t.Errorf("expected linear history, got merges %s", merges)
+	}
+	if n := runGit(t, dir, "rev-list", "--count", "origin/main"); n != "3" {
+		t.Errorf("expected 3 commits on origin/main, got %s", n)
+	}
+}
+
+func TestProcessBatch_RegatesAfterCatchingUp(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+	// Each MR passes on its own, but not on top of the other.
+	e.config.Gates = map[string]*GateConfig{
+		"test": {Cmd: "! { [ -f nux.txt ] && [ -f slit.txt ]; }"},
+	}
+	addBranch(t, dir, "polecat/nux", "nux.txt", "nux\n")
+	addBranch(t, dir, "polecat/slit", "slit.txt", "slit\n")
+
+	results := e.ProcessBatch(context.Background(), []*MRInfo{
+		{ID: "mr-1", Branch: "polecat/nux", Target: "main"},
+		{ID: "mr-2", Branch: "polecat/slit", Target: "main"},
+	})
+	landed := 0
+	for _, tr := range results {
+		if tr.Result.Success {
+			landed++
+		}
+	}
+	if landed != 1 {
+		t.Fatalf("expected exactly one MR to land, got %d: %+v", landed, results)
+	}
+	files := runGit(t, dir, "ls-tree", "--name-only", "origin/main")
+	if strings.Contains(files, "nux.txt") && strings.Contains(files, "slit.txt") {
+		t.Errorf("ungated re-merge was pushed:\n%s", files)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, w: &buf, prefix: "[mr-1] "}
+
+	_, _ = w.Write([]byte("one\ntw"))
+	_, _ = w.Write([]byte("o\nthree\n"))
+
+	if got, want := buf.String(), "[mr-1] one\n[mr-1] two\n[mr-1] three\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains queue planning: predicted order, conflicts, and ETAs.
+
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// PlanEntry is one MR's place in a QueuePlan.
+type PlanEntry struct {
+	Position int     `json:"position"`
+	MR       *MRInfo `json:"mr"`
+	Score    float64 `json:"score"`
+
+	// TargetConflicts lists files that conflict with the target as it is now.
+	TargetConflicts []string `json:"target_conflicts,omitempty"`
+
+	// ConflictsWith lists MRs ahead of this one (same target) whose branches
+	// conflict with it, and ConflictFiles the files involved.
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+	ConflictFiles []string `json:"conflict_files,omitempty"`
+
+	// ConflictCheckError is set when a conflict check could not run.
+	ConflictCheckError string `json:"conflict_check_error,omitempty"`
+
+	// GateEstimate is the expected gate time for this MR, from the rig's
+	// gate duration history. Zero with EstimateKnown false means some gate
+	// that applies has never run.
+	GateEstimate  time.Duration `json:"gate_estimate"`
+	EstimateKnown bool          `json:"estimate_known"`
+
+	// ETA is when the MR is expected to land if processed one at a time in
+	// plan order. Zero when unknown, or when the MR is expected to fail on a
+	// conflict and won't land without rework.
+	ETA time.Time `json:"eta,omitempty"`
+}
+
+// Conflicted reports whether the MR is expected to fail on a conflict.
+func (p *PlanEntry) Conflicted() bool {
+	return len(p.TargetConflicts) > 0 || len(p.ConflictsWith) > 0
+}
+
+// PlanOptions controls Engineer.Plan.
+type PlanOptions struct {
+	// SkipConflicts skips the conflict checks, which test-merge every pair
+	// of queued branches and can be slow on large queues.
+	SkipConflicts bool
+}
+
+// Plan predicts how the refinery will work through the ready queue: MRs in
+// score order, which of them conflict with the target or with MRs
+// ahead of them, and when each is expected to land based on historical gate
+// durations. Conflict checks run in a temporary detached worktree, so the
+// refinery's own worktree is untouched.
+func (e *Engineer) Plan(now time.Time, opts PlanOptions) ([]*PlanEntry, error) {
+	mrs, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(mrs, func(i, j int) bool {
+		return mrs[i].ScoreWith(e.config.Scoring, now) > mrs[j].ScoreWith(e.config.Scoring, now)
+	})
+
+	plan := make([]*PlanEntry, len(mrs))
+	for i, mr := range mrs {
+		plan[i] = &PlanEntry{Position: i + 1, MR: mr, Score: mr.ScoreWith(e.config.Scoring, now)}
+	}
+
+	if !opts.SkipConflicts && len(plan) > 0 {
+		if err := e.checkPlanConflicts(plan); err != nil {
+			return nil, err
+		}
+	}
+
+	averages, err := NewGateStats(e.rig.Path).Averages("quality")
+	if err != nil {
+		return nil, fmt.Errorf("loading gate history: %w", err)
+	}
+	for _, entry := range plan {
+		entry.GateEstimate, entry.EstimateKnown = e.estimateGates(entry.MR, averages)
+	}
+	scheduleETAs(plan, now)
+	return plan, nil
+}
+
+// checkPlanConflicts fills in each entry's conflicts: against its target,
+// and pairwise against every MR ahead of it with the same target.
+func (e *Engineer) checkPlanConflicts(plan []*PlanEntry) error {
+	if err := e.git.Fetch("origin"); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin: %v (continuing)\n", err)
+	}
+
+	dir, err := os.MkdirTemp("", "gt-mq-plan-")
+	if err != nil {
+		return fmt.Errorf("creating plan worktree directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	if err := e.git.WorktreeAddDetached(dir, "origin/"+plan[0].MR.Target); err != nil {
+		return fmt.Errorf("creating plan worktree: %w", err)
+	}
+	defer func() {
+		if err := e.git.WorktreeRemove(dir, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove plan worktree %s: %v\n", dir, err)
+		}
+	}()
+	scratch := git.NewGit(dir)
+
+	// Branch tips are checked out detached: the branches themselves may be
+	// checked out in polecat worktrees.
+	tips := make([]string, len(plan))
+	for i, entry := range plan {
+		tip, err := scratch.Rev(entry.MR.Branch)
+		if err != nil {
+			entry.ConflictCheckError = fmt.Sprintf("branch %s not found", entry.MR.Branch)
+			continue
+		}
+		tips[i] = tip
+	}
+
+	for j, entry := range plan {
+		if tips[j] == "" {
+			continue
+		}
+		conflicts, err := scratch.CheckConflicts(tips[j], "origin/"+entry.MR.Target)
+		if err != nil {
+			entry.ConflictCheckError = err.Error()
+			continue
+		}
+		entry.TargetConflicts = conflicts
+
+		for i, ahead := range plan[:j] {
+			if tips[i] == "" || ahead.MR.Target != entry.MR.Target {
+				continue
+			}
+			conflicts, err := scratch.CheckConflicts(tips[j], tips[i])
+			if err != nil {
+				entry.ConflictCheckError = fmt.Sprintf("checking against %s: %v", ahead.MR.ID, err)
+				continue
+			}
+			if len(conflicts) > 0 {
+				entry.ConflictsWith = append(entry.ConflictsWith, ahead.MR.ID)
+				entry.ConflictFiles = append(entry.ConflictFiles, conflicts...)
+			}
+		}
+		entry.ConflictFiles = dedupe(entry.ConflictFiles)
+	}
+	return nil
+}
+
+// estimateGates returns the expected gate time for mr from per-gate average
+// durations: the sum of the gates that apply to its changes, or the longest
+// of them when gates run in parallel. known is false if an applicable gate
+// has no history.
+func (e *Engineer) estimateGates(mr *MRInfo, averages map[string]time.Duration) (estimate time.Duration, known bool) {
+	if len(e.config.Gates) == 0 {
+		if e.config.RunTests && e.config.TestCommand != "" {
+			avg, ok := averages[legacyTestGate]
+			return avg, ok
+		}
+		return 0, true
+	}
+
+	var changed []string
+	if files, ok := e.mrChangedFiles(mr); ok {
+		changed = files
+	}
+	known = true
+	for name, gate := range e.config.Gates {
+		if !gate.appliesTo(changed) {
+			continue
+		}
+		avg, ok := averages[name]
+		if !ok {
+			known = false
+			continue
+		}
+		if e.config.GatesParallel {
+			estimate = max(estimate, avg)
+		} else {
+			estimate += avg
+		}
+	}
+	return estimate, known
+}
+
+// scheduleETAs assigns landing times assuming MRs are processed one at a
+// time in plan order. Conflicted MRs fail before their gates run, so they
+// take no time and get no ETA; after the first unknown estimate, every
+// later ETA is unknown too.
+func scheduleETAs(plan []*PlanEntry, now time.Time) {
+	at := now
+	for _, entry := range plan {
+		if entry.Conflicted() {
+			continue
+		}
+		if !entry.EstimateKnown {
+			return
+		}
+		at = at.Add(entry.GateEstimate)
+		entry.ETA = at
+	}
+}
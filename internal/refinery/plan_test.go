@@ -0,0 +1,131 @@
+package refinery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestCheckPlanConflicts(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addBranch(t, dir, "polecat/nux", "README.md", "nux\nline2\nline3\n")
+	addBranch(t, dir, "polecat/slit", "README.md", "slit\nline2\nline3\n")
+	addBranch(t, dir, "polecat/rust", "rust.txt", "rust\n")
+
+	plan := []*PlanEntry{
+		{Position: 1, MR: &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}},
+		{Position: 2, MR: &MRInfo{ID: "mr-2", Branch: "polecat/slit", Target: "main"}},
+		{Position: 3, MR: &MRInfo{ID: "mr-3", Branch: "polecat/rust", Target: "main"}},
+		{Position: 4, MR: &MRInfo{ID: "mr-4", Branch: "polecat/gone", Target: "main"}},
+	}
+	if err := e.checkPlanConflicts(plan); err != nil {
+		t.Fatalf("checkPlanConflicts: %v", err)
+	}
+
+	if plan[0].Conflicted() {
+		t.Errorf("mr-1 should be clean, got %+v", plan[0])
+	}
+	if got := plan[1].ConflictsWith; len(got) != 1 || got[0] != "mr-1" {
+		t.Errorf("mr-2 ConflictsWith = %v, want [mr-1]", got)
+	}
+	if got := plan[1].ConflictFiles; len(got) != 1 || got[0] != "README.md" {
+		t.Errorf("mr-2 ConflictFiles = %v, want [README.md]", got)
+	}
+	if len(plan[1].TargetConflicts) != 0 {
+		t.Errorf("mr-2 should merge cleanly into main, got %v", plan[1].TargetConflicts)
+	}
+	if plan[2].Conflicted() {
+		t.Errorf("mr-3 should be clean, got %+v", plan[2])
+	}
+	if plan[3].ConflictCheckError == "" {
+		t.Error("expected a conflict check error for a missing branch")
+	}
+
+	if worktrees := runGit(t, dir, "worktree", "list"); strings.Count(worktrees, "\n") != 0 {
+		t.Errorf("expected plan worktree removed, got:\n%s", worktrees)
+	}
+	if got := runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Errorf("refinery worktree left on %s", got)
+	}
+}
+
+func TestEstimateGates(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.config.Gates = map[string]*GateConfig{
+		"build": {Cmd: "make build"},
+		"test":  {Cmd: "make test"},
+	}
+	averages := map[string]time.Duration{
+		"build": 2 * time.Minute,
+		"test":  5 * time.Minute,
+	}
+	mr := &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}
+
+	// Changed files are unknown without a repo, so every gate applies.
+	if got, known := e.estimateGates(mr, averages); !known || got != 7*time.Minute {
+		t.Errorf("serial estimate = %v (known=%v), want 7m", got, known)
+	}
+
+	e.config.GatesParallel = true
+	if got, known := e.estimateGates(mr, averages); !known || got != 5*time.Minute {
+		t.Errorf("parallel estimate = %v (known=%v), want 5m", got, known)
+	}
+
+	delete(averages, "test")
+	if _, known := e.estimateGates(mr, averages); known {
+		t.Error("expected estimate unknown when a gate has no history")
+	}
+}
+
+func TestScheduleETAs(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	plan := []*PlanEntry{
+		{MR: &MRInfo{ID: "mr-1"}, GateEstimate: 5 * time.Minute, EstimateKnown: true},
+		{MR: &MRInfo{ID: "mr-2"}, GateEstimate: 5 * time.Minute, EstimateKnown: true, ConflictsWith: []string{"mr-1"}},
+		{MR: &MRInfo{ID: "mr-3"}, GateEstimate: 3 * time.Minute, EstimateKnown: true},
+		{MR: &MRInfo{ID: "mr-4"}},
+		{MR: &MRInfo{ID: "mr-5"}, GateEstimate: time.Minute, EstimateKnown: true},
+	}
+	scheduleETAs(plan, now)
+
+	if want := now.Add(5 * time.Minute); !plan[0].ETA.Equal(want) {
+		t.Errorf("mr-1 ETA = %v, want %v", plan[0].ETA, want)
+	}
+	if !plan[1].ETA.IsZero() {
+		t.Errorf("conflicted mr-2 should have no ETA, got %v", plan[1].ETA)
+	}
+	if want := now.Add(8 * time.Minute); !plan[2].ETA.Equal(want) {
+		t.Errorf("mr-3 ETA = %v, want %v (conflicted MR takes no time)", plan[2].ETA, want)
+	}
+	if !plan[3].ETA.IsZero() || !plan[4].ETA.IsZero() {
+		t.Error("expected no ETAs from the first unknown estimate on")
+	}
+}
+
+func TestGateStats_Averages(t *testing.T) {
+	stats := NewGateStats(t.TempDir())
+	stats.Record("quality", map[string]time.Duration{"test": time.Minute, "lint": 10 * time.Second})
+	stats.Record("quality", map[string]time.Duration{"test": 3 * time.Minute})
+	stats.Record("post-merge", map[string]time.Duration{"smoke": time.Hour})
+
+	avgs, err := stats.Averages("quality")
+	if err != nil {
+		t.Fatalf("Averages: %v", err)
+	}
+	if avgs["test"] != 2*time.Minute || avgs["lint"] != 10*time.Second {
+		t.Errorf("averages = %v, want test=2m lint=10s", avgs)
+	}
+	if _, ok := avgs["smoke"]; ok {
+		t.Error("post-merge gate leaked into quality averages")
+	}
+
+	for i := 0; i < gateStatsSamples; i++ {
+		stats.Record("quality", map[string]time.Duration{"test": time.Second})
+	}
+	avgs, _ = stats.Averages("quality")
+	if avgs["test"] != time.Second {
+		t.Errorf("expected only the last %d samples kept, got average %v", gateStatsSamples, avgs["test"])
+	}
+}
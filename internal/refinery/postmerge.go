@@ -0,0 +1,214 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains post-merge verification: gates that run on the pushed
+// target head, automatic reverts, and the rig's incident log.
+
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// incidentLogFile is the incident log's file name under <rig>/.runtime/.
+const incidentLogFile = "refinery-incidents.json"
+
+// maxIncidents caps the incident log; the oldest records are dropped first.
+const maxIncidents = 200
+
+// CloseReasonReverted is the close reason of an MR whose merge was reverted
+// because post-merge gates failed on it.
+const CloseReasonReverted = "reverted"
+
+// IncidentRecord describes one MR whose merge failed post-merge verification.
+type IncidentRecord struct {
+	MR          string `json:"mr"`
+	SourceIssue string `json:"source_issue,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+	Target      string `json:"target"`
+
+	// MergeCommit is the commit that landed and failed verification.
+	MergeCommit string `json:"merge_commit"`
+
+	// RevertCommit is the revert pushed to undo it. Empty means the revert
+	// could not be pushed and the target is still broken.
+	RevertCommit string `json:"revert_commit,omitempty"`
+
+	Error        string    `json:"error"`
+	FailingTests []string  `json:"failing_tests,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// incidentLogState is the on-disk incident log, oldest first.
+type incidentLogState struct {
+	Incidents []*IncidentRecord `json:"incidents"`
+}
+
+func newIncidentLogState() *incidentLogState {
+	return &incidentLogState{}
+}
+
+// IncidentLog is a rig's log of post-merge verification failures.
+type IncidentLog struct {
+	mu    sync.Mutex
+	store *agent.StateManager[incidentLogState]
+}
+
+// NewIncidentLog returns the incident log for the rig at rigPath.
+func NewIncidentLog(rigPath string) *IncidentLog {
+	return &IncidentLog{
+		store: agent.NewStateManager[incidentLogState](rigPath, incidentLogFile, newIncidentLogState),
+	}
+}
+
+// Record appends an incident to the log. Safe for use by parallel workers
+// and concurrent processes.
+func (l *IncidentLog) Record(rec *IncidentRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fl, err := lockStateFile(l.store.StateFile())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	state, err := l.store.Load()
+	if err != nil {
+		return fmt.Errorf("loading incident log: %w", err)
+	}
+	state.Incidents = append(state.Incidents, rec)
+	if n := len(state.Incidents); n > maxIncidents {
+		state.Incidents = state.Incidents[n-maxIncidents:]
+	}
+	return l.store.Save(state)
+}
+
+// List returns all recorded incidents, newest first.
+func (l *IncidentLog) List() ([]*IncidentRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading incident log: %w", err)
+	}
+	records := make([]*IncidentRecord, len(state.Incidents))
+	for i, rec := range state.Incidents {
+		records[len(records)-1-i] = rec
+	}
+	return records, nil
+}
+
+// verifyPostMerge runs the post-merge gates on the checked-out target, which
+// has just been pushed on top of base. If they fail, it pushes a commit that
+// restores base's tree and returns a PostMergeFailed result carrying
+// RevertCommit; landed describes what is being reverted, for the commit
+// message. Must be called while still holding the push slot, so nothing else
+// lands between the merge and its revert.
+func (e *Engineer) verifyPostMerge(ctx context.Context, target, base, landed string) ProcessResult {
+	if len(e.config.PostMergeGates) == 0 {
+		return ProcessResult{Success: true}
+	}
+
+	changed, err := e.git.ChangedFiles(base, "HEAD")
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files against %.8s, running all post-merge gates: %v\n", base, err)
+	}
+	// Post-merge gates exist to catch what the cache and the merged-tree
+	// gates missed, so they never use the gate cache.
+	result := e.runGateSet(ctx, "post-merge", e.config.PostMergeGates, changed, false)
+	if result.Success {
+		return result
+	}
+	result.PostMergeFailed = true
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Post-merge gates failed on %s, reverting %s...\n", target, landed)
+	msg := fmt.Sprintf("Revert %s: post-merge gates failed\n\n%s", landed, result.Error)
+	revert, err := e.git.RevertTo(base, msg)
+	if err != nil {
+		_ = e.git.ResetHard("origin/" + target)
+		result.Error = fmt.Sprintf("%s; revert failed, origin/%s is still broken: %v", result.Error, target, err)
+		return result
+	}
+	if err := e.git.Push("origin", e.pushRefspec(target), false); err != nil {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after revert push failure: %v\n", target, resetErr)
+		}
+		result.Error = fmt.Sprintf("%s; pushing revert failed, origin/%s is still broken: %v", result.Error, target, err)
+		return result
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed revert %.8s to origin/%s\n", revert, target)
+	result.RevertCommit = revert
+	return result
+}
+
+// handlePostMergeFailure closes an MR whose merge failed post-merge
+// verification, reopens its source issue for rework, and records the
+// incident. The witness has already been sent MERGE_FAILED (post_merge).
+func (e *Engineer) handlePostMergeFailure(mr *MRInfo, result ProcessResult) {
+	// If the revert didn't land the work is still on the target, so the MR
+	// is closed as merged; either way it must not be retried.
+	closeReason := CloseReasonReverted
+	if result.RevertCommit == "" {
+		closeReason = "merged"
+	}
+
+	if mrBead, err := e.beads.Show(mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+	} else {
+		mrFields := beads.ParseMRFields(mrBead)
+		if mrFields == nil {
+			mrFields = &beads.MRFields{}
+		}
+		mrFields.MergeCommit = result.MergeCommit
+		mrFields.RevertCommit = result.RevertCommit
+		mrFields.CloseReason = closeReason
+		newDesc := beads.SetMRFields(mrBead, mrFields)
+		if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with revert commit: %v\n", mr.ID, err)
+		}
+	}
+	if err := e.beads.CloseWithReason(closeReason, mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close MR %s: %v\n", mr.ID, err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Closed MR bead: %s (%s)\n", mr.ID, closeReason)
+	}
+
+	// Reopen the source issue, unassigned, so the fix can be slung afresh.
+	if mr.SourceIssue != "" {
+		open, unassigned := "open", ""
+		if err := e.beads.Update(mr.SourceIssue, beads.UpdateOptions{Status: &open, Assignee: &unassigned}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reopen source issue %s: %v\n", mr.SourceIssue, err)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Reopened source issue: %s\n", mr.SourceIssue)
+		}
+	}
+
+	if mr.AgentBead != "" {
+		if err := e.beads.UpdateAgentActiveMR(mr.AgentBead, ""); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to clear agent bead %s active_mr: %v\n", mr.AgentBead, err)
+		}
+	}
+
+	rec := &IncidentRecord{
+		MR:           mr.ID,
+		SourceIssue:  mr.SourceIssue,
+		Branch:       mr.Branch,
+		Target:       mr.Target,
+		MergeCommit:  result.MergeCommit,
+		RevertCommit: result.RevertCommit,
+		Error:        result.Error,
+		FailingTests: result.FailingTests,
+		At:           time.Now(),
+	}
+	if err := NewIncidentLog(e.rig.Path).Record(rec); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record post-merge incident: %v\n", err)
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Reverted: %s - %s\n", mr.ID, result.Error)
+}
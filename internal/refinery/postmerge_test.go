@@ -0,0 +1,170 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestProcessMRInfo_PostMergeFailureReverts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+	e.config.PostMergeGates = map[string]*GateConfig{
+		"smoke": {Cmd: "test ! -f bad.txt"},
+	}
+	addBranch(t, dir, "polecat/nux", "bad.txt", "bad\n")
+	base := runGit(t, dir, "rev-parse", "origin/main")
+
+	r := e.ProcessMRInfo(context.Background(), &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"})
+	if r.Success || !r.PostMergeFailed {
+		t.Fatalf("expected post-merge failure, got %+v", r)
+	}
+	if r.MergeCommit == "" || r.RevertCommit == "" {
+		t.Fatalf("expected merge and revert commits, got %+v", r)
+	}
+	if !strings.Contains(r.Error, "post-merge gates failed") {
+		t.Errorf("unexpected error %q", r.Error)
+	}
+
+	if got := runGit(t, dir, "rev-parse", "origin/main"); got != r.RevertCommit {
+		t.Errorf("origin/main = %s, want revert %s", got, r.RevertCommit)
+	}
+	if got := runGit(t, dir, "rev-parse", "origin/main^"); got != r.MergeCommit {
+		t.Errorf("revert parent = %s, want merge %s", got, r.MergeCommit)
+	}
+	if runGit(t, dir, "rev-parse", "origin/main^{tree}") != runGit(t, dir, "rev-parse", base+"^{tree}") {
+		t.Error("expected revert to restore the pre-merge tree")
+	}
+}
+
+func TestProcessMRInfo_PostMergePass(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+	e.config.PostMergeGates = map[string]*GateConfig{
+		"smoke": {Cmd: "test -f feature.txt"},
+	}
+	addBranch(t, dir, "polecat/nux", "feature.txt", "ok\n")
+
+	r := e.ProcessMRInfo(context.Background(), &MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"})
+	if !r.Success {
+		t.Fatalf("merge failed: %s", r.Error)
+	}
+	if got := runGit(t, dir, "rev-parse", "origin/main"); got != r.MergeCommit {
+		t.Errorf("origin/main = %s, want merge %s", got, r.MergeCommit)
+	}
+}
+
+func TestProcessTrain_PostMergeFailureRevertsWholeTrain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gate commands run via sh -c; Windows paths break under MSYS2 shell")
+	}
+	e, dir := newGitTestEngineer(t)
+	e.config.PostMergeGates = map[string]*GateConfig{
+		"smoke": {Cmd: "test ! -f b.txt"},
+	}
+	addBranch(t, dir, "polecat/a", "a.txt", "a\n")
+	addBranch(t, dir, "polecat/b", "b.txt", "b\n")
+	base := runGit(t, dir, "rev-parse", "origin/main")
+
+	now := time.Now()
+	results := e.ProcessTrain(context.Background(), []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main", CreatedAt: now},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main", CreatedAt: now},
+	})
+	for _, tr := range results {
+		if tr.Result.Success || !tr.Result.PostMergeFailed || tr.Result.RevertCommit == "" {
+			t.Fatalf("%s: expected reverted post-merge failure, got %+v", tr.MR.ID, tr.Result)
+		}
+	}
+	if results[0].Result.MergeCommit == results[1].Result.MergeCommit {
+		t.Error("expected each car to keep its own merge commit")
+	}
+	if runGit(t, dir, "rev-parse", "origin/main^{tree}") != runGit(t, dir, "rev-parse", base+"^{tree}") {
+		t.Error("expected one revert restoring the pre-train tree")
+	}
+}
+
+func TestEngineer_LoadConfig_PostMergeGates(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"merge_queue": {"post_merge_gates": {"smoke": {"cmd": "make smoke", "timeout": "5m"}}}}`)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	gate := e.Config().PostMergeGates["smoke"]
+	if gate == nil || gate.Cmd != "make smoke" || gate.Timeout != 5*time.Minute {
+		t.Errorf("unexpected post-merge gate %+v", gate)
+	}
+
+	write(`{"merge_queue": {"post_merge_gates": {"smoke": {"cmd": "make smoke", "timeout": "soon"}}}}`)
+	e = NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err == nil || !strings.Contains(err.Error(), "post_merge_gates") {
+		t.Errorf("expected post_merge_gates error, got %v", err)
+	}
+}
+
+func TestIncidentLog_NewestFirst(t *testing.T) {
+	l := NewIncidentLog(t.TempDir())
+	now := time.Now()
+
+	for _, id := range []string{"mr-1", "mr-2"} {
+		if err := l.Record(&IncidentRecord{MR: id, Target: "main", MergeCommit: "abc", At: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := l.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].MR != "mr-2" || records[1].MR != "mr-1" {
+		t.Errorf("expected [mr-2 mr-1], got %+v", records)
+	}
+}
+
+func TestIncidentLog_ConcurrentLogsShareFile(t *testing.T) {
+	// Each post-merge failure opens its own log on the rig's file
+	rigPath := t.TempDir()
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				rec := &IncidentRecord{MR: fmt.Sprintf("mr-%d-%d", i, j), Target: "main", MergeCommit: "abc", At: now}
+				if err := NewIncidentLog(rigPath).Record(rec); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := NewIncidentLog(rigPath).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 40 {
+		t.Errorf("expected 40 incidents, got %d", len(records))
+	}
+}
@@ -0,0 +1,414 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the review gate: MRs touching owned paths wait for an
+// approved review bead before they merge.
+
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// ownersFile is the rig's Gas Town ownership file, next to its settings. It
+// uses CODEOWNERS syntax with Gas Town addresses as owners.
+const ownersFile = "OWNERS"
+
+// codeownersPaths are where a CODEOWNERS file is looked for on the target
+// branch, in GitHub's order.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ownerRule is one line of an ownership file.
+type ownerRule struct {
+	pattern string
+	owners  []string
+}
+
+// parseOwners parses CODEOWNERS-syntax content: a path pattern followed by
+// owners per line, with # comments.
+func parseOwners(content string) []ownerRule {
+	var rules []ownerRule
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, ownerRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules
+}
+
+// matchOwnerPattern reports whether file matches a CODEOWNERS pattern. As in
+// gitignore, a pattern without a slash (other than a trailing one) matches at
+// any depth, and a pattern naming a directory matches everything under it.
+func matchOwnerPattern(pattern, file string) bool {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+	return matchGlob(pattern, file) || matchGlob(pattern+"/**", file)
+}
+
+// ownersOf returns the owners of file under rules, where the last matching
+// rule wins. matched is false if no rule matches; a matching rule may list
+// no owners, which makes the file unowned.
+func ownersOf(rules []ownerRule, file string) (owners []string, matched bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchOwnerPattern(rules[i].pattern, file) {
+			return rules[i].owners, true
+		}
+	}
+	return nil, false
+}
+
+// LoadReviewConfig returns the review gate from merge_queue.review in the
+// rig settings, or nil if it isn't enabled.
+func LoadReviewConfig(rigPath string) (*config.ReviewConfig, error) {
+	mq, err := loadMergeQueueSettings(rigPath)
+	if err != nil || mq == nil || mq.Review == nil || !mq.Review.Enabled {
+		return nil, err
+	}
+	return mq.Review, nil
+}
+
+// ReviewRequest describes the review an MR needs before it can merge.
+type ReviewRequest struct {
+	// Paths are the owned files the MR changes.
+	Paths []string
+
+	// Owners are the Gas Town addresses that own them.
+	Owners []string
+
+	// Unmapped are CODEOWNERS owners with no Gas Town address. Their paths
+	// go to the configured reviewer instead.
+	Unmapped []string
+}
+
+// ReviewFor works out which owned paths mr touches and who owns them. The
+// rig's OWNERS file is consulted first; files it doesn't match fall back to
+// CODEOWNERS on the target branch. Returns nil if mr touches no owned paths.
+func (e *Engineer) ReviewFor(mr *MRInfo) (*ReviewRequest, error) {
+	files, ok := e.mrChangedFiles(mr)
+	if !ok {
+		return nil, fmt.Errorf("can't determine files changed by %s", mr.Branch)
+	}
+
+	var gtRules, coRules []ownerRule
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(config.RigSettingsPath(e.rig.Path)), ownersFile))
+	if err == nil {
+		gtRules = parseOwners(string(data))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", ownersFile, err)
+	}
+	for _, p := range codeownersPaths {
+		if content, err := e.git.ShowFile("origin/"+mr.Target, p); err == nil {
+			coRules = parseOwners(content)
+			break
+		}
+	}
+
+	req := &ReviewRequest{}
+	owners := make(map[string]bool)
+	unmapped := make(map[string]bool)
+	for _, file := range files {
+		if addrs, ok := ownersOf(gtRules, file); ok {
+			if len(addrs) > 0 {
+				req.Paths = append(req.Paths, file)
+			}
+			for _, addr := range addrs {
+				owners[addr] = true
+			}
+			continue
+		}
+		tokens, ok := ownersOf(coRules, file)
+		if !ok || len(tokens) == 0 {
+			continue
+		}
+		req.Paths = append(req.Paths, file)
+		for _, token := range tokens {
+			if addr, ok := e.resolveCodeOwner(token); ok {
+				owners[addr] = true
+			} else {
+				unmapped[token] = true
+			}
+		}
+	}
+	if len(req.Paths) == 0 {
+		return nil, nil
+	}
+	req.Owners = sortedKeys(owners)
+	req.Unmapped = sortedKeys(unmapped)
+	return req, nil
+}
+
+// resolveCodeOwner maps a CODEOWNERS owner to a Gas Town address: through
+// the review owner_map, or "@name" to the rig's crew member name.
+func (e *Engineer) resolveCodeOwner(token string) (string, bool) {
+	if e.config.Review != nil {
+		if addr, ok := e.config.Review.OwnerMap[token]; ok {
+			return addr, true
+		}
+	}
+	if name, ok := strings.CutPrefix(token, "@"); ok && !strings.Contains(name, "/") {
+		for _, crew := range e.rig.Crew {
+			if strings.EqualFold(crew, name) {
+				return fmt.Sprintf("%s/crew/%s", e.rig.Name, crew), true
+			}
+		}
+	}
+	return "", false
+}
+
+// reviewers returns who to ask for req: its owners, plus the configured
+// reviewer when some owned paths have no Gas Town owner.
+func (e *Engineer) reviewers(req *ReviewRequest) []string {
+	reviewers := req.Owners
+	if (len(reviewers) == 0 || len(req.Unmapped) > 0) && e.config.Review != nil && e.config.Review.Reviewer != "" {
+		reviewers = append(append([]string{}, reviewers...), e.config.Review.Reviewer)
+	}
+	return dedupe(reviewers)
+}
+
+// checkReview applies the review gate to mr. ok is true when the MR may
+// merge: the gate is off, the MR touches no owned paths, or its branch head
+// has an approved review. Otherwise result says why it can't merge yet: a
+// review is pending (a new one is requested if needed), was closed without
+// an outcome, or was rejected.
+func (e *Engineer) checkReview(mr *MRInfo) (result ProcessResult, ok bool) {
+	if e.config.Review == nil {
+		return ProcessResult{}, true
+	}
+	head, err := e.git.Rev(mr.Branch)
+	if err != nil {
+		// doMerge reports the missing branch.
+		return ProcessResult{}, true
+	}
+
+	if mr.ReviewBead != "" {
+		review, err := e.beads.Show(mr.ReviewBead)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch review %s: %v\n", mr.ReviewBead, err)
+		} else if fields := beads.ParseReviewFields(review.Description); fields != nil && fields.Head == head {
+			if review.Status != "closed" {
+				return ProcessResult{ReviewPending: true, Error: fmt.Sprintf("awaiting review %s", review.ID)}, false
+			}
+			switch reviewOutcome(review, fields) {
+			case beads.ReviewApproved:
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Review %s approved by %s\n", review.ID, fields.ReviewedBy)
+				return ProcessResult{}, true
+			case beads.ReviewChangesRequested:
+				msg := fmt.Sprintf("changes requested in review %s", review.ID)
+				if fields.Comment != "" {
+					msg += ": " + fields.Comment
+				}
+				return ProcessResult{ReviewRejected: true, Error: msg}, false
+			default:
+				// Closed without saying how, e.g. by a plain bd close. Don't
+				// guess: hold the MR until someone records an outcome.
+				return ProcessResult{ReviewPending: true, Error: fmt.Sprintf(
+					"review %s closed without an outcome (reason %q); reopen it and run gt mq review %s %s --approve or --request-changes",
+					review.ID, review.CloseReason, e.rig.Name, review.ID)}, false
+			}
+		}
+		// A review of an older head doesn't cover what would land now.
+	}
+
+	req, err := e.ReviewFor(mr)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("review gate: %v", err)}, false
+	}
+	if req == nil {
+		return ProcessResult{}, true
+	}
+	reviewID, err := e.requestReview(mr, head, req)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("review gate: %v", err)}, false
+	}
+	return ProcessResult{ReviewPending: true, Error: fmt.Sprintf("review requested: %s", reviewID)}, false
+}
+
+// reviewOutcome returns a closed review's outcome: the outcome field set by
+// gt mq review, else its close reason. Returns "" when neither says
+// approved or changes_requested.
+func reviewOutcome(review *beads.Issue, fields *beads.ReviewFields) string {
+	outcome := fields.Outcome
+	if outcome == "" {
+		outcome = strings.ToLower(strings.TrimSpace(review.CloseReason))
+	}
+	switch outcome {
+	case beads.ReviewApproved, "approve":
+		return beads.ReviewApproved
+	case beads.ReviewChangesRequested, "request_changes", "request-changes", "changes-requested":
+		return beads.ReviewChangesRequested
+	}
+	return ""
+}
+
+// requestReview creates a review bead for mr at head, blocks the MR on it,
+// and mails the reviewers. The bead is assigned to the first reviewer, or
+// left unassigned for a reviewer polecat. Returns the review bead ID.
+func (e *Engineer) requestReview(mr *MRInfo, head string, req *ReviewRequest) (string, error) {
+	reviewers := e.reviewers(req)
+
+	title := mr.Branch
+	if mr.SourceIssue != "" {
+		title = mr.SourceIssue
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil {
+			title = issue.Title
+		}
+	}
+
+	summary := fmt.Sprintf(`Review %s before the refinery merges it into %s. It changes paths you own.
+
+Inspect the change with: git diff origin/%s...%s
+Approve with: gt mq review %s <this-review-id> --approve
+Request changes with: gt mq review %s <this-review-id> --request-changes --comment "<what to fix>"`,
+		mr.Branch, mr.Target, mr.Target, head[:8], e.rig.Name, e.rig.Name)
+	fields := &beads.ReviewFields{
+		MR:     mr.ID,
+		Branch: mr.Branch,
+		Target: mr.Target,
+		Head:   head,
+		Owners: reviewers,
+		Paths:  req.Paths,
+	}
+
+	review, err := e.beads.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Review: %s", title),
+		Type:        "task",
+		Priority:    mr.Priority,
+		Description: beads.FormatReviewDescription(summary, fields),
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating review bead: %w", err)
+	}
+
+	update := beads.UpdateOptions{AddLabels: []string{beads.ReviewLabel}}
+	if len(reviewers) > 0 {
+		update.Assignee = &reviewers[0]
+	}
+	if err := e.beads.Update(review.ID, update); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to label/assign review %s: %v\n", review.ID, err)
+	}
+
+	// Block the MR on the review; it re-enters the ready queue when the
+	// review closes, and checkReview reads the outcome then.
+	if err := e.beads.AddDependency(mr.ID, review.ID); err != nil {
+		return review.ID, fmt.Errorf("blocking MR on review %s: %w", review.ID, err)
+	}
+	if mrBead, err := e.beads.Show(mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+	} else {
+		mrFields := beads.ParseMRFields(mrBead)
+		if mrFields == nil {
+			mrFields = &beads.MRFields{}
+		}
+		mrFields.ReviewBead = review.ID
+		newDesc := beads.SetMRFields(mrBead, mrFields)
+		if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to link review on MR %s: %v\n", mr.ID, err)
+		}
+	}
+	mr.ReviewBead = review.ID
+	mr.BlockedBy = review.ID
+
+	for _, reviewer := range reviewers {
+		msg := &mail.Message{
+			From:     e.rig.Name + "/refinery",
+			To:       reviewer,
+			Subject:  fmt.Sprintf("Review requested: %s", title),
+			Priority: mail.PriorityNormal,
+			Type:     mail.TypeTask,
+			Body: fmt.Sprintf("Review %s is waiting on you.\n\nMR: %s\nBranch: %s → %s\nOwned paths:\n  %s\n\nRun 'bd show %s' for instructions.",
+				review.ID, mr.ID, mr.Branch, mr.Target, strings.Join(req.Paths, "\n  "), review.ID),
+		}
+		if err := e.router.Send(msg); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to notify reviewer %s: %v\n", reviewer, err)
+		}
+	}
+
+	who := "a reviewer polecat"
+	if len(reviewers) > 0 {
+		who = strings.Join(reviewers, ", ")
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on review %s (%s)\n", mr.ID, review.ID, who)
+	return review.ID, nil
+}
+
+// handleReviewRejected closes an MR whose review requested changes. The
+// worker was sent MERGE_FAILED and resubmits with gt done once the feedback
+// is addressed, which gets a fresh review.
+func (e *Engineer) handleReviewRejected(mr *MRInfo, result ProcessResult) {
+	reason := string(CloseReasonRejected)
+	if mrBead, err := e.beads.Show(mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+	} else {
+		mrFields := beads.ParseMRFields(mrBead)
+		if mrFields == nil {
+			mrFields = &beads.MRFields{}
+		}
+		mrFields.CloseReason = reason
+		newDesc := beads.SetMRFields(mrBead, mrFields)
+		if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s: %v\n", mr.ID, err)
+		}
+	}
+	if err := e.beads.CloseWithReason(reason, mr.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close MR %s: %v\n", mr.ID, err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Review rejected: %s - %s\n", mr.ID, result.Error)
+}
+
+// RecordReviewOutcome records a reviewer's decision on a review bead and
+// closes it, unblocking its MR. outcome is beads.ReviewApproved or
+// beads.ReviewChangesRequested.
+func RecordReviewOutcome(bd *beads.Beads, reviewID, outcome, reviewer, comment string) (*beads.ReviewFields, error) {
+	review, err := bd.Show(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching review %s: %w", reviewID, err)
+	}
+	fields := beads.ParseReviewFields(review.Description)
+	if fields == nil || !beads.HasLabel(review, beads.ReviewLabel) {
+		return nil, fmt.Errorf("%s is not a review bead", reviewID)
+	}
+	if review.Status == "closed" {
+		return nil, fmt.Errorf("review %s is already closed", reviewID)
+	}
+
+	fields.Outcome = outcome
+	fields.ReviewedBy = reviewer
+	fields.Comment = comment
+	desc := beads.SetReviewFields(review, fields)
+	if err := bd.Update(reviewID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return nil, fmt.Errorf("recording outcome on %s: %w", reviewID, err)
+	}
+	if err := bd.CloseWithReason(outcome, reviewID); err != nil {
+		return nil, fmt.Errorf("closing review %s: %w", reviewID, err)
+	}
+	return fields, nil
+}
+
+// sortedKeys returns the keys of set, sorted.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchOwnerPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/cmd/root.go", true},
+		{"docs/", "docs/reference.md", true},
+		{"docs/", "internal/docs/x.md", true}, // trailing slash alone doesn't anchor
+		{"/docs/", "internal/docs/x.md", false},
+		{"internal/refinery", "internal/refinery/engineer.go", true},
+		{"internal/refinery", "cmd/internal/refinery/x.go", false},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+		{"internal/**/types.go", "internal/config/types.go", true},
+	} {
+		if got := matchOwnerPattern(tc.pattern, tc.file); got != tc.want {
+			t.Errorf("matchOwnerPattern(%q, %q) = %v, want %v", tc.pattern, tc.file, got, tc.want)
+		}
+	}
+}
+
+func TestOwnersOf_LastMatchWins(t *testing.T) {
+	rules := parseOwners(`
+# Default owners
+*               @alice
+internal/config @bob @carol  # config team
+internal/config/generated.go
+`)
+
+	for _, tc := range []struct {
+		file    string
+		owners  []string
+		matched bool
+	}{
+		{"README.md", []string{"@alice"}, true},
+		{"internal/config/types.go", []string{"@bob", "@carol"}, true},
+		{"internal/config/generated.go", []string{}, true}, // explicitly unowned
+	} {
+		owners, matched := ownersOf(rules, tc.file)
+		if matched != tc.matched || len(owners) != len(tc.owners) || (len(owners) > 0 && !reflect.DeepEqual(owners, tc.owners)) {
+			t.Errorf("ownersOf(%q) = %v, %v; want %v, %v", tc.file, owners, matched, tc.owners, tc.matched)
+		}
+	}
+
+	if _, matched := ownersOf(parseOwners("docs/ @alice"), "main.go"); matched {
+		t.Error("expected main.go to match no rule")
+	}
+}
+
+func TestReviewFor(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	e.rig.Crew = []string{"alice"}
+	e.config.Review = &config.ReviewConfig{
+		Enabled:  true,
+		OwnerMap: map[string]string{"@org/infra": "test-rig/crew/ops"},
+		Reviewer: "test-rig/crew/lead",
+	}
+
+	// CODEOWNERS is read from the target branch.
+	commitFile(t, dir, ".github/CODEOWNERS", "*.md @alice\n/deploy/ @org/infra\n/scripts/ @stranger\n", "add codeowners")
+	runGit(t, dir, "push", "-q", "origin", "main")
+
+	// The rig's OWNERS file takes precedence over CODEOWNERS.
+	ownersPath := filepath.Join(filepath.Dir(config.RigSettingsPath(e.rig.Path)), ownersFile)
+	if err := os.MkdirAll(filepath.Dir(ownersPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ownersPath, []byte("CHANGELOG.md test-rig/crew/release\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	addBranch(t, dir, "polecat/unowned", "src/main.go", "package main\n")
+	if req, err := e.ReviewFor(&MRInfo{Branch: "polecat/unowned", Target: "main"}); err != nil || req != nil {
+		t.Fatalf("unowned change: ReviewFor = %+v, %v; want nil", req, err)
+	}
+
+	runGit(t, dir, "checkout", "-q", "-b", "polecat/owned", "main")
+	commitFile(t, dir, "README.md", "changed\n", "docs")
+	commitFile(t, dir, "CHANGELOG.md", "v2\n", "changelog")
+	commitFile(t, dir, "deploy/app.yaml", "replicas: 2\n", "deploy")
+	runGit(t, dir, "checkout", "-q", "main")
+
+	req, err := e.ReviewFor(&MRInfo{Branch: "polecat/owned", Target: "main"})
+	if err != nil || req == nil {
+		t.Fatalf("ReviewFor = %+v, %v", req, err)
+	}
+	if want := []string{"CHANGELOG.md", "README.md", "deploy/app.yaml"}; !reflect.DeepEqual(req.Paths, want) {
+		t.Errorf("Paths = %v, want %v", req.Paths, want)
+	}
+	if want := []string{"test-rig/crew/alice", "test-rig/crew/ops", "test-rig/crew/release"}; !reflect.DeepEqual(req.Owners, want) {
+		t.Errorf("Owners = %v, want %v", req.Owners, want)
+	}
+	if len(req.Unmapped) != 0 {
+		t.Errorf("Unmapped = %v, want none", req.Unmapped)
+	}
+
+	// An owner with no Gas Town address falls back to the reviewer.
+	addBranch(t, dir, "polecat/scripts", "scripts/build.sh", "make\n")
+	req, err = e.ReviewFor(&MRInfo{Branch: "polecat/scripts", Target: "main"})
+	if err != nil || req == nil {
+		t.Fatalf("ReviewFor = %+v, %v", req, err)
+	}
+	if got := e.reviewers(req); !reflect.DeepEqual(got, []string{"test-rig/crew/lead"}) {
+		t.Errorf("reviewers = %v, want the configured reviewer", got)
+	}
+}
+
+func TestCheckReview_DisabledAllowsMerge(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	addBranch(t, dir, "polecat/nux", "README.md", "changed\n")
+
+	if _, ok := e.checkReview(&MRInfo{ID: "mr-1", Branch: "polecat/nux", Target: "main"}); !ok {
+		t.Error("expected the review gate to be a no-op when disabled")
+	}
+}
+
+func TestReviewOutcome(t *testing.T) {
+	tests := []struct {
+		outcome, closeReason string
+		want                 string
+	}{
+		{beads.ReviewApproved, "", beads.ReviewApproved},
+		{beads.ReviewChangesRequested, "approved", beads.ReviewChangesRequested},
+		{"", "Approved", beads.ReviewApproved},
+		{"", "changes_requested", beads.ReviewChangesRequested},
+		{"", "done", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		review := &beads.Issue{Status: "closed", CloseReason: tt.closeReason}
+		if got := reviewOutcome(review, &beads.ReviewFields{Outcome: tt.outcome}); got != tt.want {
+			t.Errorf("reviewOutcome(outcome %q, reason %q) = %q, want %q", tt.outcome, tt.closeReason, got, tt.want)
+		}
+	}
+}
@@ -4,7 +4,13 @@
 package refinery
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // ScoreConfig contains tunable weights for MR priority scoring.
@@ -37,6 +43,31 @@ type ScoreConfig struct {
 	// MaxRetryPenalty caps the total retry penalty to prevent permanent deprioritization.
 	// Default: 300.0 (after 6 retries, penalty is capped)
 	MaxRetryPenalty float64
+
+	// LabelWeights adds points for each label an MR carries, so urgent work
+	// jumps the queue. Labels not listed add nothing.
+	// Default: hotfix +300, security +300
+	LabelWeights map[string]float64
+
+	// DiffSizeWeight is points subtracted per 100 changed lines, so small,
+	// low-risk changes land ahead of large ones.
+	// Default: 5.0 (a 1000-line diff loses 50 pts)
+	DiffSizeWeight float64
+
+	// MaxDiffSizePenalty caps the diff size penalty.
+	// Default: 100.0
+	MaxDiffSizePenalty float64
+
+	// ConvoyDeadlineWeight is points added per hour an MR's convoy is inside
+	// ConvoyDeadlineHorizon of its deadline. Overdue convoys get the full
+	// horizon's worth.
+	// Default: 10.0 (up to +240 with a 24h horizon)
+	ConvoyDeadlineWeight float64
+
+	// ConvoyDeadlineHorizon is how long before a convoy's deadline its MRs
+	// start gaining points.
+	// Default: 24h
+	ConvoyDeadlineHorizon time.Duration
 }
 
 // DefaultScoreConfig returns sensible defaults for MR scoring.
@@ -48,7 +79,77 @@ func DefaultScoreConfig() ScoreConfig {
 		RetryPenalty:    50.0,
 		MRAgeWeight:     1.0,
 		MaxRetryPenalty: 300.0,
+		LabelWeights: map[string]float64{
+			"hotfix":   300.0,
+			"security": 300.0,
+		},
+		DiffSizeWeight:        5.0,
+		MaxDiffSizePenalty:    100.0,
+		ConvoyDeadlineWeight:  10.0,
+		ConvoyDeadlineHorizon: 24 * time.Hour,
+	}
+}
+
+// LoadScoreConfig returns the scoring config for the rig at rigPath: the
+// defaults with any merge_queue.scoring overrides from its settings applied.
+// A rig without settings uses the defaults.
+func LoadScoreConfig(rigPath string) (ScoreConfig, error) {
+	cfg := DefaultScoreConfig()
+	mq, err := loadMergeQueueSettings(rigPath)
+	if err != nil || mq == nil || mq.Scoring == nil {
+		return cfg, err
+	}
+	return applyScoringSettings(cfg, mq.Scoring)
+}
+
+// loadMergeQueueSettings returns the merge_queue section of the rig settings
+// at rigPath, or nil if the rig has no settings or no such section.
+func loadMergeQueueSettings(rigPath string) (*config.MergeQueueConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loading rig settings: %w", err)
+	}
+	return settings.MergeQueue, nil
+}
+
+// applyScoringSettings overlays rig settings onto cfg.
+func applyScoringSettings(cfg ScoreConfig, s *config.ScoringConfig) (ScoreConfig, error) {
+	for dst, src := range map[*float64]*float64{
+		&cfg.BaseScore:            s.BaseScore,
+		&cfg.ConvoyAgeWeight:      s.ConvoyAgeWeight,
+		&cfg.PriorityWeight:       s.PriorityWeight,
+		&cfg.RetryPenalty:         s.RetryPenalty,
+		&cfg.MaxRetryPenalty:      s.MaxRetryPenalty,
+		&cfg.MRAgeWeight:          s.MRAgeWeight,
+		&cfg.DiffSizeWeight:       s.DiffSizeWeight,
+		&cfg.MaxDiffSizePenalty:   s.MaxDiffSizePenalty,
+		&cfg.ConvoyDeadlineWeight: s.ConvoyDeadlineWeight,
+	} {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	if len(s.LabelWeights) > 0 {
+		labels := make(map[string]float64, len(cfg.LabelWeights)+len(s.LabelWeights))
+		for label, w := range cfg.LabelWeights {
+			labels[label] = w
+		}
+		for label, w := range s.LabelWeights {
+			labels[label] = w
+		}
+		cfg.LabelWeights = labels
+	}
+	if s.ConvoyDeadlineHorizon != "" {
+		dur, err := time.ParseDuration(s.ConvoyDeadlineHorizon)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid convoy_deadline_horizon %q: %w", s.ConvoyDeadlineHorizon, err)
+		}
+		cfg.ConvoyDeadlineHorizon = dur
 	}
+	return cfg, nil
 }
 
 // ScoreInput contains the data needed to score an MR.
@@ -69,11 +170,33 @@ type ScoreInput struct {
 	// 0 = first attempt.
 	RetryCount int
 
+	// Labels are the MR bead's labels, matched against LabelWeights.
+	Labels []string
+
+	// DiffLines is how many lines the MR changes. 0 if unknown.
+	DiffLines int
+
+	// ConvoyDeadline is when the MR's convoy is due. Nil if none.
+	ConvoyDeadline *time.Time
+
 	// Now is the current time (for deterministic testing).
 	// If zero, time.Now() is used.
 	Now time.Time
 }
 
+// ScoreFactor is one term of an MR's score.
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// ScoreBreakdown is an MR's score and the factors that sum to it.
+type ScoreBreakdown struct {
+	Total   float64       `json:"total"`
+	Factors []ScoreFactor `json:"factors"`
+}
+
 // ScoreMR calculates the priority score for a merge request.
 // Higher scores mean higher priority (process first).
 //
@@ -84,21 +207,32 @@ type ScoreInput struct {
 //	      + PriorityWeight * (4 - priority)          // P0=+400, P4=+0
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
+//	      + sum(LabelWeights[label])                 // hotfix, security, ...
+//	      - min(DiffSizeWeight * lines/100, MaxDiffSizePenalty)  // Small diffs first
+//	      + ConvoyDeadlineWeight * hoursInsideHorizon(deadline)  // Meet deadlines
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	return ExplainScore(input, config).Total
+}
+
+// ExplainScore calculates an MR's score like ScoreMR, itemized by factor.
+func ExplainScore(input ScoreInput, cfg ScoreConfig) ScoreBreakdown {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	var b ScoreBreakdown
+	add := func(name string, points float64, detail string) {
+		b.Factors = append(b.Factors, ScoreFactor{Name: name, Points: points, Detail: detail})
+		b.Total += points
+	}
+
+	add("base", cfg.BaseScore, "")
 
 	// Convoy age factor: prevent starvation of old convoys
 	if input.ConvoyCreatedAt != nil {
-		convoyAge := now.Sub(*input.ConvoyCreatedAt)
-		convoyHours := convoyAge.Hours()
-		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
-		}
+		convoyHours := max(now.Sub(*input.ConvoyCreatedAt).Hours(), 0)
+		add("convoy_age", cfg.ConvoyAgeWeight*convoyHours, fmt.Sprintf("convoy %.1fh old", convoyHours))
 	}
 
 	// Priority factor: P0 (0) gets +400, P4 (4) gets +0
@@ -109,23 +243,73 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	add("priority", cfg.PriorityWeight*float64(priorityBonus), fmt.Sprintf("P%d", input.Priority))
 
 	// Retry penalty: prevent thrashing on repeatedly failing MRs
-	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
-	if retryPenalty > config.MaxRetryPenalty {
-		retryPenalty = config.MaxRetryPenalty
+	if input.RetryCount > 0 {
+		retryPenalty := min(cfg.RetryPenalty*float64(input.RetryCount), cfg.MaxRetryPenalty)
+		add("retries", -retryPenalty, fmt.Sprintf("%d retries", input.RetryCount))
 	}
-	score -= retryPenalty
 
 	// MR age factor: FIFO ordering as tiebreaker
-	mrAge := now.Sub(input.MRCreatedAt)
-	mrHours := mrAge.Hours()
-	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+	mrHours := max(now.Sub(input.MRCreatedAt).Hours(), 0)
+	add("mr_age", cfg.MRAgeWeight*mrHours, fmt.Sprintf("queued %.1fh", mrHours))
+
+	// Label factor: urgent labels jump the queue. Sorted for stable output.
+	labels := append([]string(nil), input.Labels...)
+	sort.Strings(labels)
+	for _, label := range labels {
+		if w, ok := cfg.LabelWeights[label]; ok && w != 0 {
+			add("label", w, label)
+		}
+	}
+
+	// Diff size factor: small changes are cheaper to gate and less risky
+	if input.DiffLines > 0 {
+		penalty := min(cfg.DiffSizeWeight*float64(input.DiffLines)/100, cfg.MaxDiffSizePenalty)
+		add("diff_size", -penalty, fmt.Sprintf("%d lines", input.DiffLines))
+	}
+
+	// Convoy deadline factor: ramp up as the deadline approaches
+	if input.ConvoyDeadline != nil && cfg.ConvoyDeadlineHorizon > 0 {
+		left := input.ConvoyDeadline.Sub(now)
+		inside := min(max(cfg.ConvoyDeadlineHorizon-left, 0), cfg.ConvoyDeadlineHorizon)
+		detail := fmt.Sprintf("due in %s", left.Round(time.Minute))
+		if left <= 0 {
+			detail = fmt.Sprintf("overdue by %s", (-left).Round(time.Minute))
+		}
+		add("convoy_deadline", cfg.ConvoyDeadlineWeight*inside.Hours(), detail)
+	}
+
+	return b
+}
+
+// ScoreInputFromIssue builds the score input for an MR bead.
+func ScoreInputFromIssue(issue *beads.Issue, fields *beads.MRFields, now time.Time) ScoreInput {
+	mrCreatedAt := parseTime(issue.CreatedAt)
+	if mrCreatedAt.IsZero() {
+		mrCreatedAt = now // Fallback
 	}
 
-	return score
+	input := ScoreInput{
+		Priority:    issue.Priority,
+		MRCreatedAt: mrCreatedAt,
+		Labels:      issue.Labels,
+		Now:         now,
+	}
+
+	// Add fields from MR metadata if available
+	if fields != nil {
+		input.RetryCount = fields.RetryCount
+		input.DiffLines = fields.DiffLines
+		if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
+			input.ConvoyCreatedAt = &convoyTime
+		}
+		if deadline := parseTime(fields.ConvoyDeadline); !deadline.IsZero() {
+			input.ConvoyDeadline = &deadline
+		}
+	}
+	return input
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
@@ -141,12 +325,23 @@ func (mr *MRInfo) Score() float64 {
 
 // ScoreAt calculates the priority score at a specific time (for deterministic testing).
 func (mr *MRInfo) ScoreAt(now time.Time) float64 {
-	input := ScoreInput{
+	return ScoreMRWithDefaults(mr.scoreInput(now))
+}
+
+// ScoreWith calculates the priority score at now using cfg.
+func (mr *MRInfo) ScoreWith(cfg ScoreConfig, now time.Time) float64 {
+	return ScoreMR(mr.scoreInput(now), cfg)
+}
+
+func (mr *MRInfo) scoreInput(now time.Time) ScoreInput {
+	return ScoreInput{
 		Priority:        mr.Priority,
 		MRCreatedAt:     mr.CreatedAt,
 		ConvoyCreatedAt: mr.ConvoyCreatedAt,
 		RetryCount:      mr.RetryCount,
+		Labels:          mr.Labels,
+		DiffLines:       mr.DiffLines,
+		ConvoyDeadline:  mr.ConvoyDeadline,
 		Now:             now,
 	}
-	return ScoreMRWithDefaults(input)
 }
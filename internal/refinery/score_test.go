@@ -0,0 +1,157 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestExplainScore_FactorsSumToTotal(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	convoyCreated := now.Add(-10 * time.Hour)
+	deadline := now.Add(4 * time.Hour)
+	input := ScoreInput{
+		Priority:        1,
+		MRCreatedAt:     now.Add(-2 * time.Hour),
+		ConvoyCreatedAt: &convoyCreated,
+		RetryCount:      2,
+		Labels:          []string{"gt:merge-request", "security", "hotfix"},
+		DiffLines:       600,
+		ConvoyDeadline:  &deadline,
+		Now:             now,
+	}
+
+	b := ExplainScore(input, DefaultScoreConfig())
+
+	want := map[string]float64{
+		"base":            1000,
+		"convoy_age":      100, // 10h * 10
+		"priority":        300, // P1
+		"retries":         -100,
+		"mr_age":          2,
+		"diff_size":       -30, // 600 lines * 5/100
+		"convoy_deadline": 200, // 20h inside a 24h horizon * 10
+	}
+	var sum, labels float64
+	for _, f := range b.Factors {
+		sum += f.Points
+		if f.Name == "label" {
+			labels += f.Points
+			continue
+		}
+		if w, ok := want[f.Name]; !ok || w != f.Points {
+			t.Errorf("factor %s = %v (%s), want %v", f.Name, f.Points, f.Detail, w)
+		}
+	}
+	if labels != 600 {
+		t.Errorf("label points = %v, want 600 (hotfix + security)", labels)
+	}
+	if sum != b.Total {
+		t.Errorf("factors sum to %v, total is %v", sum, b.Total)
+	}
+	if got := ScoreMR(input, DefaultScoreConfig()); got != b.Total {
+		t.Errorf("ScoreMR = %v, ExplainScore total = %v", got, b.Total)
+	}
+}
+
+func TestExplainScore_CapsAndOverdue(t *testing.T) {
+	now := time.Now()
+	overdue := now.Add(-6 * time.Hour)
+	b := ExplainScore(ScoreInput{
+		Priority:       4,
+		MRCreatedAt:    now,
+		RetryCount:     20,
+		DiffLines:      100000,
+		ConvoyDeadline: &overdue,
+		Now:            now,
+	}, DefaultScoreConfig())
+
+	got := make(map[string]float64)
+	for _, f := range b.Factors {
+		got[f.Name] = f.Points
+	}
+	if got["retries"] != -300 {
+		t.Errorf("retries = %v, want capped at -300", got["retries"])
+	}
+	if got["diff_size"] != -100 {
+		t.Errorf("diff_size = %v, want capped at -100", got["diff_size"])
+	}
+	if got["convoy_deadline"] != 240 {
+		t.Errorf("overdue convoy_deadline = %v, want the full horizon (240)", got["convoy_deadline"])
+	}
+}
+
+func TestLoadScoreConfig_RigSettings(t *testing.T) {
+	rigPath := t.TempDir()
+
+	cfg, err := LoadScoreConfig(rigPath)
+	if err != nil {
+		t.Fatalf("LoadScoreConfig without settings: %v", err)
+	}
+	if cfg.PriorityWeight != DefaultScoreConfig().PriorityWeight {
+		t.Errorf("expected defaults without settings, got %+v", cfg)
+	}
+
+	settings := `{
+  "type": "rig-settings",
+  "version": 1,
+  "merge_queue": {
+    "scoring": {
+      "priority_weight": 50,
+      "diff_size_weight": 0,
+      "label_weights": {"hotfix": 0, "customer": 250},
+      "convoy_deadline_horizon": "72h"
+    }
+  }
+}`
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = LoadScoreConfig(rigPath)
+	if err != nil {
+		t.Fatalf("LoadScoreConfig: %v", err)
+	}
+	if cfg.PriorityWeight != 50 || cfg.DiffSizeWeight != 0 || cfg.ConvoyDeadlineHorizon != 72*time.Hour {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+	if cfg.BaseScore != 1000 || cfg.RetryPenalty != 50 {
+		t.Errorf("unset weights should keep defaults: %+v", cfg)
+	}
+	if cfg.LabelWeights["hotfix"] != 0 || cfg.LabelWeights["security"] != 300 || cfg.LabelWeights["customer"] != 250 {
+		t.Errorf("label weights = %v, want hotfix disabled, security default, customer added", cfg.LabelWeights)
+	}
+}
+
+func TestScoreInputFromIssue(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	issue := &beads.Issue{
+		ID:        "gt-mr-1",
+		Priority:  2,
+		CreatedAt: "2026-01-10T10:00:00Z",
+		Labels:    []string{"hotfix"},
+	}
+	fields := &beads.MRFields{
+		RetryCount:      1,
+		DiffLines:       42,
+		ConvoyCreatedAt: "2026-01-09T12:00:00Z",
+		ConvoyDeadline:  "2026-01-11T00:00:00Z",
+	}
+
+	input := ScoreInputFromIssue(issue, fields, now)
+	if input.Priority != 2 || input.RetryCount != 1 || input.DiffLines != 42 || len(input.Labels) != 1 {
+		t.Errorf("unexpected input: %+v", input)
+	}
+	if input.ConvoyCreatedAt == nil || input.ConvoyDeadline == nil {
+		t.Fatalf("expected convoy times parsed, got %+v", input)
+	}
+	if !input.ConvoyDeadline.Equal(time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ConvoyDeadline = %v", input.ConvoyDeadline)
+	}
+}
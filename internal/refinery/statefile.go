@@ -0,0 +1,29 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the lock shared by the refinery's rig state files.
+
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// lockStateFile takes the cross-process lock on a state file under
+// <rig>/.runtime/. Parallel workers, and gt commands such as gt mq flakes
+// and gt mq freeze, each hold their own handle on the rig's state files
+// (gate cache, flake ledger, gate stats, manual freezes, incident log), so
+// only a file lock keeps their read-modify-writes from losing each other's
+// updates.
+func lockStateFile(path string) (*flock.Flock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("locking %s: %w", filepath.Base(path), err)
+	}
+	return fl, nil
+}
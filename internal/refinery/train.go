@@ -0,0 +1,413 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains speculative batched merging (merge trains).
+
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tracing"
+)
+
+// trainBranchPrefix namespaces the temporary branches a merge train is
+// stacked on. They live only for the duration of a single ProcessTrain call.
+const trainBranchPrefix = "refinery/train/"
+
+// TrainResult holds the outcome for one MR in a merge train.
+type TrainResult struct {
+	MR     *MRInfo
+	Result ProcessResult
+
+	// Deferred is true when the MR was not decided by this train, e.g. it sat
+	// behind the failing MR or only conflicted with MRs ahead of it. Deferred
+	// MRs stay in the queue for the next train without any notification.
+	Deferred bool
+}
+
+// trainEnabled reports whether the merge queue is configured for trains.
+func (e *Engineer) trainEnabled() bool {
+	return e.config.TrainSize > 1
+}
+
+// NextTrain returns the MRs for the next merge train: the highest-scoring
+// ready MRs (by the rig's scoring at now) that share a target with the top MR, capped at
+// TrainSize. With trains disabled this is just the single top MR. MRs for
+// frozen targets are left out.
+func (e *Engineer) NextTrain(now time.Time) ([]*MRInfo, error) {
+	mrs, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	return selectTrain(e.withoutFrozen(mrs), e.config.TrainSize, e.config.MergeStrategy, e.config.Scoring, now), nil
+}
+
+// selectTrain orders mrs by score and picks up to size MRs targeting the
+// same branch as the highest-scoring one. Trains never mix targets since the
+// combination is gated and landed as a single push.
+//
+// Trains stack squash commits, so only MRs that land by squash (their own
+// merge_strategy, or defaultStrategy) ride together. If the top MR uses
+// another strategy it travels alone and ProcessTrain merges it normally.
+func selectTrain(mrs []*MRInfo, size int, defaultStrategy string, scoring ScoreConfig, now time.Time) []*MRInfo {
+	if len(mrs) == 0 {
+		return nil
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	sorted := make([]*MRInfo, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreWith(scoring, now) > sorted[j].ScoreWith(scoring, now)
+	})
+
+	squash := func(mr *MRInfo) bool {
+		strategy := mr.MergeStrategy
+		if strategy == "" {
+			strategy = defaultStrategy
+		}
+		return strategy == "" || strategy == MergeStrategySquash
+	}
+	if !squash(sorted[0]) {
+		return sorted[:1]
+	}
+
+	target := sorted[0].Target
+	var train []*MRInfo
+	for _, mr := range sorted {
+		if mr.Target != target || !squash(mr) {
+			continue
+		}
+		train = append(train, mr)
+		if len(train) == size {
+			break
+		}
+	}
+	return train
+}
+
+// ProcessTrain speculatively merges mrs (in order) onto a temporary branch
+// off their shared target and runs the quality gates once on the combination.
+//
+// If the gates pass, the whole train lands in a single push. If they fail,
+// the train is bisected over its prefixes to find the first MR whose addition
+// breaks the gates: that MR fails, the passing prefix ahead of it lands, and
+// everything behind it is deferred to the next train. While the target is
+// frozen, the passing prefix is held (Frozen) instead of landing. MRs held
+// by the review gate are left out of the train.
+//
+// An MR that conflicts with the target itself (it is first in the train)
+// fails as a conflict. An MR that only conflicts with MRs stacked ahead of it
+// is deferred, since those MRs may not land.
+func (e *Engineer) ProcessTrain(ctx context.Context, mrs []*MRInfo) []TrainResult {
+	results := make([]TrainResult, len(mrs))
+	for i, mr := range mrs {
+		results[i].MR = mr
+	}
+	if len(mrs) == 0 {
+		return results
+	}
+
+	// A lone MR with a non-squash strategy can't be stacked; merge it normally.
+	if len(mrs) == 1 && e.mergeStrategyFor(mrs[0]) != MergeStrategySquash {
+		results[0].Result = e.ProcessMRInfo(ctx, mrs[0])
+		return results
+	}
+
+	target := mrs[0].Target
+	ctx, endSpans := e.startTrainSpans(ctx, mrs, results)
+	defer endSpans()
+
+	failAll := func(msg string) []TrainResult {
+		for i := range results {
+			results[i].Result = ProcessResult{Success: false, Error: msg}
+		}
+		return results
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Building merge train of %d MR(s) onto %s\n", len(mrs), target)
+	if err := e.git.Checkout(target); err != nil {
+		return failAll(fmt.Sprintf("failed to checkout target %s: %v", target, err))
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+
+	trainBranch := fmt.Sprintf("%s%s-%d", trainBranchPrefix, strings.ReplaceAll(target, "/", "-"), time.Now().UnixNano())
+	if err := e.git.CreateBranchFrom(trainBranch, target); err != nil {
+		return failAll(fmt.Sprintf("failed to create train branch: %v", err))
+	}
+	defer func() {
+		if err := e.git.Checkout(target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to checkout %s after train: %v\n", target, err)
+		}
+		if err := e.git.DeleteBranch(trainBranch, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete train branch %s: %v\n", trainBranch, err)
+		}
+	}()
+	if err := e.git.Checkout(trainBranch); err != nil {
+		return failAll(fmt.Sprintf("failed to checkout train branch: %v", err))
+	}
+
+	// Step 1: Stack each MR as one squash commit. cars[i] is the index into
+	// mrs of the i-th stacked MR; heads[i] is the train tip after stacking it.
+	var cars []int
+	var heads []string
+	for i, mr := range mrs {
+		if mr.Target != target || e.mergeStrategyFor(mr) != MergeStrategySquash {
+			results[i].Deferred = true
+			continue
+		}
+		exists, err := e.git.BranchExists(mr.Branch)
+		if err != nil || !exists {
+			results[i].Result = ProcessResult{Success: false, Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}
+			continue
+		}
+		if result, ok := e.checkReview(mr); !ok {
+			results[i].Result = result
+			continue
+		}
+
+		msg, err := e.git.GetBranchCommitMessage(mr.Branch)
+		if err != nil {
+			msg = fmt.Sprintf("Squash merge %s into %s", mr.Branch, target)
+			if mr.SourceIssue != "" {
+				msg = fmt.Sprintf("Squash merge %s into %s (%s)", mr.Branch, target, mr.SourceIssue)
+			}
+		}
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			conflicts, _ := e.git.GetConflictingFiles()
+			// A squash merge leaves no MERGE_HEAD, so reset instead of merge --abort.
+			_ = e.git.ResetHard("HEAD")
+			switch {
+			case len(conflicts) > 0 && len(cars) == 0:
+				results[i].Result = ProcessResult{Success: false, Conflict: true, Error: fmt.Sprintf("merge conflicts in: %v", conflicts)}
+			case len(conflicts) > 0:
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Train: %s conflicts with MRs ahead of it, deferring\n", mr.ID)
+				results[i].Deferred = true
+			default:
+				results[i].Result = ProcessResult{Success: false, Error: fmt.Sprintf("merge failed: %v", err)}
+			}
+			continue
+		}
+
+		if err := e.pushSubmoduleChanges(target, mr.Branch); err != nil {
+			_ = e.git.ResetHard(e.trainTip(heads, target))
+			results[i].Result = ProcessResult{Success: false, Error: err.Error()}
+			continue
+		}
+
+		head, err := e.git.Rev("HEAD")
+		if err != nil {
+			return failAll(fmt.Sprintf("failed to get train head: %v", err))
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Train car %d: %s (%s)\n", len(cars)+1, mr.ID, head[:8])
+		cars = append(cars, i)
+		heads = append(heads, head)
+	}
+
+	if len(cars) == 0 {
+		return results
+	}
+
+	// Step 2: Gate the full train once.
+	landed := len(cars)
+	gateResult := e.runQualityChecks(ctx, "origin/"+target)
+	if !gateResult.Success {
+		// Step 3: Bisect over prefixes. Invariant: prefix lo passes (the empty
+		// prefix is the target, assumed green) and prefix hi fails with hiResult.
+		lo, hi := 0, len(cars)
+		hiResult := gateResult
+		for hi-lo > 1 && ctx.Err() == nil {
+			mid := (lo + hi) / 2
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Train bisect: gating first %d of %d car(s)\n", mid, len(cars))
+			tracing.SpanFromContext(ctx).AddEvent(fmt.Sprintf("bisect: gating first %d of %d car(s)", mid, len(cars)), time.Now())
+			if err := e.git.ResetHard(heads[mid-1]); err != nil {
+				return failAll(fmt.Sprintf("train bisect reset failed: %v", err))
+			}
+			r := e.runQualityChecks(ctx, "origin/"+target)
+			if r.Success {
+				lo = mid
+			} else {
+				hi, hiResult = mid, r
+			}
+		}
+
+		culprit := cars[hi-1]
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Train: %s breaks the gates\n", mrs[culprit].ID)
+		results[culprit].Result = hiResult
+		for _, idx := range cars[hi:] {
+			results[idx].Deferred = true
+		}
+		landed = lo
+	}
+
+	if landed == 0 {
+		return results
+	}
+
+	// Step 4: Hold the passing prefix while the target is frozen; its gates
+	// are cached for the next train.
+	if freeze := e.activeFreeze(target); freeze != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Holding train push: %s\n", freeze)
+		for _, idx := range cars[:landed] {
+			results[idx].Result = ProcessResult{Success: false, Frozen: true, Error: freeze.String()}
+		}
+		return results
+	}
+
+	// Step 5: Land the passing prefix in one push.
+	verify, err := e.landTrain(ctx, target, heads[landed-1], fmt.Sprintf("merge train of %d MR(s)", landed))
+	if err != nil {
+		for _, idx := range cars[:landed] {
+			results[idx].Result = ProcessResult{
+				Success:     false,
+				SlotTimeout: errors.Is(err, errMergeSlotTimeout),
+				Error:       err.Error(),
+			}
+		}
+		return results
+	}
+	if !verify.Success {
+		// The whole push is reverted as one, so every car it carried failed.
+		for n, idx := range cars[:landed] {
+			results[idx].Result = verify
+			results[idx].Result.MergeCommit = heads[n]
+		}
+		return results
+	}
+	for n, idx := range cars[:landed] {
+		results[idx].Result = ProcessResult{Success: true, MergeCommit: heads[n]}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Train landed %d MR(s) on %s\n", landed, target)
+	return results
+}
+
+// startTrainSpans traces a train. Each traced MR gets its own
+// refinery.process_mr span under gt done's, ended with its result when the
+// train is decided (the returned func). The train's gate runs, bisection,
+// and push are traced under one refinery.train span, carried in the returned
+// ctx and linked both ways with the MRs' spans, since a span has only one
+// parent. Untraced trains get no spans.
+func (e *Engineer) startTrainSpans(ctx context.Context, mrs []*MRInfo, results []TrainResult) (context.Context, func()) {
+	spans := make([]*tracing.Span, len(mrs))
+	var train *tracing.Span
+	for i, mr := range mrs {
+		spans[i] = e.startProcessSpan(mr)
+		if spans[i] == nil {
+			continue
+		}
+		if train == nil {
+			train = e.tracer.Start("refinery.train", tracing.SpanContext{})
+			train.SetAttr("gt.target", mr.Target)
+			train.SetAttr("gt.train.size", len(mrs))
+		}
+		train.AddLink(spans[i].Context())
+		spans[i].AddLink(train.Context())
+	}
+	if train == nil {
+		return ctx, func() {}
+	}
+
+	return tracing.ContextWithSpan(ctx, train), func() {
+		landed := 0
+		for i, span := range spans {
+			if results[i].Result.Success {
+				landed++
+			}
+			if span == nil {
+				continue
+			}
+			if results[i].Deferred {
+				span.SetAttr("gt.outcome", "deferred")
+				span.End(nil)
+				continue
+			}
+			endProcessSpan(span, results[i].Result)
+		}
+		train.SetAttr("gt.train.landed", landed)
+		train.End(nil)
+	}
+}
+
+// trainTip returns the current train head, or the target if nothing is stacked.
+func (e *Engineer) trainTip(heads []string, target string) string {
+	if len(heads) == 0 {
+		return target
+	}
+	return heads[len(heads)-1]
+}
+
+// landTrain fast-forwards target to head and pushes it, holding the push lock
+// and, when target is the rig's default branch, the main push slot (same as
+// doMerge). Once pushed,
+// the head is checked by the post-merge gates, whose result is returned;
+// landed describes the train for a revert commit message.
+func (e *Engineer) landTrain(ctx context.Context, target, head, landed string) (ProcessResult, error) {
+	if err := e.git.Checkout(target); err != nil {
+		return ProcessResult{}, fmt.Errorf("failed to checkout target %s: %w", target, err)
+	}
+	base, baseErr := e.git.Rev("origin/" + target)
+	if err := e.git.ResetHard(head); err != nil {
+		return ProcessResult{}, fmt.Errorf("failed to advance %s to train head: %w", target, err)
+	}
+
+	// Take turns with parallel workers pushing through doMerge.
+	e.pushLock.Lock()
+	defer e.pushLock.Unlock()
+	if target == e.rig.DefaultBranch() {
+		holder, err := e.acquireMainPushSlot(ctx)
+		if err != nil {
+			_ = e.git.ResetHard("origin/" + target)
+			return ProcessResult{}, fmt.Errorf("failed to acquire merge slot before push: %w", err)
+		}
+		if holder != "" {
+			defer func() {
+				if releaseErr := e.mergeSlotRelease(holder); releaseErr != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", holder, releaseErr)
+				}
+			}()
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing train to origin/%s...\n", target)
+	if err := e.git.Push("origin", target, false); err != nil {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after push failure: %v\n", target, resetErr)
+		}
+		return ProcessResult{}, fmt.Errorf("failed to push to origin: %w", err)
+	}
+
+	if len(e.config.PostMergeGates) > 0 {
+		if baseErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: skipping post-merge gates, no pre-push head of %s: %v\n", target, baseErr)
+		} else {
+			return e.verifyPostMerge(ctx, target, base, landed), nil
+		}
+	}
+	return ProcessResult{Success: true}, nil
+}
+
+// HandleTrainResults applies the outcome of a train to each MR: landed MRs go
+// through HandleMRInfoSuccess, failed MRs through HandleMRInfoFailure, and
+// deferred MRs are left in the queue untouched. Every decided MR's outcome is
+// sent to the witness through NotifyMergeOutcome.
+func (e *Engineer) HandleTrainResults(results []TrainResult) {
+	for _, tr := range results {
+		switch {
+		case tr.Deferred:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Deferred to next train: %s\n", tr.MR.ID)
+		case tr.Result.Success:
+			e.HandleMRInfoSuccess(tr.MR, tr.Result)
+			e.notifyMergeOutcome(tr.MR, tr.Result, "")
+		default:
+			e.HandleMRInfoFailure(tr.MR, tr.Result)
+		}
+	}
+}
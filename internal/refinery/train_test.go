@@ -0,0 +1,221 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tracing"
+)
+
+func TestSelectTrain_OrdersByScoreAndGroupsByTarget(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "p3", Target: "main", Priority: 3, CreatedAt: now},
+		{ID: "p0", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "int", Target: "integration/x", Priority: 1, CreatedAt: now},
+		{ID: "p1", Target: "main", Priority: 1, CreatedAt: now},
+	}
+
+	train := selectTrain(mrs, 2, MergeStrategySquash, DefaultScoreConfig(), now)
+	if len(train) != 2 {
+		t.Fatalf("expected 2 MRs, got %d", len(train))
+	}
+	if train[0].ID != "p0" || train[1].ID != "p1" {
+		t.Errorf("expected [p0 p1], got [%s %s]", train[0].ID, train[1].ID)
+	}
+
+	if got := selectTrain(mrs, 0, MergeStrategySquash, DefaultScoreConfig(), now); len(got) != 1 || got[0].ID != "p0" {
+		t.Errorf("expected size 0 to select just the top MR, got %v", got)
+	}
+	if got := selectTrain(nil, 3, MergeStrategySquash, DefaultScoreConfig(), now); got != nil {
+		t.Errorf("expected nil for empty queue, got %v", got)
+	}
+}
+
+func TestProcessTrain_AllPass(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "true"}}
+
+	var mrs []*MRInfo
+	for i := 1; i <= 3; i++ {
+		branch := fmt.Sprintf("polecat/p%d", i)
+		addBranch(t, dir, branch, fmt.Sprintf("f%d.txt", i), "ok\n")
+		mrs = append(mrs, &MRInfo{ID: fmt.Sprintf("mr-%d", i), Branch: branch, Target: "main"})
+	}
+
+	results := e.ProcessTrain(context.Background(), mrs)
+	for _, r := range results {
+		if !r.Result.Success {
+			t.Errorf("%s: expected success, got %q (deferred=%v)", r.MR.ID, r.Result.Error, r.Deferred)
+		}
+	}
+
+	// All three landed on origin in a single push, in train order
+	log := runGit(t, dir, "log", "--format=%s", "origin/main")
+	if !strings.HasPrefix(log, "feat: polecat/p3\nfeat: polecat/p2\nfeat: polecat/p1") {
+		t.Errorf("unexpected origin/main history:\n%s", log)
+	}
+	if results[2].Result.MergeCommit != runGit(t, dir, "rev-parse", "origin/main") {
+		t.Error("expected last car's merge commit to be the new origin/main")
+	}
+	if branches := runGit(t, dir, "branch", "--list", trainBranchPrefix+"*"); branches != "" {
+		t.Errorf("expected train branch cleaned up, got %q", branches)
+	}
+}
+
+func TestProcessTrain_BisectsToFailingMR(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	// Gate fails whenever the "bad" marker file is present in the tree
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "test ! -f bad.txt"}}
+
+	addBranch(t, dir, "polecat/a", "a.txt", "ok\n")
+	addBranch(t, dir, "polecat/b", "b.txt", "ok\n")
+	addBranch(t, dir, "polecat/c", "bad.txt", "boom\n")
+	addBranch(t, dir, "polecat/d", "d.txt", "ok\n")
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+		{ID: "mr-c", Branch: "polecat/c", Target: "main"},
+		{ID: "mr-d", Branch: "polecat/d", Target: "main"},
+	}
+
+	results := e.ProcessTrain(context.Background(), mrs)
+
+	for _, i := range []int{0, 1} {
+		if !results[i].Result.Success {
+			t.Errorf("%s: expected to land, got %q", mrs[i].ID, results[i].Result.Error)
+		}
+	}
+	if results[2].Result.Success || !results[2].Result.TestsFailed || results[2].Deferred {
+		t.Errorf("mr-c: expected gate failure, got %+v", results[2])
+	}
+	if !results[3].Deferred {
+		t.Errorf("mr-d: expected deferral behind failing MR, got %+v", results[3])
+	}
+
+	log := runGit(t, dir, "log", "--format=%s", "origin/main")
+	if !strings.HasPrefix(log, "feat: polecat/b\nfeat: polecat/a\ninitial") {
+		t.Errorf("expected only the passing prefix on origin/main, got:\n%s", log)
+	}
+}
+
+func TestProcessTrain_Traced(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+	townRoot := t.TempDir()
+	e.tracer = tracing.NewWithConfig(townRoot, &config.TracingConfig{Exporter: tracing.ExporterFile})
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "test ! -f bad.txt"}}
+
+	// gt done's spans, as recorded on each MR
+	done := tracing.NewWithConfig("", nil)
+	addBranch(t, dir, "polecat/a", "a.txt", "ok\n")
+	addBranch(t, dir, "polecat/b", "bad.txt", "boom\n")
+	addBranch(t, dir, "polecat/c", "c.txt", "ok\n")
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main", Traceparent: done.Start("gt done", tracing.SpanContext{}).Traceparent()},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main", Traceparent: done.Start("gt done", tracing.SpanContext{}).Traceparent()},
+		{ID: "mr-c", Branch: "polecat/c", Target: "main", Traceparent: done.Start("gt done", tracing.SpanContext{}).Traceparent()},
+	}
+	e.ProcessTrain(context.Background(), mrs)
+
+	type span struct {
+		TraceID      string `json:"traceId"`
+		SpanID       string `json:"spanId"`
+		ParentSpanID string `json:"parentSpanId"`
+		Name         string `json:"name"`
+		Attributes   []struct {
+			Key   string `json:"key"`
+			Value struct {
+				StringValue string `json:"stringValue"`
+			} `json:"value"`
+		} `json:"attributes"`
+		Links []struct {
+			SpanID string `json:"spanId"`
+		} `json:"links"`
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, tracing.DefaultFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var train *span
+	var gates []span
+	mrSpans := make(map[string]span) // by gt.mr
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []span `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			t.Fatal(err)
+		}
+		sp := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		attrs := make(map[string]string)
+		for _, a := range sp.Attributes {
+			attrs[a.Key] = a.Value.StringValue
+		}
+		switch {
+		case sp.Name == "refinery.train":
+			train = &sp
+		case sp.Name == "gate test":
+			gates = append(gates, sp)
+		case sp.Name == "refinery.process_mr":
+			sp.Name = attrs["gt.outcome"]
+			mrSpans[attrs["gt.mr"]] = sp
+		}
+	}
+
+	if train == nil || len(train.Links) != 3 {
+		t.Fatalf("train span = %+v, want one linked to 3 MRs", train)
+	}
+	// Full train, then bisection: first 1 car passes, first 2 fail
+	if len(gates) != 3 {
+		t.Errorf("got %d gate spans, want 3", len(gates))
+	}
+	for _, g := range gates {
+		if g.TraceID != train.TraceID || g.ParentSpanID != train.SpanID {
+			t.Errorf("gate span %+v not under the train span", g)
+		}
+	}
+	for i, want := range []string{"merged", "gates_failed", "deferred"} {
+		sp, ok := mrSpans[mrs[i].ID]
+		parent, _ := tracing.ParseTraceparent(mrs[i].Traceparent)
+		if !ok || sp.TraceID != parent.TraceID || sp.ParentSpanID != parent.SpanID {
+			t.Errorf("%s: span %+v not under gt done's %+v", mrs[i].ID, sp, parent)
+			continue
+		}
+		if sp.Name != want {
+			t.Errorf("%s: outcome %q, want %q", mrs[i].ID, sp.Name, want)
+		}
+		if len(sp.Links) != 1 || sp.Links[0].SpanID != train.SpanID {
+			t.Errorf("%s: links %+v, want the train span", mrs[i].ID, sp.Links)
+		}
+	}
+}
+
+func TestProcessTrain_ConflictWithEarlierCarIsDeferred(t *testing.T) {
+	e, dir := newGitTestEngineer(t)
+
+	addBranch(t, dir, "polecat/a", "shared.txt", "from a\n")
+	addBranch(t, dir, "polecat/b", "shared.txt", "from b\n")
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+	}
+
+	results := e.ProcessTrain(context.Background(), mrs)
+	if !results[0].Result.Success {
+		t.Errorf("mr-a: expected to land, got %q", results[0].Result.Error)
+	}
+	if !results[1].Deferred || results[1].Result.Conflict {
+		t.Errorf("mr-b: expected deferral (conflicts only with train), got %+v", results[1])
+	}
+}
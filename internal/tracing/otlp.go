@@ -0,0 +1,263 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Exporter names for TracingConfig.Exporter.
+const (
+	ExporterFile = "file"
+	ExporterOTLP = "otlp"
+)
+
+// DefaultFile is where the file exporter writes, relative to the town root.
+const DefaultFile = "logs/traces.jsonl"
+
+// DefaultEndpoint is the OTLP/HTTP collector used when none is configured.
+const DefaultEndpoint = "http://localhost:4318"
+
+// otlpTimeout bounds each OTLP/HTTP export, so a missing collector costs a
+// traced command at most this long per span.
+const otlpTimeout = 2 * time.Second
+
+// The OTLP/JSON encoding of ExportTraceServiceRequest, limited to what gt
+// emits. IDs are hex and 64-bit integers are decimal strings, per the OTLP
+// JSON mapping.
+type (
+	exportRequest struct {
+		ResourceSpans []resourceSpans `json:"resourceSpans"`
+	}
+	resourceSpans struct {
+		Resource   resource     `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+	resource struct {
+		Attributes []attribute `json:"attributes"`
+	}
+	scopeSpans struct {
+		Scope scope      `json:"scope"`
+		Spans []spanData `json:"spans"`
+	}
+	scope struct {
+		Name string `json:"name"`
+	}
+	spanData struct {
+		TraceID           string      `json:"traceId"`
+		SpanID            string      `json:"spanId"`
+		ParentSpanID      string      `json:"parentSpanId,omitempty"`
+		Name              string      `json:"name"`
+		Kind              int         `json:"kind"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+		Attributes        []attribute `json:"attributes,omitempty"`
+		Events            []eventData `json:"events,omitempty"`
+		Links             []linkData  `json:"links,omitempty"`
+		Status            status      `json:"status"`
+	}
+	linkData struct {
+		TraceID string `json:"traceId"`
+		SpanID  string `json:"spanId"`
+	}
+	eventData struct {
+		TimeUnixNano string `json:"timeUnixNano"`
+		Name         string `json:"name"`
+	}
+	status struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	attribute struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// OTLP span kind and status codes.
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+// scopeName is the instrumentation scope of exported spans.
+const scopeName = "github.com/steveyegge/gastown/internal/tracing"
+
+func stringAttr(key, value string) attribute {
+	return attribute{Key: key, Value: anyValue{StringValue: &value}}
+}
+
+func newAttribute(key string, value interface{}) attribute {
+	switch v := value.(type) {
+	case string:
+		return stringAttr(key, v)
+	case bool:
+		return attribute{Key: key, Value: anyValue{BoolValue: &v}}
+	case int:
+		s := strconv.Itoa(v)
+		return attribute{Key: key, Value: anyValue{IntValue: &s}}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return attribute{Key: key, Value: anyValue{IntValue: &s}}
+	case float64:
+		return attribute{Key: key, Value: anyValue{DoubleValue: &v}}
+	}
+	return stringAttr(key, fmt.Sprint(value))
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// snapshot returns the span as exported, ending at end. Callers hold s.mu.
+func (s *Span) snapshot(end time.Time, err error) spanData {
+	data := spanData{
+		TraceID:           s.ctx.TraceID,
+		SpanID:            s.ctx.SpanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: unixNano(s.start),
+		EndTimeUnixNano:   unixNano(end),
+		Attributes:        append([]attribute(nil), s.attrs...),
+	}
+	for _, e := range s.events {
+		data.Events = append(data.Events, eventData{TimeUnixNano: unixNano(e.at), Name: e.name})
+	}
+	for _, l := range s.links {
+		data.Links = append(data.Links, linkData{TraceID: l.TraceID, SpanID: l.SpanID})
+	}
+	if err != nil {
+		data.Status = status{Code: statusCodeError, Message: err.Error()}
+	}
+	return data
+}
+
+// encode returns the ExportTraceServiceRequest for a single span.
+func encode(res []attribute, span spanData) ([]byte, error) {
+	return json.Marshal(exportRequest{ResourceSpans: []resourceSpans{{
+		Resource: resource{Attributes: res},
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: scopeName},
+			Spans: []spanData{span},
+		}},
+	}}})
+}
+
+// exporter delivers ended spans.
+type exporter interface {
+	export(res []attribute, span spanData) error
+}
+
+// newExporter returns the exporter cfg selects, or nil if none.
+func newExporter(townRoot string, cfg *config.TracingConfig) exporter {
+	switch cfg.Exporter {
+	case ExporterFile:
+		path := cfg.File
+		if path == "" {
+			path = DefaultFile
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		return &fileExporter{path: path}
+	case ExporterOTLP:
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			headers[k] = os.ExpandEnv(v)
+		}
+		return &otlpExporter{
+			url:     tracesURL(cfg.Endpoint),
+			headers: headers,
+			client:  &http.Client{Timeout: otlpTimeout},
+		}
+	}
+	return nil
+}
+
+// tracesURL returns where to post spans: the configured endpoint, else the
+// standard OTEL_EXPORTER_OTLP_* environment variables, else the default.
+func tracesURL(endpoint string) string {
+	if endpoint == "" {
+		if url := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); url != "" {
+			return url // Signal-specific endpoints are used as-is
+		}
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+}
+
+// fileExporter appends one ExportTraceServiceRequest per line to a file,
+// the format of the OpenTelemetry Collector's file exporter and receiver.
+type fileExporter struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (e *fileExporter) export(res []attribute, span spanData) error {
+	data, err := encode(res, span)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(e.path), 0755); err != nil {
+		return fmt.Errorf("creating trace directory: %w", err)
+	}
+	f, err := os.OpenFile(e.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is from town config
+	if err != nil {
+		return fmt.Errorf("opening trace file: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// otlpExporter posts spans to an OTLP/HTTP collector as JSON.
+type otlpExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (e *otlpExporter) export(res []attribute, span spanData) error {
+	data, err := encode(res, span)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting spans: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("posting spans: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+// Package tracing emits OpenTelemetry spans for work as it moves from gt
+// sling through polecat spawn, gt done, and the refinery's gates and merge.
+//
+// Spans are exported in OTLP/JSON, either appended to a file or posted to an
+// OTLP/HTTP collector, as configured by the tracing section of
+// settings/config.json. The processes involved are separate gt invocations,
+// so spans are linked through the hook bead: gt sling records its span as a
+// W3C traceparent on the bead, and later spans are started as its children.
+// Work tracked by a convoy shares the convoy's trace ID, so the whole convoy
+// renders as one trace tree.
+//
+// Tracing never fails the work being traced. A Tracer with no exporter still
+// gives spans IDs, so traceparents are recorded whether or not spans are
+// exported, and a nil *Tracer or *Span is a no-op.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ServiceName is the service.name resource attribute of exported spans.
+const ServiceName = "gastown"
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID string // 32 lowercase hex digits
+	SpanID  string // 16 lowercase hex digits, or "" for a trace with no parent span
+}
+
+// IsValid reports whether sc names a trace.
+func (sc SpanContext) IsValid() bool {
+	return isHex(sc.TraceID, 32)
+}
+
+// Traceparent returns sc as a W3C traceparent header value, or "" if sc
+// doesn't name a span.
+func (sc SpanContext) Traceparent() string {
+	if !sc.IsValid() || !isHex(sc.SpanID, 16) {
+		return ""
+	}
+	return "00-" + sc.TraceID + "-" + sc.SpanID + "-01"
+}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(s string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || !isHex(parts[0], 2) || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if sc.Traceparent() == "" || sc.TraceID == strings.Repeat("0", 32) || sc.SpanID == strings.Repeat("0", 16) {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+// TraceIDFor returns a trace ID derived from key, so separate processes
+// agree on the trace of the same work without passing it around.
+func TraceIDFor(key string) string {
+	sum := sha256.Sum256([]byte("trace\x00" + key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ConvoyContext returns the context of a convoy's root span. Work tracked by
+// the convoy is traced as its children; see StartConvoy.
+func ConvoyContext(convoyID string) SpanContext {
+	sum := sha256.Sum256([]byte("convoy\x00" + convoyID))
+	return SpanContext{TraceID: TraceIDFor("convoy:" + convoyID), SpanID: hex.EncodeToString(sum[:8])}
+}
+
+// Tracer starts spans and exports them when they end.
+type Tracer struct {
+	exporter exporter // nil when tracing is disabled
+	resource []attribute
+}
+
+// New returns a Tracer for the town at townRoot, configured by the tracing
+// section of its settings/config.json.
+func New(townRoot string) *Tracer {
+	var cfg *config.TracingConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		cfg = settings.Tracing
+	}
+	return NewWithConfig(townRoot, cfg)
+}
+
+// NewWithConfig returns a Tracer for the town at townRoot using cfg. A nil
+// cfg, or one with no exporter, disables export.
+func NewWithConfig(townRoot string, cfg *config.TracingConfig) *Tracer {
+	t := &Tracer{resource: []attribute{stringAttr("service.name", ServiceName)}}
+	if townRoot != "" {
+		t.resource = append(t.resource, stringAttr("gt.town", townRoot))
+	}
+	if cfg != nil {
+		t.exporter = newExporter(townRoot, cfg)
+	}
+	return t
+}
+
+// Enabled reports whether spans are exported.
+func (t *Tracer) Enabled() bool {
+	return t != nil && t.exporter != nil
+}
+
+// Start starts a span named name. If parent names a span, the new span is
+// its child; if it names only a trace, the span is a root in that trace;
+// otherwise the span starts a new trace.
+func (t *Tracer) Start(name string, parent SpanContext) *Span {
+	if t == nil {
+		return nil
+	}
+	s := &Span{
+		tracer: t,
+		name:   name,
+		start:  time.Now(),
+		ctx:    SpanContext{TraceID: randomHex(16), SpanID: randomHex(8)},
+	}
+	s.SetParent(parent)
+	return s
+}
+
+// StartConvoy starts the root span of a convoy's trace, with the IDs of
+// ConvoyContext, so spans for its tracked work hang off it.
+func (t *Tracer) StartConvoy(convoyID string) *Span {
+	s := t.Start("convoy", SpanContext{})
+	if s != nil {
+		s.ctx = ConvoyContext(convoyID)
+		s.SetAttr("gt.convoy", convoyID)
+	}
+	return s
+}
+
+// Span is an operation being traced. Its methods are safe to call on nil.
+type Span struct {
+	tracer *Tracer
+
+	mu       sync.Mutex
+	name     string
+	ctx      SpanContext
+	parentID string
+	start    time.Time
+	attrs    []attribute
+	events   []spanEvent
+	links    []SpanContext
+	ended    bool
+}
+
+// spanEvent is a point in time within a span, such as a first commit.
+type spanEvent struct {
+	name string
+	at   time.Time
+}
+
+// SetParent moves the span into parent's trace, as parent's child if parent
+// names a span. Spans are exported when they end, so the parent can be set
+// once it's known, such as after finding the work's convoy.
+func (s *Span) SetParent(parent SpanContext) {
+	if s == nil || !parent.IsValid() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx.TraceID = parent.TraceID
+	s.parentID = ""
+	if isHex(parent.SpanID, 16) {
+		s.parentID = parent.SpanID
+	}
+}
+
+// HasParent reports whether the span is the child of another span.
+func (s *Span) HasParent() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parentID != ""
+}
+
+// Context returns the span's context, or the zero SpanContext for nil.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx
+}
+
+// Traceparent returns the span's W3C traceparent, for recording on beads.
+func (s *Span) Traceparent() string {
+	return s.Context().Traceparent()
+}
+
+// SetAttr sets an attribute. value may be a string, bool, int, int64, or
+// float64; anything else is recorded as its string form. Empty strings are
+// skipped.
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	if v, ok := value.(string); ok && v == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.attrs {
+		if s.attrs[i].Key == key {
+			s.attrs[i] = newAttribute(key, value)
+			return
+		}
+	}
+	s.attrs = append(s.attrs, newAttribute(key, value))
+}
+
+// AddEvent records that something named name happened at at.
+func (s *Span) AddEvent(name string, at time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, spanEvent{name: name, at: at})
+}
+
+// AddLink links the span to other, a span in another trace it is related to
+// but not a child of, such as each MR a merge train gates together.
+func (s *Span) AddLink(other SpanContext) {
+	if s == nil || other.Traceparent() == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = append(s.links, other)
+}
+
+// End ends the span and exports it, with an error status if err is non-nil.
+// Only the first call has any effect. Export failures are dropped: tracing
+// must never get in the way of the work.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := s.snapshot(time.Now(), err)
+	s.mu.Unlock()
+
+	if s.tracer.exporter != nil {
+		_ = s.tracer.exporter.export(s.tracer.resource, data)
+	}
+}
+
+type contextKey struct{}
+
+// ContextWithSpan returns ctx carrying span, for StartFromContext.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, span)
+}
+
+// SpanFromContext returns the span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextKey{}).(*Span)
+	return span
+}
+
+// StartFromContext starts a child of the span carried by ctx, with t. It
+// returns nil if ctx carries no span, so untraced callers stay untraced.
+func (t *Tracer) StartFromContext(ctx context.Context, name string) *Span {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return nil
+	}
+	return t.Start(name, parent.Context())
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to the clock; uniqueness matters more than randomness
+		sum := sha256.Sum256([]byte(time.Now().String()))
+		copy(b, sum[:])
+	}
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
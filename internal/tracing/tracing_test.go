@@ -0,0 +1,223 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	sc := SpanContext{TraceID: TraceIDFor("tr-1234abcd"), SpanID: "00f067aa0ba902b7"}
+	tp := sc.Traceparent()
+	if want := "00-" + sc.TraceID + "-00f067aa0ba902b7-01"; tp != want {
+		t.Fatalf("Traceparent() = %q, want %q", tp, want)
+	}
+	got, ok := ParseTraceparent(tp)
+	if !ok || got != sc {
+		t.Errorf("ParseTraceparent(%q) = %+v, %v", tp, got, ok)
+	}
+
+	for _, bad := range []string{
+		"",
+		"tr-1234abcd",
+		"00-" + sc.TraceID + "-00f067aa0ba902b7",
+		"ff-" + sc.TraceID + "-00f067aa0ba902b7-01",
+		"00-" + strings.Repeat("0", 32) + "-00f067aa0ba902b7-01",
+		"00-" + sc.TraceID + "-0000000000000000-01",
+		"00-" + strings.ToUpper(sc.TraceID) + "-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(bad); ok {
+			t.Errorf("ParseTraceparent(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConvoyContext(t *testing.T) {
+	a, b := ConvoyContext("hq-cv-abc"), ConvoyContext("hq-cv-abc")
+	if a != b || a.Traceparent() == "" {
+		t.Fatalf("ConvoyContext not stable: %+v, %+v", a, b)
+	}
+	if ConvoyContext("hq-cv-xyz").TraceID == a.TraceID {
+		t.Error("different convoys share a trace")
+	}
+}
+
+func TestSpanParenting(t *testing.T) {
+	tracer := NewWithConfig("", nil)
+	if tracer.Enabled() {
+		t.Fatal("tracer without config is enabled")
+	}
+
+	root := tracer.Start("gt sling", SpanContext{})
+	if root.Traceparent() == "" {
+		t.Fatal("disabled tracer gave span no IDs")
+	}
+
+	// Moved into a convoy's trace after it started
+	convoy := ConvoyContext("hq-cv-abc")
+	root.SetParent(convoy)
+	if root.Context().TraceID != convoy.TraceID || root.parentID != convoy.SpanID {
+		t.Errorf("SetParent: ctx %+v parent %q", root.Context(), root.parentID)
+	}
+
+	// A trace without a parent span makes a root in that trace
+	other := tracer.Start("gt sling", SpanContext{TraceID: TraceIDFor("tr-1")})
+	if other.Context().TraceID != TraceIDFor("tr-1") || other.parentID != "" {
+		t.Errorf("trace-only parent: ctx %+v parent %q", other.Context(), other.parentID)
+	}
+
+	ctx := ContextWithSpan(context.Background(), root)
+	child := tracer.StartFromContext(ctx, "gate test")
+	if child.Context().TraceID != convoy.TraceID || child.parentID != root.Context().SpanID {
+		t.Errorf("StartFromContext: ctx %+v parent %q", child.Context(), child.parentID)
+	}
+	if tracer.StartFromContext(context.Background(), "gate test") != nil {
+		t.Error("StartFromContext without a span started one")
+	}
+
+	// Nil tracers and spans are no-ops
+	var nilTracer *Tracer
+	span := nilTracer.Start("x", SpanContext{})
+	span.SetAttr("k", "v")
+	span.End(nil)
+	if span.Traceparent() != "" {
+		t.Error("nil span has a traceparent")
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	townRoot := t.TempDir()
+	tracer := NewWithConfig(townRoot, &config.TracingConfig{Exporter: ExporterFile})
+
+	parent := tracer.Start("gt sling", SpanContext{})
+	parent.SetAttr("gt.bead", "gt-abc")
+	parent.SetAttr("gt.attempt", 2)
+	parent.SetAttr("gt.no_merge", true)
+	parent.SetAttr("gt.empty", "")
+	parent.End(nil)
+	parent.End(errors.New("ignored")) // Only the first End exports
+
+	child := tracer.Start("gt done", parent.Context())
+	child.AddEvent("first commit", time.Unix(1700000000, 0))
+	other := ConvoyContext("hq-cv-abc")
+	child.AddLink(other)
+	child.AddLink(SpanContext{}) // Not a span; ignored
+	child.End(errors.New("push failed"))
+
+	data, err := os.ReadFile(filepath.Join(townRoot, DefaultFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+
+	first := decodeSpan(t, []byte(lines[0]))
+	if first.Name != "gt sling" || first.ParentSpanID != "" || first.Status.Code != 0 {
+		t.Errorf("parent span = %+v", first)
+	}
+	attrs := map[string]anyValue{}
+	for _, a := range first.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	if v := attrs["gt.bead"].StringValue; v == nil || *v != "gt-abc" {
+		t.Errorf("gt.bead = %+v", attrs["gt.bead"])
+	}
+	if v := attrs["gt.attempt"].IntValue; v == nil || *v != "2" {
+		t.Errorf("gt.attempt = %+v", attrs["gt.attempt"])
+	}
+	if v := attrs["gt.no_merge"].BoolValue; v == nil || !*v {
+		t.Errorf("gt.no_merge = %+v", attrs["gt.no_merge"])
+	}
+	if _, ok := attrs["gt.empty"]; ok {
+		t.Error("empty attribute exported")
+	}
+
+	second := decodeSpan(t, []byte(lines[1]))
+	if second.TraceID != first.TraceID || second.ParentSpanID != first.SpanID {
+		t.Errorf("child not linked: %+v", second)
+	}
+	if second.Status.Code != statusCodeError || second.Status.Message != "push failed" {
+		t.Errorf("child status = %+v", second.Status)
+	}
+	if len(second.Events) != 1 || second.Events[0].TimeUnixNano != "1700000000000000000" {
+		t.Errorf("child events = %+v", second.Events)
+	}
+	if len(second.Links) != 1 || second.Links[0].TraceID != other.TraceID || second.Links[0].SpanID != other.SpanID {
+		t.Errorf("child links = %+v", second.Links)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	t.Setenv("TRACE_TOKEN", "s3cret")
+	var gotPath, gotType, gotAuth string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotType, gotAuth = r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	tracer := NewWithConfig("", &config.TracingConfig{
+		Exporter: ExporterOTLP,
+		Endpoint: srv.URL + "/",
+		Headers:  map[string]string{"Authorization": "Bearer $TRACE_TOKEN"},
+	})
+	span := tracer.StartConvoy("hq-cv-abc")
+	span.End(nil)
+
+	if gotPath != "/v1/traces" || gotType != "application/json" || gotAuth != "Bearer s3cret" {
+		t.Errorf("request: path %q, type %q, auth %q", gotPath, gotType, gotAuth)
+	}
+	got := decodeSpan(t, body)
+	if want := ConvoyContext("hq-cv-abc"); got.TraceID != want.TraceID || got.SpanID != want.SpanID {
+		t.Errorf("convoy span = %+v, want %+v", got, want)
+	}
+}
+
+func TestTracesURL(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if got := tracesURL(""); got != DefaultEndpoint+"/v1/traces" {
+		t.Errorf("default = %q", got)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	if got := tracesURL(""); got != "http://collector:4318/v1/traces" {
+		t.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT = %q", got)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://collector:4318/custom")
+	if got := tracesURL(""); got != "http://collector:4318/custom" {
+		t.Errorf("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT = %q", got)
+	}
+	if got := tracesURL("http://local:4318"); got != "http://local:4318/v1/traces" {
+		t.Errorf("configured = %q", got)
+	}
+}
+
+// decodeSpan returns the single span in an ExportTraceServiceRequest.
+func decodeSpan(t *testing.T, data []byte) spanData {
+	t.Helper()
+	var req exportRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("want one span, got %s", data)
+	}
+	rs := req.ResourceSpans[0]
+	if v := rs.Resource.Attributes[0]; v.Key != "service.name" || *v.Value.StringValue != ServiceName {
+		t.Errorf("resource = %+v", rs.Resource)
+	}
+	return rs.ScopeSpans[0].Spans[0]
+}
@@ -181,6 +181,7 @@ var (
 		"merged":        "✓",
 		"merge_failed":  "✗",
 		"merge_skipped": "⊘",
+		"mail_rejected": "⛔",
 		// General gt events
 		"sling":   "🎯",
 		"hook":    "🪝",
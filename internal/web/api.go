@@ -103,6 +103,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleMailInbox(w, r)
 	case path == "/mail/threads" && r.Method == http.MethodGet:
 		h.handleMailThreads(w, r)
+	case path == "/mail/search" && r.Method == http.MethodGet:
+		h.handleMailSearch(w, r)
 	case path == "/mail/read" && r.Method == http.MethodGet:
 		h.handleMailRead(w, r)
 	case path == "/mail/send" && r.Method == http.MethodPost:
@@ -497,6 +499,81 @@ func (h *APIHandler) handleMailRead(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(msg)
 }
 
+// MailSearchHit is a search result from /api/mail/search. Highlights are
+// byte ranges of matched words in Snippet.
+type MailSearchHit struct {
+	Message    MailMessage `json:"message"`
+	Score      float64     `json:"score"`
+	Archived   bool        `json:"archived"`
+	Snippet    string      `json:"snippet,omitempty"`
+	Highlights []struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	} `json:"highlights,omitempty"`
+}
+
+// MailSearchResponse is the response for /api/mail/search.
+type MailSearchResponse struct {
+	Query   string          `json:"query"`
+	Results []MailSearchHit `json:"results"`
+	Total   int             `json:"total"`
+}
+
+// handleMailSearch searches the user's mail with the gt mail search query
+// syntax. Parameters: q (the query), archive=true to include archived
+// mail, limit (default 50).
+func (h *APIHandler) handleMailSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	const maxQueryLen = 500
+	if len(query) > maxQueryLen {
+		h.sendError(w, fmt.Sprintf("Query too long (max %d bytes)", maxQueryLen), http.StatusBadRequest)
+		return
+	}
+	for _, c := range query {
+		if c < 0x20 || c == 0x7f {
+			h.sendError(w, "Query cannot contain control characters", http.StatusBadRequest)
+			return
+		}
+	}
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "50"
+	}
+	if !isNumeric(limit) {
+		h.sendError(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	// Flags go first, then -- so a query starting with "-" isn't a flag.
+	args := []string{"mail", "search", "--json", "--limit", limit}
+	if r.URL.Query().Get("archive") == "true" {
+		args = append(args, "--archive")
+	}
+	args = append(args, "--", query)
+
+	output, err := h.runGtCommand(r.Context(), 15*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Failed to search mail: "+err.Error()+"\n"+output, http.StatusInternalServerError)
+		return
+	}
+
+	var results []MailSearchHit
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		h.sendError(w, "Failed to parse search results: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if results == nil {
+		results = []MailSearchHit{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(MailSearchResponse{
+		Query:   query,
+		Results: results,
+		Total:   len(results),
+	})
+}
+
 // MailSendRequest is the request body for /api/mail/send.
 type MailSendRequest struct {
 	To      string `json:"to"`
@@ -877,6 +877,7 @@
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 loading.style.display = 'none';
+                if (mailSearchInput && mailSearchInput.value.trim()) return; // showing search results
 
                 if (data.threads && data.threads.length > 0) {
                     threadsContainer.style.display = 'block';
@@ -994,6 +995,92 @@
         return dateStr + relative;
     }
 
+    // Search mail with the gt mail search query syntax; an empty query
+    // returns to the threaded inbox.
+    var mailSearchInput = document.getElementById('mail-search');
+    var mailSearchTimer = null;
+    if (mailSearchInput) {
+        mailSearchInput.addEventListener('input', function() {
+            clearTimeout(mailSearchTimer);
+            mailSearchTimer = setTimeout(function() { searchMail(mailSearchInput.value.trim()); }, 300);
+        });
+    }
+
+    function searchMail(query) {
+        var results = document.getElementById('mail-search-results');
+        var threadsContainer = document.getElementById('mail-threads');
+        var empty = document.getElementById('mail-empty');
+        if (!results) return;
+
+        if (!query) {
+            results.style.display = 'none';
+            loadMailInbox();
+            return;
+        }
+        threadsContainer.style.display = 'none';
+        empty.style.display = 'none';
+
+        fetch('/api/mail/search?archive=true&q=' + encodeURIComponent(query))
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                if (mailSearchInput && mailSearchInput.value.trim() !== query) return; // stale
+                results.style.display = 'block';
+                if (data.error) {
+                    results.innerHTML = '<div class="empty-state"><p>' + escapeHtml(data.error) + '</p></div>';
+                    return;
+                }
+                if (!data.results || data.results.length === 0) {
+                    results.innerHTML = '<div class="empty-state"><p>No matching mail</p></div>';
+                    return;
+                }
+                results.innerHTML = '';
+                data.results.forEach(function(hit) {
+                    var msg = hit.message;
+                    var el = document.createElement('div');
+                    el.className = 'mail-thread' + (msg.read ? '' : ' mail-thread-unread');
+                    var headerEl = document.createElement('div');
+                    headerEl.className = 'mail-thread-header';
+                    headerEl.setAttribute('data-msg-id', msg.id);
+                    headerEl.setAttribute('data-from', msg.from);
+                    headerEl.innerHTML =
+                        '<div class="mail-thread-left">' +
+                            '<span class="mail-from">' + escapeHtml(msg.from) + '</span>' +
+                        '</div>' +
+                        '<div class="mail-thread-center">' +
+                            '<span class="mail-subject">' + escapeHtml(msg.subject) + '</span>' +
+                            (hit.archived ? ' <span class="mail-search-archived">archived</span>' : '') +
+                            (hit.snippet ? '<div class="mail-search-snippet">' + highlightSnippet(hit.snippet, hit.highlights) + '</div>' : '') +
+                        '</div>' +
+                        '<div class="mail-thread-right">' +
+                            '<span class="mail-time">' + formatMailTime(msg.timestamp) + '</span>' +
+                        '</div>';
+                    el.appendChild(headerEl);
+                    results.appendChild(el);
+                });
+            })
+            .catch(function(err) {
+                results.style.display = 'block';
+                results.innerHTML = '<div class="empty-state"><p>Search failed</p></div>';
+                console.error('Mail search error:', err);
+            });
+    }
+
+    // highlightSnippet escapes a search snippet and marks its highlights,
+    // which are UTF-8 byte ranges.
+    function highlightSnippet(snippet, highlights) {
+        if (!highlights || highlights.length === 0) return escapeHtml(snippet);
+        var bytes = new TextEncoder().encode(snippet);
+        var decoder = new TextDecoder();
+        var html = '';
+        var last = 0;
+        highlights.forEach(function(h) {
+            html += escapeHtml(decoder.decode(bytes.slice(last, h.start)));
+            html += '<mark>' + escapeHtml(decoder.decode(bytes.slice(h.start, h.end))) + '</mark>';
+            last = h.end;
+        });
+        return html + escapeHtml(decoder.decode(bytes.slice(last)));
+    }
+
     // Load mail on page load
     loadMailInbox();
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -258,6 +259,24 @@ func TestHandler_MailRead_InvalidID(t *testing.T) {
 	}
 }
 
+func TestHandler_MailSearch_InvalidQuery(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+
+	for _, target := range []string{
+		"/api/mail/search?q=" + url.QueryEscape("from:witness\x00"),
+		"/api/mail/search?q=merge&limit=--all",
+		"/api/mail/search?q=" + strings.Repeat("x", 501),
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET %.60s status = %d, want %d", target, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestHandler_MailSend_InvalidRecipient(t *testing.T) {
 	handler := NewAPIHandler(30*time.Second, 60*time.Second)
 
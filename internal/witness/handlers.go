@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -47,7 +48,89 @@ type HandlerResult struct {
 	Error        error
 }
 
+// protocolSenders maps the protocol mail the witness acts on to the role
+// that sends it.
+var protocolSenders = map[ProtocolType]session.Role{
+	ProtoPolecatDone: session.RolePolecat,
+	ProtoMerged:      session.RoleRefinery,
+	ProtoMergeFailed: session.RoleRefinery,
+}
+
+// verifySender checks that msg carries the router's signature as the role
+// that sends proto, so mail forged to look like it is refused before the
+// witness nukes or notifies anything. Rejections are reported to the
+// activity feed.
+func verifySender(workDir string, msg *mail.Message, proto ProtocolType) error {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("verifying %s: %s is not in a Gas Town workspace", msg.Subject, workDir)
+	}
+	key, err := mail.SigningKey(townRoot)
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", msg.Subject, err)
+	}
+	if err := mail.VerifySender(key, msg, protocolSenders[proto]); err != nil {
+		_ = events.LogFeed(events.TypeMailRejected, msg.From,
+			events.MailRejectedPayload(msg.ID, msg.To, msg.Subject, err.Error()))
+		return fmt.Errorf("rejected %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// handleProtocol verifies msg's sender, runs handle, and keeps msg in the
+// rig's dead-letter queue if handling failed, so the daemon retries it
+// through the rig's protocol registry (see Redeliver). Rejected mail is
+// not queued.
+func handleProtocol(workDir, rigName string, msg *mail.Message, proto ProtocolType, handle func(*HandlerResult) *HandlerResult) *HandlerResult {
+	result := &HandlerResult{
+		MessageID:    msg.ID,
+		ProtocolType: proto,
+	}
+	if err := verifySender(workDir, msg, proto); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result = handle(result)
+	if result.Error != nil && !result.Handled {
+		if townRoot, err := workspace.Find(workDir); err == nil && townRoot != "" {
+			dl := mail.OpenDeadLetters(filepath.Join(townRoot, rigName))
+			if _, err := dl.Add(msg, result.Error, time.Now()); err != nil {
+				result.Error = fmt.Errorf("%w (recording dead letter: %v)", result.Error, err)
+			}
+		}
+	}
+	return result
+}
+
+// Redeliver hands a dead-lettered POLECAT_DONE, MERGED, or MERGE_FAILED
+// message back to its handler. Like the handlers it verifies the sender,
+// but it leaves recording failures to the caller's dead-letter queue.
+func Redeliver(workDir, rigName string, msg *mail.Message, router *mail.Router) error {
+	proto := ClassifyMessage(msg.Subject)
+	if _, ok := protocolSenders[proto]; !ok {
+		return fmt.Errorf("witness doesn't redeliver %s", msg.Subject)
+	}
+	if err := verifySender(workDir, msg, proto); err != nil {
+		return err
+	}
+	result := &HandlerResult{MessageID: msg.ID, ProtocolType: proto}
+	switch proto {
+	case ProtoPolecatDone:
+		result = handlePolecatDone(workDir, rigName, msg, router, result)
+	case ProtoMerged:
+		result = handleMerged(workDir, rigName, msg, result)
+	case ProtoMergeFailed:
+		result = handleMergeFailed(workDir, rigName, msg, router, result)
+	}
+	if !result.Handled {
+		return result.Error
+	}
+	return nil
+}
+
 // HandlePolecatDone processes a POLECAT_DONE message from a polecat.
+// Messages not signed by the router as a polecat are rejected unhandled.
 // For ESCALATED/DEFERRED exits (no pending MR), auto-nukes if clean.
 // For PHASE_COMPLETE exits, recycles the polecat (session ends, worktree kept).
 // For COMPLETED exits with MR and clean state, auto-nukes immediately (ephemeral model).
@@ -62,12 +145,15 @@ type HandlerResult struct {
 // Once the branch is pushed (cleanup_status=clean), the polecat can be nuked.
 // The MR lifecycle continues independently in the Refinery.
 // If conflicts arise, Refinery creates a NEW conflict-resolution task for a NEW polecat.
+//
+// Failures are kept in the rig's dead-letter queue for retry.
 func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.Router) *HandlerResult {
-	result := &HandlerResult{
-		MessageID:    msg.ID,
-		ProtocolType: ProtoPolecatDone,
-	}
+	return handleProtocol(workDir, rigName, msg, ProtoPolecatDone, func(result *HandlerResult) *HandlerResult {
+		return handlePolecatDone(workDir, rigName, msg, router, result)
+	})
+}
 
+func handlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.Router, result *HandlerResult) *HandlerResult {
 	payload, err := ParsePolecatDone(msg.Subject, msg.Body)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing POLECAT_DONE: %w", err)
@@ -265,12 +351,15 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 
 // HandleMerged processes a MERGED message from the Refinery.
 // Verifies cleanup_status before allowing nuke, escalates if work is at risk.
+// Messages not signed by the router as the refinery are rejected unhandled.
+// Failures to act on it are kept in the rig's dead-letter queue for retry.
 func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
-	result := &HandlerResult{
-		MessageID:    msg.ID,
-		ProtocolType: ProtoMerged,
-	}
+	return handleProtocol(workDir, rigName, msg, ProtoMerged, func(result *HandlerResult) *HandlerResult {
+		return handleMerged(workDir, rigName, msg, result)
+	})
+}
 
+func handleMerged(workDir, rigName string, msg *mail.Message, result *HandlerResult) *HandlerResult {
 	payload, err := ParseMerged(msg.Subject, msg.Body)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing MERGED: %w", err)
@@ -347,12 +436,15 @@ func handleMergedCleanupStatus(workDir, rigName, polecatName, cleanupStatus, wis
 
 // HandleMergeFailed processes a MERGE_FAILED message from the Refinery.
 // Notifies the polecat that their merge was rejected and rework is needed.
+// Messages not signed by the router as the refinery are rejected unhandled.
+// Failures are kept in the rig's dead-letter queue for retry.
 func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.Router) *HandlerResult {
-	result := &HandlerResult{
-		MessageID:    msg.ID,
-		ProtocolType: ProtoMergeFailed,
-	}
+	return handleProtocol(workDir, rigName, msg, ProtoMergeFailed, func(result *HandlerResult) *HandlerResult {
+		return handleMergeFailed(workDir, rigName, msg, router, result)
+	})
+}
 
+func handleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.Router, result *HandlerResult) *HandlerResult {
 	// Parse the message
 	payload, err := ParseMergeFailed(msg.Subject, msg.Body)
 	if err != nil {
@@ -374,12 +466,13 @@ Branch: %s
 Issue: %s
 Failure: %s
 Error: %s
-
+%s
 Please fix the issue and resubmit with 'gt done'.`,
 			payload.Branch,
 			payload.IssueID,
 			payload.FailureType,
 			payload.Error,
+			FormatFailureReport(payload.FailingTests, payload.Excerpt, payload.Artifact, payload.RevertCommit),
 		),
 	}
 
@@ -395,6 +488,32 @@ Please fix the issue and resubmit with 'gt done'.`,
 	return result
 }
 
+// FormatFailureReport renders the structured gate failure report of a
+// MERGE_FAILED for the polecat's notification. Returns "" when there is
+// nothing to add. The protocol package's witness handler uses it too, so
+// both notifications read the same.
+func FormatFailureReport(failingTests []string, excerpt, artifact, revertCommit string) string {
+	var sb strings.Builder
+	if len(failingTests) > 0 {
+		sb.WriteString("\nFailing tests:\n")
+		for _, t := range failingTests {
+			sb.WriteString(fmt.Sprintf("  - %s\n", t))
+		}
+	}
+	if excerpt != "" {
+		sb.WriteString("\nOutput excerpt:\n")
+		sb.WriteString(excerpt)
+		sb.WriteString("\n")
+	}
+	if artifact != "" {
+		sb.WriteString(fmt.Sprintf("\nFull gate output: %s\n", artifact))
+	}
+	if revertCommit != "" {
+		sb.WriteString(fmt.Sprintf("\nThe merge landed but failed post-merge gates and was reverted in %s.\n", revertCommit))
+	}
+	return sb.String()
+}
+
 // HandleSwarmStart processes a SWARM_START message from the Mayor.
 // Creates a swarm tracking wisp to monitor batch polecat work.
 func HandleSwarmStart(workDir string, msg *mail.Message) *HandlerResult {
@@ -578,20 +697,24 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 // sendMergeReady sends a MERGE_READY notification to the Refinery.
 // This signals that a polecat's work is ready for merge queue processing.
 func sendMergeReady(router *mail.Router, rigName string, payload *PolecatDonePayload) (string, error) {
-	msg := mail.NewMessage(
-		fmt.Sprintf("%s/witness", rigName),
-		fmt.Sprintf("%s/refinery", rigName),
-		fmt.Sprintf("MERGE_READY %s", payload.PolecatName),
-		fmt.Sprintf(`Branch: %s
+	body := fmt.Sprintf(`Branch: %s
 Issue: %s
 MR: %s
 Polecat: %s
 Verified: clean git state`,
-			payload.Branch,
-			payload.IssueID,
-			payload.MRID,
-			payload.PolecatName,
-		),
+		payload.Branch,
+		payload.IssueID,
+		payload.MRID,
+		payload.PolecatName,
+	)
+	if payload.TraceID != "" {
+		body += fmt.Sprintf("\nTrace: %s", payload.TraceID)
+	}
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		fmt.Sprintf("%s/refinery", rigName),
+		fmt.Sprintf("MERGE_READY %s", payload.PolecatName),
+		body,
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -1249,3 +1250,116 @@ esac
 	}
 }
 
+func TestHandlers_RejectUnverifiedSenders(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	workDir := filepath.Join(townRoot, "gastown", "witness")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	key, err := mail.SigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := func(from, subject, body string) *mail.Message {
+		msg := mail.NewMessage(from, "gastown/witness", subject, body)
+		mail.Sign(key, msg)
+		return msg
+	}
+	mergedBody := "Branch: polecat/nux\nIssue: gt-abc\nMerged-At: 2026-03-01T12:00:00Z\n"
+	unsigned := mail.NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", mergedBody)
+	forgedMerged := signed("gastown/polecats/nux", "MERGED nux", mergedBody)
+	forgedFailed := signed("gastown/polecats/nux", "MERGE_FAILED nux", "Branch: polecat/nux\nFailure-Type: tests\n")
+	forgedDone := signed("gastown/refinery", "POLECAT_DONE nux", "Exit: COMPLETED\n")
+
+	for name, result := range map[string]*HandlerResult{
+		"unsigned MERGED":             HandleMerged(workDir, "gastown", unsigned),
+		"MERGED from a polecat":       HandleMerged(workDir, "gastown", forgedMerged),
+		"MERGE_FAILED from a polecat": HandleMergeFailed(workDir, "gastown", forgedFailed, nil),
+		"POLECAT_DONE from refinery":  HandlePolecatDone(workDir, "gastown", forgedDone, nil),
+	} {
+		if result.Handled || result.Error == nil || !strings.Contains(result.Error.Error(), "rejected") {
+			t.Errorf("%s: handled %v, error %v; want rejected", name, result.Handled, result.Error)
+		}
+	}
+
+	// A polecat's own POLECAT_DONE gets past verification to parsing.
+	done := signed("gastown/polecats/nux", "POLECAT_DONE nux", "")
+	result := HandlePolecatDone(workDir, "gastown", done, nil)
+	if result.Error != nil && strings.Contains(result.Error.Error(), "rejected") {
+		t.Errorf("signed POLECAT_DONE rejected: %v", result.Error)
+	}
+
+	// Rejected mail is never dead-lettered.
+	letters, _ := mail.OpenDeadLetters(filepath.Join(townRoot, "gastown")).List()
+	for _, l := range letters {
+		if l.Message.ID != done.ID {
+			t.Errorf("rejected %s was dead-lettered", l.Message.Subject)
+		}
+	}
+}
+
+func TestHandleMerged_DeadLettersFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(rigPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	key, err := mail.SigningKey(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// bd fails until dolt is "up".
+	binDir := t.TempDir()
+	doltUp := filepath.Join(binDir, "dolt-up")
+	script := fmt.Sprintf(`#!/bin/sh
+[ -f %q ] || { echo "dolt: connection refused" >&2; exit 1; }
+echo "[]"
+`, doltUp)
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	msg := mail.NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "Branch: polecat/nux\nIssue: gt-abc\n")
+	mail.Sign(key, msg)
+	if result := HandleMerged(rigPath, "gastown", msg); result.Error == nil || result.Handled {
+		t.Fatalf("result = %+v, want a failure", result)
+	}
+
+	dl := mail.OpenDeadLetters(rigPath)
+	letters, err := dl.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 1 || letters[0].Message.ID != msg.ID {
+		t.Fatalf("dead letters = %+v, want the MERGED message", letters)
+	}
+
+	// Retried through Redeliver once bd is back.
+	if err := os.WriteFile(doltUp, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resolved, _, err := dl.Retry(time.Now(), []string{letters[0].ID}, func(m *mail.Message) error {
+		return Redeliver(rigPath, "gastown", m, nil)
+	})
+	if err != nil || len(resolved) != 1 {
+		t.Errorf("retry: resolved %d, err %v; want the letter resolved", len(resolved), err)
+	}
+}
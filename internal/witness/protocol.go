@@ -58,6 +58,7 @@ type PolecatDonePayload struct {
 	MRID        string
 	Branch      string
 	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	TraceID     string // Trace ID of the work, from gt sling (see gt trace)
 }
 
 // HelpPayload contains parsed data from a HELP message.
@@ -96,6 +97,15 @@ type MergeFailedPayload struct {
 	FailureType string // "build", "test", "lint", etc.
 	Error       string
 	FailedAt    time.Time
+
+	// Structured gate failure report, when the refinery could parse one.
+	FailingTests []string
+	Excerpt      string
+	Artifact     string
+
+	// RevertCommit is set for post_merge failures: the merge landed and
+	// this commit undid it.
+	RevertCommit string
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
@@ -139,6 +149,7 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	Trace: <trace-id>
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -162,6 +173,8 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "Trace:") {
+			payload.TraceID = strings.TrimSpace(strings.TrimPrefix(line, "Trace:"))
 		}
 	}
 
@@ -247,6 +260,12 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //	Issue: <issue-id>
 //	FailureType: <type>
 //	Error: <error-message>
+//	Failing-Test: <test>            (optional, one line per test)
+//	Artifact: <gate-log-path>       (optional)
+//	Revert-Commit: <sha>            (optional, post_merge only)
+//
+//	Excerpt:                        (optional, always last)
+//	<gate output lines>
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -259,8 +278,14 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	}
 
 	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
 		line = strings.TrimSpace(line)
+		if line == "Excerpt:" {
+			// Free-form gate output runs to the end of the body.
+			payload.Excerpt = strings.TrimRight(strings.Join(lines[i+1:], "\n"), "\n")
+			break
+		}
 		switch {
 		case strings.HasPrefix(line, "Branch:"):
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
@@ -270,6 +295,14 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+		case strings.HasPrefix(line, "Failing-Test:"):
+			if test := strings.TrimSpace(strings.TrimPrefix(line, "Failing-Test:")); test != "" {
+				payload.FailingTests = append(payload.FailingTests, test)
+			}
+		case strings.HasPrefix(line, "Artifact:"):
+			payload.Artifact = strings.TrimSpace(strings.TrimPrefix(line, "Artifact:"))
+		case strings.HasPrefix(line, "Revert-Commit:"):
+			payload.RevertCommit = strings.TrimSpace(strings.TrimPrefix(line, "Revert-Commit:"))
 		}
 	}
 
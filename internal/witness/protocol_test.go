@@ -1,6 +1,7 @@
 package witness
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -42,7 +43,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Trace: tr-1a2b3c4d`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -64,6 +66,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if payload.TraceID != "tr-1a2b3c4d" {
+		t.Errorf("TraceID = %q, want %q", payload.TraceID, "tr-1a2b3c4d")
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
@@ -193,6 +198,56 @@ Error: unit tests failed with 3 errors`
 	}
 }
 
+func TestParseMergeFailed_WithReport(t *testing.T) {
+	subject := "MERGE_FAILED nux"
+	body := `Branch: feature-nux
+Issue: gt-abc123
+Error: quality gates failed
+Failing-Test: TestA (example.com/pkg)
+Failing-Test: suite.TestB[a, b]
+Artifact: /tmp/gate-logs/gt-mr1.log
+
+Excerpt:
+[test]
+    a_test.go:10: Branch: not a field`
+
+	payload, err := ParseMergeFailed(subject, body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if len(payload.FailingTests) != 2 || payload.FailingTests[0] != "TestA (example.com/pkg)" || payload.FailingTests[1] != "suite.TestB[a, b]" {
+		t.Errorf("FailingTests = %v", payload.FailingTests)
+	}
+	if payload.Artifact != "/tmp/gate-logs/gt-mr1.log" {
+		t.Errorf("Artifact = %q", payload.Artifact)
+	}
+	if payload.Excerpt != "[test]\n    a_test.go:10: Branch: not a field" {
+		t.Errorf("Excerpt = %q", payload.Excerpt)
+	}
+	if payload.Branch != "feature-nux" {
+		t.Errorf("Branch = %q, want %q (excerpt must not override header)", payload.Branch, "feature-nux")
+	}
+}
+
+func TestParseMergeFailed_PostMergeRevert(t *testing.T) {
+	body := `Branch: feature-nux
+Issue: gt-abc123
+Error: post-merge gates failed: smoke: exit status 1
+Merge-Commit: abc123
+Revert-Commit: def456`
+
+	payload, err := ParseMergeFailed("MERGE_FAILED nux", body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if payload.RevertCommit != "def456" {
+		t.Errorf("RevertCommit = %q, want %q", payload.RevertCommit, "def456")
+	}
+	if report := FormatFailureReport(payload.FailingTests, payload.Excerpt, payload.Artifact, payload.RevertCommit); !strings.Contains(report, "reverted in def456") {
+		t.Errorf("failure report missing revert: %q", report)
+	}
+}
+
 func TestParseMergeFailed_MinimalBody(t *testing.T) {
 	subject := "MERGE_FAILED ace"
 	body := "FailureType: build"